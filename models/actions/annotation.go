@@ -0,0 +1,105 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// AnnotationLevel is the level of an annotation, it's the name of the workflow command which creates the annotation
+type AnnotationLevel string
+
+const (
+	AnnotationLevelNotice  AnnotationLevel = "notice"
+	AnnotationLevelWarning AnnotationLevel = "warning"
+	AnnotationLevelError   AnnotationLevel = "error"
+)
+
+// MaxAnnotationsPerTask is the max number of annotations a task can create, the others are ignored
+const MaxAnnotationsPerTask = 50
+
+// ActionAnnotation is an annotation created by a workflow command like "::error file=app.js,line=1::Missing semicolon" in the logs of a task
+type ActionAnnotation struct {
+	ID        int64
+	RepoID    int64           `xorm:"index"`
+	RunID     int64           `xorm:"index"`
+	JobID     int64           `xorm:"index"`
+	TaskID    int64           `xorm:"index"`
+	CommitSHA string          `xorm:"index"`
+	Level     AnnotationLevel `xorm:"VARCHAR(16)"`
+	// Path is the path of the file in the repository which the annotation is about, it's empty if the annotation isn't about a file
+	Path      string `xorm:"VARCHAR(500)"`
+	StartLine int64
+	EndLine   int64
+	Title     string             `xorm:"VARCHAR(255)"`
+	Message   string             `xorm:"TEXT"`
+	LogIndex  int64              // the index of the log line which creates the annotation
+	Created   timeutil.TimeStamp `xorm:"created"`
+
+	Run *ActionRun `xorm:"-"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionAnnotation))
+}
+
+type AnnotationList []*ActionAnnotation
+
+// LoadRuns loads the runs of the annotations
+func (annotations AnnotationList) LoadRuns(ctx context.Context) error {
+	runIDs := container.FilterSlice(annotations, func(annotation *ActionAnnotation) (int64, bool) {
+		return annotation.RunID, annotation.Run == nil
+	})
+	if len(runIDs) == 0 {
+		return nil
+	}
+	runs := make(map[int64]*ActionRun, len(runIDs))
+	if err := db.GetEngine(ctx).In("id", runIDs).Find(&runs); err != nil {
+		return err
+	}
+	for _, annotation := range annotations {
+		if annotation.Run == nil {
+			annotation.Run = runs[annotation.RunID]
+		}
+	}
+	return nil
+}
+
+type FindAnnotationOptions struct {
+	db.ListOptions
+	RepoID  int64
+	TaskIDs []int64
+	// CommitSHA finds the annotations of the latest tasks of the jobs which run for the commit
+	CommitSHA string
+}
+
+func (opts FindAnnotationOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.TaskIDs != nil {
+		cond = cond.And(builder.In("task_id", opts.TaskIDs))
+	}
+	if opts.CommitSHA != "" {
+		cond = cond.And(builder.Eq{"commit_sha": opts.CommitSHA}).
+			And(builder.In("task_id", builder.Select("task_id").From("action_run_job").Where(builder.Eq{"commit_sha": opts.CommitSHA})))
+	}
+	return cond
+}
+
+func (opts FindAnnotationOptions) ToOrders() string {
+	return "`id` ASC"
+}
+
+// CountTaskAnnotations returns the number of the annotations created by the task
+func CountTaskAnnotations(ctx context.Context, taskID int64) (int64, error) {
+	return db.GetEngine(ctx).Where("task_id=?", taskID).Count(new(ActionAnnotation))
+}
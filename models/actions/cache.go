@@ -0,0 +1,151 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ActionCache is a cache entry uploaded by `actions/cache`.
+// A cache is created for the ref of the run which uploads it, and it could be restored by the runs of the same ref,
+// or by the runs of other refs if it's created for the default branch.
+type ActionCache struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"index NOT NULL"`
+	Ref         string             `xorm:"VARCHAR(255)"`
+	CacheKey    string             `xorm:"VARCHAR(512)"`
+	Version     string             `xorm:"VARCHAR(255)"` // the hash of the paths and the compression method, a cache could only be restored with the same version
+	Size        int64              // the size of the whole cache, it's known only after the upload is committed
+	StoragePath string             // the path to the cache in the storage
+	Complete    bool               `xorm:"index"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UsedUnix    timeutil.TimeStamp `xorm:"index"` // the time when the cache was created or restored last time, used for evicting
+}
+
+func init() {
+	db.RegisterModel(new(ActionCache))
+}
+
+type FindCachesOptions struct {
+	db.ListOptions
+	RepoID     int64
+	Refs       []string
+	Version    string
+	Complete   optional.Option[bool]
+	UsedBefore timeutil.TimeStamp
+}
+
+func (opts FindCachesOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if len(opts.Refs) > 0 {
+		cond = cond.And(builder.In("ref", opts.Refs))
+	}
+	if opts.Version != "" {
+		cond = cond.And(builder.Eq{"version": opts.Version})
+	}
+	if opts.Complete.Has() {
+		cond = cond.And(builder.Eq{"complete": opts.Complete.Value()})
+	}
+	if opts.UsedBefore > 0 {
+		cond = cond.And(builder.Lt{"used_unix": opts.UsedBefore})
+	}
+	return cond
+}
+
+func (opts FindCachesOptions) ToOrders() string {
+	return "`used_unix` ASC, `id` ASC"
+}
+
+func InsertCache(ctx context.Context, cache *ActionCache) error {
+	cache.UsedUnix = timeutil.TimeStampNow()
+	return db.Insert(ctx, cache)
+}
+
+func GetCacheByID(ctx context.Context, repoID, id int64) (*ActionCache, error) {
+	cache := &ActionCache{}
+	has, err := db.GetEngine(ctx).Where("id=? AND repo_id=?", id, repoID).Get(cache)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("cache with id %d: %w", id, util.ErrNotExist)
+	}
+	return cache, nil
+}
+
+// FindCacheToRestore finds the complete cache matching the keys with the version.
+// Like GitHub, the refs are searched in order, and for each ref, a cache is matched when one of the keys is
+// the same as its key, or else the most recently created cache whose key has one of the keys as prefix.
+func FindCacheToRestore(ctx context.Context, repoID int64, refs, keys []string, version string) (*ActionCache, error) {
+	var caches []*ActionCache
+	if err := db.GetEngine(ctx).Where(FindCachesOptions{
+		RepoID:   repoID,
+		Refs:     refs,
+		Version:  version,
+		Complete: optional.Some(true),
+	}.ToConds()).OrderBy("`created_unix` DESC, `id` DESC").Find(&caches); err != nil {
+		return nil, err
+	}
+	if cache := matchCache(caches, refs, keys); cache != nil {
+		return cache, nil
+	}
+	return nil, fmt.Errorf("cache with keys %v: %w", keys, util.ErrNotExist)
+}
+
+// matchCache expects the caches are sorted from the newest to the oldest
+func matchCache(caches []*ActionCache, refs, keys []string) *ActionCache {
+	for _, ref := range refs {
+		for _, key := range keys {
+			for _, cache := range caches {
+				if cache.Ref == ref && cache.CacheKey == key {
+					return cache
+				}
+			}
+			for _, cache := range caches {
+				if cache.Ref == ref && strings.HasPrefix(cache.CacheKey, key) {
+					return cache
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// GetCacheByKey returns the cache, no matter whether it's complete, with the exactly same key, version and ref
+func GetCacheByKey(ctx context.Context, repoID int64, ref, key, version string) (*ActionCache, error) {
+	cache := &ActionCache{}
+	has, err := db.GetEngine(ctx).Where("repo_id=? AND ref=? AND cache_key=? AND version=?", repoID, ref, key, version).Get(cache)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("cache with key %q: %w", key, util.ErrNotExist)
+	}
+	return cache, nil
+}
+
+// GetCachesSizeOfRepo returns the total size of the complete caches of a repository
+func GetCachesSizeOfRepo(ctx context.Context, repoID int64) (int64, error) {
+	return db.GetEngine(ctx).Where("repo_id=? AND complete=?", repoID, true).SumInt(new(ActionCache), "size")
+}
+
+func UpdateCache(ctx context.Context, cache *ActionCache, cols ...string) error {
+	_, err := db.GetEngine(ctx).ID(cache.ID).Cols(cols...).Update(cache)
+	return err
+}
+
+func DeleteCache(ctx context.Context, id int64) error {
+	_, err := db.DeleteByID[ActionCache](ctx, id)
+	return err
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchCache(t *testing.T) {
+	// sorted from the newest to the oldest
+	caches := []*ActionCache{
+		{ID: 5, Ref: "refs/heads/feature", CacheKey: "npm-linux-new"},
+		{ID: 4, Ref: "refs/heads/main", CacheKey: "npm-linux-main"},
+		{ID: 3, Ref: "refs/heads/feature", CacheKey: "npm-linux-old"},
+		{ID: 2, Ref: "refs/heads/feature", CacheKey: "npm-linux"},
+		{ID: 1, Ref: "refs/heads/main", CacheKey: "go-linux"},
+	}
+	refs := []string{"refs/heads/feature", "refs/heads/main"}
+
+	cases := []struct {
+		keys     []string
+		expected int64
+	}{
+		{keys: []string{"npm-linux"}, expected: 2},                       // exact match takes precedence
+		{keys: []string{"npm-linux-"}, expected: 5},                      // the newest prefix match
+		{keys: []string{"npm-linux-old", "npm-linux-"}, expected: 3},     // keys are matched in order
+		{keys: []string{"npm-linux-main"}, expected: 4},                  // fallback to the other ref
+		{keys: []string{"go-"}, expected: 1},                             // prefix match of the other ref
+		{keys: []string{"npm-windows", "npm-macos"}, expected: 0},        // no match
+		{keys: []string{"npm-linux-main", "npm-linux-"}, expected: 5},    // the refs take precedence over the keys
+		{keys: []string{"npm-linux-new", "npm-linux-main"}, expected: 5}, // exact match of the first key
+	}
+	for _, c := range cases {
+		cache := matchCache(caches, refs, c.keys)
+		if c.expected == 0 {
+			assert.Nil(t, cache, "keys: %v", c.keys)
+		} else if assert.NotNil(t, cache, "keys: %v", c.keys) {
+			assert.Equal(t, c.expected, cache.ID, "keys: %v", c.keys)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+)
+
+// FindDeploymentsOptions are the options to find the deployments of a repository, the latest ones come first.
+// A deployment is a job which targets an environment with the `environment` keyword.
+type FindDeploymentsOptions struct {
+	db.ListOptions
+	RepoID      int64
+	Environment string
+}
+
+func (opts FindDeploymentsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	if opts.Environment != "" {
+		cond = cond.And(builder.Eq{"environment": opts.Environment})
+	} else {
+		cond = cond.And(builder.Neq{"environment": ""})
+	}
+	return cond
+}
+
+func (opts FindDeploymentsOptions) ToOrders() string {
+	return "`id` DESC"
+}
+
+// GetDeploymentEnvironmentNames returns the names of the environments which have been targeted by the jobs of the repository
+func GetDeploymentEnvironmentNames(ctx context.Context, repoID int64) ([]string, error) {
+	var names []string
+	return names, db.GetEngine(ctx).Table("action_run_job").
+		Where(builder.Eq{"repo_id": repoID}.And(builder.Neq{"environment": ""})).
+		Distinct("environment").
+		Find(&names)
+}
+
+// GetLatestDeployments returns the latest successful deployments of the environments of the repository with their runs,
+// the keys are the names of the environments
+func GetLatestDeployments(ctx context.Context, repoID int64) (map[string]*ActionRunJob, error) {
+	var ids []int64
+	if err := db.GetEngine(ctx).Table("action_run_job").
+		Select("MAX(id)").
+		Where(builder.Eq{"repo_id": repoID, "status": StatusSuccess}.And(builder.Neq{"environment": ""})).
+		GroupBy("environment").
+		Find(&ids); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return map[string]*ActionRunJob{}, nil
+	}
+
+	var jobs ActionJobList
+	if err := db.GetEngine(ctx).In("id", ids).Find(&jobs); err != nil {
+		return nil, err
+	}
+	if err := jobs.LoadRuns(ctx, false); err != nil {
+		return nil, err
+	}
+	deployments := make(map[string]*ActionRunJob, len(jobs))
+	for _, job := range jobs {
+		deployments[job.Environment] = job
+	}
+	return deployments, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ActionEnvironment represents a deployment environment of a repository which jobs could target with the `environment` keyword.
+// The jobs targeting an environment could access its secrets, and they have to be approved by one of the reviewers
+// before starting if the environment has reviewers.
+type ActionEnvironment struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"UNIQUE(repo_name) NOT NULL"`
+	Name        string             `xorm:"UNIQUE(repo_name) NOT NULL"`
+	ReviewerIDs []int64            `xorm:"JSON TEXT"` // the users who could approve the jobs targeting the environment
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionEnvironment))
+}
+
+// IsProtected returns whether the jobs targeting the environment need to be approved
+func (env *ActionEnvironment) IsProtected() bool {
+	return len(env.ReviewerIDs) > 0
+}
+
+// IsReviewer returns whether the user could approve the jobs targeting the environment
+func (env *ActionEnvironment) IsReviewer(userID int64) bool {
+	return slices.Contains(env.ReviewerIDs, userID)
+}
+
+type FindEnvironmentsOptions struct {
+	db.ListOptions
+	RepoID int64
+	Names  []string
+}
+
+func (opts FindEnvironmentsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	if len(opts.Names) > 0 {
+		cond = cond.And(builder.In("name", opts.Names))
+	}
+	return cond
+}
+
+func (opts FindEnvironmentsOptions) ToOrders() string {
+	return "`name` ASC"
+}
+
+func InsertEnvironment(ctx context.Context, repoID int64, name string) (*ActionEnvironment, error) {
+	env := &ActionEnvironment{
+		RepoID: repoID,
+		Name:   name,
+	}
+	return env, db.Insert(ctx, env)
+}
+
+func GetEnvironmentByID(ctx context.Context, repoID, id int64) (*ActionEnvironment, error) {
+	env := &ActionEnvironment{}
+	has, err := db.GetEngine(ctx).Where("id=? AND repo_id=?", id, repoID).Get(env)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("environment with id %d: %w", id, util.ErrNotExist)
+	}
+	return env, nil
+}
+
+func GetEnvironmentByName(ctx context.Context, repoID int64, name string) (*ActionEnvironment, error) {
+	env := &ActionEnvironment{}
+	has, err := db.GetEngine(ctx).Where("repo_id=? AND name=?", repoID, name).Get(env)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("environment with name %q: %w", name, util.ErrNotExist)
+	}
+	return env, nil
+}
+
+func UpdateEnvironment(ctx context.Context, env *ActionEnvironment, cols ...string) error {
+	_, err := db.GetEngine(ctx).ID(env.ID).Cols(cols...).Update(env)
+	return err
+}
+
+func DeleteEnvironment(ctx context.Context, id int64) error {
+	_, err := db.DeleteByID[ActionEnvironment](ctx, id)
+	return err
+}
@@ -13,6 +13,10 @@ func TestMain(m *testing.M) {
 	unittest.MainTest(m, &unittest.TestOptions{
 		FixtureFiles: []string{
 			"action_runner_token.yml",
+			"action_run.yml",
+			"action_run_job.yml",
+			"action_task.yml",
+			"repository.yml",
 		},
 	})
 }
@@ -0,0 +1,150 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ActionOrgRequiredWorkflow is a workflow in a repository of an organization which runs in the repositories of the organization
+// on push and pull request events, as if it were one of their own workflows. Its failed runs block merging the pull requests.
+type ActionOrgRequiredWorkflow struct {
+	ID         int64
+	OrgID      int64              `xorm:"index"`
+	RepoID     int64              `xorm:"index"`        // the repository which the workflow is in
+	WorkflowID string             `xorm:"VARCHAR(255)"` // the entry name of the workflow in the repository, like "security.yml"
+	RepoIDs    []int64            `xorm:"JSON TEXT"`    // the repositories which run the workflow, it's all repositories of the organization if it's empty
+	Created    timeutil.TimeStamp `xorm:"created"`
+	Updated    timeutil.TimeStamp `xorm:"updated"`
+
+	Repo *repo_model.Repository `xorm:"-"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionOrgRequiredWorkflow))
+}
+
+// AppliesTo returns whether the workflow runs in the repository, it never runs in the repository which it's in,
+// because it runs there as an ordinary workflow
+func (w *ActionOrgRequiredWorkflow) AppliesTo(repo *repo_model.Repository) bool {
+	if repo.OwnerID != w.OrgID || repo.ID == w.RepoID {
+		return false
+	}
+	return len(w.RepoIDs) == 0 || slices.Contains(w.RepoIDs, repo.ID)
+}
+
+// RunWorkflowID returns the workflow id of the runs of the workflow in the other repositories, like "security/scan.yml",
+// it never conflicts with the workflows of the repositories since their ids have no slash.
+func (w *ActionOrgRequiredWorkflow) RunWorkflowID() string {
+	return fmt.Sprintf("%s/%s", w.Repo.Name, w.WorkflowID)
+}
+
+func (w *ActionOrgRequiredWorkflow) LoadRepo(ctx context.Context) (err error) {
+	if w.Repo == nil {
+		w.Repo, err = repo_model.GetRepositoryByID(ctx, w.RepoID)
+	}
+	return err
+}
+
+type OrgRequiredWorkflowList []*ActionOrgRequiredWorkflow
+
+func (workflows OrgRequiredWorkflowList) LoadRepos(ctx context.Context) error {
+	repoIDs := make([]int64, 0, len(workflows))
+	for _, w := range workflows {
+		repoIDs = append(repoIDs, w.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		return err
+	}
+	for _, w := range workflows {
+		w.Repo = repos[w.RepoID]
+	}
+	return nil
+}
+
+type FindOrgRequiredWorkflowOptions struct {
+	db.ListOptions
+	OrgID int64
+}
+
+func (opts FindOrgRequiredWorkflowOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OrgID > 0 {
+		cond = cond.And(builder.Eq{"org_id": opts.OrgID})
+	}
+	return cond
+}
+
+func (opts FindOrgRequiredWorkflowOptions) ToOrders() string {
+	return "`id` ASC"
+}
+
+// GetOrgRequiredWorkflowsForRepo returns the required workflows of the organization which run in the repository,
+// the workflows whose repository has been deleted or transferred to another owner are ignored
+func GetOrgRequiredWorkflowsForRepo(ctx context.Context, repo *repo_model.Repository) (OrgRequiredWorkflowList, error) {
+	workflows, err := db.Find[ActionOrgRequiredWorkflow](ctx, FindOrgRequiredWorkflowOptions{OrgID: repo.OwnerID})
+	if err != nil {
+		return nil, err
+	}
+	workflows = slices.DeleteFunc(workflows, func(w *ActionOrgRequiredWorkflow) bool {
+		return !w.AppliesTo(repo)
+	})
+	if err := OrgRequiredWorkflowList(workflows).LoadRepos(ctx); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(workflows, func(w *ActionOrgRequiredWorkflow) bool {
+		return w.Repo == nil || w.Repo.OwnerID != w.OrgID
+	}), nil
+}
+
+func GetOrgRequiredWorkflowByID(ctx context.Context, orgID, id int64) (*ActionOrgRequiredWorkflow, error) {
+	w := &ActionOrgRequiredWorkflow{}
+	has, err := db.GetEngine(ctx).Where("id=? AND org_id=?", id, orgID).Get(w)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("required workflow with id %d: %w", id, util.ErrNotExist)
+	}
+	return w, nil
+}
+
+func DeleteOrgRequiredWorkflow(ctx context.Context, orgID, id int64) error {
+	n, err := db.GetEngine(ctx).Where("id=? AND org_id=?", id, orgID).Delete(new(ActionOrgRequiredWorkflow))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("required workflow with id %d: %w", id, util.ErrNotExist)
+	}
+	return nil
+}
+
+// GetOrgRequiredWorkflowStates returns the states of the required workflows of the organization which have run for the commit in the repository,
+// the workflows which haven't run for the commit aren't required since their events don't match.
+func GetOrgRequiredWorkflowStates(ctx context.Context, repo *repo_model.Repository, commitSHA string) ([]*RequiredWorkflowState, error) {
+	workflows, err := GetOrgRequiredWorkflowsForRepo(ctx, repo)
+	if err != nil || len(workflows) == 0 {
+		return nil, err
+	}
+	workflowIDs := make([]string, 0, len(workflows))
+	for _, w := range workflows {
+		workflowIDs = append(workflowIDs, w.RunWorkflowID())
+	}
+	states, err := GetRequiredWorkflowStates(ctx, repo.ID, commitSHA, workflowIDs)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(states, func(state *RequiredWorkflowState) bool {
+		return state.Run == nil
+	}), nil
+}
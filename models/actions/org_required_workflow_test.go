@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionOrgRequiredWorkflow_AppliesTo(t *testing.T) {
+	all := &ActionOrgRequiredWorkflow{OrgID: 3, RepoID: 32}
+	selected := &ActionOrgRequiredWorkflow{OrgID: 3, RepoID: 32, RepoIDs: []int64{5}}
+
+	assert.True(t, all.AppliesTo(&repo_model.Repository{ID: 3, OwnerID: 3}))
+	assert.True(t, all.AppliesTo(&repo_model.Repository{ID: 5, OwnerID: 3}))
+	assert.False(t, all.AppliesTo(&repo_model.Repository{ID: 32, OwnerID: 3}), "the repository of the workflow")
+	assert.False(t, all.AppliesTo(&repo_model.Repository{ID: 1, OwnerID: 2}), "the repository of another owner")
+
+	assert.False(t, selected.AppliesTo(&repo_model.Repository{ID: 3, OwnerID: 3}))
+	assert.True(t, selected.AppliesTo(&repo_model.Repository{ID: 5, OwnerID: 3}))
+}
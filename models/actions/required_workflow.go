@@ -0,0 +1,96 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// RequiredWorkflowJobSeparator separates the workflow and the job of a required workflow, like "ci.yml:build"
+const RequiredWorkflowJobSeparator = ":"
+
+// ParseRequiredWorkflow parses a required workflow like "ci.yml" or "ci.yml:build",
+// the job id is empty if all jobs of the workflow are required
+func ParseRequiredWorkflow(s string) (workflowID, jobID string) {
+	workflowID, jobID, _ = strings.Cut(strings.TrimSpace(s), RequiredWorkflowJobSeparator)
+	return strings.TrimSpace(workflowID), strings.TrimSpace(jobID)
+}
+
+// IsValidRequiredWorkflow returns whether the required workflow refers to a workflow file, like "ci.yml" or "ci.yml:build"
+func IsValidRequiredWorkflow(s string) bool {
+	workflowID, _ := ParseRequiredWorkflow(s)
+	if workflowID == "" || strings.ContainsAny(workflowID, "/\\") {
+		return false
+	}
+	return strings.HasSuffix(workflowID, ".yml") || strings.HasSuffix(workflowID, ".yaml")
+}
+
+// RequiredWorkflowState is the state of a required workflow for a commit
+type RequiredWorkflowState struct {
+	RequiredWorkflow string
+	// Run is the latest run of the workflow for the commit, it's nil if there is no run
+	Run   *ActionRun
+	State api.CommitStatusState
+}
+
+// GetRequiredWorkflowStates returns the states of the required workflows for the commit.
+// The state of a required workflow is the worst state of the jobs in the latest run of the workflow for the commit,
+// it's pending if the workflow or the job hasn't run for the commit.
+func GetRequiredWorkflowStates(ctx context.Context, repoID int64, commitSHA string, requiredWorkflows []string) ([]*RequiredWorkflowState, error) {
+	states := make([]*RequiredWorkflowState, 0, len(requiredWorkflows))
+	for _, required := range requiredWorkflows {
+		workflowID, jobID := ParseRequiredWorkflow(required)
+		if workflowID == "" {
+			continue
+		}
+		state := &RequiredWorkflowState{RequiredWorkflow: required, State: api.CommitStatusPending}
+		states = append(states, state)
+
+		run := &ActionRun{}
+		has, err := db.GetEngine(ctx).Where("repo_id=? AND workflow_id=? AND commit_sha=?", repoID, workflowID, commitSHA).
+			Desc("id").Get(run)
+		if err != nil {
+			return nil, err
+		} else if !has {
+			continue
+		}
+		state.Run = run
+
+		jobs, err := GetRunJobsByRunID(ctx, run.ID)
+		if err != nil {
+			return nil, err
+		}
+		var matched bool
+		for _, job := range jobs {
+			// the jobs called by a job with a reusable workflow are required by the id of the caller job
+			if jobID != "" && job.JobID != jobID && !strings.HasPrefix(job.JobID, jobID+ReusableWorkflowJobSeparator) {
+				continue
+			}
+			jobState := job.Status.AsCommitStatusState()
+			if !matched || jobState.NoBetterThan(state.State) {
+				state.State = jobState
+			}
+			matched = true
+		}
+		if !matched {
+			state.State = api.CommitStatusPending
+		}
+	}
+	return states, nil
+}
+
+// MergeRequiredWorkflowStates returns the worst state of the required workflows, it's success if there is no required workflow
+func MergeRequiredWorkflowStates(states []*RequiredWorkflowState) api.CommitStatusState {
+	res := api.CommitStatusSuccess
+	for _, state := range states {
+		if state.State.NoBetterThan(res) {
+			res = state.State
+		}
+	}
+	return res
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidRequiredWorkflow(t *testing.T) {
+	for _, s := range []string{"ci.yml", "ci.yaml", " ci.yml ", "ci.yml:build", "ci.yml : build"} {
+		assert.True(t, IsValidRequiredWorkflow(s), s)
+	}
+	for _, s := range []string{"", "ci", "ci.json", ":build", ".gitea/workflows/ci.yml", "ci.yml/build"} {
+		assert.False(t, IsValidRequiredWorkflow(s), s)
+	}
+}
+
+func TestGetRequiredWorkflowStates(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	const sha = "c2d72f548424103f01ee1dc02889c1e2bff816b0"
+	states, err := GetRequiredWorkflowStates(db.DefaultContext, 4, sha, []string{
+		"artifact.yaml",
+		"artifact.yaml:job_2",
+		"artifact.yaml:job_1",
+		"ci.yml",
+	})
+	require.NoError(t, err)
+	require.Len(t, states, 4)
+
+	// the latest run of the workflow for the commit is used
+	assert.EqualValues(t, 792, states[0].Run.ID)
+	assert.Equal(t, api.CommitStatusSuccess, states[0].State)
+	assert.Equal(t, api.CommitStatusSuccess, states[1].State)
+	// the job hasn't run
+	assert.EqualValues(t, 792, states[2].Run.ID)
+	assert.Equal(t, api.CommitStatusPending, states[2].State)
+	// the workflow hasn't run
+	assert.Nil(t, states[3].Run)
+	assert.Equal(t, api.CommitStatusPending, states[3].State)
+
+	assert.Equal(t, api.CommitStatusPending, MergeRequiredWorkflowStates(states))
+	assert.Equal(t, api.CommitStatusSuccess, MergeRequiredWorkflowStates(states[:2]))
+	assert.Equal(t, api.CommitStatusSuccess, MergeRequiredWorkflowStates(nil))
+}
@@ -44,17 +44,36 @@ type ActionRun struct {
 	Event             webhook_module.HookEventType // the webhook event that causes the workflow to run
 	EventPayload      string                       `xorm:"LONGTEXT"`
 	TriggerEvent      string                       // the trigger event defined in the `on` configuration of the triggered workflow
+	ConcurrencyGroup  string                       `xorm:"index"` // the evaluated workflow-level concurrency group, empty if not set
+	ConcurrencyCancel bool                         // whether to cancel the in-progress runs of the concurrency group
 	Status            Status                       `xorm:"index"`
+	CancelReason      RunCancelReason              `xorm:"VARCHAR(64)"`       // why the run was cancelled automatically, empty if it wasn't
 	Version           int                          `xorm:"version default 0"` // Status could be updated concomitantly, so an optimistic lock is needed
 	// Started and Stopped is used for recording last run time, if rerun happened, they will be reset to 0
 	Started timeutil.TimeStamp
 	Stopped timeutil.TimeStamp
 	// PreviousDuration is used for recording previous duration
 	PreviousDuration time.Duration
-	Created          timeutil.TimeStamp `xorm:"created"`
-	Updated          timeutil.TimeStamp `xorm:"updated"`
+	// Attempt is the number of the latest attempt of the run, it starts from 1 and increases every time the run is rerun,
+	// the tasks and the artifacts of the previous attempts are kept
+	Attempt int64 `xorm:"NOT NULL DEFAULT 1"`
+	// CompletionNotified is the stopped time of the last attempt whose completion has been notified,
+	// so the completion of every attempt triggers the workflow_run workflows only once
+	CompletionNotified timeutil.TimeStamp `xorm:"DEFAULT 0"`
+	// StartNotified is the started time of the last attempt whose start has been notified
+	StartNotified timeutil.TimeStamp `xorm:"DEFAULT 0"`
+	Created       timeutil.TimeStamp `xorm:"created"`
+	Updated       timeutil.TimeStamp `xorm:"updated"`
 }
 
+// RunCancelReason is the reason why a run is cancelled automatically
+type RunCancelReason string
+
+const (
+	RunCancelReasonBranchDeleted     RunCancelReason = "branch_deleted"
+	RunCancelReasonPullRequestClosed RunCancelReason = "pull_request_closed"
+)
+
 func init() {
 	db.RegisterModel(new(ActionRun))
 	db.RegisterModel(new(ActionRunIndex))
@@ -74,6 +93,16 @@ func (run *ActionRun) Link() string {
 	return fmt.Sprintf("%s/actions/runs/%d", run.Repo.Link(), run.Index)
 }
 
+// WorkflowName returns the name of the workflow of the run, it's the file name of the workflow if the name isn't set
+func (run *ActionRun) WorkflowName(jobs []*ActionRunJob) string {
+	for _, job := range jobs {
+		if name := job.WorkflowName(); name != "" {
+			return name
+		}
+	}
+	return run.WorkflowID
+}
+
 func (run *ActionRun) WorkflowLink() string {
 	if run.Repo == nil {
 		return ""
@@ -221,42 +250,51 @@ func CancelPreviousJobs(ctx context.Context, repoID int64, ref, workflowID strin
 			return err
 		}
 
-		// Iterate over each job and attempt to cancel it.
-		for _, job := range jobs {
-			// Skip jobs that are already in a terminal state (completed, cancelled, etc.).
-			status := job.Status
-			if status.IsDone() {
-				continue
-			}
+		if err := CancelJobs(ctx, jobs); err != nil {
+			return err
+		}
+	}
 
-			// If the job has no associated task (probably an error), set its status to 'Cancelled' and stop it.
-			if job.TaskID == 0 {
-				job.Status = StatusCancelled
-				job.Stopped = timeutil.TimeStampNow()
+	// Return nil to indicate successful cancellation of all running and waiting jobs.
+	return nil
+}
 
-				// Update the job's status and stopped time in the database.
-				n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
-				if err != nil {
-					return err
-				}
+// CancelJobs cancels the jobs which are not done yet
+func CancelJobs(ctx context.Context, jobs []*ActionRunJob) error {
+	// Iterate over each job and attempt to cancel it.
+	for _, job := range jobs {
+		// Skip jobs that are already in a terminal state (completed, cancelled, etc.).
+		status := job.Status
+		if status.IsDone() {
+			continue
+		}
 
-				// If the update affected 0 rows, it means the job has changed in the meantime, so we need to try again.
-				if n == 0 {
-					return fmt.Errorf("job has changed, try again")
-				}
+		// If the job has no associated task (probably an error), set its status to 'Cancelled' and stop it.
+		if job.TaskID == 0 {
+			job.Status = StatusCancelled
+			job.Stopped = timeutil.TimeStampNow()
 
-				// Continue with the next job.
-				continue
+			// Update the job's status and stopped time in the database.
+			n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
+			if err != nil {
+				return err
 			}
 
-			// If the job has an associated task, try to stop the task, effectively cancelling the job.
-			if err := StopTask(ctx, job.TaskID, StatusCancelled); err != nil {
-				return err
+			// If the update affected 0 rows, it means the job has changed in the meantime, so we need to try again.
+			if n == 0 {
+				return fmt.Errorf("job has changed, try again")
 			}
+
+			// Continue with the next job.
+			continue
+		}
+
+		// If the job has an associated task, try to stop the task, effectively cancelling the job.
+		if err := StopTask(ctx, job.TaskID, StatusCancelled); err != nil {
+			return err
 		}
 	}
 
-	// Return nil to indicate successful cancellation of all running and waiting jobs.
 	return nil
 }
 
@@ -273,6 +311,7 @@ func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWork
 		return err
 	}
 	run.Index = index
+	run.Attempt = 1
 
 	if err := db.Insert(ctx, run); err != nil {
 		return err
@@ -300,7 +339,8 @@ func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWork
 		}
 		payload, _ := v.Marshal()
 		status := StatusWaiting
-		if len(needs) > 0 || run.NeedApproval {
+		// a blocked run is waiting for the other runs of its concurrency group
+		if len(needs) > 0 || run.NeedApproval || run.Status.IsBlocked() {
 			status = StatusBlocked
 		} else {
 			hasWaiting = true
@@ -374,11 +414,14 @@ func GetLatestRun(ctx context.Context, repoID int64) (*ActionRun, error) {
 	return run, nil
 }
 
-func GetWorkflowLatestRun(ctx context.Context, repoID int64, workflowFile, branch, event string) (*ActionRun, error) {
+// GetWorkflowLatestDoneRun returns the latest completed run of the workflow on the branch,
+// the in-progress runs are ignored so the result won't flap while a new run is going.
+func GetWorkflowLatestDoneRun(ctx context.Context, repoID int64, workflowFile, branch, event string) (*ActionRun, error) {
 	var run ActionRun
 	q := db.GetEngine(ctx).Where("repo_id=?", repoID).
 		And("ref = ?", branch).
-		And("workflow_id = ?", workflowFile)
+		And("workflow_id = ?", workflowFile).
+		In("status", StatusSuccess, StatusFailure, StatusCancelled, StatusSkipped)
 	if event != "" {
 		q.And("event = ?", event)
 	}
@@ -386,7 +429,7 @@ func GetWorkflowLatestRun(ctx context.Context, repoID int64, workflowFile, branc
 	if err != nil {
 		return nil, err
 	} else if !has {
-		return nil, util.NewNotExistErrorf("run with repo_id %d, ref %s, workflow_id %s", repoID, branch, workflowFile)
+		return nil, util.NewNotExistErrorf("done run with repo_id %d, ref %s, workflow_id %s", repoID, branch, workflowFile)
 	}
 	return &run, nil
 }
@@ -430,4 +473,103 @@ func UpdateRun(ctx context.Context, run *ActionRun, cols ...string) error {
 	return nil
 }
 
+// SetRunCompletionNotified marks the completion of the current attempt of the run as notified.
+// It returns false if the run isn't completed or the completion has been notified by others.
+func SetRunCompletionNotified(ctx context.Context, run *ActionRun) (bool, error) {
+	if !run.Status.IsDone() || run.Stopped.IsZero() {
+		return false, nil
+	}
+	affected, err := db.GetEngine(ctx).Table("action_run").
+		Where("id = ? AND completion_notified <> ?", run.ID, run.Stopped).
+		Update(map[string]any{"completion_notified": run.Stopped})
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	run.CompletionNotified = run.Stopped
+	return true, nil
+}
+
+// SetRunStartNotified marks the start of the current attempt of the run as notified.
+// It returns false if the run hasn't started or the start has been notified by others.
+func SetRunStartNotified(ctx context.Context, run *ActionRun) (bool, error) {
+	if run.Started.IsZero() {
+		return false, nil
+	}
+	affected, err := db.GetEngine(ctx).Table("action_run").
+		Where("id = ? AND start_notified <> ?", run.ID, run.Started).
+		Update(map[string]any{"start_notified": run.Started})
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	run.StartNotified = run.Started
+	return true, nil
+}
+
+// FindOldRunsToCleanup returns the finished runs of a repository which stopped before olderThan.
+// Only the runs whose ID is greater than afterID are returned, so the caller can page through the runs
+// even if some of them fail to be deleted.
+func FindOldRunsToCleanup(ctx context.Context, repoID int64, olderThan timeutil.TimeStamp, afterID int64, limit int) ([]*ActionRun, error) {
+	runs := make([]*ActionRun, 0, limit)
+	// Check "stopped > 0" to avoid deleting runs that are still running or have been rerun
+	return runs, db.GetEngine(ctx).
+		Where("repo_id = ? AND id > ? AND stopped > 0 AND stopped < ?", repoID, afterID, olderThan).
+		In("status", StatusSuccess, StatusFailure, StatusCancelled, StatusSkipped).
+		Asc("id").
+		Limit(limit).
+		Find(&runs)
+}
+
+// GetRepoIDsWithRuns returns the IDs of the repositories which have runs
+func GetRepoIDsWithRuns(ctx context.Context) ([]int64, error) {
+	repoIDs := make([]int64, 0, 10)
+	return repoIDs, db.GetEngine(ctx).Table("action_run").Distinct("repo_id").Find(&repoIDs)
+}
+
+// DeleteRun deletes a run and the records of its jobs, tasks, steps, outputs, artifacts, annotations and step summaries.
+// It doesn't remove the log files and the artifact files in storage, the caller should do it before.
+func DeleteRun(ctx context.Context, run *ActionRun) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+
+		jobIDs := builder.Select("id").From("action_run_job").Where(builder.Eq{"run_id": run.ID})
+		taskIDs := builder.Select("id").From("action_task").Where(builder.In("job_id", jobIDs))
+
+		if _, err := e.Where(builder.In("task_id", taskIDs)).Delete(new(ActionTaskStep)); err != nil {
+			return err
+		}
+		if _, err := e.Where(builder.In("task_id", taskIDs)).Delete(new(ActionTaskOutput)); err != nil {
+			return err
+		}
+		if _, err := e.Where(builder.In("job_id", jobIDs)).Delete(new(ActionTask)); err != nil {
+			return err
+		}
+		if _, err := e.Where("run_id = ?", run.ID).Delete(new(ActionRunJob)); err != nil {
+			return err
+		}
+		if _, err := e.Where("run_id = ?", run.ID).Delete(new(ActionArtifact)); err != nil {
+			return err
+		}
+		if _, err := e.Where("run_id = ?", run.ID).Delete(new(ActionAnnotation)); err != nil {
+			return err
+		}
+		if _, err := e.Where("run_id = ?", run.ID).Delete(new(ActionStepSummary)); err != nil {
+			return err
+		}
+		if _, err := e.ID(run.ID).NoAutoCondition().Delete(new(ActionRun)); err != nil {
+			return err
+		}
+
+		if err := run.LoadRepo(ctx); err != nil {
+			return err
+		}
+		return updateRepoRunsNumbers(ctx, run.Repo)
+	})
+}
+
 type ActionRunIndex db.ResourceIndex
@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+)
+
+// GetLatestTasksOfRunAttempt returns the latest tasks created in or before the attempt of the run, keyed by the job ids.
+// Like the reruns of GitHub, the jobs which haven't been rerun in an attempt are carried over from the previous attempts.
+func GetLatestTasksOfRunAttempt(ctx context.Context, jobIDs []int64, attempt int64) (map[int64]*ActionTask, error) {
+	tasks := make(map[int64]*ActionTask, len(jobIDs))
+	if len(jobIDs) == 0 {
+		return tasks, nil
+	}
+
+	var list []*ActionTask
+	if err := db.GetEngine(ctx).In("job_id", jobIDs).And("run_attempt <= ?", attempt).Asc("id").Find(&list); err != nil {
+		return nil, err
+	}
+	for _, task := range list {
+		tasks[task.JobID] = task
+	}
+	return tasks, nil
+}
+
+// GetRunAttemptJobs returns the jobs of the run with the states they had in the attempt, and the status of the attempt.
+// The jobs are returned as they are for the latest attempt, otherwise they are copies with the states of their tasks,
+// the jobs which didn't run in or before the attempt are treated as skipped.
+func GetRunAttemptJobs(ctx context.Context, run *ActionRun, jobs []*ActionRunJob, attempt int64) ([]*ActionRunJob, Status, error) {
+	if attempt >= run.Attempt {
+		return jobs, run.Status, nil
+	}
+
+	jobIDs := make([]int64, 0, len(jobs))
+	for _, job := range jobs {
+		jobIDs = append(jobIDs, job.ID)
+	}
+	tasks, err := GetLatestTasksOfRunAttempt(ctx, jobIDs, attempt)
+	if err != nil {
+		return nil, StatusUnknown, err
+	}
+
+	attemptJobs := make([]*ActionRunJob, 0, len(jobs))
+	for _, job := range jobs {
+		attemptJob := *job
+		if task, ok := tasks[job.ID]; ok {
+			attemptJob.TaskID = task.ID
+			attemptJob.Attempt = task.Attempt
+			attemptJob.Status = task.Status
+			attemptJob.Started = task.Started
+			attemptJob.Stopped = task.Stopped
+		} else if job.TaskID != 0 || !job.Status.IsDone() {
+			// the job has run in a later attempt, or it's waiting to run in the latest attempt
+			attemptJob.TaskID = 0
+			attemptJob.Attempt = 0
+			attemptJob.Status = StatusSkipped
+			attemptJob.Started = 0
+			attemptJob.Stopped = 0
+		}
+		attemptJobs = append(attemptJobs, &attemptJob)
+	}
+	return attemptJobs, aggregateJobStatus(attemptJobs), nil
+}
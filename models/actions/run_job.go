@@ -7,38 +7,50 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
+	"github.com/nektos/act/pkg/jobparser"
 	"xorm.io/builder"
 )
 
 // ActionRunJob represents a job of a run
 type ActionRunJob struct {
-	ID                int64
-	RunID             int64      `xorm:"index"`
-	Run               *ActionRun `xorm:"-"`
-	RepoID            int64      `xorm:"index"`
-	OwnerID           int64      `xorm:"index"`
-	CommitSHA         string     `xorm:"index"`
-	IsForkPullRequest bool
-	Name              string `xorm:"VARCHAR(255)"`
-	Attempt           int64
-	WorkflowPayload   []byte
-	JobID             string   `xorm:"VARCHAR(255)"` // job id in workflow, not job's id
-	Needs             []string `xorm:"JSON TEXT"`
-	RunsOn            []string `xorm:"JSON TEXT"`
-	TaskID            int64    // the latest task of the job
-	Status            Status   `xorm:"index"`
-	Started           timeutil.TimeStamp
-	Stopped           timeutil.TimeStamp
-	Created           timeutil.TimeStamp `xorm:"created"`
-	Updated           timeutil.TimeStamp `xorm:"updated index"`
+	ID                    int64
+	RunID                 int64      `xorm:"index"`
+	Run                   *ActionRun `xorm:"-"`
+	RepoID                int64      `xorm:"index"`
+	OwnerID               int64      `xorm:"index"`
+	CommitSHA             string     `xorm:"index"`
+	IsForkPullRequest     bool
+	Name                  string `xorm:"VARCHAR(255)"`
+	Attempt               int64
+	WorkflowPayload       []byte
+	JobID                 string   `xorm:"VARCHAR(255)"` // job id in workflow, not job's id
+	Needs                 []string `xorm:"JSON TEXT"`
+	RunsOn                []string `xorm:"JSON TEXT"`
+	TaskID                int64    // the latest task of the job
+	ConcurrencyGroup      string   `xorm:"index"` // the evaluated job-level concurrency group, empty if not set
+	ConcurrencyCancel     bool     // whether to cancel the in-progress jobs of the concurrency group
+	Environment           string   `xorm:"VARCHAR(255)"` // the name of the environment which the job targets
+	EnvironmentApprovedBy int64    // the user who approved the job to target the protected environment, it's reset when the job is rerun
+	TokenPermissions      string   `xorm:"TEXT"` // the evaluated `permissions` of the token of the job in JSON, empty means the default permissions
+	Status                Status   `xorm:"index"`
+	NotifiedStatus        Status   `xorm:"DEFAULT 0"` // the last status of the job which has been notified
+	Started               timeutil.TimeStamp
+	Stopped               timeutil.TimeStamp
+	Created               timeutil.TimeStamp `xorm:"created"`
+	Updated               timeutil.TimeStamp `xorm:"updated index"`
 }
 
+// ReusableWorkflowJobSeparator separates the job ids of the caller job and the called job in the job id
+// of a job from a reusable workflow, it can't be a part of a job id in workflows.
+const ReusableWorkflowJobSeparator = "/"
+
 func init() {
 	db.RegisterModel(new(ActionRunJob))
 }
@@ -47,6 +59,22 @@ func (job *ActionRunJob) Duration() time.Duration {
 	return calculateDuration(job.Started, job.Stopped, job.Status)
 }
 
+// WorkflowName returns the name of the workflow which the job belongs to, it's empty if the workflow has no name
+func (job *ActionRunJob) WorkflowName() string {
+	if wfs, err := jobparser.Parse(job.WorkflowPayload); err == nil && len(wfs) == 1 {
+		return wfs[0].Name
+	}
+	return ""
+}
+
+// CallerJobID returns the job id of the caller job if the job is from a reusable workflow, otherwise it returns empty
+func (job *ActionRunJob) CallerJobID() string {
+	if idx := strings.LastIndex(job.JobID, ReusableWorkflowJobSeparator); idx >= 0 {
+		return job.JobID[:idx]
+	}
+	return ""
+}
+
 func (job *ActionRunJob) LoadRun(ctx context.Context) error {
 	if job.Run == nil {
 		run, err := GetRunByID(ctx, job.RunID)
@@ -152,10 +180,37 @@ func UpdateRunJob(ctx context.Context, job *ActionRunJob, cond builder.Cond, col
 	return affected, nil
 }
 
+// SetRunJobStatusNotified marks the current status of the job as notified.
+// It returns false if the status has been notified by others.
+func SetRunJobStatusNotified(ctx context.Context, job *ActionRunJob) (bool, error) {
+	affected, err := db.GetEngine(ctx).Table("action_run_job").
+		Where("id = ? AND notified_status <> ?", job.ID, job.Status).
+		Update(map[string]any{"notified_status": job.Status})
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	job.NotifiedStatus = job.Status
+	return true, nil
+}
+
+// SetRunJobAttributes sets the attributes of a newly inserted job which are evaluated by Gitea itself.
+// A waiting job with a concurrency group or an environment will be blocked, the job emitter decides when it can start according to them.
+func SetRunJobAttributes(ctx context.Context, job *ActionRunJob) error {
+	if job.Status.IsWaiting() && (job.ConcurrencyGroup != "" || job.Environment != "") {
+		job.Status = StatusBlocked
+	}
+	_, err := db.GetEngine(ctx).ID(job.ID).Cols("concurrency_group", "concurrency_cancel", "environment", "token_permissions", "status").Update(job)
+	return err
+}
+
 func aggregateJobStatus(jobs []*ActionRunJob) Status {
 	allDone := true
 	allWaiting := true
 	hasFailure := false
+	hasCancelled := false
 	for _, job := range jobs {
 		if !job.Status.IsDone() {
 			allDone = false
@@ -163,11 +218,18 @@ func aggregateJobStatus(jobs []*ActionRunJob) Status {
 		if job.Status != StatusWaiting && !job.Status.IsDone() {
 			allWaiting = false
 		}
-		if job.Status == StatusFailure || job.Status == StatusCancelled {
+		switch job.Status {
+		case StatusFailure:
 			hasFailure = true
+		case StatusCancelled:
+			hasCancelled = true
 		}
 	}
 	if allDone {
+		// a run is cancelled if any of its jobs is cancelled, so a cancelled run isn't reported as failed
+		if hasCancelled {
+			return StatusCancelled
+		}
 		if hasFailure {
 			return StatusFailure
 		}
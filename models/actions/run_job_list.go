@@ -48,12 +48,13 @@ func (jobs ActionJobList) LoadAttributes(ctx context.Context, withRepo bool) err
 
 type FindRunJobOptions struct {
 	db.ListOptions
-	RunID         int64
-	RepoID        int64
-	OwnerID       int64
-	CommitSHA     string
-	Statuses      []Status
-	UpdatedBefore timeutil.TimeStamp
+	RunID            int64
+	RepoID           int64
+	OwnerID          int64
+	CommitSHA        string
+	ConcurrencyGroup string
+	Statuses         []Status
+	UpdatedBefore    timeutil.TimeStamp
 }
 
 func (opts FindRunJobOptions) ToConds() builder.Cond {
@@ -70,6 +71,9 @@ func (opts FindRunJobOptions) ToConds() builder.Cond {
 	if opts.CommitSHA != "" {
 		cond = cond.And(builder.Eq{"commit_sha": opts.CommitSHA})
 	}
+	if opts.ConcurrencyGroup != "" {
+		cond = cond.And(builder.Eq{"concurrency_group": opts.ConcurrencyGroup})
+	}
 	if len(opts.Statuses) > 0 {
 		cond = cond.And(builder.In("status", opts.Statuses))
 	}
@@ -0,0 +1,33 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateJobStatus(t *testing.T) {
+	testCases := []struct {
+		statuses []Status
+		expected Status
+	}{
+		{[]Status{StatusWaiting, StatusWaiting}, StatusWaiting},
+		{[]Status{StatusSuccess, StatusWaiting}, StatusWaiting},
+		{[]Status{StatusSuccess, StatusRunning}, StatusRunning},
+		{[]Status{StatusSuccess, StatusSkipped}, StatusSuccess},
+		{[]Status{StatusSuccess, StatusFailure}, StatusFailure},
+		{[]Status{StatusCancelled, StatusCancelled}, StatusCancelled},
+		{[]Status{StatusFailure, StatusCancelled}, StatusCancelled},
+		{[]Status{StatusCancelled, StatusRunning}, StatusRunning},
+	}
+	for _, tc := range testCases {
+		jobs := make([]*ActionRunJob, 0, len(tc.statuses))
+		for _, status := range tc.statuses {
+			jobs = append(jobs, &ActionRunJob{Status: status})
+		}
+		assert.Equal(t, tc.expected, aggregateJobStatus(jobs), "statuses: %v", tc.statuses)
+	}
+}
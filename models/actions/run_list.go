@@ -8,8 +8,10 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/timeutil"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 
 	"xorm.io/builder"
@@ -63,14 +65,18 @@ func (runs RunList) LoadRepos(ctx context.Context) error {
 
 type FindRunOptions struct {
 	db.ListOptions
-	RepoID        int64
-	OwnerID       int64
-	WorkflowID    string
-	Ref           string // the commit/tag/… that caused this workflow
-	TriggerUserID int64
-	TriggerEvent  webhook_module.HookEventType
-	Approved      bool // not util.OptionalBool, it works only when it's true
-	Status        []Status
+	RepoID           int64
+	OwnerID          int64
+	WorkflowID       string
+	Ref              string // the commit/tag/… that caused this workflow
+	TriggerUserID    int64
+	TriggerEvent     webhook_module.HookEventType
+	Approved         bool // not util.OptionalBool, it works only when it's true
+	ConcurrencyGroup string
+	Status           []Status
+	RepoCond         builder.Cond       // the condition of the repositories of the runs, it works with the column "repo_id"
+	CreatedAfter     timeutil.TimeStamp // inclusive
+	CreatedBefore    timeutil.TimeStamp // exclusive
 }
 
 func (opts FindRunOptions) ToConds() builder.Cond {
@@ -99,6 +105,18 @@ func (opts FindRunOptions) ToConds() builder.Cond {
 	if opts.TriggerEvent != "" {
 		cond = cond.And(builder.Eq{"trigger_event": opts.TriggerEvent})
 	}
+	if opts.ConcurrencyGroup != "" {
+		cond = cond.And(builder.Eq{"concurrency_group": opts.ConcurrencyGroup})
+	}
+	if opts.RepoCond != nil {
+		cond = cond.And(opts.RepoCond)
+	}
+	if opts.CreatedAfter > 0 {
+		cond = cond.And(builder.Gte{"created": opts.CreatedAfter})
+	}
+	if opts.CreatedBefore > 0 {
+		cond = cond.And(builder.Lt{"created": opts.CreatedBefore})
+	}
 	return cond
 }
 
@@ -114,8 +132,8 @@ type StatusInfo struct {
 // GetStatusInfoList returns a slice of StatusInfo
 func GetStatusInfoList(ctx context.Context) []StatusInfo {
 	// same as those in aggregateJobStatus
-	allStatus := []Status{StatusSuccess, StatusFailure, StatusWaiting, StatusRunning}
-	statusInfoList := make([]StatusInfo, 0, 4)
+	allStatus := []Status{StatusSuccess, StatusFailure, StatusCancelled, StatusWaiting, StatusRunning}
+	statusInfoList := make([]StatusInfo, 0, len(allStatus))
 	for _, s := range allStatus {
 		statusInfoList = append(statusInfoList, StatusInfo{
 			Status:          int(s),
@@ -125,6 +143,30 @@ func GetStatusInfoList(ctx context.Context) []StatusInfo {
 	return statusInfoList
 }
 
+// actionsAccessibleRepoCond returns the condition of the repositories of the owner which have enabled actions and whose runs the doer can read
+func actionsAccessibleRepoCond(ownerID int64, doer *user_model.User) builder.Cond {
+	cond := builder.NewCond().And(
+		builder.Eq{"`repository`.owner_id": ownerID},
+		builder.In("`repository`.id", builder.Select("repo_id").From("repo_unit").Where(builder.Eq{"type": unit.TypeActions})),
+	)
+	if doer == nil || !doer.IsAdmin {
+		cond = cond.And(repo_model.AccessibleRepositoryCondition(doer, unit.TypeActions))
+	}
+	return cond
+}
+
+// ActionsAccessibleRepoCond returns the condition of the runs of the repositories of the owner which have enabled actions
+// and whose runs the doer can read, it works with the column "repo_id"
+func ActionsAccessibleRepoCond(ownerID int64, doer *user_model.User) builder.Cond {
+	return builder.In("repo_id", builder.Select("`repository`.id").From("repository").Where(actionsAccessibleRepoCond(ownerID, doer)))
+}
+
+// FindActionsAccessibleRepos returns the repositories of the owner which have enabled actions and whose runs the doer can read
+func FindActionsAccessibleRepos(ctx context.Context, ownerID int64, doer *user_model.User) (repo_model.RepositoryList, error) {
+	repos := make(repo_model.RepositoryList, 0, 10)
+	return repos, db.GetEngine(ctx).Where(actionsAccessibleRepoCond(ownerID, doer)).OrderBy("lower_name").Find(&repos)
+}
+
 // GetActors returns a slice of Actors
 func GetActors(ctx context.Context, repoID int64) ([]*user_model.User, error) {
 	actors := make([]*user_model.User, 0, 10)
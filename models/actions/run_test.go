@@ -0,0 +1,61 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindOldRunsToCleanup(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	runs, err := FindOldRunsToCleanup(db.DefaultContext, 4, timeutil.TimeStampNow(), 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+
+	runs, err = FindOldRunsToCleanup(db.DefaultContext, 4, timeutil.TimeStampNow(), runs[0].ID, 10)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 1)
+
+	runs, err = FindOldRunsToCleanup(db.DefaultContext, 4, timeutil.TimeStamp(1683636626), 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestDeleteRun(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, db.Insert(db.DefaultContext, []*ActionAnnotation{
+		{RepoID: 4, RunID: 791, JobID: 192, TaskID: 47, Level: AnnotationLevelError, Message: "build failed"},
+		{RepoID: 4, RunID: 792, JobID: 193, TaskID: 48, Level: AnnotationLevelWarning, Message: "deprecated"},
+	}))
+	assert.NoError(t, db.Insert(db.DefaultContext, []*ActionStepSummary{
+		{RepoID: 4, RunID: 791, JobID: 192, TaskID: 47, StepIndex: 0, Content: "# Coverage"},
+		{RepoID: 4, RunID: 792, JobID: 193, TaskID: 48, StepIndex: 0, Content: "# Report"},
+	}))
+
+	run := unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 791})
+	assert.NoError(t, DeleteRun(db.DefaultContext, run))
+
+	unittest.AssertNotExistsBean(t, &ActionRun{ID: 791})
+	unittest.AssertNotExistsBean(t, &ActionRunJob{ID: 192})
+	unittest.AssertNotExistsBean(t, &ActionTask{ID: 47})
+	unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 792})
+	unittest.AssertExistsAndLoadBean(t, &ActionTask{ID: 48})
+	unittest.AssertNotExistsBean(t, &ActionAnnotation{RunID: 791})
+	unittest.AssertCount(t, &ActionAnnotation{RunID: 792}, 1)
+	unittest.AssertNotExistsBean(t, &ActionStepSummary{RunID: 791})
+	unittest.AssertCount(t, &ActionStepSummary{RunID: 792}, 1)
+
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: 792})
+	assert.NoError(t, DeleteRun(db.DefaultContext, run))
+	unittest.AssertCount(t, &ActionAnnotation{}, 0)
+	unittest.AssertCount(t, &ActionStepSummary{}, 0)
+}
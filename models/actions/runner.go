@@ -14,11 +14,13 @@ import (
 	"code.gitea.io/gitea/models/shared/types"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/translation"
 	"code.gitea.io/gitea/modules/util"
 
 	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/hashicorp/go-version"
 	"xorm.io/builder"
 )
 
@@ -57,6 +59,8 @@ type ActionRunner struct {
 
 	// Store labels defined in state file (default: .runner file) of `act_runner`
 	AgentLabels []string `xorm:"TEXT"`
+	// Ephemeral runners can only pick up one task, and they will be deleted when the task is done
+	Ephemeral bool `xorm:"NOT NULL DEFAULT false"`
 
 	Created timeutil.TimeStamp `xorm:"created"`
 	Updated timeutil.TimeStamp `xorm:"updated"`
@@ -105,7 +109,11 @@ func (r *ActionRunner) Status() runnerv1.RunnerStatus {
 }
 
 func (r *ActionRunner) StatusName() string {
-	return strings.ToLower(strings.TrimPrefix(r.Status().String(), "RUNNER_STATUS_"))
+	return runnerStatusName(r.Status())
+}
+
+func runnerStatusName(status runnerv1.RunnerStatus) string {
+	return strings.ToLower(strings.TrimPrefix(status.String(), "RUNNER_STATUS_"))
 }
 
 func (r *ActionRunner) StatusLocaleName(lang translation.Locale) string {
@@ -120,6 +128,33 @@ func (r *ActionRunner) IsOnline() bool {
 	return false
 }
 
+// IsOutdated returns whether the version of the runner is below the minimum runner version
+func (r *ActionRunner) IsOutdated() bool {
+	return isRunnerVersionBelow(r.Version, setting.Actions.MinRunnerVersion)
+}
+
+// IsUpdateRecommended returns whether the version of the runner is below the recommended runner version
+func (r *ActionRunner) IsUpdateRecommended() bool {
+	return isRunnerVersionBelow(r.Version, setting.Actions.RecommendedRunnerVersion)
+}
+
+// isRunnerVersionBelow compares the core versions, so the development builds like "v0.2.11-5-gabcdef" aren't below "v0.2.11".
+// The versions which can't be parsed like "dev" are never below any version.
+func isRunnerVersionBelow(runnerVersion, target string) bool {
+	if runnerVersion == "" || target == "" {
+		return false
+	}
+	v, err := version.NewVersion(runnerVersion)
+	if err != nil {
+		return false
+	}
+	t, err := version.NewVersion(target)
+	if err != nil {
+		return false
+	}
+	return v.Core().LessThan(t.Core())
+}
+
 // Editable checks if the runner is editable by the user
 func (r *ActionRunner) Editable(ownerID, repoID int64) bool {
 	if ownerID == 0 && repoID == 0 {
@@ -0,0 +1,40 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionRunner_IsOutdated(t *testing.T) {
+	defer test.MockVariableValue(&setting.Actions.MinRunnerVersion, "v0.2.10")()
+	defer test.MockVariableValue(&setting.Actions.RecommendedRunnerVersion, "0.2.11")()
+
+	cases := []struct {
+		version           string
+		outdated          bool
+		updateRecommended bool
+	}{
+		{version: "v0.2.9", outdated: true, updateRecommended: true},
+		{version: "v0.2.10", outdated: false, updateRecommended: true},
+		{version: "v0.2.10-3-gabcdef", outdated: false, updateRecommended: true},
+		{version: "v0.2.11", outdated: false, updateRecommended: false},
+		{version: "v0.3.0", outdated: false, updateRecommended: false},
+		{version: "dev", outdated: false, updateRecommended: false},
+		{version: "", outdated: false, updateRecommended: false},
+	}
+	for _, c := range cases {
+		runner := &ActionRunner{Version: c.version}
+		assert.Equal(t, c.outdated, runner.IsOutdated(), c.version)
+		assert.Equal(t, c.updateRecommended, runner.IsUpdateRecommended(), c.version)
+	}
+
+	defer test.MockVariableValue(&setting.Actions.MinRunnerVersion, "")()
+	assert.False(t, (&ActionRunner{Version: "v0.1.0"}).IsOutdated())
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+)
+
+// Statistic contains the statistics of actions
+type Statistic struct {
+	JobsQueued  int64
+	JobsRunning int64
+	// RunnersByStatus is keyed by the status names of the runners
+	RunnersByStatus map[string]int64
+	// LogBytes is the size of the logs which haven't expired
+	LogBytes int64
+}
+
+// GetStatistic returns the statistics of actions
+func GetStatistic(ctx context.Context) (stats Statistic) {
+	e := db.GetEngine(ctx)
+	stats.JobsQueued, _ = e.Where("status=?", StatusWaiting).Count(new(ActionRunJob))
+	stats.JobsRunning, _ = e.Where("status=?", StatusRunning).Count(new(ActionRunJob))
+
+	offlineBefore := time.Now().Add(-RunnerOfflineTime).Unix()
+	idleBefore := time.Now().Add(-RunnerIdleTime).Unix()
+	stats.RunnersByStatus = make(map[string]int64, 3)
+	stats.RunnersByStatus[runnerStatusName(runnerv1.RunnerStatus_RUNNER_STATUS_OFFLINE)], _ = e.
+		Where("last_online<=?", offlineBefore).Count(new(ActionRunner))
+	stats.RunnersByStatus[runnerStatusName(runnerv1.RunnerStatus_RUNNER_STATUS_IDLE)], _ = e.
+		Where("last_online>? AND last_active<=?", offlineBefore, idleBefore).Count(new(ActionRunner))
+	stats.RunnersByStatus[runnerStatusName(runnerv1.RunnerStatus_RUNNER_STATUS_ACTIVE)], _ = e.
+		Where("last_online>? AND last_active>?", offlineBefore, idleBefore).Count(new(ActionRunner))
+
+	stats.LogBytes, _ = e.Where("log_expired=?", false).SumInt(new(ActionTask), "log_size")
+	return stats
+}
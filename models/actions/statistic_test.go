@@ -0,0 +1,54 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStatistic(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	stats := GetStatistic(db.DefaultContext)
+	assert.EqualValues(t, 0, stats.JobsQueued)
+	assert.EqualValues(t, 0, stats.JobsRunning)
+	assert.EqualValues(t, 90179*2, stats.LogBytes)
+	assert.Equal(t, map[string]int64{"offline": 0, "idle": 0, "active": 0}, stats.RunnersByStatus)
+
+	assert.NoError(t, db.Insert(db.DefaultContext, []*ActionRunJob{
+		{RunID: 791, RepoID: 4, Name: "queued", Status: StatusWaiting},
+		{RunID: 791, RepoID: 4, Name: "running", Status: StatusRunning},
+		{RunID: 791, RepoID: 4, Name: "blocked", Status: StatusBlocked},
+	}))
+	assert.NoError(t, db.Insert(db.DefaultContext, &ActionTask{JobID: 1, TokenHash: "statistic-expired", LogSize: 100, LogExpired: true}))
+	now := time.Now()
+	newRunner := func(name string, lastOnline, lastActive time.Time) *ActionRunner {
+		return &ActionRunner{
+			UUID:       name,
+			Name:       name,
+			TokenHash:  name,
+			LastOnline: timeutil.TimeStamp(lastOnline.Unix()),
+			LastActive: timeutil.TimeStamp(lastActive.Unix()),
+		}
+	}
+	assert.NoError(t, db.Insert(db.DefaultContext, []*ActionRunner{
+		newRunner("offline", now.Add(-time.Hour), now.Add(-time.Hour)),
+		newRunner("idle", now, now.Add(-time.Minute)),
+		newRunner("active-1", now, now),
+		newRunner("active-2", now, now),
+	}))
+
+	stats = GetStatistic(db.DefaultContext)
+	assert.EqualValues(t, 1, stats.JobsQueued)
+	assert.EqualValues(t, 1, stats.JobsRunning)
+	assert.EqualValues(t, 90179*2, stats.LogBytes)
+	assert.Equal(t, map[string]int64{"offline": 1, "idle": 1, "active": 2}, stats.RunnersByStatus)
+}
@@ -4,6 +4,7 @@
 package actions
 
 import (
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/translation"
 
 	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
@@ -39,6 +40,16 @@ func (s Status) String() string {
 	return statusNames[s]
 }
 
+// ParseStatus returns the Status with the given name, the second return value is false if the name is unknown
+func ParseStatus(name string) (Status, bool) {
+	for s, n := range statusNames {
+		if n == name {
+			return s, true
+		}
+	}
+	return StatusUnknown, false
+}
+
 // LocaleString returns the locale string name of the Status
 func (s Status) LocaleString(lang translation.Locale) string {
 	return lang.TrString("actions.status." + s.String())
@@ -102,3 +113,17 @@ func (s Status) AsResult() runnerv1.Result {
 	}
 	return runnerv1.Result_RESULT_UNSPECIFIED
 }
+
+// AsCommitStatusState returns the state of the commit status for the status
+func (s Status) AsCommitStatusState() api.CommitStatusState {
+	switch s {
+	case StatusSuccess, StatusSkipped:
+		return api.CommitStatusSuccess
+	case StatusFailure, StatusCancelled:
+		return api.CommitStatusFailure
+	case StatusWaiting, StatusBlocked, StatusRunning:
+		return api.CommitStatusPending
+	default:
+		return api.CommitStatusError
+	}
+}
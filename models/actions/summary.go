@@ -0,0 +1,71 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// MaxStepSummarySize is the max size of the summary of a step, the same as GitHub
+const MaxStepSummarySize = 1024 * 1024
+
+// ActionStepSummary is the Markdown written to the file $GITHUB_STEP_SUMMARY by a step of a task
+type ActionStepSummary struct {
+	ID        int64
+	RepoID    int64              `xorm:"index"`
+	RunID     int64              `xorm:"index"`
+	JobID     int64              `xorm:"index"`
+	TaskID    int64              `xorm:"index unique(task_step)"`
+	StepIndex int64              `xorm:"unique(task_step)"`
+	Content   string             `xorm:"LONGTEXT"`
+	Created   timeutil.TimeStamp `xorm:"created"`
+	Updated   timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionStepSummary))
+}
+
+type FindStepSummaryOptions struct {
+	db.ListOptions
+	RepoID  int64
+	TaskIDs []int64
+}
+
+func (opts FindStepSummaryOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.TaskIDs != nil {
+		cond = cond.And(builder.In("task_id", opts.TaskIDs))
+	}
+	return cond
+}
+
+func (opts FindStepSummaryOptions) ToOrders() string {
+	return "`task_id` ASC, `step_index` ASC"
+}
+
+// SetStepSummary creates the summary of the step of the task, or replaces its content if it exists
+func SetStepSummary(ctx context.Context, summary *ActionStepSummary) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing := &ActionStepSummary{}
+		has, err := db.GetEngine(ctx).Where("task_id=? AND step_index=?", summary.TaskID, summary.StepIndex).Get(existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return db.Insert(ctx, summary)
+		}
+		summary.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(summary.ID).Cols("content").Update(summary)
+		return err
+	})
+}
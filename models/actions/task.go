@@ -29,14 +29,18 @@ import (
 type ActionTask struct {
 	ID       int64
 	JobID    int64
-	Job      *ActionRunJob     `xorm:"-"`
-	Steps    []*ActionTaskStep `xorm:"-"`
+	Job      *ActionRunJob      `xorm:"-"`
+	Steps    []*ActionTaskStep  `xorm:"-"`
+	Queued   timeutil.TimeStamp `xorm:"-"` // when the job of the task was queued, it's only set when the task is created
 	Attempt  int64
 	RunnerID int64              `xorm:"index"`
 	Status   Status             `xorm:"index"`
 	Started  timeutil.TimeStamp `xorm:"index"`
 	Stopped  timeutil.TimeStamp `xorm:"index(stopped_log_expired)"`
 
+	// RunAttempt is the attempt of the run when the task is created, the tasks of the previous attempts are kept for the history
+	RunAttempt int64
+
 	RepoID            int64  `xorm:"index"`
 	OwnerID           int64  `xorm:"index"`
 	CommitSHA         string `xorm:"index"`
@@ -53,6 +57,12 @@ type ActionTask struct {
 	LogSize      int64      // blob size
 	LogIndexes   LogIndexes `xorm:"LONGBLOB"`                   // line number to offset
 	LogExpired   bool       `xorm:"index(stopped_log_expired)"` // files that are too old will be deleted
+	// LogInColdStorage is true if the log has been moved from the storage to the cold storage, LogInStorage is still true
+	LogInColdStorage bool `xorm:"NOT NULL DEFAULT false"`
+	// LogRehydrated is when the log was moved back from the cold storage, it isn't moved again until it's old enough since then
+	LogRehydrated timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	// LogMasks are the secret values to be masked in the logs, it's a JSON array encrypted with the secret key
+	LogMasks string `xorm:"LONGTEXT"`
 
 	Created timeutil.TimeStamp `xorm:"created"`
 	Updated timeutil.TimeStamp `xorm:"updated index"`
@@ -224,6 +234,15 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 
 	e := db.GetEngine(ctx)
 
+	if runner.Ephemeral {
+		// an ephemeral runner can only pick up one task
+		if has, err := e.Where("runner_id=?", runner.ID).Exist(&ActionTask{}); err != nil {
+			return nil, false, err
+		} else if has {
+			return nil, false, nil
+		}
+	}
+
 	jobCond := builder.NewCond()
 	if runner.RepoID != 0 {
 		jobCond = builder.Eq{"repo_id": runner.RepoID}
@@ -258,6 +277,8 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 	}
 
 	now := timeutil.TimeStampNow()
+	// the waiting jobs are picked in the order of their update time, which is the time they were queued
+	queued := job.Updated
 	job.Attempt++
 	job.Started = now
 	job.Status = StatusRunning
@@ -265,8 +286,10 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 	task := &ActionTask{
 		JobID:             job.ID,
 		Attempt:           job.Attempt,
+		RunAttempt:        job.Run.Attempt,
 		RunnerID:          runner.ID,
 		Started:           now,
+		Queued:            queued,
 		Status:            StatusRunning,
 		RepoID:            job.RepoID,
 		OwnerID:           job.OwnerID,
@@ -374,6 +397,9 @@ func UpdateTaskByState(ctx context.Context, state *runnerv1.TaskState) (*ActionT
 		if err := UpdateTask(ctx, task, "status", "stopped"); err != nil {
 			return nil, err
 		}
+		if err := addTaskUsage(ctx, task); err != nil {
+			return nil, err
+		}
 		if _, err := UpdateRunJob(ctx, &ActionRunJob{
 			ID:      task.JobID,
 			Status:  task.Status,
@@ -449,6 +475,9 @@ func StopTask(ctx context.Context, taskID int64, status Status) error {
 	if err := UpdateTask(ctx, task, "status", "stopped"); err != nil {
 		return err
 	}
+	if err := addTaskUsage(ctx, task); err != nil {
+		return err
+	}
 
 	if err := task.LoadAttributes(ctx); err != nil {
 		return err
@@ -480,6 +509,20 @@ func FindOldTasksToExpire(ctx context.Context, olderThan timeutil.TimeStamp, lim
 		Find(&tasks)
 }
 
+// FindOldTasksToMoveLogs returns the stopped tasks whose logs are in the storage and haven't been stopped or rehydrated since olderThan,
+// so their logs can be moved to the cold storage.
+// Only the tasks whose ID is greater than afterID are returned, so the caller can page through the tasks
+// even if the logs of some of them fail to be moved.
+func FindOldTasksToMoveLogs(ctx context.Context, olderThan timeutil.TimeStamp, afterID int64, limit int) ([]*ActionTask, error) {
+	tasks := make([]*ActionTask, 0, limit)
+	return tasks, db.GetEngine(ctx).
+		Where("id > ? AND stopped > 0 AND stopped < ? AND log_rehydrated < ?", afterID, olderThan, olderThan).
+		And(builder.Eq{"log_in_storage": true, "log_expired": false, "log_in_cold_storage": false}).
+		Asc("id").
+		Limit(limit).
+		Find(&tasks)
+}
+
 func isSubset(set, subset []string) bool {
 	m := make(container.Set[string], len(set))
 	for _, v := range set {
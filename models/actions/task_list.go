@@ -49,6 +49,7 @@ type FindTaskOptions struct {
 	db.ListOptions
 	RepoID        int64
 	OwnerID       int64
+	RunID         int64
 	CommitSHA     string
 	Status        Status
 	UpdatedBefore timeutil.TimeStamp
@@ -64,6 +65,9 @@ func (opts FindTaskOptions) ToConds() builder.Cond {
 	if opts.OwnerID > 0 {
 		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
 	}
+	if opts.RunID > 0 {
+		cond = cond.And(builder.In("job_id", builder.Select("id").From("action_run_job").Where(builder.Eq{"run_id": opts.RunID})))
+	}
 	if opts.CommitSHA != "" {
 		cond = cond.And(builder.Eq{"commit_sha": opts.CommitSHA})
 	}
@@ -0,0 +1,35 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindOldTasksToMoveLogs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	tasks, err := FindOldTasksToMoveLogs(db.DefaultContext, timeutil.TimeStampNow(), 0, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 2) {
+		assert.EqualValues(t, 47, tasks[0].ID)
+		assert.EqualValues(t, 48, tasks[1].ID)
+	}
+
+	tasks, err = FindOldTasksToMoveLogs(db.DefaultContext, timeutil.TimeStampNow(), 47, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 1) {
+		assert.EqualValues(t, 48, tasks[0].ID)
+	}
+
+	tasks, err = FindOldTasksToMoveLogs(db.DefaultContext, timeutil.TimeStamp(1683636626), 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, tasks)
+}
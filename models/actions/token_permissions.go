@@ -0,0 +1,131 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	perm_model "code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// TokenScope is a scope of the `permissions` of a workflow or a job, which decides what the token of the job can access.
+// See https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#permissions
+type TokenScope string
+
+const (
+	TokenScopeActions      TokenScope = "actions"
+	TokenScopeContents     TokenScope = "contents"
+	TokenScopeIssues       TokenScope = "issues"
+	TokenScopePackages     TokenScope = "packages"
+	TokenScopePullRequests TokenScope = "pull-requests"
+	TokenScopeProjects     TokenScope = "repository-projects"
+)
+
+// tokenScopeUnits are the units of a repository covered by the scopes, the other scopes of GitHub are ignored
+var tokenScopeUnits = map[TokenScope][]unit.Type{
+	TokenScopeActions:      {unit.TypeActions},
+	TokenScopeContents:     {unit.TypeCode, unit.TypeReleases, unit.TypeWiki},
+	TokenScopeIssues:       {unit.TypeIssues},
+	TokenScopePackages:     {unit.TypePackages},
+	TokenScopePullRequests: {unit.TypePullRequests},
+	TokenScopeProjects:     {unit.TypeProjects},
+}
+
+// IsValid returns whether the scope is supported
+func (s TokenScope) IsValid() bool {
+	_, ok := tokenScopeUnits[s]
+	return ok
+}
+
+// TokenPermissions are the access modes of the scopes granted to the token of a job, the missing scopes have no access
+type TokenPermissions map[TokenScope]perm_model.AccessMode
+
+// NewTokenPermissions returns the permissions granting the access mode to all scopes
+func NewTokenPermissions(mode perm_model.AccessMode) TokenPermissions {
+	p := make(TokenPermissions, len(tokenScopeUnits))
+	for scope := range tokenScopeUnits {
+		p[scope] = mode
+	}
+	return p
+}
+
+// DefaultTokenPermissions returns the permissions of the jobs which don't declare `permissions`, see [actions].DEFAULT_TOKEN_PERMISSIONS
+func DefaultTokenPermissions() TokenPermissions {
+	if setting.Actions.DefaultTokenPermissions == setting.ActionsTokenPermissionsPermissive {
+		return NewTokenPermissions(perm_model.AccessModeWrite)
+	}
+	return TokenPermissions{
+		TokenScopeContents: perm_model.AccessModeRead,
+		TokenScopePackages: perm_model.AccessModeRead,
+	}
+}
+
+// ClampTo returns the permissions which don't exceed the access mode
+func (p TokenPermissions) ClampTo(mode perm_model.AccessMode) TokenPermissions {
+	ret := make(TokenPermissions, len(p))
+	for scope, m := range p {
+		ret[scope] = min(m, mode)
+	}
+	return ret
+}
+
+// Intersect returns the permissions which exceed neither p nor other, the scopes missing in either of them have no access
+func (p TokenPermissions) Intersect(other TokenPermissions) TokenPermissions {
+	ret := make(TokenPermissions, len(p))
+	for scope, m := range p {
+		ret[scope] = min(m, other[scope])
+	}
+	return ret
+}
+
+// UnitAccessModes returns the access modes of the units of a repository granted by the permissions
+func (p TokenPermissions) UnitAccessModes() map[unit.Type]perm_model.AccessMode {
+	modes := make(map[unit.Type]perm_model.AccessMode)
+	for scope, m := range p {
+		for _, u := range tokenScopeUnits[scope] {
+			modes[u] = m
+		}
+	}
+	return modes
+}
+
+// ToDB exports the permissions to the serialized format of ActionRunJob.TokenPermissions
+func (p TokenPermissions) ToDB() string {
+	m := make(map[TokenScope]string, len(p))
+	for scope, mode := range p {
+		m[scope] = mode.ToString()
+	}
+	bs, _ := json.Marshal(m)
+	return string(bs)
+}
+
+// GetTokenPermissions returns the permissions of the token of the job, the token of a job triggered by a pull request from a fork can't write
+func (job *ActionRunJob) GetTokenPermissions() (TokenPermissions, error) {
+	p := DefaultTokenPermissions()
+	if job.TokenPermissions != "" {
+		m := map[TokenScope]string{}
+		if err := json.Unmarshal([]byte(job.TokenPermissions), &m); err != nil {
+			return nil, err
+		}
+		p = make(TokenPermissions, len(m))
+		for scope, mode := range m {
+			p[scope] = perm_model.ParseAccessMode(mode, perm_model.AccessModeRead, perm_model.AccessModeWrite)
+		}
+	}
+	if job.IsForkPullRequest {
+		p = p.ClampTo(perm_model.AccessModeRead)
+	}
+	return p, nil
+}
+
+// GetTaskTokenPermissions returns the permissions of the token of the task
+func GetTaskTokenPermissions(ctx context.Context, task *ActionTask) (TokenPermissions, error) {
+	if err := task.LoadJob(ctx); err != nil {
+		return nil, err
+	}
+	return task.Job.GetTokenPermissions()
+}
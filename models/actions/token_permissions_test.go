@@ -0,0 +1,60 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	perm_model "code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionRunJob_GetTokenPermissions(t *testing.T) {
+	// the jobs which don't declare `permissions` can write all scopes by default, like before the permissions are supported
+	job := &ActionRunJob{}
+	perms, err := job.GetTokenPermissions()
+	assert.NoError(t, err)
+	assert.Equal(t, NewTokenPermissions(perm_model.AccessModeWrite), perms)
+
+	defer test.MockVariableValue(&setting.Actions.DefaultTokenPermissions, setting.ActionsTokenPermissionsRestricted)()
+	perms, err = job.GetTokenPermissions()
+	assert.NoError(t, err)
+	assert.Equal(t, map[unit.Type]perm_model.AccessMode{
+		unit.TypeCode:     perm_model.AccessModeRead,
+		unit.TypeReleases: perm_model.AccessModeRead,
+		unit.TypeWiki:     perm_model.AccessModeRead,
+		unit.TypePackages: perm_model.AccessModeRead,
+	}, perms.UnitAccessModes())
+
+	job.TokenPermissions = TokenPermissions{TokenScopeIssues: perm_model.AccessModeWrite, TokenScopeContents: perm_model.AccessModeNone}.ToDB()
+	perms, err = job.GetTokenPermissions()
+	assert.NoError(t, err)
+	assert.Equal(t, TokenPermissions{TokenScopeIssues: perm_model.AccessModeWrite, TokenScopeContents: perm_model.AccessModeNone}, perms)
+
+	// the token of a job triggered by a pull request from a fork can't write
+	job.IsForkPullRequest = true
+	perms, err = job.GetTokenPermissions()
+	assert.NoError(t, err)
+	assert.Equal(t, perm_model.AccessModeRead, perms[TokenScopeIssues])
+}
+
+func TestTokenPermissions_Intersect(t *testing.T) {
+	p := TokenPermissions{
+		TokenScopeContents: perm_model.AccessModeWrite,
+		TokenScopeIssues:   perm_model.AccessModeRead,
+		TokenScopePackages: perm_model.AccessModeWrite,
+	}
+	assert.Equal(t, TokenPermissions{
+		TokenScopeContents: perm_model.AccessModeRead,
+		TokenScopeIssues:   perm_model.AccessModeRead,
+		TokenScopePackages: perm_model.AccessModeNone,
+	}, p.Intersect(TokenPermissions{
+		TokenScopeContents: perm_model.AccessModeRead,
+		TokenScopeIssues:   perm_model.AccessModeWrite,
+	}))
+}
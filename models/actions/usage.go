@@ -0,0 +1,119 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// UsageMonthLayout is the layout of the month of the usage, like "2024-10"
+const UsageMonthLayout = "2006-01"
+
+// ActionUsage is the execution duration of the jobs of a repository in a month
+type ActionUsage struct {
+	ID      int64  `xorm:"pk autoincr"`
+	OwnerID int64  `xorm:"UNIQUE(owner_repo_month) NOT NULL"`
+	RepoID  int64  `xorm:"INDEX UNIQUE(owner_repo_month) NOT NULL"`
+	Month   string `xorm:"INDEX UNIQUE(owner_repo_month) VARCHAR(7) NOT NULL"`
+	Jobs    int64  `xorm:"NOT NULL DEFAULT 0"`
+	// Duration is the total execution duration of the jobs in seconds
+	Duration int64 `xorm:"NOT NULL DEFAULT 0"`
+	// Minutes is the billable minutes, the duration of every job is rounded up to the nearest whole minute
+	Minutes     int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionUsage))
+}
+
+// UsageMonth returns the month of the usage which the time belongs to
+func UsageMonth(t time.Time) string {
+	return t.Format(UsageMonthLayout)
+}
+
+// addTaskUsage adds the execution duration of a stopped task to the usage of its repository
+func addTaskUsage(ctx context.Context, task *ActionTask) error {
+	if task.Stopped == 0 {
+		return nil
+	}
+	var duration int64
+	if task.Started > 0 && task.Stopped > task.Started {
+		duration = int64(task.Stopped - task.Started)
+	}
+	minutes := (duration + 59) / 60
+	month := UsageMonth(task.Stopped.AsLocalTime())
+
+	result, err := db.GetEngine(ctx).Exec("UPDATE action_usage SET jobs = jobs + 1, duration = duration + ?, minutes = minutes + ?, updated_unix = ? WHERE owner_id = ? AND repo_id = ? AND month = ?",
+		duration, minutes, timeutil.TimeStampNow(), task.OwnerID, task.RepoID, month)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected > 0 {
+		return nil
+	}
+
+	_, err = db.GetEngine(ctx).Insert(&ActionUsage{
+		OwnerID:  task.OwnerID,
+		RepoID:   task.RepoID,
+		Month:    month,
+		Jobs:     1,
+		Duration: duration,
+		Minutes:  minutes,
+	})
+	return err
+}
+
+type FindUsageOptions struct {
+	db.ListOptions
+	OwnerID int64
+	RepoID  int64
+	Month   string
+}
+
+func (opts FindUsageOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.Month != "" {
+		cond = cond.And(builder.Eq{"month": opts.Month})
+	}
+	return cond
+}
+
+func (opts FindUsageOptions) ToOrders() string {
+	return "minutes DESC, repo_id ASC"
+}
+
+// OwnerUsage is the sum of the usages of the repositories of an owner in a month
+type OwnerUsage struct {
+	OwnerID  int64
+	Jobs     int64
+	Duration int64
+	Minutes  int64
+}
+
+// SumUsageByOwner returns the usages of the owners in the month, the owners which consume more minutes come first
+func SumUsageByOwner(ctx context.Context, month string) ([]*OwnerUsage, error) {
+	usages := make([]*OwnerUsage, 0, 10)
+	return usages, db.GetEngine(ctx).Table("action_usage").
+		Select("owner_id, SUM(jobs) AS jobs, SUM(duration) AS duration, SUM(minutes) AS minutes").
+		Where(builder.Eq{"month": month}).
+		GroupBy("owner_id").
+		OrderBy("minutes DESC, owner_id ASC").
+		Find(&usages)
+}
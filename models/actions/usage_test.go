@@ -0,0 +1,59 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTaskUsage(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	stopped := time.Date(2024, 10, 15, 12, 0, 0, 0, time.Local)
+	newTask := func(ownerID, repoID int64, seconds int64) *ActionTask {
+		return &ActionTask{
+			OwnerID: ownerID,
+			RepoID:  repoID,
+			Started: timeutil.TimeStamp(stopped.Unix() - seconds),
+			Stopped: timeutil.TimeStamp(stopped.Unix()),
+		}
+	}
+
+	assert.NoError(t, addTaskUsage(db.DefaultContext, newTask(2, 4, 61)))
+	assert.NoError(t, addTaskUsage(db.DefaultContext, newTask(2, 4, 30)))
+	assert.NoError(t, addTaskUsage(db.DefaultContext, newTask(2, 1, 120)))
+	assert.NoError(t, addTaskUsage(db.DefaultContext, newTask(3, 3, 5)))
+	// the task which has never started is counted as a job without duration
+	assert.NoError(t, addTaskUsage(db.DefaultContext, &ActionTask{OwnerID: 3, RepoID: 3, Stopped: timeutil.TimeStamp(stopped.Unix())}))
+
+	usage := unittest.AssertExistsAndLoadBean(t, &ActionUsage{OwnerID: 2, RepoID: 4, Month: "2024-10"})
+	assert.EqualValues(t, 2, usage.Jobs)
+	assert.EqualValues(t, 91, usage.Duration)
+	assert.EqualValues(t, 3, usage.Minutes)
+
+	usages, err := db.Find[ActionUsage](db.DefaultContext, FindUsageOptions{OwnerID: 2, Month: "2024-10"})
+	assert.NoError(t, err)
+	if assert.Len(t, usages, 2) {
+		assert.EqualValues(t, 4, usages[0].RepoID)
+		assert.EqualValues(t, 1, usages[1].RepoID)
+	}
+
+	owners, err := SumUsageByOwner(db.DefaultContext, "2024-10")
+	assert.NoError(t, err)
+	assert.Equal(t, []*OwnerUsage{
+		{OwnerID: 2, Jobs: 3, Duration: 211, Minutes: 5},
+		{OwnerID: 3, Jobs: 2, Duration: 5, Minutes: 1},
+	}, owners)
+
+	owners, err = SumUsageByOwner(db.DefaultContext, "2024-09")
+	assert.NoError(t, err)
+	assert.Empty(t, owners)
+}
@@ -51,6 +51,7 @@ type ProtectedBranch struct {
 	ForcePushAllowlistDeployKeys  bool     `xorm:"NOT NULL DEFAULT false"`
 	EnableStatusCheck             bool     `xorm:"NOT NULL DEFAULT false"`
 	StatusCheckContexts           []string `xorm:"JSON TEXT"`
+	RequiredWorkflows             []string `xorm:"JSON TEXT"` // like "ci.yml" for all jobs of the workflow or "ci.yml:build" for a job
 	EnableApprovalsWhitelist      bool     `xorm:"NOT NULL DEFAULT false"`
 	ApprovalsWhitelistUserIDs     []int64  `xorm:"JSON TEXT"`
 	ApprovalsWhitelistTeamIDs     []int64  `xorm:"JSON TEXT"`
@@ -601,6 +601,38 @@ var migrations = []Migration{
 	NewMigration("Add metadata column for comment table", v1_23.AddCommentMetaDataColumn),
 	// v304 -> v305
 	NewMigration("Add index for release sha1", v1_23.AddIndexForReleaseSha1),
+	// v305 -> v306
+	NewMigration("Add concurrency columns for action run and action run job", v1_23.AddConcurrencyColumnsForActionRunAndJob),
+	// v306 -> v307
+	NewMigration("Add action environment table and environment columns", v1_23.AddActionEnvironmentTable),
+	// v307 -> v308
+	NewMigration("Add action cache table", v1_23.AddActionCacheTable),
+	// v308 -> v309
+	NewMigration("Add ephemeral column to action runner table", v1_23.AddEphemeralColumnToActionRunner),
+	// v309 -> v310
+	NewMigration("Add action usage table", v1_23.AddActionUsageTable),
+	// v310 -> v311
+	NewMigration("Add required workflows column to protected branch table", v1_23.AddRequiredWorkflowsToProtectedBranch),
+	// v311 -> v312
+	NewMigration("Add completion notified column to action run table", v1_23.AddCompletionNotifiedToActionRun),
+	// v312 -> v313
+	NewMigration("Add run attempt columns to action run, task and artifact tables", v1_23.AddRunAttemptToActionRunTaskAndArtifact),
+	// v313 -> v314
+	NewMigration("Add status notified columns to action run and job tables", v1_23.AddStatusNotifiedToActionRunAndJob),
+	// v314 -> v315
+	NewMigration("Add action annotation table", v1_23.AddActionAnnotationTable),
+	// v315 -> v316
+	NewMigration("Add action step summary table", v1_23.AddActionStepSummaryTable),
+	// v316 -> v317
+	NewMigration("Add action org required workflow table", v1_23.AddActionOrgRequiredWorkflowTable),
+	// v317 -> v318
+	NewMigration("Add cancel reason column to action run table", v1_23.AddCancelReasonToActionRun),
+	// v318 -> v319
+	NewMigration("Add log masks column to action task table", v1_23.AddLogMasksToActionTask),
+	// v319 -> v320
+	NewMigration("Add log cold storage columns to action task table", v1_23.AddLogColdStorageToActionTask),
+	// v320 -> v321
+	NewMigration("Add token permissions column to action run job table", v1_23.AddTokenPermissionsToActionRunJob),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,21 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import "xorm.io/xorm"
+
+func AddConcurrencyColumnsForActionRunAndJob(x *xorm.Engine) error {
+	type ActionRun struct {
+		ConcurrencyGroup  string `xorm:"index"`
+		ConcurrencyCancel bool
+	}
+	type ActionRunJob struct {
+		ConcurrencyGroup  string `xorm:"index"`
+		ConcurrencyCancel bool
+	}
+	_, err := x.SyncWithOptions(xorm.SyncOptions{
+		IgnoreDropIndices: true,
+	}, new(ActionRun), new(ActionRunJob))
+	return err
+}
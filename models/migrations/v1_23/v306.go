@@ -0,0 +1,46 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionEnvironmentTable(x *xorm.Engine) error {
+	type ActionEnvironment struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(repo_name) NOT NULL"`
+		Name        string             `xorm:"UNIQUE(repo_name) NOT NULL"`
+		ReviewerIDs []int64            `xorm:"JSON TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+	if err := x.Sync(new(ActionEnvironment)); err != nil {
+		return err
+	}
+
+	type ActionRunJob struct {
+		Environment           string `xorm:"VARCHAR(255)"`
+		EnvironmentApprovedBy int64
+	}
+	if _, err := x.SyncWithOptions(xorm.SyncOptions{
+		IgnoreDropIndices: true,
+	}, new(ActionRunJob)); err != nil {
+		return err
+	}
+
+	// the unique index of secret is changed, so all the columns and indexes have to be declared
+	type Secret struct {
+		ID            int64
+		OwnerID       int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
+		RepoID        int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+		EnvironmentID int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+		Name          string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+		Data          string             `xorm:"LONGTEXT"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created NOT NULL"`
+	}
+	return x.Sync(new(Secret))
+}
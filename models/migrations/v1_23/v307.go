@@ -0,0 +1,26 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionCacheTable(x *xorm.Engine) error {
+	type ActionCache struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"index NOT NULL"`
+		Ref         string `xorm:"VARCHAR(255)"`
+		CacheKey    string `xorm:"VARCHAR(512)"`
+		Version     string `xorm:"VARCHAR(255)"`
+		Size        int64
+		StoragePath string
+		Complete    bool               `xorm:"index"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UsedUnix    timeutil.TimeStamp `xorm:"index"`
+	}
+	return x.Sync(new(ActionCache))
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionUsageTable(x *xorm.Engine) error {
+	type ActionUsage struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerID     int64              `xorm:"UNIQUE(owner_repo_month) NOT NULL"`
+		RepoID      int64              `xorm:"INDEX UNIQUE(owner_repo_month) NOT NULL"`
+		Month       string             `xorm:"INDEX UNIQUE(owner_repo_month) VARCHAR(7) NOT NULL"`
+		Jobs        int64              `xorm:"NOT NULL DEFAULT 0"`
+		Duration    int64              `xorm:"NOT NULL DEFAULT 0"`
+		Minutes     int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionUsage))
+}
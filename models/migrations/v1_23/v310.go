@@ -0,0 +1,18 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddRequiredWorkflowsToProtectedBranch(x *xorm.Engine) error {
+	type ProtectedBranch struct {
+		RequiredWorkflows []string `xorm:"JSON TEXT"`
+	}
+	_, err := x.SyncWithOptions(xorm.SyncOptions{
+		IgnoreDropIndices: true,
+	}, new(ProtectedBranch))
+	return err
+}
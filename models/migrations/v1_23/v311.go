@@ -0,0 +1,24 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddCompletionNotifiedToActionRun(x *xorm.Engine) error {
+	type ActionRun struct {
+		CompletionNotified timeutil.TimeStamp `xorm:"DEFAULT 0"`
+	}
+
+	if err := x.Sync(new(ActionRun)); err != nil {
+		return err
+	}
+
+	// the completed runs shouldn't trigger the workflow_run workflows after upgrading
+	_, err := x.Exec("UPDATE action_run SET completion_notified = stopped")
+	return err
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"context"
+
+	"xorm.io/xorm"
+)
+
+func AddRunAttemptToActionRunTaskAndArtifact(x *xorm.Engine) error {
+	type ActionRun struct {
+		Attempt int64 `xorm:"NOT NULL DEFAULT 1"`
+	}
+	type ActionTask struct {
+		RunAttempt int64
+	}
+	type ActionArtifact struct {
+		RunID        int64  `xorm:"index unique(runid_attempt_name_path)"`
+		RunAttempt   int64  `xorm:"unique(runid_attempt_name_path)"`
+		ArtifactPath string `xorm:"index unique(runid_attempt_name_path)"`
+		ArtifactName string `xorm:"index unique(runid_attempt_name_path)"`
+	}
+	// the unique index runid_name_path is replaced by runid_attempt_name_path, drop it explicitly instead of relying on
+	// the sync options, or the artifacts uploaded by the reruns couldn't have the same name and path
+	indexes, err := x.Dialect().GetIndexes(x.DB(), context.Background(), "action_artifact")
+	if err != nil {
+		return err
+	}
+	if index, ok := indexes["runid_name_path"]; ok {
+		if _, err := x.Exec(x.Dialect().DropIndexSQL("action_artifact", index)); err != nil {
+			return err
+		}
+	}
+	if _, err := x.SyncWithOptions(xorm.SyncOptions{
+		IgnoreDropIndices: true,
+	}, new(ActionRun), new(ActionTask), new(ActionArtifact)); err != nil {
+		return err
+	}
+
+	// the attempts of the jobs are the best guess of the attempts of the existing runs and tasks
+	if _, err := x.Exec("UPDATE action_run SET attempt = (SELECT MAX(action_run_job.attempt) FROM action_run_job WHERE action_run_job.run_id = action_run.id) " +
+		"WHERE EXISTS (SELECT 1 FROM action_run_job WHERE action_run_job.run_id = action_run.id AND action_run_job.attempt > 1)"); err != nil {
+		return err
+	}
+	if _, err := x.Exec("UPDATE action_task SET run_attempt = attempt"); err != nil {
+		return err
+	}
+	_, err = x.Exec("UPDATE action_artifact SET run_attempt = COALESCE((SELECT attempt FROM action_run WHERE action_run.id = action_artifact.run_id), 1)")
+	return err
+}
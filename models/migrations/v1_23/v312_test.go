@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/models/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddRunAttemptToActionRunTaskAndArtifact(t *testing.T) {
+	type ActionRun struct {
+		ID     int64
+		RepoID int64 `xorm:"index unique(repo_index)"`
+		Index  int64 `xorm:"index unique(repo_index)"`
+	}
+	type ActionRunJob struct {
+		ID      int64
+		RunID   int64 `xorm:"index"`
+		Attempt int64
+	}
+	type ActionTask struct {
+		ID      int64
+		JobID   int64
+		Attempt int64
+	}
+	type ActionArtifact struct {
+		ID           int64  `xorm:"pk autoincr"`
+		RunID        int64  `xorm:"index unique(runid_name_path)"`
+		ArtifactPath string `xorm:"index unique(runid_name_path)"`
+		ArtifactName string `xorm:"index unique(runid_name_path)"`
+	}
+
+	// Prepare and load the testing database
+	x, deferable := base.PrepareTestEnv(t, 0, new(ActionRun), new(ActionRunJob), new(ActionTask), new(ActionArtifact))
+	defer deferable()
+	if x == nil || t.Failed() {
+		return
+	}
+
+	assert.NoError(t, AddRunAttemptToActionRunTaskAndArtifact(x))
+
+	type ActionRunAfter struct {
+		ID      int64
+		Attempt int64
+	}
+	var runs []*ActionRunAfter
+	assert.NoError(t, x.Table("action_run").Asc("id").Find(&runs))
+	assert.Equal(t, []*ActionRunAfter{{ID: 1, Attempt: 1}, {ID: 2, Attempt: 2}}, runs)
+
+	type ActionTaskAfter struct {
+		ID         int64
+		RunAttempt int64
+	}
+	var tasks []*ActionTaskAfter
+	assert.NoError(t, x.Table("action_task").Asc("id").Find(&tasks))
+	assert.Equal(t, []*ActionTaskAfter{{ID: 1, RunAttempt: 1}, {ID: 2, RunAttempt: 2}}, tasks)
+
+	type ActionArtifactAfter struct {
+		ID           int64 `xorm:"pk autoincr"`
+		RunID        int64
+		RunAttempt   int64
+		ArtifactPath string
+		ArtifactName string
+	}
+	var artifacts []*ActionArtifactAfter
+	assert.NoError(t, x.Table("action_artifact").Asc("id").Find(&artifacts))
+	if assert.Len(t, artifacts, 2) {
+		assert.EqualValues(t, 1, artifacts[0].RunAttempt)
+		assert.EqualValues(t, 2, artifacts[1].RunAttempt)
+	}
+
+	indexes, err := x.Dialect().GetIndexes(x.DB(), context.Background(), "action_artifact")
+	assert.NoError(t, err)
+	assert.NotContains(t, indexes, "runid_name_path")
+	assert.Contains(t, indexes, "runid_attempt_name_path")
+
+	// a rerun could upload an artifact with the same name and path, but not the same attempt
+	_, err = x.Table("action_artifact").Insert(&ActionArtifactAfter{RunID: 2, RunAttempt: 3, ArtifactPath: "report.txt", ArtifactName: "report"})
+	assert.NoError(t, err)
+	_, err = x.Table("action_artifact").Insert(&ActionArtifactAfter{RunID: 2, RunAttempt: 3, ArtifactPath: "report.txt", ArtifactName: "report"})
+	assert.Error(t, err)
+}
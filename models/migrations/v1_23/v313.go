@@ -0,0 +1,30 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddStatusNotifiedToActionRunAndJob(x *xorm.Engine) error {
+	type ActionRun struct {
+		StartNotified timeutil.TimeStamp `xorm:"DEFAULT 0"`
+	}
+	type ActionRunJob struct {
+		NotifiedStatus int `xorm:"DEFAULT 0"`
+	}
+
+	if err := x.Sync(new(ActionRun), new(ActionRunJob)); err != nil {
+		return err
+	}
+
+	// the existing runs and jobs shouldn't be notified again after upgrading
+	if _, err := x.Exec("UPDATE action_run SET start_notified = started"); err != nil {
+		return err
+	}
+	_, err := x.Exec("UPDATE action_run_job SET notified_status = status")
+	return err
+}
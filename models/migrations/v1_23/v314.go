@@ -0,0 +1,30 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionAnnotationTable(x *xorm.Engine) error {
+	type ActionAnnotation struct {
+		ID        int64
+		RepoID    int64  `xorm:"index"`
+		RunID     int64  `xorm:"index"`
+		JobID     int64  `xorm:"index"`
+		TaskID    int64  `xorm:"index"`
+		CommitSHA string `xorm:"index"`
+		Level     string `xorm:"VARCHAR(16)"`
+		Path      string `xorm:"VARCHAR(500)"`
+		StartLine int64
+		EndLine   int64
+		Title     string `xorm:"VARCHAR(255)"`
+		Message   string `xorm:"TEXT"`
+		LogIndex  int64
+		Created   timeutil.TimeStamp `xorm:"created"`
+	}
+	return x.Sync(new(ActionAnnotation))
+}
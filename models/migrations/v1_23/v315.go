@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionStepSummaryTable(x *xorm.Engine) error {
+	type ActionStepSummary struct {
+		ID        int64
+		RepoID    int64              `xorm:"index"`
+		RunID     int64              `xorm:"index"`
+		JobID     int64              `xorm:"index"`
+		TaskID    int64              `xorm:"index unique(task_step)"`
+		StepIndex int64              `xorm:"unique(task_step)"`
+		Content   string             `xorm:"LONGTEXT"`
+		Created   timeutil.TimeStamp `xorm:"created"`
+		Updated   timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionStepSummary))
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionOrgRequiredWorkflowTable(x *xorm.Engine) error {
+	type ActionOrgRequiredWorkflow struct {
+		ID         int64
+		OrgID      int64              `xorm:"index"`
+		RepoID     int64              `xorm:"index"`
+		WorkflowID string             `xorm:"VARCHAR(255)"`
+		RepoIDs    []int64            `xorm:"JSON TEXT"`
+		Created    timeutil.TimeStamp `xorm:"created"`
+		Updated    timeutil.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync(new(ActionOrgRequiredWorkflow))
+}
@@ -0,0 +1,18 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddLogColdStorageToActionTask(x *xorm.Engine) error {
+	type ActionTask struct {
+		LogInColdStorage bool               `xorm:"NOT NULL DEFAULT false"`
+		LogRehydrated    timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+	return x.Sync(new(ActionTask))
+}
@@ -93,6 +93,15 @@ func (p *Permission) SetUnitsWithDefaultAccessMode(units []*repo_model.RepoUnit,
 	}
 }
 
+// SetUnitsWithAccessModes sets the units with their own access modes, the units missing in the modes have no access
+func (p *Permission) SetUnitsWithAccessModes(units []*repo_model.RepoUnit, modes map[unit.Type]perm_model.AccessMode) {
+	p.units = units
+	p.unitsMode = make(map[unit.Type]perm_model.AccessMode)
+	for _, u := range p.units {
+		p.unitsMode[u.Type] = modes[u.Type]
+	}
+}
+
 // CanAccess returns true if user has mode access to the unit of the repository
 func (p *Permission) CanAccess(mode perm_model.AccessMode, unitType unit.Type) bool {
 	return p.UnitAccessMode(unitType) >= mode
@@ -168,8 +168,24 @@ func (cfg *PullRequestsConfig) GetDefaultMergeStyle() MergeStyle {
 	return MergeStyleMerge
 }
 
+// ActionsApprovalPolicy decides which runs triggered by pull requests from forks need to be approved by a maintainer before running
+type ActionsApprovalPolicy string
+
+const (
+	// ActionsApprovalPolicyFirstTime requires approval for the users who haven't contributed to the repository, it's the default policy
+	ActionsApprovalPolicyFirstTime ActionsApprovalPolicy = "first_time"
+	// ActionsApprovalPolicyAllOutside requires approval for all the users who don't have write permission
+	ActionsApprovalPolicyAllOutside ActionsApprovalPolicy = "all_outside"
+)
+
 type ActionsConfig struct {
 	DisabledWorkflows []string
+	// RunRetentionDays overrides the instance's [actions].RUN_RETENTION_DAYS if it's greater than 0
+	RunRetentionDays int64
+	ApprovalPolicy   ActionsApprovalPolicy
+	// AllowedActions restricts the actions used by the workflows further than the instance and the owner, empty means no more restrictions
+	AllowedActions         string
+	AllowedActionsPatterns []string
 }
 
 func (cfg *ActionsConfig) EnableWorkflow(file string) {
@@ -194,6 +210,22 @@ func (cfg *ActionsConfig) DisableWorkflow(file string) {
 	cfg.DisabledWorkflows = append(cfg.DisabledWorkflows, file)
 }
 
+// GetRunRetentionDays returns the retention days of finished runs, 0 means the runs are kept forever
+func (cfg *ActionsConfig) GetRunRetentionDays() int64 {
+	if cfg.RunRetentionDays > 0 {
+		return cfg.RunRetentionDays
+	}
+	return setting.Actions.RunRetentionDays
+}
+
+// GetApprovalPolicy returns the approval policy for the runs triggered by pull requests from forks
+func (cfg *ActionsConfig) GetApprovalPolicy() ActionsApprovalPolicy {
+	if cfg.ApprovalPolicy == ActionsApprovalPolicyAllOutside {
+		return cfg.ApprovalPolicy
+	}
+	return ActionsApprovalPolicyFirstTime
+}
+
 // FromDB fills up a ActionsConfig from serialized format.
 func (cfg *ActionsConfig) FromDB(bs []byte) error {
 	return json.UnmarshalHandleDoubleEncode(bs, &cfg)
@@ -5,6 +5,7 @@ package secret
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -25,6 +26,7 @@ import (
 // It can be:
 //  1. org/user level secret, OwnerID is org/user ID and RepoID is 0
 //  2. repo level secret, OwnerID is 0 and RepoID is repo ID
+//  3. environment level secret, OwnerID is 0, RepoID is repo ID and EnvironmentID is the ID of an environment of the repo
 //
 // Please note that it's not acceptable to have both OwnerID and RepoID to be non-zero,
 // or it will be complicated to find secrets belonging to a specific owner.
@@ -35,12 +37,13 @@ import (
 // Please note that it's not acceptable to have both OwnerID and RepoID to zero, global secrets are not supported.
 // It's for security reasons, admin may be not aware of that the secrets could be stolen by any user when setting them as global.
 type Secret struct {
-	ID          int64
-	OwnerID     int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
-	RepoID      int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
-	Name        string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
-	Data        string             `xorm:"LONGTEXT"` // encrypted data
-	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	ID            int64
+	OwnerID       int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
+	RepoID        int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+	EnvironmentID int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+	Name          string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+	Data          string             `xorm:"LONGTEXT"` // encrypted data
+	CreatedUnix   timeutil.TimeStamp `xorm:"created NOT NULL"`
 }
 
 // ErrSecretNotFound represents a "secret not found" error.
@@ -57,7 +60,7 @@ func (err ErrSecretNotFound) Unwrap() error {
 }
 
 // InsertEncryptedSecret Creates, encrypts, and validates a new secret with yet unencrypted data and insert into database
-func InsertEncryptedSecret(ctx context.Context, ownerID, repoID int64, name, data string) (*Secret, error) {
+func InsertEncryptedSecret(ctx context.Context, ownerID, repoID, environmentID int64, name, data string) (*Secret, error) {
 	if ownerID != 0 && repoID != 0 {
 		// It's trying to create a secret that belongs to a repository, but OwnerID has been set accidentally.
 		// Remove OwnerID to avoid confusion; it's not worth returning an error here.
@@ -66,16 +69,20 @@ func InsertEncryptedSecret(ctx context.Context, ownerID, repoID int64, name, dat
 	if ownerID == 0 && repoID == 0 {
 		return nil, fmt.Errorf("%w: ownerID and repoID cannot be both zero, global secrets are not supported", util.ErrInvalidArgument)
 	}
+	if environmentID != 0 && repoID == 0 {
+		return nil, fmt.Errorf("%w: environment secrets must belong to a repository", util.ErrInvalidArgument)
+	}
 
 	encrypted, err := secret_module.EncryptSecret(setting.SecretKey, data)
 	if err != nil {
 		return nil, err
 	}
 	secret := &Secret{
-		OwnerID: ownerID,
-		RepoID:  repoID,
-		Name:    strings.ToUpper(name),
-		Data:    encrypted,
+		OwnerID:       ownerID,
+		RepoID:        repoID,
+		EnvironmentID: environmentID,
+		Name:          strings.ToUpper(name),
+		Data:          encrypted,
 	}
 	return secret, db.Insert(ctx, secret)
 }
@@ -86,10 +93,11 @@ func init() {
 
 type FindSecretsOptions struct {
 	db.ListOptions
-	RepoID   int64
-	OwnerID  int64 // it will be ignored if RepoID is set
-	SecretID int64
-	Name     string
+	RepoID        int64
+	OwnerID       int64 // it will be ignored if RepoID is set
+	EnvironmentID int64 // only the secrets of the environment are found if it's set, otherwise the environment level secrets are excluded
+	SecretID      int64
+	Name          string
 }
 
 func (opts FindSecretsOptions) ToConds() builder.Cond {
@@ -102,6 +110,7 @@ func (opts FindSecretsOptions) ToConds() builder.Cond {
 	} else {
 		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
 	}
+	cond = cond.And(builder.Eq{"environment_id": opts.EnvironmentID})
 
 	if opts.SecretID != 0 {
 		cond = cond.And(builder.Eq{"id": opts.SecretID})
@@ -154,7 +163,24 @@ func GetSecretsOfTask(ctx context.Context, task *actions_model.ActionTask) (map[
 		return nil, err
 	}
 
-	for _, secret := range append(ownerSecrets, repoSecrets...) {
+	var environmentSecrets []*Secret
+	if task.Job.Environment != "" {
+		env, err := actions_model.GetEnvironmentByName(ctx, task.Job.Run.RepoID, task.Job.Environment)
+		if err != nil && !errors.Is(err, util.ErrNotExist) {
+			log.Error("get environment %q of repo %v: %v", task.Job.Environment, task.Job.Run.RepoID, err)
+			return nil, err
+		}
+		if env != nil {
+			environmentSecrets, err = db.Find[Secret](ctx, FindSecretsOptions{RepoID: task.Job.Run.RepoID, EnvironmentID: env.ID})
+			if err != nil {
+				log.Error("find secrets of environment %v: %v", env.ID, err)
+				return nil, err
+			}
+		}
+	}
+
+	// Level precedence: Environment > Repo > Org / User
+	for _, secret := range append(ownerSecrets, append(repoSecrets, environmentSecrets...)...) {
 		v, err := secret_module.DecryptSecret(setting.SecretKey, secret.Data)
 		if err != nil {
 			log.Error("decrypt secret %v %q: %v", secret.ID, secret.Name, err)
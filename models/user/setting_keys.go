@@ -10,6 +10,12 @@ const (
 	SettingsKeyDiffWhitespaceBehavior = "diff.whitespace_behaviour"
 	// SettingsKeyShowOutdatedComments is the setting key wether or not to show outdated comments in PRs
 	SettingsKeyShowOutdatedComments = "comment_code.show_outdated"
+	// SettingsKeyActionsUsageSoftQuota is the setting key for the soft quota of the actions minutes per month
+	SettingsKeyActionsUsageSoftQuota = "actions.usage_soft_quota"
+	// SettingsKeyActionsAllowedActions is the setting key for the policy of the actions allowed to be used by the workflows of the organization
+	SettingsKeyActionsAllowedActions = "actions.allowed_actions"
+	// SettingsKeyActionsAllowedActionsPatterns is the setting key for the patterns of the allowed actions, separated by newlines
+	SettingsKeyActionsAllowedActionsPatterns = "actions.allowed_actions_patterns"
 	// UserActivityPubPrivPem is user's private key
 	UserActivityPubPrivPem = "activitypub.priv_pem"
 	// UserActivityPubPubPem is user's public key
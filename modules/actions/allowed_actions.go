@@ -0,0 +1,106 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/gobwas/glob"
+)
+
+// AllowedActionsPolicy decides which actions may be used by the workflows
+type AllowedActionsPolicy string
+
+const (
+	// AllowedActionsAll allows any action, it's the default policy
+	AllowedActionsAll AllowedActionsPolicy = "all"
+	// AllowedActionsLocal only allows the actions hosted on this instance
+	AllowedActionsLocal AllowedActionsPolicy = "local"
+	// AllowedActionsSelected only allows the actions matching the patterns and the local ones
+	AllowedActionsSelected AllowedActionsPolicy = "selected"
+)
+
+// AllowedActions is a policy of the instance, an organization or a repository restricting the actions used by the workflows.
+// The actions in the same repository (`uses: ./path`) are always allowed.
+type AllowedActions struct {
+	Policy   AllowedActionsPolicy
+	Patterns []string
+
+	globs []allowedActionsGlob // the compiled patterns, the invalid ones are skipped
+}
+
+type allowedActionsGlob struct {
+	glob.Glob
+	hasVersion bool
+}
+
+// NewAllowedActions returns the policy, an unknown policy is treated as AllowedActionsAll
+func NewAllowedActions(policy string, patterns []string) *AllowedActions {
+	a := &AllowedActions{Policy: AllowedActionsPolicy(policy)}
+	if a.Policy != AllowedActionsLocal && a.Policy != AllowedActionsSelected {
+		a.Policy = AllowedActionsAll
+	}
+	if a.Policy == AllowedActionsSelected {
+		a.Patterns = ParseAllowedActionsPatterns(strings.Join(patterns, "\n"))
+		a.globs = make([]allowedActionsGlob, 0, len(a.Patterns))
+		for _, pattern := range a.Patterns {
+			g, err := glob.Compile(pattern)
+			if err != nil {
+				log.Warn("Invalid allowed actions pattern %q: %v", pattern, err)
+				continue
+			}
+			a.globs = append(a.globs, allowedActionsGlob{Glob: g, hasVersion: strings.Contains(pattern, "@")})
+		}
+	}
+	return a
+}
+
+// ParseAllowedActionsPatterns parses the patterns separated by newlines or commas, the empty ones are ignored
+func ParseAllowedActionsPatterns(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == ',' })
+	patterns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			patterns = append(patterns, field)
+		}
+	}
+	return patterns
+}
+
+// IsAllowed returns whether the action referenced by `uses` of a step, or the reusable workflow referenced by `uses` of a job, is allowed
+func (a *AllowedActions) IsAllowed(uses string) bool {
+	if a.Policy == AllowedActionsAll || strings.HasPrefix(uses, "./") || isInstanceAction(uses) {
+		return true
+	}
+	if a.Policy == AllowedActionsLocal {
+		return false
+	}
+
+	name, _, hasRef := strings.Cut(uses, "@")
+	if strings.HasPrefix(uses, "docker://") {
+		// the tag of a docker image isn't a version of an action
+		name, hasRef = uses, false
+	}
+	for _, g := range a.globs {
+		// a pattern without a version matches any version of the action
+		if g.Match(uses) || hasRef && !g.hasVersion && g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInstanceAction returns whether the action is hosted on this instance
+func isInstanceAction(uses string) bool {
+	if strings.HasPrefix(uses, "docker://") {
+		return false
+	}
+	if strings.HasPrefix(uses, "https://") || strings.HasPrefix(uses, "http://") {
+		return strings.HasPrefix(uses, setting.AppURL)
+	}
+	return setting.Actions.DefaultActionsURL.URL() == strings.TrimSuffix(setting.AppURL, "/")
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAllowedActionsPatterns(t *testing.T) {
+	assert.Equal(t, []string{"actions/*", "docker://alpine:*", "owner/repo@v1"},
+		ParseAllowedActionsPatterns("actions/*, docker://alpine:*\n\n  owner/repo@v1  \n,"))
+	assert.Empty(t, ParseAllowedActionsPatterns(" \n "))
+}
+
+func TestAllowedActions(t *testing.T) {
+	defer test.MockVariableValue(&setting.AppURL, "https://gitea.example.com/")()
+	defer test.MockVariableValue(&setting.Actions.DefaultActionsURL, "github")()
+
+	all := NewAllowedActions("", []string{"actions/*"})
+	assert.Equal(t, AllowedActionsAll, all.Policy)
+	assert.Empty(t, all.Patterns)
+	assert.True(t, all.IsAllowed("evil/action@v1"))
+	assert.True(t, all.IsAllowed("docker://alpine:3"))
+
+	local := NewAllowedActions("local", nil)
+	assert.True(t, local.IsAllowed("./.gitea/actions/build"))
+	assert.True(t, local.IsAllowed("https://gitea.example.com/org/action@v1"))
+	assert.False(t, local.IsAllowed("actions/checkout@v4"))
+	assert.False(t, local.IsAllowed("https://github.com/actions/checkout@v4"))
+	assert.False(t, local.IsAllowed("docker://alpine:3"))
+
+	selected := NewAllowedActions("selected", []string{"actions/*", "owner/repo@v1", "docker://alpine:*", "[invalid"})
+	assert.True(t, selected.IsAllowed("./local"))
+	assert.True(t, selected.IsAllowed("actions/checkout@v4"))
+	assert.True(t, selected.IsAllowed("actions/cache/restore@v4"))
+	assert.True(t, selected.IsAllowed("owner/repo@v1"))
+	assert.False(t, selected.IsAllowed("owner/repo@v2"))
+	assert.True(t, selected.IsAllowed("docker://alpine:3"))
+	assert.False(t, selected.IsAllowed("docker://ubuntu:22.04"))
+	assert.False(t, selected.IsAllowed("evil/action@v1"))
+	assert.True(t, selected.IsAllowed("actions/workflows/.gitea/workflows/build.yml@main"))
+	assert.False(t, local.IsAllowed("evil/workflows/.gitea/workflows/build.yml@main"))
+
+	// the short names are resolved to this instance
+	defer test.MockVariableValue(&setting.Actions.DefaultActionsURL, "self")()
+	assert.True(t, local.IsAllowed("actions/checkout@v4"))
+	assert.False(t, local.IsAllowed("https://github.com/actions/checkout@v4"))
+}
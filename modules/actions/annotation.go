@@ -0,0 +1,69 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Annotation is an annotation created by a workflow command in the logs,
+// like "::error file=app.js,line=1,col=5,title=Lint::Missing semicolon".
+// See https://docs.github.com/en/actions/writing-workflows/choosing-what-your-workflow-does/workflow-commands-for-github-actions
+type Annotation struct {
+	Level     string // "error", "warning" or "notice"
+	File      string
+	Line      int64
+	EndLine   int64
+	Column    int64
+	EndColumn int64
+	Title     string
+	Message   string
+}
+
+var annotationCommandPattern = regexp.MustCompile(`^::(error|warning|notice)(?:\s+(.*?))?::(.*)$`)
+
+var (
+	annotationMessageUnescaper  = strings.NewReplacer("%0D", "\r", "%0A", "\n", "%25", "%")
+	annotationPropertyUnescaper = strings.NewReplacer("%0D", "\r", "%0A", "\n", "%3A", ":", "%2C", ",", "%25", "%")
+)
+
+// ParseAnnotation parses the annotation from a line of the logs, it returns false if the line isn't an annotation command
+func ParseAnnotation(line string) (*Annotation, bool) {
+	matches := annotationCommandPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil {
+		return nil, false
+	}
+
+	annotation := &Annotation{
+		Level:   matches[1],
+		Message: annotationMessageUnescaper.Replace(matches[3]),
+	}
+	for _, property := range strings.Split(matches[2], ",") {
+		key, value, ok := strings.Cut(property, "=")
+		if !ok {
+			continue
+		}
+		value = annotationPropertyUnescaper.Replace(strings.TrimSpace(value))
+		switch strings.TrimSpace(key) {
+		case "file":
+			annotation.File = strings.TrimPrefix(value, "./")
+		case "line":
+			annotation.Line, _ = strconv.ParseInt(value, 10, 64)
+		case "endLine":
+			annotation.EndLine, _ = strconv.ParseInt(value, 10, 64)
+		case "col":
+			annotation.Column, _ = strconv.ParseInt(value, 10, 64)
+		case "endColumn":
+			annotation.EndColumn, _ = strconv.ParseInt(value, 10, 64)
+		case "title":
+			annotation.Title = value
+		}
+	}
+	if annotation.Line > 0 && annotation.EndLine < annotation.Line {
+		annotation.EndLine = annotation.Line
+	}
+	return annotation, true
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAnnotation(t *testing.T) {
+	cases := []struct {
+		line       string
+		annotation *Annotation
+	}{
+		{
+			line:       "::error::Something went wrong",
+			annotation: &Annotation{Level: "error", Message: "Something went wrong"},
+		},
+		{
+			line:       "::warning file=./src/app.js,line=3,col=5,endColumn=9,title=Lint::Missing semicolon",
+			annotation: &Annotation{Level: "warning", File: "src/app.js", Line: 3, EndLine: 3, Column: 5, EndColumn: 9, Title: "Lint", Message: "Missing semicolon"},
+		},
+		{
+			line:       "  ::notice file=README.md,line=1,endLine=4::Long line",
+			annotation: &Annotation{Level: "notice", File: "README.md", Line: 1, EndLine: 4, Message: "Long line"},
+		},
+		{
+			line:       "::error title=a%3A b%2C c%25,line=abc::line one%0Aline two",
+			annotation: &Annotation{Level: "error", Title: "a: b, c%", Message: "line one\nline two"},
+		},
+		{line: "::debug::not an annotation"},
+		{line: "::set-output name=foo::bar"},
+		{line: "error: not a command"},
+		{line: "echo ::error::it's not at the start of the line"},
+	}
+
+	for _, c := range cases {
+		annotation, ok := ParseAnnotation(c.line)
+		assert.Equal(t, c.annotation != nil, ok, c.line)
+		assert.Equal(t, c.annotation, annotation, c.line)
+	}
+}
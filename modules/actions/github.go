@@ -23,6 +23,7 @@ const (
 	GithubEventPullRequestComment       = "pull_request_comment"
 	GithubEventGollum                   = "gollum"
 	GithubEventSchedule                 = "schedule"
+	GithubEventWorkflowRun              = "workflow_run"
 )
 
 // IsDefaultBranchWorkflow returns true if the event only triggers workflows on the default branch
@@ -52,6 +53,10 @@ func IsDefaultBranchWorkflow(triggedEvent webhook_module.HookEventType) bool {
 		// GitHub "schedule" event
 		// https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#schedule
 		return true
+	case webhook_module.HookEventWorkflowRun:
+		// GitHub "workflow_run" event
+		// https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#workflow_run
+		return true
 	case webhook_module.HookEventIssues,
 		webhook_module.HookEventIssueAssign,
 		webhook_module.HookEventIssueLabel,
@@ -0,0 +1,317 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// LintSeverity is the severity of a problem found in a workflow
+type LintSeverity string
+
+const (
+	// LintSeverityError means the workflow can't run as expected, it should be fixed before committing
+	LintSeverityError LintSeverity = "error"
+	// LintSeverityWarning means the workflow may not run as expected, like there is no matching runner for now
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// LintProblemKind is the kind of a problem found in a workflow
+type LintProblemKind string
+
+const (
+	LintInvalidWorkflow   LintProblemKind = "invalid_workflow"
+	LintUnknownKey        LintProblemKind = "unknown_key"
+	LintInvalidCron       LintProblemKind = "invalid_cron"
+	LintNoMatchingRunner  LintProblemKind = "no_matching_runner"
+	LintNoJob             LintProblemKind = "no_job"
+	LintNoJobWithoutNeeds LintProblemKind = "no_job_without_needs"
+	// LintContainerNoImage means the job container or a service container has no image
+	LintContainerNoImage LintProblemKind = "container_no_image"
+	// LintInvalidContainerPort means a port of a container isn't like "[host:]container[/protocol]"
+	LintInvalidContainerPort LintProblemKind = "invalid_container_port"
+	// LintUnsupportedContainerOption means an option of a container is set by the runner and can't be overridden
+	LintUnsupportedContainerOption LintProblemKind = "unsupported_container_option"
+)
+
+// LintProblem is a problem found in a workflow, Line and Column start from 1 and are 0 if the position is unknown
+type LintProblem struct {
+	Line     int
+	Column   int
+	Severity LintSeverity
+	Kind     LintProblemKind
+	// Arg is the subject of the problem, like the unknown key or the invalid cron spec
+	Arg string
+}
+
+var (
+	workflowKeys = container.SetOf("name", "run-name", "on", "permissions", "env", "defaults", "concurrency", "jobs")
+	jobKeys      = container.SetOf("name", "permissions", "needs", "if", "runs-on", "environment", "concurrency", "outputs", "env",
+		"defaults", "timeout-minutes", "strategy", "continue-on-error", "container", "services", "steps", "uses", "with", "secrets")
+	stepKeys = container.SetOf("id", "if", "name", "uses", "run", "working-directory", "shell", "with", "env",
+		"continue-on-error", "timeout-minutes")
+	containerKeys   = container.SetOf("image", "credentials", "env", "ports", "volumes", "options", "cmd")
+	credentialsKeys = container.SetOf("username", "password")
+
+	// unsupportedContainerOptions are the options of `docker create` which conflict with the ones set by the runner
+	unsupportedContainerOptions = container.SetOf("--network", "--net", "--entrypoint")
+
+	containerPortRegexp = regexp.MustCompile(`^(?:(?:[0-9.]+|\[[0-9a-fA-F:]+\]):)?(?:\d+(?:-\d+)?:)?\d+(?:-\d+)?(?:/(?:tcp|udp|sctp))?$`)
+
+	cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+	yamlErrorLineRegexp = regexp.MustCompile(`line (\d+)`)
+)
+
+// LintWorkflow checks the content of a workflow and returns the problems sorted by position.
+// The labels of the jobs are checked against runnerLabels unless it's nil.
+func LintWorkflow(content []byte, runnerLabels container.Set[string]) []*LintProblem {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return []*LintProblem{invalidWorkflowProblem(err)}
+	}
+	if _, err := model.ReadWorkflow(bytes.NewReader(content)); err != nil {
+		return []*LintProblem{invalidWorkflowProblem(err)}
+	}
+	if _, err := jobparser.Parse(content); err != nil {
+		return []*LintProblem{invalidWorkflowProblem(err)}
+	}
+
+	var problems []*LintProblem
+	addProblem := func(node *yaml.Node, severity LintSeverity, kind LintProblemKind, arg string) {
+		p := &LintProblem{Severity: severity, Kind: kind, Arg: arg}
+		if node != nil {
+			p.Line, p.Column = node.Line, node.Column
+		}
+		problems = append(problems, p)
+	}
+
+	doc := &root
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		doc = doc.Content[0]
+	}
+	if doc.Kind != yaml.MappingNode {
+		addProblem(doc, LintSeverityError, LintNoJob, "")
+		return problems
+	}
+
+	var jobsKey, jobsNode *yaml.Node
+	forEachMappingPair(doc, func(key, value *yaml.Node) {
+		if !workflowKeys.Contains(key.Value) {
+			addProblem(key, LintSeverityError, LintUnknownKey, key.Value)
+		}
+		switch key.Value {
+		case "on":
+			lintSchedules(value, addProblem)
+		case "jobs":
+			jobsKey, jobsNode = key, value
+		}
+	})
+
+	jobsNumber, hasJobWithoutNeeds := 0, false
+	if jobsNode != nil && jobsNode.Kind == yaml.MappingNode {
+		forEachMappingPair(jobsNode, func(_, job *yaml.Node) {
+			if job.Kind != yaml.MappingNode {
+				return
+			}
+			jobsNumber++
+			hasNeeds := false
+			forEachMappingPair(job, func(key, value *yaml.Node) {
+				if !jobKeys.Contains(key.Value) {
+					addProblem(key, LintSeverityError, LintUnknownKey, key.Value)
+				}
+				switch key.Value {
+				case "needs":
+					hasNeeds = !isEmptyNode(value)
+				case "runs-on":
+					if runnerLabels != nil {
+						lintRunsOn(value, runnerLabels, addProblem)
+					}
+				case "container":
+					lintContainer(key, value, addProblem)
+				case "services":
+					forEachMappingPair(value, func(key, value *yaml.Node) {
+						lintContainer(key, value, addProblem)
+					})
+				case "steps":
+					if value.Kind != yaml.SequenceNode {
+						return
+					}
+					for _, step := range value.Content {
+						forEachMappingPair(step, func(key, _ *yaml.Node) {
+							if !stepKeys.Contains(key.Value) {
+								addProblem(key, LintSeverityError, LintUnknownKey, key.Value)
+							}
+						})
+					}
+				}
+			})
+			if !hasNeeds {
+				hasJobWithoutNeeds = true
+			}
+		})
+	}
+	if jobsNumber == 0 {
+		addProblem(jobsKey, LintSeverityError, LintNoJob, "")
+	} else if !hasJobWithoutNeeds {
+		// The workflow must contain at least one job without "needs". Otherwise, a deadlock will occur and no jobs will be able to run.
+		addProblem(jobsKey, LintSeverityError, LintNoJobWithoutNeeds, "")
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems
+}
+
+// HasLintErrors returns whether any of the problems is an error
+func HasLintErrors(problems []*LintProblem) bool {
+	for _, p := range problems {
+		if p.Severity == LintSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidWorkflowProblem(err error) *LintProblem {
+	p := &LintProblem{Severity: LintSeverityError, Kind: LintInvalidWorkflow, Arg: err.Error()}
+	if m := yamlErrorLineRegexp.FindStringSubmatch(err.Error()); m != nil {
+		p.Line, _ = strconv.Atoi(m[1])
+	}
+	return p
+}
+
+func lintSchedules(on *yaml.Node, addProblem func(*yaml.Node, LintSeverity, LintProblemKind, string)) {
+	if on.Kind != yaml.MappingNode {
+		return
+	}
+	forEachMappingPair(on, func(key, value *yaml.Node) {
+		if key.Value != "schedule" || value.Kind != yaml.SequenceNode {
+			return
+		}
+		for _, item := range value.Content {
+			forEachMappingPair(item, func(key, value *yaml.Node) {
+				if key.Value != "cron" {
+					return
+				}
+				if _, err := cronParser.Parse(value.Value); err != nil {
+					addProblem(value, LintSeverityError, LintInvalidCron, value.Value)
+				}
+			})
+		}
+	})
+}
+
+func lintRunsOn(runsOn *yaml.Node, runnerLabels container.Set[string], addProblem func(*yaml.Node, LintSeverity, LintProblemKind, string)) {
+	var labels []*yaml.Node
+	switch runsOn.Kind {
+	case yaml.ScalarNode:
+		labels = []*yaml.Node{runsOn}
+	case yaml.SequenceNode:
+		labels = runsOn.Content
+	case yaml.MappingNode:
+		forEachMappingPair(runsOn, func(key, value *yaml.Node) {
+			if key.Value != "labels" {
+				return
+			}
+			if value.Kind == yaml.SequenceNode {
+				labels = value.Content
+			} else {
+				labels = []*yaml.Node{value}
+			}
+		})
+	}
+	for _, label := range labels {
+		// Skip if it contains expressions, the expressions could be very complex and could not be evaluated here.
+		if label.Kind != yaml.ScalarNode || strings.Contains(label.Value, "${{") {
+			continue
+		}
+		if !runnerLabels.Contains(label.Value) {
+			addProblem(label, LintSeverityWarning, LintNoMatchingRunner, label.Value)
+		}
+	}
+}
+
+// lintContainer checks the job container or a service container, the key is where the problems without a position are reported
+func lintContainer(key, value *yaml.Node, addProblem func(*yaml.Node, LintSeverity, LintProblemKind, string)) {
+	// the short syntax only has the image, an empty image means no container
+	if value.Kind != yaml.MappingNode {
+		return
+	}
+	hasImage := false
+	forEachMappingPair(value, func(k, v *yaml.Node) {
+		if !containerKeys.Contains(k.Value) {
+			addProblem(k, LintSeverityError, LintUnknownKey, k.Value)
+		}
+		switch k.Value {
+		case "image":
+			hasImage = true
+		case "credentials":
+			forEachMappingPair(v, func(k, _ *yaml.Node) {
+				if !credentialsKeys.Contains(k.Value) {
+					addProblem(k, LintSeverityError, LintUnknownKey, k.Value)
+				}
+			})
+		case "ports":
+			if v.Kind != yaml.SequenceNode {
+				return
+			}
+			for _, port := range v.Content {
+				if port.Kind != yaml.ScalarNode || strings.Contains(port.Value, "${{") {
+					continue
+				}
+				if !containerPortRegexp.MatchString(port.Value) {
+					addProblem(port, LintSeverityError, LintInvalidContainerPort, port.Value)
+				}
+			}
+		case "options":
+			if strings.Contains(v.Value, "${{") {
+				return
+			}
+			for _, field := range strings.Fields(v.Value) {
+				option, _, _ := strings.Cut(field, "=")
+				if unsupportedContainerOptions.Contains(option) {
+					addProblem(v, LintSeverityError, LintUnsupportedContainerOption, option)
+				}
+			}
+		}
+	})
+	if !hasImage {
+		addProblem(key, LintSeverityError, LintContainerNoImage, key.Value)
+	}
+}
+
+func forEachMappingPair(node *yaml.Node, f func(key, value *yaml.Node)) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		f(node.Content[i], node.Content[i+1])
+	}
+}
+
+func isEmptyNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value == ""
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
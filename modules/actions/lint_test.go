@@ -0,0 +1,165 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"testing"
+
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintWorkflow(t *testing.T) {
+	kinds := func(problems []*LintProblem) []string {
+		res := make([]string, 0, len(problems))
+		for _, p := range problems {
+			res = append(res, fmt.Sprintf("%s@%d:%s", p.Kind, p.Line, p.Arg))
+		}
+		return res
+	}
+	runnerLabels := container.SetOf("ubuntu-latest")
+
+	testCases := []struct {
+		name     string
+		content  string
+		expected []string
+		hasError bool
+	}{
+		{
+			name: "valid",
+			content: `name: test
+on:
+  push:
+  schedule:
+    - cron: "0 0 * * *"
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ok
+`,
+			expected: []string{},
+		},
+		{
+			name: "unknown keys",
+			content: `name: test
+on: push
+job:
+  test:
+    runs-on: ubuntu-latest
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    step:
+      - run: echo ok
+    steps:
+      - run: echo ok
+        args: foo
+`,
+			expected: []string{"unknown_key@3:job", "unknown_key@9:step", "unknown_key@13:args"},
+			hasError: true,
+		},
+		{
+			name: "invalid cron",
+			content: `on:
+  schedule:
+    - cron: "0 0 * *"
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ok
+`,
+			expected: []string{"invalid_cron@3:0 0 * *"},
+			hasError: true,
+		},
+		{
+			name: "no matching runner",
+			content: `on: push
+jobs:
+  test:
+    runs-on: [ubuntu-latest, gpu]
+    steps:
+      - run: echo ok
+  matrix:
+    runs-on: ${{ matrix.os }}
+    strategy:
+      matrix:
+        os: [windows-latest]
+    steps:
+      - run: echo ok
+`,
+			expected: []string{"no_matching_runner@4:gpu"},
+		},
+		{
+			name: "no job without needs",
+			content: `on: push
+jobs:
+  test:
+    needs: [test]
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ok
+`,
+			expected: []string{"no_job_without_needs@2:"},
+			hasError: true,
+		},
+		{
+			name: "containers",
+			content: `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    container:
+      image: node:20
+      ports: [80, "8080:80/tcp", "127.0.0.1:5432:5432", "${{ matrix.port }}", "80:http"]
+      options: --cpus 1 --network=host
+      workdir: /app
+    services:
+      redis:
+        image: redis:7
+      db:
+        credentials:
+          username: user
+          token: foo
+        ports: ["3306-3310:3306-3310/udp"]
+    steps:
+      - run: echo ok
+`,
+			expected: []string{
+				"invalid_container_port@7:80:http",
+				"unsupported_container_option@8:--network",
+				"unknown_key@9:workdir",
+				"container_no_image@13:db",
+				"unknown_key@16:token",
+			},
+			hasError: true,
+		},
+		{
+			name: "invalid yaml",
+			content: `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+   steps:
+`,
+			expected: []string{"invalid_workflow@2:"},
+			hasError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			problems := LintWorkflow([]byte(tc.content), runnerLabels)
+			actual := kinds(problems)
+			if tc.name == "invalid yaml" && len(problems) == 1 {
+				// the message of the parser is not a part of the test
+				actual = []string{fmt.Sprintf("%s@%d:", problems[0].Kind, problems[0].Line)}
+			}
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.hasError, HasLintErrors(problems))
+		})
+	}
+}
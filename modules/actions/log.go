@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/dbfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/storage"
@@ -136,21 +137,9 @@ func TransferLogs(ctx context.Context, filename string) (func(), error) {
 
 	var reader io.Reader = f
 	if strings.HasSuffix(filename, ".zst") {
-		r, w := io.Pipe()
-		reader = r
-		zstdWriter, err := zstd.NewSeekableWriter(w, logZstdBlockSize)
-		if err != nil {
-			return nil, fmt.Errorf("zstd NewSeekableWriter: %w", err)
+		if reader, err = compressLogs(f); err != nil {
+			return nil, err
 		}
-		go func() {
-			defer func() {
-				_ = w.CloseWithError(zstdWriter.Close())
-			}()
-			if _, err := io.Copy(zstdWriter, f); err != nil {
-				_ = w.CloseWithError(err)
-				return
-			}
-		}()
 	}
 
 	if _, err := storage.Actions.Save(filename, reader, -1); err != nil {
@@ -159,6 +148,83 @@ func TransferLogs(ctx context.Context, filename string) (func(), error) {
 	return remove, nil
 }
 
+// compressLogs returns a reader of the content compressed in the seekable zstd format, so the logs can still be read by offsets
+func compressLogs(content io.Reader) (io.Reader, error) {
+	r, w := io.Pipe()
+	zstdWriter, err := zstd.NewSeekableWriter(w, logZstdBlockSize)
+	if err != nil {
+		return nil, fmt.Errorf("zstd NewSeekableWriter: %w", err)
+	}
+	go func() {
+		defer func() {
+			_ = w.CloseWithError(zstdWriter.Close())
+		}()
+		if _, err := io.Copy(zstdWriter, content); err != nil {
+			_ = w.CloseWithError(err)
+			return
+		}
+	}()
+	return r, nil
+}
+
+// TransferLogsToColdStorage moves logs from the storage to the cold storage, and compresses the content if it isn't compressed yet.
+// It returns the file name in the cold storage, which has the ".zst" suffix, and a function to remove the logs from the storage,
+// which should be called only after the task has been updated to refer to the cold storage.
+func TransferLogsToColdStorage(filename string) (string, func(), error) {
+	remove := func() {
+		if err := storage.Actions.Delete(filename); err != nil {
+			log.Warn("storage delete %q: %v", filename, err)
+		}
+	}
+	f, err := storage.Actions.Open(filename)
+	if err != nil {
+		return "", nil, fmt.Errorf("storage open %q: %w", filename, err)
+	}
+	defer f.Close()
+
+	coldFilename := filename
+	var reader io.Reader = f
+	if !strings.HasSuffix(filename, ".zst") {
+		coldFilename = filename + ".zst"
+		if reader, err = compressLogs(f); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if _, err := storage.ActionsLogCold.Save(coldFilename, reader, -1); err != nil {
+		return "", nil, fmt.Errorf("cold storage save %q: %w", coldFilename, err)
+	}
+	return coldFilename, remove, nil
+}
+
+// TransferLogsFromColdStorage moves logs from the cold storage back to the storage, so they can be read
+func TransferLogsFromColdStorage(filename string) error {
+	f, err := storage.ActionsLogCold.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cold storage open %q: %w", filename, err)
+	}
+	defer f.Close()
+
+	if _, err := storage.Actions.Save(filename, f, -1); err != nil {
+		return fmt.Errorf("storage save %q: %w", filename, err)
+	}
+	if err := storage.ActionsLogCold.Delete(filename); err != nil {
+		return fmt.Errorf("cold storage delete %q: %w", filename, err)
+	}
+	return nil
+}
+
+// RemoveTaskLogs removes the logs of the task wherever they are
+func RemoveTaskLogs(ctx context.Context, task *actions_model.ActionTask) error {
+	if !task.LogInColdStorage {
+		return RemoveLogs(ctx, task.LogInStorage, task.LogFilename)
+	}
+	if err := storage.ActionsLogCold.Delete(task.LogFilename); err != nil {
+		return fmt.Errorf("cold storage delete %q: %w", task.LogFilename, err)
+	}
+	return nil
+}
+
 func RemoveLogs(ctx context.Context, inStorage bool, filename string) error {
 	if !inStorage {
 		name := DBFSPrefix + filename
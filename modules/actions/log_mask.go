@@ -0,0 +1,103 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+)
+
+// LogMask is what the secret values are replaced with in the logs
+const LogMask = "***"
+
+var addMaskCommandPattern = regexp.MustCompile(`^::add-mask::(.*)$`)
+
+// LogMasker replaces the secret values in the logs with LogMask
+type LogMasker struct {
+	values   []string
+	replacer *strings.Replacer
+}
+
+// NewLogMasker returns a masker of the values, the empty values are ignored
+func NewLogMasker(values ...string) *LogMasker {
+	m := &LogMasker{}
+	for _, value := range values {
+		m.add(value)
+	}
+	m.reset()
+	return m
+}
+
+// Values returns the masked values
+func (m *LogMasker) Values() []string {
+	return m.values
+}
+
+// Add adds a value to be masked, a multi-line value is also masked line by line, since the logs are split into lines.
+// It returns false if the value has been masked already.
+func (m *LogMasker) Add(value string) bool {
+	if !m.add(value) {
+		return false
+	}
+	m.reset()
+	return true
+}
+
+func (m *LogMasker) add(value string) bool {
+	added := false
+	for _, v := range append([]string{value}, strings.Split(value, "\n")...) {
+		v = strings.TrimSpace(v)
+		if v == "" || v == LogMask {
+			continue
+		}
+		if !slices.Contains(m.values, v) {
+			m.values = append(m.values, v)
+			added = true
+		}
+	}
+	return added
+}
+
+// reset rebuilds the replacer, the longer values are replaced first so a value containing another one is masked entirely
+func (m *LogMasker) reset() {
+	values := make([]string, len(m.values))
+	copy(values, m.values)
+	sort.SliceStable(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	oldnew := make([]string, 0, len(values)*2)
+	for _, v := range values {
+		oldnew = append(oldnew, v, LogMask)
+	}
+	m.replacer = strings.NewReplacer(oldnew...)
+}
+
+// Mask replaces the masked values in the content
+func (m *LogMasker) Mask(content string) string {
+	if len(m.values) == 0 {
+		return content
+	}
+	return m.replacer.Replace(content)
+}
+
+// MaskRows masks the rows of the logs in place, the values of the "::add-mask::" commands are masked in the rows since the commands.
+// It returns true if any value is added by the commands.
+func (m *LogMasker) MaskRows(rows []*runnerv1.LogRow) bool {
+	added := false
+	for _, row := range rows {
+		if matches := addMaskCommandPattern.FindStringSubmatch(strings.TrimSpace(row.Content)); matches != nil {
+			if m.Add(annotationMessageUnescaper.Replace(matches[1])) {
+				added = true
+			}
+			// the value in the command may be escaped, so mask it as it is
+			if matches[1] != "" {
+				row.Content = strings.Replace(row.Content, matches[1], LogMask, 1)
+			}
+		}
+		row.Content = m.Mask(row.Content)
+	}
+	return added
+}
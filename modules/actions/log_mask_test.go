@@ -0,0 +1,53 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogMasker(t *testing.T) {
+	masker := NewLogMasker("", "  ", "password", "pass", "line1\nline2\n")
+	assert.ElementsMatch(t, []string{"password", "pass", "line1\nline2", "line1", "line2"}, masker.Values())
+
+	assert.Equal(t, "echo ***", masker.Mask("echo password"))
+	assert.Equal(t, "*** and ***", masker.Mask("pass and password"))
+	assert.Equal(t, "key: ***", masker.Mask("key: line2"))
+	assert.Equal(t, "nothing secret", masker.Mask("nothing secret"))
+
+	assert.False(t, masker.Add("pass"))
+	assert.True(t, masker.Add("token"))
+	assert.Equal(t, "***", masker.Mask("token"))
+
+	assert.Equal(t, "plain", NewLogMasker().Mask("plain"))
+}
+
+func TestLogMaskerMaskRows(t *testing.T) {
+	masker := NewLogMasker("password")
+	rows := []*runnerv1.LogRow{
+		{Content: "login with password"},
+		{Content: "the value is abc%25def"},
+		{Content: "::add-mask::abc%25def"},
+		{Content: "the value is abc%def"},
+		{Content: "::add-mask::password"},
+	}
+	assert.True(t, masker.MaskRows(rows))
+
+	actual := make([]string, 0, len(rows))
+	for _, row := range rows {
+		actual = append(actual, row.Content)
+	}
+	assert.Equal(t, []string{
+		"login with ***",
+		"the value is abc%25def",
+		"::add-mask::***",
+		"the value is ***",
+		"::add-mask::***",
+	}, actual)
+
+	assert.False(t, masker.MaskRows([]*runnerv1.LogRow{{Content: "::add-mask::password"}}))
+}
@@ -146,6 +146,33 @@ func DetectWorkflows(
 	return workflows, schedules, nil
 }
 
+// DetectWorkflowContent returns the events of the workflow which match the event triggered for the commit,
+// it's for the workflows which aren't in the commit, like the required workflows of an organization
+func DetectWorkflowContent(
+	gitRepo *git.Repository,
+	commit *git.Commit,
+	entryName string,
+	content []byte,
+	triggedEvent webhook_module.HookEventType,
+	payload api.Payloader,
+) ([]*DetectedWorkflow, error) {
+	events, err := GetEventsFromContent(content)
+	if err != nil {
+		return nil, err
+	}
+	var workflows []*DetectedWorkflow
+	for _, evt := range events {
+		if !evt.IsSchedule() && detectMatched(gitRepo, commit, triggedEvent, payload, evt) {
+			workflows = append(workflows, &DetectedWorkflow{
+				EntryName:    entryName,
+				TriggerEvent: evt,
+				Content:      content,
+			})
+		}
+	}
+	return workflows, nil
+}
+
 func DetectScheduledWorkflows(gitRepo *git.Repository, commit *git.Commit) ([]*DetectedWorkflow, error) {
 	entries, err := ListWorkflows(commit)
 	if err != nil {
@@ -243,6 +270,10 @@ func detectMatched(gitRepo *git.Repository, commit *git.Commit, triggedEvent web
 		webhook_module.HookEventPackage:
 		return matchPackageEvent(payload.(*api.PackagePayload), evt)
 
+	case // workflow_run
+		webhook_module.HookEventWorkflowRun:
+		return matchWorkflowRunEvent(payload.(*api.WorkflowRunPayload), evt)
+
 	default:
 		log.Warn("unsupported event %q", triggedEvent)
 		return false
@@ -255,96 +286,94 @@ func matchPushEvent(commit *git.Commit, pushPayload *api.PushPayload, evt *jobpa
 		return true
 	}
 
-	matchTimes := 0
-	hasBranchFilter := false
-	hasTagFilter := false
-	refName := git.RefName(pushPayload.Ref)
-	// all acts conditions should be satisfied
-	for cond, vals := range evt.Acts() {
+	acts := evt.Acts()
+	for cond := range acts {
 		switch cond {
-		case "branches":
-			hasBranchFilter = true
-			if !refName.IsBranch() {
-				break
-			}
-			patterns, err := workflowpattern.CompilePatterns(vals...)
-			if err != nil {
-				break
-			}
-			if !workflowpattern.Skip(patterns, []string{refName.BranchName()}, &workflowpattern.EmptyTraceWriter{}) {
-				matchTimes++
-			}
-		case "branches-ignore":
-			hasBranchFilter = true
-			if !refName.IsBranch() {
-				break
-			}
-			patterns, err := workflowpattern.CompilePatterns(vals...)
-			if err != nil {
-				break
-			}
-			if !workflowpattern.Filter(patterns, []string{refName.BranchName()}, &workflowpattern.EmptyTraceWriter{}) {
-				matchTimes++
-			}
-		case "tags":
-			hasTagFilter = true
-			if !refName.IsTag() {
-				break
-			}
-			patterns, err := workflowpattern.CompilePatterns(vals...)
-			if err != nil {
-				break
-			}
-			if !workflowpattern.Skip(patterns, []string{refName.TagName()}, &workflowpattern.EmptyTraceWriter{}) {
-				matchTimes++
+		case "branches", "branches-ignore", "tags", "tags-ignore", "paths", "paths-ignore":
+		default:
+			log.Warn("push event unsupported condition %q", cond)
+			return false
+		}
+	}
+
+	refName := git.RefName(pushPayload.Ref)
+	branches, hasBranches := acts["branches"]
+	branchesIgnore, hasBranchesIgnore := acts["branches-ignore"]
+	tags, hasTags := acts["tags"]
+	tagsIgnore, hasTagsIgnore := acts["tags-ignore"]
+
+	// If only branch filters or only tag filters are defined, the workflow doesn't run for the other kind of refs.
+	// If both are defined, the ref only needs to match the filters of its own kind.
+	if hasBranches || hasBranchesIgnore || hasTags || hasTagsIgnore {
+		switch {
+		case refName.IsBranch():
+			if !hasBranches && !hasBranchesIgnore {
+				return false
 			}
-		case "tags-ignore":
-			hasTagFilter = true
-			if !refName.IsTag() {
-				break
+			if hasBranches && !matchPatterns(branches, []string{refName.BranchName()}) {
+				return false
 			}
-			patterns, err := workflowpattern.CompilePatterns(vals...)
-			if err != nil {
-				break
+			if hasBranchesIgnore && !matchIgnorePatterns(branchesIgnore, []string{refName.BranchName()}) {
+				return false
 			}
-			if !workflowpattern.Filter(patterns, []string{refName.TagName()}, &workflowpattern.EmptyTraceWriter{}) {
-				matchTimes++
+		case refName.IsTag():
+			if !hasTags && !hasTagsIgnore {
+				return false
 			}
-		case "paths":
-			filesChanged, err := commit.GetFilesChangedSinceCommit(pushPayload.Before)
-			if err != nil {
-				log.Error("GetFilesChangedSinceCommit [commit_sha1: %s]: %v", commit.ID.String(), err)
-			} else {
-				patterns, err := workflowpattern.CompilePatterns(vals...)
-				if err != nil {
-					break
-				}
-				if !workflowpattern.Skip(patterns, filesChanged, &workflowpattern.EmptyTraceWriter{}) {
-					matchTimes++
-				}
+			if hasTags && !matchPatterns(tags, []string{refName.TagName()}) {
+				return false
 			}
-		case "paths-ignore":
-			filesChanged, err := commit.GetFilesChangedSinceCommit(pushPayload.Before)
-			if err != nil {
-				log.Error("GetFilesChangedSinceCommit [commit_sha1: %s]: %v", commit.ID.String(), err)
-			} else {
-				patterns, err := workflowpattern.CompilePatterns(vals...)
-				if err != nil {
-					break
-				}
-				if !workflowpattern.Filter(patterns, filesChanged, &workflowpattern.EmptyTraceWriter{}) {
-					matchTimes++
-				}
+			if hasTagsIgnore && !matchIgnorePatterns(tagsIgnore, []string{refName.TagName()}) {
+				return false
 			}
 		default:
-			log.Warn("push event unsupported condition %q", cond)
+			return false
 		}
 	}
-	// if both branch and tag filter are defined in the workflow only one needs to match
-	if hasBranchFilter && hasTagFilter {
-		matchTimes++
+
+	// path filters are not evaluated for pushes of tags
+	if refName.IsTag() {
+		return true
 	}
-	return matchTimes == len(evt.Acts())
+
+	paths, hasPaths := acts["paths"]
+	pathsIgnore, hasPathsIgnore := acts["paths-ignore"]
+	if !hasPaths && !hasPathsIgnore {
+		return true
+	}
+	filesChanged, err := commit.GetFilesChangedSinceCommit(pushPayload.Before)
+	if err != nil {
+		log.Error("GetFilesChangedSinceCommit [commit_sha1: %s]: %v", commit.ID.String(), err)
+		return false
+	}
+	if hasPaths && !matchPatterns(paths, filesChanged) {
+		return false
+	}
+	if hasPathsIgnore && !matchIgnorePatterns(pathsIgnore, filesChanged) {
+		return false
+	}
+	return true
+}
+
+// matchPatterns reports whether any of the inputs is included by the patterns.
+// A pattern prefixed with "!" excludes the inputs matched by the patterns before it.
+func matchPatterns(vals, inputs []string) bool {
+	patterns, err := workflowpattern.CompilePatterns(vals...)
+	if err != nil {
+		log.Warn("invalid filter patterns %v: %v", vals, err)
+		return false
+	}
+	return !workflowpattern.Skip(patterns, inputs, &workflowpattern.EmptyTraceWriter{})
+}
+
+// matchIgnorePatterns reports whether any of the inputs is not ignored by the patterns.
+func matchIgnorePatterns(vals, inputs []string) bool {
+	patterns, err := workflowpattern.CompilePatterns(vals...)
+	if err != nil {
+		log.Warn("invalid filter patterns %v: %v", vals, err)
+		return false
+	}
+	return !workflowpattern.Filter(patterns, inputs, &workflowpattern.EmptyTraceWriter{})
 }
 
 func matchIssuesEvent(issuePayload *api.IssuePayload, evt *jobparser.Event) bool {
@@ -520,6 +549,22 @@ func matchIssueCommentEvent(issueCommentPayload *api.IssueCommentPayload, evt *j
 					break
 				}
 			}
+		case "commands":
+			// Gitea extension: the comment must start with one of the commands, like "/retest" or "/deploy staging".
+			// The commands are not triggered by deleting the comments.
+			if issueCommentPayload.Action == api.HookIssueCommentDeleted || issueCommentPayload.Comment == nil {
+				break
+			}
+			command := parseCommentCommand(issueCommentPayload.Comment.Body)
+			if command == "" {
+				break
+			}
+			for _, val := range vals {
+				if glob.MustCompile(val, '/').Match(command) {
+					matchTimes++
+					break
+				}
+			}
 		default:
 			log.Warn("issue comment event unsupported condition %q", cond)
 		}
@@ -527,6 +572,15 @@ func matchIssueCommentEvent(issueCommentPayload *api.IssueCommentPayload, evt *j
 	return matchTimes == len(evt.Acts())
 }
 
+// parseCommentCommand returns the command of the comment, it's the first word of the comment like "/deploy" of "/deploy staging"
+func parseCommentCommand(body string) string {
+	fields := strings.Fields(strings.TrimSpace(body))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
 func matchPullRequestReviewEvent(prPayload *api.PullRequestPayload, evt *jobparser.Event) bool {
 	// with no special filter parameters
 	if len(evt.Acts()) == 0 {
@@ -698,3 +752,57 @@ func matchPackageEvent(payload *api.PackagePayload, evt *jobparser.Event) bool {
 	}
 	return matchTimes == len(evt.Acts())
 }
+
+func matchWorkflowRunEvent(payload *api.WorkflowRunPayload, evt *jobparser.Event) bool {
+	// with no special filter parameters
+	if len(evt.Acts()) == 0 {
+		return true
+	}
+
+	matchTimes := 0
+	// all acts conditions should be satisfied
+	for cond, vals := range evt.Acts() {
+		switch cond {
+		case "types":
+			// See https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#workflow_run
+			// Activity types with the same name:
+			// completed
+			// Unsupported activity types:
+			// requested, in_progress
+			for _, val := range vals {
+				if glob.MustCompile(val, '/').Match(string(payload.Action)) {
+					matchTimes++
+					break
+				}
+			}
+		case "workflows":
+			// the workflows could be specified by their names or their file names
+			for _, val := range vals {
+				g := glob.MustCompile(val, '/')
+				if g.Match(payload.Workflow.Name) || g.Match(payload.WorkflowRun.WorkflowID) {
+					matchTimes++
+					break
+				}
+			}
+		case "branches":
+			patterns, err := workflowpattern.CompilePatterns(vals...)
+			if err != nil {
+				break
+			}
+			if !workflowpattern.Skip(patterns, []string{payload.WorkflowRun.HeadBranch}, &workflowpattern.EmptyTraceWriter{}) {
+				matchTimes++
+			}
+		case "branches-ignore":
+			patterns, err := workflowpattern.CompilePatterns(vals...)
+			if err != nil {
+				break
+			}
+			if !workflowpattern.Filter(patterns, []string{payload.WorkflowRun.HeadBranch}, &workflowpattern.EmptyTraceWriter{}) {
+				matchTimes++
+			}
+		default:
+			log.Warn("workflow run event unsupported condition %q", cond)
+		}
+	}
+	return matchTimes == len(evt.Acts())
+}
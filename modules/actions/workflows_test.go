@@ -111,6 +111,139 @@ func TestDetectMatched(t *testing.T) {
 			yamlOn:       "on:\n  registry_package:\n    types: [updated]",
 			expected:     false,
 		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) `completed` action matches GithubEventWorkflowRun(workflow_run) with `completed` activity type and workflow name",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload:      newWorkflowRunPayload("CI", "ci.yml", "main"),
+			yamlOn:       "on:\n  workflow_run:\n    workflows: [CI]\n    types: [completed]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) matches GithubEventWorkflowRun(workflow_run) with workflow file name",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload:      newWorkflowRunPayload("CI", "ci.yml", "main"),
+			yamlOn:       "on:\n  workflow_run:\n    workflows: [ci.yml]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) doesn't match GithubEventWorkflowRun(workflow_run) with other workflows",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload:      newWorkflowRunPayload("CI", "ci.yml", "main"),
+			yamlOn:       "on:\n  workflow_run:\n    workflows: [Lint]",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) `completed` action doesn't match GithubEventWorkflowRun(workflow_run) with `requested` activity type",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload:      newWorkflowRunPayload("CI", "ci.yml", "main"),
+			yamlOn:       "on:\n  workflow_run:\n    workflows: [CI]\n    types: [requested]",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) matches GithubEventWorkflowRun(workflow_run) with branches",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload:      newWorkflowRunPayload("CI", "ci.yml", "release/v1"),
+			yamlOn:       "on:\n  workflow_run:\n    workflows: [CI]\n    branches: [release/*]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) doesn't match GithubEventWorkflowRun(workflow_run) with branches-ignore",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload:      newWorkflowRunPayload("CI", "ci.yml", "main"),
+			yamlOn:       "on:\n  workflow_run:\n    workflows: [CI]\n    branches-ignore: [main]",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventIssueComment(issue_comment) matches GithubEventIssueComment(issue_comment) with commands",
+			triggedEvent: webhook_module.HookEventIssueComment,
+			payload:      &api.IssueCommentPayload{Action: api.HookIssueCommentCreated, Comment: &api.Comment{Body: "/deploy staging\nplease"}},
+			yamlOn:       "on:\n  issue_comment:\n    commands: [/retest, /deploy]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventIssueComment(issue_comment) doesn't match GithubEventIssueComment(issue_comment) with other commands",
+			triggedEvent: webhook_module.HookEventIssueComment,
+			payload:      &api.IssueCommentPayload{Action: api.HookIssueCommentCreated, Comment: &api.Comment{Body: "/deployment"}},
+			yamlOn:       "on:\n  issue_comment:\n    commands: [/retest, /deploy]",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventIssueComment(issue_comment) doesn't match GithubEventIssueComment(issue_comment) with commands not at the start of the comment",
+			triggedEvent: webhook_module.HookEventIssueComment,
+			payload:      &api.IssueCommentPayload{Action: api.HookIssueCommentCreated, Comment: &api.Comment{Body: "LGTM, /retest"}},
+			yamlOn:       "on:\n  issue_comment:\n    commands: [/retest]",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventIssueComment(issue_comment) `deleted` action doesn't match GithubEventIssueComment(issue_comment) with commands",
+			triggedEvent: webhook_module.HookEventIssueComment,
+			payload:      &api.IssueCommentPayload{Action: api.HookIssueCommentDeleted, Comment: &api.Comment{Body: "/retest"}},
+			yamlOn:       "on:\n  issue_comment:\n    commands: [/retest]",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventPullRequestComment(pull_request_comment) `edited` action matches GithubEventIssueComment(issue_comment) with commands and types",
+			triggedEvent: webhook_module.HookEventPullRequestComment,
+			payload:      &api.IssueCommentPayload{Action: api.HookIssueCommentEdited, Comment: &api.Comment{Body: "  /retest"}, IsPull: true},
+			yamlOn:       "on:\n  issue_comment:\n    types: [created, edited]\n    commands: [/retest]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventPush(push) branch push matches GithubEventPush(push) with branches and tags",
+			triggedEvent: webhook_module.HookEventPush,
+			payload:      &api.PushPayload{Ref: "refs/heads/release/v1"},
+			yamlOn:       "on:\n  push:\n    branches: [main, release/**]\n    tags: [v*]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventPush(push) tag push matches GithubEventPush(push) with branches and tags",
+			triggedEvent: webhook_module.HookEventPush,
+			payload:      &api.PushPayload{Ref: "refs/tags/v1.0.0"},
+			yamlOn:       "on:\n  push:\n    branches: [main]\n    tags: [v*]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventPush(push) tag push doesn't match GithubEventPush(push) with only branches",
+			triggedEvent: webhook_module.HookEventPush,
+			payload:      &api.PushPayload{Ref: "refs/tags/v1.0.0"},
+			yamlOn:       "on:\n  push:\n    branches: ['**']",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventPush(push) branch push doesn't match GithubEventPush(push) with only tags-ignore",
+			triggedEvent: webhook_module.HookEventPush,
+			payload:      &api.PushPayload{Ref: "refs/heads/main"},
+			yamlOn:       "on:\n  push:\n    tags-ignore: [nightly]",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventPush(push) branch push doesn't match GithubEventPush(push) with branches excluded by a negative pattern",
+			triggedEvent: webhook_module.HookEventPush,
+			payload:      &api.PushPayload{Ref: "refs/heads/release/v1-beta"},
+			yamlOn:       "on:\n  push:\n    branches: [release/**, '!release/**-beta']\n    tags: [v*]",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventPush(push) tag push doesn't match GithubEventPush(push) with tags-ignore",
+			triggedEvent: webhook_module.HookEventPush,
+			payload:      &api.PushPayload{Ref: "refs/tags/v1.0.0-rc1"},
+			yamlOn:       "on:\n  push:\n    branches: [main]\n    tags-ignore: ['*-rc*']",
+			expected:     false,
+		},
+		{
+			desc:         "HookEventPush(push) tag push matches GithubEventPush(push) with paths because path filters aren't evaluated for tags",
+			triggedEvent: webhook_module.HookEventPush,
+			payload:      &api.PushPayload{Ref: "refs/tags/v1.0.0"},
+			yamlOn:       "on:\n  push:\n    tags: [v*]\n    paths: [docs/**]",
+			expected:     true,
+		},
+		{
+			desc:         "HookEventPush(push) doesn't match GithubEventPush(push) with unsupported conditions",
+			triggedEvent: webhook_module.HookEventPush,
+			payload:      &api.PushPayload{Ref: "refs/heads/main"},
+			yamlOn:       "on:\n  push:\n    branches: [main]\n    types: [created]",
+			expected:     false,
+		},
 		{
 			desc:         "HookEventWiki(wiki) matches GithubEventGollum(gollum)",
 			triggedEvent: webhook_module.HookEventWiki,
@@ -136,3 +269,16 @@ func TestDetectMatched(t *testing.T) {
 		})
 	}
 }
+
+func newWorkflowRunPayload(name, workflowID, branch string) *api.WorkflowRunPayload {
+	return &api.WorkflowRunPayload{
+		Action:   "completed",
+		Workflow: &api.PayloadWorkflow{Name: name},
+		WorkflowRun: &api.ActionWorkflowRun{
+			WorkflowID: workflowID,
+			HeadBranch: branch,
+			Status:     "success",
+			Conclusion: "success",
+		},
+	}
+}
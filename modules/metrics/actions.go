@@ -0,0 +1,22 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// actionsJobWaitSeconds is collected by the Collector, so it's exposed only if the metrics are enabled
+var actionsJobWaitSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Name:    namespace + "actions_job_wait_seconds",
+	Help:    "Time the Actions jobs waited in the queue before being picked up by runners",
+	Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600},
+})
+
+// ObserveActionsJobWait records the time an Actions job waited in the queue before a runner picked it up
+func ObserveActionsJobWait(d time.Duration) {
+	actionsJobWaitSeconds.Observe(d.Seconds())
+}
@@ -6,6 +6,7 @@ package metrics
 import (
 	"runtime"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	activities_model "code.gitea.io/gitea/models/activities"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/setting"
@@ -19,6 +20,10 @@ const namespace = "gitea_"
 // exposes gitea metrics for prometheus
 type Collector struct {
 	Accesses           *prometheus.Desc
+	ActionsJobsQueued  *prometheus.Desc
+	ActionsJobsRunning *prometheus.Desc
+	ActionsLogBytes    *prometheus.Desc
+	ActionsRunners     *prometheus.Desc
 	Attachments        *prometheus.Desc
 	BuildInfo          *prometheus.Desc
 	Comments           *prometheus.Desc
@@ -56,6 +61,26 @@ func NewCollector() Collector {
 			"Number of Accesses",
 			nil, nil,
 		),
+		ActionsJobsQueued: prometheus.NewDesc(
+			namespace+"actions_jobs_queued",
+			"Number of queued Actions jobs",
+			nil, nil,
+		),
+		ActionsJobsRunning: prometheus.NewDesc(
+			namespace+"actions_jobs_running",
+			"Number of running Actions jobs",
+			nil, nil,
+		),
+		ActionsLogBytes: prometheus.NewDesc(
+			namespace+"actions_log_bytes",
+			"Size of the stored Actions logs in bytes",
+			nil, nil,
+		),
+		ActionsRunners: prometheus.NewDesc(
+			namespace+"actions_runners",
+			"Number of Actions runners",
+			[]string{"status"}, nil,
+		),
 		Attachments: prometheus.NewDesc(
 			namespace+"attachments",
 			"Number of Attachments",
@@ -202,6 +227,11 @@ func NewCollector() Collector {
 // Describe returns all possible prometheus.Desc
 func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Accesses
+	ch <- c.ActionsJobsQueued
+	ch <- c.ActionsJobsRunning
+	ch <- c.ActionsLogBytes
+	ch <- c.ActionsRunners
+	actionsJobWaitSeconds.Describe(ch)
 	ch <- c.Attachments
 	ch <- c.BuildInfo
 	ch <- c.Comments
@@ -234,12 +264,37 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 // Collect returns the metrics with values
 func (c Collector) Collect(ch chan<- prometheus.Metric) {
 	stats := activities_model.GetStatistic(db.DefaultContext)
+	actionsStats := actions_model.GetStatistic(db.DefaultContext)
 
 	ch <- prometheus.MustNewConstMetric(
 		c.Accesses,
 		prometheus.GaugeValue,
 		float64(stats.Counter.Access),
 	)
+	ch <- prometheus.MustNewConstMetric(
+		c.ActionsJobsQueued,
+		prometheus.GaugeValue,
+		float64(actionsStats.JobsQueued),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.ActionsJobsRunning,
+		prometheus.GaugeValue,
+		float64(actionsStats.JobsRunning),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.ActionsLogBytes,
+		prometheus.GaugeValue,
+		float64(actionsStats.LogBytes),
+	)
+	for status, count := range actionsStats.RunnersByStatus {
+		ch <- prometheus.MustNewConstMetric(
+			c.ActionsRunners,
+			prometheus.GaugeValue,
+			float64(count),
+			status,
+		)
+	}
+	actionsJobWaitSeconds.Collect(ch)
 	ch <- prometheus.MustNewConstMetric(
 		c.Attachments,
 		prometheus.GaugeValue,
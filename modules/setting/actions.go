@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/modules/log"
+
+	"github.com/hashicorp/go-version"
 )
 
 // Actions settings
@@ -18,20 +20,43 @@ var (
 		LogStorage            *Storage          // how the created logs should be stored
 		LogRetentionDays      int64             `ini:"LOG_RETENTION_DAYS"`
 		LogCompression        logCompression    `ini:"LOG_COMPRESSION"`
+		LogColdStorage        *Storage          // how the logs moved to the cold storage tier should be stored, it's nil if the tier is disabled
+		LogColdStorageDays    int64             `ini:"LOG_COLD_STORAGE_DAYS"` // the logs older than the days are moved to the cold storage, 0 means never
 		ArtifactStorage       *Storage          // how the created artifacts should be stored
 		ArtifactRetentionDays int64             `ini:"ARTIFACT_RETENTION_DAYS"`
+		RunRetentionDays      int64             `ini:"RUN_RETENTION_DAYS"`
+		CacheRepoQuota        int64             `ini:"-"` // the total size of the caches of a repository, -1 means no limit
+		CacheRetentionDays    int64             `ini:"CACHE_RETENTION_DAYS"`
 		DefaultActionsURL     defaultActionsURL `ini:"DEFAULT_ACTIONS_URL"`
 		ZombieTaskTimeout     time.Duration     `ini:"ZOMBIE_TASK_TIMEOUT"`
 		EndlessTaskTimeout    time.Duration     `ini:"ENDLESS_TASK_TIMEOUT"`
 		AbandonedJobTimeout   time.Duration     `ini:"ABANDONED_JOB_TIMEOUT"`
 		SkipWorkflowStrings   []string          `ìni:"SKIP_WORKFLOW_STRINGS"`
+		// the runners below the minimum version are shown as outdated, and they can't pick up jobs if RejectOutdatedRunners is set
+		MinRunnerVersion         string `ini:"MIN_RUNNER_VERSION"`
+		RecommendedRunnerVersion string `ini:"RECOMMENDED_RUNNER_VERSION"`
+		RejectOutdatedRunners    bool   `ini:"REJECT_OUTDATED_RUNNERS"`
+		// which actions may be used by the workflows, the organizations and the repositories can only restrict it further
+		AllowedActions         string   `ini:"ALLOWED_ACTIONS"`
+		AllowedActionsPatterns []string `ini:"ALLOWED_ACTIONS_PATTERNS"`
+		// the permissions of the token of the jobs which don't declare `permissions` in their workflows
+		DefaultTokenPermissions string `ini:"DEFAULT_TOKEN_PERMISSIONS"`
 	}{
-		Enabled:             true,
-		DefaultActionsURL:   defaultActionsURLGitHub,
-		SkipWorkflowStrings: []string{"[skip ci]", "[ci skip]", "[no ci]", "[skip actions]", "[actions skip]"},
+		Enabled:                 true,
+		DefaultActionsURL:       defaultActionsURLGitHub,
+		SkipWorkflowStrings:     []string{"[skip ci]", "[ci skip]", "[no ci]", "[skip actions]", "[actions skip]"},
+		AllowedActions:          "all",
+		DefaultTokenPermissions: ActionsTokenPermissionsPermissive,
 	}
 )
 
+const (
+	// ActionsTokenPermissionsRestricted only grants the read access to the contents and the packages
+	ActionsTokenPermissionsRestricted = "restricted"
+	// ActionsTokenPermissionsPermissive grants the write access to all scopes
+	ActionsTokenPermissionsPermissive = "permissive"
+)
+
 type defaultActionsURL string
 
 func (url defaultActionsURL) URL() string {
@@ -98,6 +123,13 @@ func loadActionsFrom(rootCfg ConfigProvider) error {
 	if Actions.LogRetentionDays <= 0 {
 		Actions.LogRetentionDays = 365
 	}
+	Actions.LogColdStorage = nil
+	if Actions.LogColdStorageDays > 0 {
+		Actions.LogColdStorage, err = getStorage(rootCfg, "actions_log_cold", "", nil)
+		if err != nil {
+			return err
+		}
+	}
 
 	actionsSec, _ := rootCfg.GetSection("actions.artifacts")
 
@@ -111,13 +143,47 @@ func loadActionsFrom(rootCfg ConfigProvider) error {
 		Actions.ArtifactRetentionDays = 90
 	}
 
+	// default to 10 GiB and 7 days in Github Actions
+	sec.Key("CACHE_REPO_QUOTA").MustString("10 GiB")
+	Actions.CacheRepoQuota = mustBytes(sec, "CACHE_REPO_QUOTA")
+	if Actions.CacheRetentionDays <= 0 {
+		Actions.CacheRetentionDays = 7
+	}
+
 	Actions.ZombieTaskTimeout = sec.Key("ZOMBIE_TASK_TIMEOUT").MustDuration(10 * time.Minute)
 	Actions.EndlessTaskTimeout = sec.Key("ENDLESS_TASK_TIMEOUT").MustDuration(3 * time.Hour)
 	Actions.AbandonedJobTimeout = sec.Key("ABANDONED_JOB_TIMEOUT").MustDuration(24 * time.Hour)
 
+	for key, v := range map[string]string{
+		"MIN_RUNNER_VERSION":         Actions.MinRunnerVersion,
+		"RECOMMENDED_RUNNER_VERSION": Actions.RecommendedRunnerVersion,
+	} {
+		if v == "" {
+			continue
+		}
+		if _, err := version.NewVersion(v); err != nil {
+			return fmt.Errorf("invalid [actions] %s: %q", key, v)
+		}
+	}
+	if Actions.RecommendedRunnerVersion == "" {
+		Actions.RecommendedRunnerVersion = Actions.MinRunnerVersion
+	} else if Actions.MinRunnerVersion != "" &&
+		version.Must(version.NewVersion(Actions.RecommendedRunnerVersion)).LessThan(version.Must(version.NewVersion(Actions.MinRunnerVersion))) {
+		return fmt.Errorf("[actions] RECOMMENDED_RUNNER_VERSION %q is lower than MIN_RUNNER_VERSION %q", Actions.RecommendedRunnerVersion, Actions.MinRunnerVersion)
+	}
+
 	if !Actions.LogCompression.IsValid() {
 		return fmt.Errorf("invalid [actions] LOG_COMPRESSION: %q", Actions.LogCompression)
 	}
 
+	switch Actions.AllowedActions {
+	case "all", "local", "selected":
+	default:
+		return fmt.Errorf("invalid [actions] ALLOWED_ACTIONS: %q", Actions.AllowedActions)
+	}
+	if Actions.DefaultTokenPermissions != ActionsTokenPermissionsRestricted && Actions.DefaultTokenPermissions != ActionsTokenPermissionsPermissive {
+		return fmt.Errorf("invalid [actions] DEFAULT_TOKEN_PERMISSIONS: %q", Actions.DefaultTokenPermissions)
+	}
+
 	return nil
 }
@@ -97,6 +97,37 @@ STORAGE_TYPE = minio
 	assert.EqualValues(t, "actions_artifacts", filepath.Base(Actions.ArtifactStorage.Path))
 }
 
+func Test_getLogColdStorageForActions(t *testing.T) {
+	cfg, err := NewConfigProviderFromData(``)
+	assert.NoError(t, err)
+	assert.NoError(t, loadActionsFrom(cfg))
+	assert.Nil(t, Actions.LogColdStorage)
+
+	cfg, err = NewConfigProviderFromData(`
+[actions]
+LOG_COLD_STORAGE_DAYS = 30
+
+[storage.actions_log_cold]
+STORAGE_TYPE = minio
+`)
+	assert.NoError(t, err)
+	assert.NoError(t, loadActionsFrom(cfg))
+	assert.EqualValues(t, 30, Actions.LogColdStorageDays)
+	assert.EqualValues(t, "local", Actions.LogStorage.Type)
+	if assert.NotNil(t, Actions.LogColdStorage) {
+		assert.EqualValues(t, "minio", Actions.LogColdStorage.Type)
+		assert.EqualValues(t, "actions_log_cold/", Actions.LogColdStorage.MinioConfig.BasePath)
+	}
+
+	cfg, err = NewConfigProviderFromData(`
+[actions]
+LOG_COLD_STORAGE_DAYS = 0
+`)
+	assert.NoError(t, err)
+	assert.NoError(t, loadActionsFrom(cfg))
+	assert.Nil(t, Actions.LogColdStorage)
+}
+
 func Test_getDefaultActionsURLForActions(t *testing.T) {
 	oldActions := Actions
 	oldAppURL := AppURL
@@ -179,3 +210,67 @@ DEFAULT_ACTIONS_URL = gitea
 		})
 	}
 }
+
+func Test_getRunnerVersionsForActions(t *testing.T) {
+	tests := []struct {
+		name            string
+		iniStr          string
+		wantErr         assert.ErrorAssertionFunc
+		wantMin         string
+		wantRecommended string
+	}{
+		{
+			name: "min only",
+			iniStr: `
+[actions]
+MIN_RUNNER_VERSION = v0.2.10
+`,
+			wantErr:         assert.NoError,
+			wantMin:         "v0.2.10",
+			wantRecommended: "v0.2.10",
+		},
+		{
+			name: "min and recommended",
+			iniStr: `
+[actions]
+MIN_RUNNER_VERSION = v0.2.10
+RECOMMENDED_RUNNER_VERSION = 0.2.11
+`,
+			wantErr:         assert.NoError,
+			wantMin:         "v0.2.10",
+			wantRecommended: "0.2.11",
+		},
+		{
+			name: "invalid version",
+			iniStr: `
+[actions]
+MIN_RUNNER_VERSION = latest
+`,
+			wantErr: assert.Error,
+		},
+		{
+			name: "recommended below min",
+			iniStr: `
+[actions]
+MIN_RUNNER_VERSION = v0.2.10
+RECOMMENDED_RUNNER_VERSION = v0.2.9
+`,
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Actions.MinRunnerVersion, Actions.RecommendedRunnerVersion = "", ""
+			cfg, err := NewConfigProviderFromData(tt.iniStr)
+			require.NoError(t, err)
+			err = loadActionsFrom(cfg)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+			assert.EqualValues(t, tt.wantMin, Actions.MinRunnerVersion)
+			assert.EqualValues(t, tt.wantRecommended, Actions.RecommendedRunnerVersion)
+		})
+	}
+	Actions.MinRunnerVersion, Actions.RecommendedRunnerVersion = "", ""
+}
@@ -127,6 +127,8 @@ var (
 
 	// Actions represents actions storage
 	Actions ObjectStorage = uninitializedStorage
+	// ActionsLogCold represents the cold storage tier of actions logs
+	ActionsLogCold ObjectStorage = uninitializedStorage
 	// Actions Artifacts represents actions artifacts storage
 	ActionsArtifacts ObjectStorage = uninitializedStorage
 )
@@ -214,12 +216,21 @@ func initActions() (err error) {
 	if !setting.Actions.Enabled {
 		Actions = discardStorage("Actions isn't enabled")
 		ActionsArtifacts = discardStorage("ActionsArtifacts isn't enabled")
+		ActionsLogCold = discardStorage("Actions isn't enabled")
 		return nil
 	}
 	log.Info("Initialising Actions storage with type: %s", setting.Actions.LogStorage.Type)
 	if Actions, err = NewStorage(setting.Actions.LogStorage.Type, setting.Actions.LogStorage); err != nil {
 		return err
 	}
+	if setting.Actions.LogColdStorage == nil {
+		ActionsLogCold = discardStorage("ActionsLogCold isn't enabled")
+	} else {
+		log.Info("Initialising ActionsLogCold storage with type: %s", setting.Actions.LogColdStorage.Type)
+		if ActionsLogCold, err = NewStorage(setting.Actions.LogColdStorage.Type, setting.Actions.LogColdStorage); err != nil {
+			return err
+		}
+	}
 	log.Info("Initialising ActionsArtifacts storage with type: %s", setting.Actions.ArtifactStorage.Type)
 	ActionsArtifacts, err = NewStorage(setting.Actions.ArtifactStorage.Type, setting.Actions.ArtifactStorage)
 	return err
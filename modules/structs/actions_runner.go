@@ -0,0 +1,55 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// ActionRunner represents a runner of actions
+type ActionRunner struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// the status of the runner, one of "offline", "idle" and "active"
+	Status  string   `json:"status"`
+	Version string   `json:"version"`
+	Labels  []string `json:"labels"`
+	// ephemeral runners can only pick up one task, and they are deleted when the task is done
+	Ephemeral bool `json:"ephemeral"`
+	// swagger:strfmt date-time
+	LastOnline time.Time `json:"last_online"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created"`
+}
+
+// ActionRunnersResponse returns ActionRunners
+type ActionRunnersResponse struct {
+	Entries    []*ActionRunner `json:"runners"`
+	TotalCount int64           `json:"total_count"`
+}
+
+// EditActionRunnerOption options for editing a runner
+// swagger:model
+type EditActionRunnerOption struct {
+	Description *string `json:"description"`
+	// the labels of the runner, note that they will be replaced with the labels declared by the runner when it restarts
+	Labels *[]string `json:"labels"`
+}
+
+// GenerateActionRunnerJITConfigOption options for generating the just-in-time config of an ephemeral runner
+// swagger:model
+type GenerateActionRunnerJITConfigOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// required: true
+	Labels []string `json:"labels" binding:"Required"`
+}
+
+// ActionRunnerJITConfig represents the just-in-time config of an ephemeral runner
+type ActionRunnerJITConfig struct {
+	Runner *ActionRunner `json:"runner"`
+	// the base64 encoded registration file of the runner, the runner can be started with it without registering
+	EncodedJITConfig string `json:"encoded_jit_config"`
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// ActionUsage represents the usage of actions of an owner in a month
+type ActionUsage struct {
+	// the month of the usage, like "2024-10"
+	Month string `json:"month"`
+	// the number of the finished jobs
+	Jobs int64 `json:"jobs"`
+	// the total execution duration of the jobs in seconds
+	Duration int64 `json:"duration"`
+	// the billable minutes, the duration of every job is rounded up to the nearest whole minute
+	Minutes int64 `json:"minutes"`
+	// the soft quota of the minutes per month, 0 means no quota
+	SoftQuota     int64              `json:"soft_quota"`
+	QuotaExceeded bool               `json:"quota_exceeded"`
+	Repositories  []*ActionRepoUsage `json:"repositories"`
+}
+
+// ActionRepoUsage represents the usage of actions of a repository in a month
+type ActionRepoUsage struct {
+	RepoID int64 `json:"repo_id"`
+	// the full name of the repository, it's empty if the repository has been deleted
+	RepoName string `json:"repo_name"`
+	Jobs     int64  `json:"jobs"`
+	Duration int64  `json:"duration"`
+	Minutes  int64  `json:"minutes"`
+}
+
+// ActionOwnerUsage represents the usage of actions of an owner in a month
+type ActionOwnerUsage struct {
+	OwnerID int64 `json:"owner_id"`
+	// the name of the owner, it's empty if the owner has been deleted
+	OwnerName     string `json:"owner_name"`
+	Jobs          int64  `json:"jobs"`
+	Duration      int64  `json:"duration"`
+	Minutes       int64  `json:"minutes"`
+	SoftQuota     int64  `json:"soft_quota"`
+	QuotaExceeded bool   `json:"quota_exceeded"`
+}
+
+// EditActionUsageQuotaOption options for editing the soft quota of the actions minutes of an owner
+// swagger:model
+type EditActionUsageQuotaOption struct {
+	// the soft quota of the minutes per month, 0 means no quota
+	SoftQuota int64 `json:"soft_quota" binding:"Min(0)"`
+}
@@ -495,6 +495,55 @@ func (p *PackagePayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
 
+// HookWorkflowAction represents the action of the status update of a workflow run or a workflow job
+type HookWorkflowAction string
+
+const (
+	// HookWorkflowRequested a workflow run is requested
+	HookWorkflowRequested HookWorkflowAction = "requested"
+	// HookWorkflowQueued a workflow job is queued
+	HookWorkflowQueued HookWorkflowAction = "queued"
+	// HookWorkflowInProgress a workflow run or a workflow job is in progress
+	HookWorkflowInProgress HookWorkflowAction = "in_progress"
+	// HookWorkflowCompleted a workflow run or a workflow job is completed
+	HookWorkflowCompleted HookWorkflowAction = "completed"
+)
+
+// WorkflowRunPayload represents a payload of the status update of a workflow run
+type WorkflowRunPayload struct {
+	Action       HookWorkflowAction `json:"action"`
+	Workflow     *PayloadWorkflow   `json:"workflow"`
+	WorkflowRun  *ActionWorkflowRun `json:"workflow_run"`
+	Repository   *Repository        `json:"repository"`
+	Organization *User              `json:"organization,omitempty"`
+	Sender       *User              `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *WorkflowRunPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// WorkflowJobPayload represents a payload of the status update of a workflow job
+type WorkflowJobPayload struct {
+	Action       HookWorkflowAction `json:"action"`
+	WorkflowJob  *ActionWorkflowJob `json:"workflow_job"`
+	Repository   *Repository        `json:"repository"`
+	Organization *User              `json:"organization,omitempty"`
+	Sender       *User              `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *WorkflowJobPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// PayloadWorkflow represents the workflow of a workflow run in a payload
+type PayloadWorkflow struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
 // WorkflowDispatchPayload represents a workflow dispatch payload
 type WorkflowDispatchPayload struct {
 	Workflow   string         `json:"workflow"`
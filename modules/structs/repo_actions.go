@@ -32,3 +32,104 @@ type ActionTaskResponse struct {
 	Entries    []*ActionTask `json:"workflow_runs"`
 	TotalCount int64         `json:"total_count"`
 }
+
+// ActionWorkflowRun represents a workflow run of actions
+type ActionWorkflowRun struct {
+	ID           int64  `json:"id"`
+	RunNumber    int64  `json:"run_number"`
+	RunAttempt   int64  `json:"run_attempt"`
+	DisplayTitle string `json:"display_title"`
+	WorkflowID   string `json:"workflow_id"`
+	Event        string `json:"event"`
+	Status       string `json:"status"`
+	// the conclusion of the run, it's empty if the run isn't completed
+	Conclusion string `json:"conclusion"`
+	// the reason why the run was cancelled automatically, e.g. "branch_deleted" or "pull_request_closed"
+	CancelReason      string `json:"cancel_reason,omitempty"`
+	HeadBranch        string `json:"head_branch"`
+	HeadSHA           string `json:"head_sha"`
+	IsForkPullRequest bool   `json:"is_fork_pull_request"`
+	NeedApproval      bool   `json:"need_approval"`
+	Actor             *User  `json:"actor"`
+	URL               string `json:"url"`
+	HTMLURL           string `json:"html_url"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	UpdatedAt time.Time `json:"updated_at"`
+	// the time when the run started, it's omitted if the run hasn't started
+	// swagger:strfmt date-time
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// the time when the run completed, it's omitted if the run isn't completed
+	// swagger:strfmt date-time
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// ActionWorkflowJob represents a job of a workflow run
+type ActionWorkflowJob struct {
+	ID         int64  `json:"id"`
+	RunID      int64  `json:"run_id"`
+	RunURL     string `json:"run_url"`
+	RunAttempt int64  `json:"run_attempt"`
+	Name       string `json:"name"`
+	// the name of the workflow of the run
+	WorkflowName string `json:"workflow_name"`
+	HeadBranch   string `json:"head_branch"`
+	HeadSHA      string `json:"head_sha"`
+	Status       string `json:"status"`
+	// the conclusion of the job, it's empty if the job isn't completed
+	Conclusion string   `json:"conclusion"`
+	Labels     []string `json:"labels"`
+	// the runner which runs the job, it's 0 if the job hasn't been picked by a runner
+	RunnerID   int64                 `json:"runner_id"`
+	RunnerName string                `json:"runner_name"`
+	Steps      []*ActionWorkflowStep `json:"steps"`
+	URL        string                `json:"url"`
+	HTMLURL    string                `json:"html_url"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	StartedAt time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ActionWorkflowStep represents a step of a workflow job
+type ActionWorkflowStep struct {
+	Name   string `json:"name"`
+	Number int64  `json:"number"`
+	Status string `json:"status"`
+	// the conclusion of the step, it's empty if the step isn't completed
+	Conclusion string `json:"conclusion"`
+	// swagger:strfmt date-time
+	StartedAt time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ActionWorkflowRunsResponse returns ActionWorkflowRuns
+type ActionWorkflowRunsResponse struct {
+	Entries    []*ActionWorkflowRun `json:"workflow_runs"`
+	TotalCount int64                `json:"total_count"`
+}
+
+// CreateActionWorkflowDispatch represents the payload for triggering a workflow_dispatch event
+// swagger:model
+type CreateActionWorkflowDispatch struct {
+	// the git reference of the run, e.g. a branch name or a tag name
+	// required: true
+	Ref string `json:"ref" binding:"Required"`
+	// the inputs of the workflow_dispatch event, the default values are used for the inputs which are not provided
+	Inputs map[string]string `json:"inputs"`
+}
+
+// CancelActionRunsOption options to cancel the queued and running workflow runs, at least one of them is required
+// swagger:model
+type CancelActionRunsOption struct {
+	// the branch name of the runs
+	Branch string `json:"branch"`
+	// the index of the pull request of the runs
+	PullRequest int64 `json:"pull_request"`
+	// the workflow file name of the runs, e.g. "build.yml"
+	Workflow string `json:"workflow"`
+}
@@ -40,6 +40,7 @@ type BranchProtection struct {
 	MergeWhitelistTeams           []string `json:"merge_whitelist_teams"`
 	EnableStatusCheck             bool     `json:"enable_status_check"`
 	StatusCheckContexts           []string `json:"status_check_contexts"`
+	RequiredWorkflows             []string `json:"required_workflows"`
 	RequiredApprovals             int64    `json:"required_approvals"`
 	EnableApprovalsWhitelist      bool     `json:"enable_approvals_whitelist"`
 	ApprovalsWhitelistUsernames   []string `json:"approvals_whitelist_username"`
@@ -78,6 +79,7 @@ type CreateBranchProtectionOption struct {
 	MergeWhitelistTeams           []string `json:"merge_whitelist_teams"`
 	EnableStatusCheck             bool     `json:"enable_status_check"`
 	StatusCheckContexts           []string `json:"status_check_contexts"`
+	RequiredWorkflows             []string `json:"required_workflows"`
 	RequiredApprovals             int64    `json:"required_approvals"`
 	EnableApprovalsWhitelist      bool     `json:"enable_approvals_whitelist"`
 	ApprovalsWhitelistUsernames   []string `json:"approvals_whitelist_username"`
@@ -109,6 +111,7 @@ type EditBranchProtectionOption struct {
 	MergeWhitelistTeams           []string `json:"merge_whitelist_teams"`
 	EnableStatusCheck             *bool    `json:"enable_status_check"`
 	StatusCheckContexts           []string `json:"status_check_contexts"`
+	RequiredWorkflows             []string `json:"required_workflows"`
 	RequiredApprovals             *int64   `json:"required_approvals"`
 	EnableApprovalsWhitelist      *bool    `json:"enable_approvals_whitelist"`
 	ApprovalsWhitelistUsernames   []string `json:"approvals_whitelist_username"`
@@ -35,3 +35,14 @@ type GeneralAttachmentSettings struct {
 	MaxSize      int64  `json:"max_size"`
 	MaxFiles     int    `json:"max_files"`
 }
+
+// GeneralActionsSettings contains global Actions settings exposed by API
+type GeneralActionsSettings struct {
+	Enabled bool `json:"enabled"`
+	// the runners below the minimum version are outdated, it's empty if there is no requirement
+	MinRunnerVersion string `json:"min_runner_version"`
+	// the runners below the recommended version should be updated, it's empty if there is no recommendation
+	RecommendedRunnerVersion string `json:"recommended_runner_version"`
+	// whether the outdated runners are refused to pick up jobs
+	RejectOutdatedRunners bool `json:"reject_outdated_runners"`
+}
@@ -32,6 +32,8 @@ const (
 	HookEventRelease                   HookEventType = "release"
 	HookEventPackage                   HookEventType = "package"
 	HookEventSchedule                  HookEventType = "schedule"
+	HookEventWorkflowRun               HookEventType = "workflow_run"
+	HookEventWorkflowJob               HookEventType = "workflow_job"
 )
 
 // Event returns the HookEventType as an event string
@@ -64,6 +66,10 @@ func (h HookEventType) Event() string {
 		return "repository"
 	case HookEventRelease:
 		return "release"
+	case HookEventWorkflowRun:
+		return "workflow_run"
+	case HookEventWorkflowJob:
+		return "workflow_job"
 	}
 	return ""
 }
@@ -207,9 +207,10 @@ func (r artifactV4Routes) verifySignature(ctx *ArtifactContext, endp string) (*a
 	return task, artifactName, true
 }
 
-func (r *artifactV4Routes) getArtifactByName(ctx *ArtifactContext, runID int64, name string) (*actions.ActionArtifact, error) {
+func (r *artifactV4Routes) getArtifactByName(ctx *ArtifactContext, runID, runAttempt int64, name string) (*actions.ActionArtifact, error) {
 	var art actions.ActionArtifact
-	has, err := db.GetEngine(ctx).Where("run_id = ? AND artifact_name = ? AND artifact_path = ? AND content_encoding = ?", runID, name, name+".zip", ArtifactV4ContentEncoding).Get(&art)
+	has, err := db.GetEngine(ctx).Where("run_id = ? AND run_attempt <= ? AND artifact_name = ? AND artifact_path = ? AND content_encoding = ?", runID, runAttempt, name, name+".zip", ArtifactV4ContentEncoding).
+		Desc("run_attempt").Get(&art)
 	if err != nil {
 		return nil, err
 	} else if !has {
@@ -294,7 +295,7 @@ func (r *artifactV4Routes) uploadArtifact(ctx *ArtifactContext) {
 	switch comp {
 	case "block", "appendBlock":
 		// get artifact by name
-		artifact, err := r.getArtifactByName(ctx, task.Job.RunID, artifactName)
+		artifact, err := r.getArtifactByName(ctx, task.Job.RunID, task.RunAttempt, artifactName)
 		if err != nil {
 			log.Error("Error artifact not found: %v", err)
 			ctx.Error(http.StatusNotFound, "Error artifact not found")
@@ -337,7 +338,7 @@ func (r *artifactV4Routes) finalizeArtifact(ctx *ArtifactContext) {
 	}
 
 	// get artifact by name
-	artifact, err := r.getArtifactByName(ctx, runID, req.Name)
+	artifact, err := r.getArtifactByName(ctx, runID, ctx.ActionTask.RunAttempt, req.Name)
 	if err != nil {
 		log.Error("Error artifact not found: %v", err)
 		ctx.Error(http.StatusNotFound, "Error artifact not found")
@@ -383,7 +384,7 @@ func (r *artifactV4Routes) listArtifacts(ctx *ArtifactContext) {
 		return
 	}
 
-	artifacts, err := db.Find[actions.ActionArtifact](ctx, actions.FindArtifactsOptions{RunID: runID})
+	artifacts, err := db.Find[actions.ActionArtifact](ctx, actions.FindArtifactsOptions{RunID: runID, RunAttempt: ctx.ActionTask.RunAttempt})
 	if err != nil {
 		log.Error("Error getting artifacts: %v", err)
 		ctx.Error(http.StatusInternalServerError, err.Error())
@@ -439,7 +440,7 @@ func (r *artifactV4Routes) getSignedArtifactURL(ctx *ArtifactContext) {
 	artifactName := req.Name
 
 	// get artifact by name
-	artifact, err := r.getArtifactByName(ctx, runID, artifactName)
+	artifact, err := r.getArtifactByName(ctx, runID, ctx.ActionTask.RunAttempt, artifactName)
 	if err != nil {
 		log.Error("Error artifact not found: %v", err)
 		ctx.Error(http.StatusNotFound, "Error artifact not found")
@@ -467,7 +468,7 @@ func (r *artifactV4Routes) downloadArtifact(ctx *ArtifactContext) {
 	}
 
 	// get artifact by name
-	artifact, err := r.getArtifactByName(ctx, task.Job.RunID, artifactName)
+	artifact, err := r.getArtifactByName(ctx, task.Job.RunID, task.RunAttempt, artifactName)
 	if err != nil {
 		log.Error("Error artifact not found: %v", err)
 		ctx.Error(http.StatusNotFound, "Error artifact not found")
@@ -491,14 +492,14 @@ func (r *artifactV4Routes) deleteArtifact(ctx *ArtifactContext) {
 	}
 
 	// get artifact by name
-	artifact, err := r.getArtifactByName(ctx, runID, req.Name)
+	artifact, err := r.getArtifactByName(ctx, runID, ctx.ActionTask.RunAttempt, req.Name)
 	if err != nil {
 		log.Error("Error artifact not found: %v", err)
 		ctx.Error(http.StatusNotFound, "Error artifact not found")
 		return
 	}
 
-	err = actions.SetArtifactNeedDelete(ctx, runID, req.Name)
+	err = actions.SetArtifactNeedDelete(ctx, runID, ctx.ActionTask.RunAttempt, req.Name)
 	if err != nil {
 		log.Error("Error deleting artifacts: %v", err)
 		ctx.Error(http.StatusInternalServerError, err.Error())
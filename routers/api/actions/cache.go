@@ -0,0 +1,365 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// GitHub Actions Cache API Simple Description
+//
+// The jobs use this server when the runner sets its URL, `{ROOT_URL}api/actions_cache/`, as ACTIONS_CACHE_URL,
+// e.g. with the `cache.external_server` option of act_runner. The requests are authorized by ACTIONS_RUNTIME_TOKEN.
+//
+// 1. Restore cache
+// 1.1. Get cache
+// GET: /api/actions_cache/_apis/artifactcache/cache?keys=key1,key2&version=hash
+// Response:
+// 204 if no cache matches
+// {
+//  "result": "hit",
+//  "archiveLocation": "/api/actions_cache/_apis/artifactcache/artifacts/{cache_id}?expires=...&sig=...",
+//  "cacheKey": "key1-suffix"
+// }
+// it matches the keys in order, a key matches a cache with the same key, or the newest cache whose key has it as prefix
+// 1.2. Download cache
+// GET: /api/actions_cache/_apis/artifactcache/artifacts/{cache_id}?expires=...&sig=...
+// the location is signed and doesn't need the authorization header
+//
+// 2. Save cache
+// 2.1. Reserve cache
+// POST: /api/actions_cache/_apis/artifactcache/caches
+// Request:
+// {
+//  "key": "key1",
+//  "version": "hash",
+//  "cacheSize": 1024
+// }
+// Response:
+// {
+//  "cacheId": 1
+// }
+// 409 if the cache with the same key and version already exists
+// 2.2. Upload cache
+// PATCH: /api/actions_cache/_apis/artifactcache/caches/{cache_id}
+// it uploads chunks with headers:
+//    content-range: bytes 0-1023/*
+// 2.3. Commit cache
+// POST: /api/actions_cache/_apis/artifactcache/caches/{cache_id}
+// Request:
+// {
+//  "size": 1024
+// }
+// it merges all chunks to one file, and evicts the least recently used caches if the quota of the repository is exceeded
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+)
+
+const (
+	CacheRouteBase         = "/api/actions_cache"
+	artifactCacheRouteBase = "/_apis/artifactcache"
+)
+
+type cacheRoutes struct {
+	prefix string
+	fs     storage.ObjectStorage
+}
+
+func CacheRoutes(prefix string) *web.Router {
+	m := web.NewRouter()
+
+	r := cacheRoutes{
+		prefix: prefix,
+		fs:     storage.ActionsArtifacts,
+	}
+
+	m.Group(artifactCacheRouteBase, func() {
+		m.Get("/cache", r.getCache)
+		m.Post("/caches", r.reserveCache)
+		m.Combo("/caches/{cache_id}").Patch(r.uploadCache).Post(r.commitCache)
+	}, ArtifactContexter())
+	m.Group(artifactCacheRouteBase, func() {
+		m.Get("/artifacts/{cache_id}", r.downloadCache)
+	}, ArtifactV4Contexter())
+
+	return m
+}
+
+// buildSignature signs the download link of the cache of the repository, the fields are separated
+// so the signature of a cache can't be reused by another one whose fields are concatenated to the same string
+func (r cacheRoutes) buildSignature(expires string, cacheID, repoID int64) []byte {
+	mac := hmac.New(sha256.New, setting.GetGeneralTokenSigningSecret())
+	_, _ = fmt.Fprintf(mac, "ActionsCache\n%d:%s\n%d\n%d", len(expires), expires, cacheID, repoID)
+	return mac.Sum(nil)
+}
+
+func (r cacheRoutes) buildDownloadURL(ctx *ArtifactContext, cache *actions.ActionCache) string {
+	expires := time.Now().Add(60 * time.Minute).Format(time.RFC3339)
+	return strings.TrimSuffix(httplib.GuessCurrentAppURL(ctx), "/") + strings.TrimSuffix(r.prefix, "/") + artifactCacheRouteBase +
+		fmt.Sprintf("/artifacts/%d?expires=%s&sig=%s", cache.ID, url.QueryEscape(expires),
+			base64.URLEncoding.EncodeToString(r.buildSignature(expires, cache.ID, cache.RepoID)))
+}
+
+func (r cacheRoutes) parseCacheID(ctx *ArtifactContext) (int64, bool) {
+	cacheID, err := strconv.ParseInt(ctx.PathParam("cache_id"), 10, 64)
+	if err != nil {
+		log.Error("Error parse cache id: %v", err)
+		ctx.Error(http.StatusBadRequest, "Error parse cache id")
+		return 0, false
+	}
+	return cacheID, true
+}
+
+// getIncompleteCache returns the reserved cache of the repository which the task belongs to
+func (r cacheRoutes) getIncompleteCache(ctx *ArtifactContext) (*actions.ActionCache, bool) {
+	cacheID, ok := r.parseCacheID(ctx)
+	if !ok {
+		return nil, false
+	}
+	cache, err := actions.GetCacheByID(ctx, ctx.ActionTask.RepoID, cacheID)
+	if errors.Is(err, util.ErrNotExist) {
+		ctx.Error(http.StatusNotFound, "Error cache not found")
+		return nil, false
+	} else if err != nil {
+		log.Error("Error get cache %d: %v", cacheID, err)
+		ctx.Error(http.StatusInternalServerError, "Error get cache")
+		return nil, false
+	}
+	if cache.Complete {
+		ctx.Error(http.StatusBadRequest, "Error cache is already committed")
+		return nil, false
+	}
+	return cache, true
+}
+
+type getCacheResponse struct {
+	Result          string `json:"result"`
+	ArchiveLocation string `json:"archiveLocation"`
+	CacheKey        string `json:"cacheKey"`
+}
+
+func (r cacheRoutes) getCache(ctx *ArtifactContext) {
+	task := ctx.ActionTask
+	keys := strings.Split(ctx.Req.URL.Query().Get("keys"), ",")
+	version := ctx.Req.URL.Query().Get("version")
+	for i := range keys {
+		keys[i] = strings.TrimSpace(keys[i])
+	}
+
+	if err := task.Job.LoadRun(ctx); err != nil {
+		log.Error("Error load run: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error load run")
+		return
+	}
+	refs, err := actions_service.GetCacheRefsOfRun(ctx, task.Job.Run)
+	if err != nil {
+		log.Error("Error get refs of run: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error get refs of run")
+		return
+	}
+
+	cache, err := actions.FindCacheToRestore(ctx, task.RepoID, refs, keys, version)
+	if errors.Is(err, util.ErrNotExist) {
+		ctx.Status(http.StatusNoContent)
+		return
+	} else if err != nil {
+		log.Error("Error find cache: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error find cache")
+		return
+	}
+
+	cache.UsedUnix = timeutil.TimeStampNow()
+	if err := actions.UpdateCache(ctx, cache, "used_unix"); err != nil {
+		log.Error("Error update cache %d: %v", cache.ID, err)
+		ctx.Error(http.StatusInternalServerError, "Error update cache")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getCacheResponse{
+		Result:          "hit",
+		ArchiveLocation: r.buildDownloadURL(ctx, cache),
+		CacheKey:        cache.CacheKey,
+	})
+}
+
+type reserveCacheRequest struct {
+	Key       string `json:"key"`
+	Version   string `json:"version"`
+	CacheSize int64  `json:"cacheSize"`
+}
+
+type reserveCacheResponse struct {
+	CacheID int64 `json:"cacheId"`
+}
+
+func (r cacheRoutes) reserveCache(ctx *ArtifactContext) {
+	task := ctx.ActionTask
+
+	var req reserveCacheRequest
+	if err := json.NewDecoder(ctx.Req.Body).Decode(&req); err != nil {
+		log.Error("Error decode request body: %v", err)
+		ctx.Error(http.StatusBadRequest, "Error decode request body")
+		return
+	}
+	if req.Key == "" {
+		ctx.Error(http.StatusBadRequest, "Error cache key is empty")
+		return
+	}
+	if setting.Actions.CacheRepoQuota >= 0 && req.CacheSize > setting.Actions.CacheRepoQuota {
+		ctx.Error(http.StatusBadRequest, "Error cache size exceeds the quota")
+		return
+	}
+
+	if err := task.Job.LoadRun(ctx); err != nil {
+		log.Error("Error load run: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error load run")
+		return
+	}
+	ref := task.Job.Run.Ref
+
+	if cache, err := actions.GetCacheByKey(ctx, task.RepoID, ref, req.Key, req.Version); err == nil {
+		if cache.Complete {
+			ctx.Error(http.StatusConflict, "Error cache already exists")
+			return
+		}
+		// the previous upload didn't complete, start over
+		if err := actions_service.DeleteCache(ctx, cache); err != nil {
+			log.Error("Error delete cache %d: %v", cache.ID, err)
+			ctx.Error(http.StatusInternalServerError, "Error delete cache")
+			return
+		}
+	} else if !errors.Is(err, util.ErrNotExist) {
+		log.Error("Error get cache: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error get cache")
+		return
+	}
+
+	cache := &actions.ActionCache{
+		RepoID:   task.RepoID,
+		Ref:      ref,
+		CacheKey: req.Key,
+		Version:  req.Version,
+	}
+	if err := actions.InsertCache(ctx, cache); err != nil {
+		log.Error("Error insert cache: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error insert cache")
+		return
+	}
+	ctx.JSON(http.StatusOK, reserveCacheResponse{CacheID: cache.ID})
+}
+
+func (r cacheRoutes) uploadCache(ctx *ArtifactContext) {
+	cache, ok := r.getIncompleteCache(ctx)
+	if !ok {
+		return
+	}
+
+	// parse content-range header, format: bytes 0-1023/*
+	contentRange := ctx.Req.Header.Get("Content-Range")
+	var start, end int64
+	if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/", &start, &end); err != nil {
+		log.Error("Error parse content range %q: %v", contentRange, err)
+		ctx.Error(http.StatusBadRequest, "Error parse content range")
+		return
+	}
+	if setting.Actions.CacheRepoQuota >= 0 && end >= setting.Actions.CacheRepoQuota {
+		ctx.Error(http.StatusBadRequest, "Error cache size exceeds the quota")
+		return
+	}
+
+	if err := actions_service.SaveCacheChunk(cache, start, end, ctx.Req.Body); err != nil {
+		log.Error("Error save chunk of cache %d: %v", cache.ID, err)
+		ctx.Error(http.StatusInternalServerError, "Error save chunk")
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+type commitCacheRequest struct {
+	Size int64 `json:"size"`
+}
+
+func (r cacheRoutes) commitCache(ctx *ArtifactContext) {
+	cache, ok := r.getIncompleteCache(ctx)
+	if !ok {
+		return
+	}
+
+	var req commitCacheRequest
+	if err := json.NewDecoder(ctx.Req.Body).Decode(&req); err != nil {
+		log.Error("Error decode request body: %v", err)
+		ctx.Error(http.StatusBadRequest, "Error decode request body")
+		return
+	}
+
+	if err := actions_service.CommitCache(ctx, cache, req.Size); errors.Is(err, actions_service.ErrCacheChunksIncomplete) {
+		ctx.Error(http.StatusBadRequest, "Error cache chunks are incomplete")
+		return
+	} else if err != nil {
+		log.Error("Error commit cache %d: %v", cache.ID, err)
+		ctx.Error(http.StatusInternalServerError, "Error commit cache")
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (r cacheRoutes) downloadCache(ctx *ArtifactContext) {
+	cacheID, ok := r.parseCacheID(ctx)
+	if !ok {
+		return
+	}
+	cache, exist, err := db.GetByID[actions.ActionCache](ctx, cacheID)
+	if err != nil {
+		log.Error("Error get cache %d: %v", cacheID, err)
+		ctx.Error(http.StatusInternalServerError, "Error get cache")
+		return
+	}
+
+	// the link is signed with the repository of the cache, so it can't be used to download the caches of other repositories
+	expires := ctx.Req.URL.Query().Get("expires")
+	sig, _ := base64.URLEncoding.DecodeString(ctx.Req.URL.Query().Get("sig"))
+	if !exist || !hmac.Equal(sig, r.buildSignature(expires, cache.ID, cache.RepoID)) {
+		ctx.Error(http.StatusUnauthorized, "Error unauthorized")
+		return
+	}
+	if t, err := time.Parse(time.RFC3339, expires); err != nil || t.Before(time.Now()) {
+		ctx.Error(http.StatusUnauthorized, "Error link expired")
+		return
+	}
+	if !cache.Complete {
+		ctx.Error(http.StatusNotFound, "Error cache not found")
+		return
+	}
+
+	f, err := r.fs.Open(cache.StoragePath)
+	if err != nil {
+		log.Error("Error open cache %d: %v", cache.ID, err)
+		ctx.Error(http.StatusInternalServerError, "Error open cache")
+		return
+	}
+	defer f.Close()
+
+	ctx.Resp.Header().Set("Content-Type", "application/octet-stream")
+	ctx.Resp.Header().Set("Content-Length", strconv.FormatInt(cache.Size, 10))
+	_, _ = io.Copy(ctx.Resp, f)
+}
@@ -0,0 +1,19 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheRoutesBuildSignature(t *testing.T) {
+	r := cacheRoutes{}
+	expires := "2024-01-01T00:00:00Z"
+	assert.Equal(t, r.buildSignature(expires, 1, 23), r.buildSignature(expires, 1, 23))
+	// the fields mustn't be concatenated to the same message
+	assert.NotEqual(t, r.buildSignature(expires, 1, 23), r.buildSignature(expires, 12, 3))
+	assert.NotEqual(t, r.buildSignature(expires, 12, 3), r.buildSignature(expires+"1", 2, 3))
+}
@@ -13,6 +13,7 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	actions_service "code.gitea.io/gitea/services/actions"
 
@@ -136,6 +137,9 @@ func (s *Service) FetchTask(
 	req *connect.Request[runnerv1.FetchTaskRequest],
 ) (*connect.Response[runnerv1.FetchTaskResponse], error) {
 	runner := GetRunner(ctx)
+	if setting.Actions.RejectOutdatedRunners && runner.IsOutdated() {
+		return nil, status.Errorf(codes.FailedPrecondition, "runner version %s is below the minimum version %s, please update the runner", runner.Version, setting.Actions.MinRunnerVersion)
+	}
 
 	var task *runnerv1.Task
 	tasksVersion := req.Msg.TasksVersion // task version from runner
@@ -179,6 +183,7 @@ func (s *Service) UpdateTask(
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "update task: %v", err)
 	}
+	actions_service.NotifyJobUpdated(task.JobID)
 
 	for k, v := range req.Msg.Outputs {
 		if len(k) > 255 {
@@ -218,9 +223,17 @@ func (s *Service) UpdateTask(
 	}
 
 	if req.Msg.State.Result != runnerv1.Result_RESULT_UNSPECIFIED {
+		actions_service.NotifyWorkflowJobsStatusUpdate(ctx, task.Job)
 		if err := actions_service.EmitJobsIfReady(task.Job.RunID); err != nil {
 			log.Error("Emit ready jobs of run %d: %v", task.Job.RunID, err)
 		}
+
+		// the ephemeral runner has done its only task, so it's deregistered
+		if runner := GetRunner(ctx); runner.Ephemeral && task.RunnerID == runner.ID {
+			if err := actions_model.DeleteRunner(ctx, runner.ID); err != nil {
+				log.Error("Delete ephemeral runner %d: %v", runner.ID, err)
+			}
+		}
 	}
 
 	return connect.NewResponse(&runnerv1.UpdateTaskResponse{
@@ -254,11 +267,21 @@ func (s *Service) UpdateLog(
 		return nil, status.Errorf(codes.AlreadyExists, "log file has been archived")
 	}
 
+	masker, err := actions_service.GetTaskLogMasker(task)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get log masker: %v", err)
+	}
 	rows := req.Msg.Rows[ack-req.Msg.Index:]
+	// mask the secrets before persisting, so they never get into the logs or the annotations
+	masksAdded := masker.MaskRows(rows)
 	ns, err := actions.WriteLogs(ctx, task.LogFilename, task.LogSize, rows)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "write logs: %v", err)
 	}
+	if err := actions_service.CreateAnnotationsFromLogs(ctx, task, rows, ack); err != nil {
+		// the annotations are only for display, so don't fail the logs
+		log.Error("CreateAnnotationsFromLogs for task %d: %v", task.ID, err)
+	}
 	task.LogLength += int64(len(rows))
 	for _, n := range ns {
 		task.LogIndexes = append(task.LogIndexes, task.LogSize)
@@ -276,9 +299,17 @@ func (s *Service) UpdateLog(
 		}
 	}
 
-	if err := actions_model.UpdateTask(ctx, task, "log_indexes", "log_length", "log_size", "log_in_storage"); err != nil {
+	cols := []string{"log_indexes", "log_length", "log_size", "log_in_storage"}
+	if masksAdded {
+		if err := actions_service.SetTaskLogMasker(task, masker); err != nil {
+			return nil, status.Errorf(codes.Internal, "set log masker: %v", err)
+		}
+		cols = append(cols, "log_masks")
+	}
+	if err := actions_model.UpdateTask(ctx, task, cols...); err != nil {
 		return nil, status.Errorf(codes.Internal, "update task: %v", err)
 	}
+	actions_service.NotifyJobUpdated(task.JobID)
 	if remove != nil {
 		remove()
 	}
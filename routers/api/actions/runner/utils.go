@@ -6,6 +6,7 @@ package runner
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
@@ -15,6 +16,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/services/actions"
 
@@ -23,17 +25,61 @@ import (
 )
 
 func pickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv1.Task, bool, error) {
-	t, ok, err := actions_model.CreateTaskForRunner(ctx, runner)
-	if err != nil {
-		return nil, false, fmt.Errorf("CreateTaskForRunner: %w", err)
+	var (
+		t          *actions_model.ActionTask
+		ok         bool
+		secrets    map[string]string
+		disallowed []*actions.DisallowedAction
+	)
+	// a task mustn't be assigned without the masks of its secrets, or the logs uploaded for it would leak them
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		t, ok, err = actions_model.CreateTaskForRunner(ctx, runner)
+		if err != nil {
+			return fmt.Errorf("CreateTaskForRunner: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+		// the runner downloads the actions by itself, so the task of a job using any disallowed action
+		// is failed in the same transaction, it's never seen as assigned to the runner
+		disallowed, err = actions.FindDisallowedActions(ctx, t.Job)
+		if err != nil {
+			return fmt.Errorf("FindDisallowedActions: %w", err)
+		}
+		if len(disallowed) > 0 {
+			if err := actions.RejectTaskOfDisallowedActions(ctx, t, disallowed); err != nil {
+				return fmt.Errorf("RejectTaskOfDisallowedActions: %w", err)
+			}
+			return nil
+		}
+		secrets, err = secret_model.GetSecretsOfTask(ctx, t)
+		if err != nil {
+			return fmt.Errorf("GetSecretsOfTask: %w", err)
+		}
+		if err := actions.InitTaskLogMasks(ctx, t, secrets); err != nil {
+			return fmt.Errorf("InitTaskLogMasks: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, false, err
 	}
 	if !ok {
 		return nil, false, nil
 	}
+	metrics.ObserveActionsJobWait(t.Started.AsTime().Sub(t.Queued.AsTime()))
 
-	secrets, err := secret_model.GetSecretsOfTask(ctx, t)
-	if err != nil {
-		return nil, false, fmt.Errorf("GetSecretsOfTask: %w", err)
+	if len(disallowed) > 0 {
+		if err := actions.NotifyRejectedTask(ctx, t); err != nil {
+			log.Error("Notify rejected task %d: %v", t.ID, err)
+		}
+		// the ephemeral runner can't pick up another task
+		if runner.Ephemeral {
+			if err := actions_model.DeleteRunner(ctx, runner.ID); err != nil {
+				log.Error("Delete ephemeral runner %d: %v", runner.ID, err)
+			}
+		}
+		return nil, false, nil
 	}
 
 	vars, err := actions_model.GetVariablesOfRun(ctx, t.Job.Run)
@@ -42,6 +88,7 @@ func pickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv
 	}
 
 	actions.CreateCommitStatus(ctx, t.Job)
+	actions.NotifyWorkflowJobsStatusUpdate(ctx, t.Job)
 
 	task := &runnerv1.Task{
 		Id:              t.ID,
@@ -128,7 +175,7 @@ func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
 		"retention_days":    "",                                                   // string, The number of days that workflow run logs and artifacts are kept.
 		"run_id":            fmt.Sprint(t.Job.RunID),                              // string, A unique number for each workflow run within a repository. This number does not change if you re-run the workflow run.
 		"run_number":        fmt.Sprint(t.Job.Run.Index),                          // string, A unique number for each run of a particular workflow in a repository. This number begins at 1 for the workflow's first run, and increments with each new run. This number does not change if you re-run the workflow run.
-		"run_attempt":       fmt.Sprint(t.Job.Attempt),                            // string, A unique number for each attempt of a particular workflow run in a repository. This number begins at 1 for the workflow run's first attempt, and increments with each re-run.
+		"run_attempt":       fmt.Sprint(t.RunAttempt),                             // string, A unique number for each attempt of a particular workflow run in a repository. This number begins at 1 for the workflow run's first attempt, and increments with each re-run.
 		"secret_source":     "Actions",                                            // string, The source of a secret used in a workflow. Possible values are None, Actions, Dependabot, or Codespaces.
 		"server_url":        setting.AppURL,                                       // string, The URL of the GitHub server. For example: https://github.com.
 		"sha":               sha,                                                  // string, The commit SHA that triggered the workflow. The value of this commit SHA depends on the event that triggered the workflow. For more information, see "Events that trigger workflows." For example, ffac537e6cbbf934b08745a378932722df287a53.
@@ -162,6 +209,12 @@ func findTaskNeeds(ctx context.Context, task *actions_model.ActionTask) (map[str
 		return nil, fmt.Errorf("FindRunJobs: %w", err)
 	}
 
+	// the jobs of a reusable workflow refer to each other with the job ids in the called workflow
+	callerPrefix := ""
+	if callerJobID := task.Job.CallerJobID(); callerJobID != "" {
+		callerPrefix = callerJobID + actions_model.ReusableWorkflowJobSeparator
+	}
+
 	ret := make(map[string]*runnerv1.TaskNeed, len(needs))
 	for _, job := range jobs {
 		if !needs.Contains(job.JobID) {
@@ -179,7 +232,7 @@ func findTaskNeeds(ctx context.Context, task *actions_model.ActionTask) (map[str
 		for _, v := range got {
 			outputs[v.OutputKey] = v.OutputValue
 		}
-		ret[job.JobID] = &runnerv1.TaskNeed{
+		ret[strings.TrimPrefix(job.JobID, callerPrefix)] = &runnerv1.TaskNeed{
 			Outputs: outputs,
 			Result:  runnerv1.Result(job.Status),
 		}
@@ -0,0 +1,64 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListActionsUsage lists the usages of actions of all owners in a month
+func ListActionsUsage(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/usage admin adminListActionsUsage
+	// ---
+	// summary: List the usages of actions of all owners in a month, the owners which consume more minutes come first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: month
+	//   in: query
+	//   description: the month of the usages like "2024-10", defaults to the current month
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionOwnerUsageList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.ListOwnerUsages(ctx)
+}
+
+// EditActionsUsageQuota sets the soft quota of the actions minutes of a user or an organization
+func EditActionsUsageQuota(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/users/{username}/actions/usage-quota admin adminEditActionsUsageQuota
+	// ---
+	// summary: Set the soft quota of the actions minutes per month of a user or an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionUsageQuotaOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.EditUsageQuota(ctx, ctx.ContextUser.ID)
+}
@@ -24,3 +24,141 @@ func GetRegistrationToken(ctx *context.APIContext) {
 
 	shared.GetRegistrationToken(ctx, 0, 0)
 }
+
+// CreateRegistrationToken creates a new token to register global runners
+func CreateRegistrationToken(ctx *context.APIContext) {
+	// swagger:operation POST /admin/runners/registration-token admin adminCreateRunnerRegistrationToken
+	// ---
+	// summary: Create a new actions runner registration token for the runners, the previous tokens are invalidated
+	// produces:
+	// - application/json
+	// parameters:
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RegistrationToken"
+
+	shared.CreateRegistrationToken(ctx, 0, 0)
+}
+
+// ListRunners lists the global runners
+func ListRunners(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runners admin adminListRunners
+	// ---
+	// summary: List the actions runners
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerList"
+
+	shared.ListRunners(ctx, 0, 0)
+}
+
+// GetRunner gets one of the global runners
+func GetRunner(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runners/{runner_id} admin adminGetRunner
+	// ---
+	// summary: Get the actions runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunner"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetRunner(ctx, 0, 0)
+}
+
+// EditRunner edits one of the global runners
+func EditRunner(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/runners/{runner_id} admin adminEditRunner
+	// ---
+	// summary: Edit the description or the labels of the actions runner
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionRunnerOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunner"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.EditRunner(ctx, 0, 0)
+}
+
+// DeleteRunner deletes one of the global runners
+func DeleteRunner(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/runners/{runner_id} admin adminDeleteRunner
+	// ---
+	// summary: Delete the actions runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: runner has been deleted
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteRunner(ctx, 0, 0)
+}
+
+// GenerateRunnerJITConfig creates an ephemeral global runner and returns its just-in-time config
+func GenerateRunnerJITConfig(ctx *context.APIContext) {
+	// swagger:operation POST /admin/runners/generate-jitconfig admin adminGenerateRunnerJITConfig
+	// ---
+	// summary: Create an ephemeral global actions runner and get its just-in-time config
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/GenerateActionRunnerJITConfigOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRunnerJITConfig"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GenerateJITConfig(ctx, 0, 0)
+}
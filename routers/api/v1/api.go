@@ -200,17 +200,19 @@ func repoAssignment() func(ctx *context.APIContext) {
 				return
 			}
 
-			if task.IsForkPullRequest {
-				ctx.Repo.Permission.AccessMode = perm.AccessModeRead
-			} else {
-				ctx.Repo.Permission.AccessMode = perm.AccessModeWrite
+			tokenPerms, err := actions_model.GetTaskTokenPermissions(ctx, task)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetTaskTokenPermissions", err)
+				return
 			}
 
 			if err := ctx.Repo.Repository.LoadUnits(ctx); err != nil {
 				ctx.Error(http.StatusInternalServerError, "LoadUnits", err)
 				return
 			}
-			ctx.Repo.Permission.SetUnitsWithDefaultAccessMode(ctx.Repo.Repository.Units, ctx.Repo.Permission.AccessMode)
+			// the token only has the access to the units granted by the `permissions` of the job
+			ctx.Repo.Permission.AccessMode = perm.AccessModeNone
+			ctx.Repo.Permission.SetUnitsWithAccessModes(ctx.Repo.Repository.Units, tokenPerms.UnitAccessModes())
 		} else {
 			ctx.Repo.Permission, err = access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
 			if err != nil {
@@ -859,7 +861,15 @@ func Routes() *web.Router {
 			})
 
 			m.Group("/runners", func() {
-				m.Get("/registration-token", reqToken(), reqChecker, act.GetRegistrationToken)
+				m.Get("", reqToken(), reqChecker, act.ListRunners)
+				m.Combo("/registration-token").
+					Get(reqToken(), reqChecker, act.GetRegistrationToken).
+					Post(reqToken(), reqChecker, act.CreateRegistrationToken)
+				m.Post("/generate-jitconfig", reqToken(), reqChecker, bind(api.GenerateActionRunnerJITConfigOption{}), act.GenerateRunnerJITConfig)
+				m.Combo("/{runner_id}").
+					Get(reqToken(), reqChecker, act.GetRunner).
+					Patch(reqToken(), reqChecker, bind(api.EditActionRunnerOption{}), act.EditRunner).
+					Delete(reqToken(), reqChecker, act.DeleteRunner)
 			})
 		})
 	}
@@ -906,6 +916,7 @@ func Routes() *web.Router {
 				m.Get("/api", settings.GetGeneralAPISettings)
 				m.Get("/attachment", settings.GetGeneralAttachmentSettings)
 				m.Get("/repository", settings.GetGeneralRepoSettings)
+				m.Get("/actions", settings.GetGeneralActionsSettings)
 			})
 		})
 
@@ -989,8 +1000,18 @@ func Routes() *web.Router {
 						Put(bind(api.UpdateVariableOption{}), user.UpdateVariable)
 				})
 
+				m.Get("/usage", reqToken(), user.GetActionsUsage)
+
 				m.Group("/runners", func() {
-					m.Get("/registration-token", reqToken(), user.GetRegistrationToken)
+					m.Get("", reqToken(), user.ListRunners)
+					m.Combo("/registration-token").
+						Get(reqToken(), user.GetRegistrationToken).
+						Post(reqToken(), user.CreateRegistrationToken)
+					m.Post("/generate-jitconfig", reqToken(), bind(api.GenerateActionRunnerJITConfigOption{}), user.GenerateRunnerJITConfig)
+					m.Combo("/{runner_id}").
+						Get(reqToken(), user.GetRunner).
+						Patch(reqToken(), bind(api.EditActionRunnerOption{}), user.EditRunner).
+						Delete(reqToken(), user.DeleteRunner)
 				})
 			})
 
@@ -1179,6 +1200,13 @@ func Routes() *web.Router {
 				}, reqToken(), reqAdmin())
 				m.Group("/actions", func() {
 					m.Get("/tasks", repo.ListActionTasks)
+					m.Group("/runs", func() {
+						m.Get("", repo.ListActionRuns)
+						m.Post("/cancel", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, bind(api.CancelActionRunsOption{}), repo.CancelActionRuns)
+						m.Get("/{run}", repo.GetActionRun)
+						m.Get("/{run}/logs", repo.DownloadActionRunLogs)
+					})
+					m.Post("/workflows/{workflow_id}/dispatches", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, bind(api.CreateActionWorkflowDispatch{}), repo.DispatchActionWorkflow)
 				}, reqRepoReader(unit.TypeActions), context.ReferencesGitRepo(true))
 				m.Group("/keys", func() {
 					m.Combo("").Get(repo.ListDeployKeys).
@@ -1491,6 +1519,8 @@ func Routes() *web.Router {
 				reqOrgOwnership(),
 				org.NewAction(),
 			)
+			m.Get("/actions/usage", reqToken(), reqOrgOwnership(), org.GetActionsUsage)
+			m.Get("/actions/runs", org.ListActionRuns)
 			m.Group("/public_members", func() {
 				m.Get("", org.ListPublicMembers)
 				m.Combo("/{username}").Get(org.IsPublicMember).
@@ -1575,6 +1605,7 @@ func Routes() *web.Router {
 					m.Get("/badges", admin.ListUserBadges)
 					m.Post("/badges", bind(api.UserBadgeOption{}), admin.AddUserBadges)
 					m.Delete("/badges", bind(api.UserBadgeOption{}), admin.DeleteUserBadges)
+					m.Put("/actions/usage-quota", bind(api.EditActionUsageQuotaOption{}), admin.EditActionsUsageQuota)
 				}, context.UserAssignmentAPI())
 			})
 			m.Group("/emails", func() {
@@ -1593,8 +1624,27 @@ func Routes() *web.Router {
 					Patch(bind(api.EditHookOption{}), admin.EditHook).
 					Delete(admin.DeleteHook)
 			})
+			m.Group("/actions", func() {
+				m.Get("/usage", admin.ListActionsUsage)
+				m.Group("/variables", func() {
+					m.Get("", admin.ListVariables)
+					m.Combo("/{variablename}").
+						Get(admin.GetVariable).
+						Delete(admin.DeleteVariable).
+						Post(bind(api.CreateVariableOption{}), admin.CreateVariable).
+						Put(bind(api.UpdateVariableOption{}), admin.UpdateVariable)
+				})
+			})
 			m.Group("/runners", func() {
-				m.Get("/registration-token", admin.GetRegistrationToken)
+				m.Get("", admin.ListRunners)
+				m.Combo("/registration-token").
+					Get(admin.GetRegistrationToken).
+					Post(admin.CreateRegistrationToken)
+				m.Post("/generate-jitconfig", bind(api.GenerateActionRunnerJITConfigOption{}), admin.GenerateRunnerJITConfig)
+				m.Combo("/{runner_id}").
+					Get(admin.GetRunner).
+					Patch(bind(api.EditActionRunnerOption{}), admin.EditRunner).
+					Delete(admin.DeleteRunner)
 			})
 		}, tokenRequiresScopes(auth_model.AccessTokenScopeCategoryAdmin), reqToken(), reqSiteAdmin())
 
@@ -10,6 +10,7 @@ import (
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	secret_model "code.gitea.io/gitea/models/secret"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
@@ -17,6 +18,7 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
 	secret_service "code.gitea.io/gitea/services/secrets"
 )
 
@@ -106,7 +108,7 @@ func (Action) CreateOrUpdateSecret(ctx *context.APIContext) {
 
 	opt := web.GetForm(ctx).(*api.CreateOrUpdateSecretOption)
 
-	_, created, err := secret_service.CreateOrUpdateSecret(ctx, ctx.Org.Organization.ID, 0, ctx.PathParam("secretname"), opt.Data)
+	_, created, err := secret_service.CreateOrUpdateSecret(ctx, ctx.Org.Organization.ID, 0, 0, ctx.PathParam("secretname"), opt.Data)
 	if err != nil {
 		if errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusBadRequest, "CreateOrUpdateSecret", err)
@@ -189,6 +191,174 @@ func (Action) GetRegistrationToken(ctx *context.APIContext) {
 	shared.GetRegistrationToken(ctx, ctx.Org.Organization.ID, 0)
 }
 
+// CreateRegistrationToken creates a new token to register org runners
+func (Action) CreateRegistrationToken(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/runners/registration-token organization orgCreateRunnerRegistrationToken
+	// ---
+	// summary: Create a new actions runner registration token for an organization's runners, the previous tokens are invalidated
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RegistrationToken"
+
+	shared.CreateRegistrationToken(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// ListRunners lists the org runners
+func (Action) ListRunners(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runners organization orgListRunners
+	// ---
+	// summary: List an organization's actions runners
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerList"
+
+	shared.ListRunners(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// GetRunner gets one of the org runners
+func (Action) GetRunner(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runners/{runner_id} organization orgGetRunner
+	// ---
+	// summary: Get an organization's actions runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunner"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetRunner(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// EditRunner edits one of the org runners
+func (Action) EditRunner(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/actions/runners/{runner_id} organization orgEditRunner
+	// ---
+	// summary: Edit the description or the labels of an organization's actions runner
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionRunnerOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunner"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.EditRunner(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// DeleteRunner deletes one of the org runners
+func (Action) DeleteRunner(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/actions/runners/{runner_id} organization orgDeleteRunner
+	// ---
+	// summary: Delete an organization's actions runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: runner has been deleted
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteRunner(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// GenerateRunnerJITConfig creates an ephemeral org runner and returns its just-in-time config
+func (Action) GenerateRunnerJITConfig(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/runners/generate-jitconfig organization orgGenerateRunnerJITConfig
+	// ---
+	// summary: Create an ephemeral actions runner for an organization and get its just-in-time config
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/GenerateActionRunnerJITConfigOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRunnerJITConfig"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GenerateJITConfig(ctx, ctx.Org.Organization.ID, 0)
+}
+
 // ListVariables list org-level variables
 func (Action) ListVariables(ctx *context.APIContext) {
 	// swagger:operation GET /orgs/{org}/actions/variables organization getOrgVariablesList
@@ -471,3 +641,129 @@ type Action struct{}
 func NewAction() actions_service.API {
 	return Action{}
 }
+
+// GetActionsUsage gets the usage of actions of the org in a month
+func GetActionsUsage(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/usage organization orgGetActionsUsage
+	// ---
+	// summary: Get the usage of actions of an organization in a month
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: month
+	//   in: query
+	//   description: the month of the usage like "2024-10", defaults to the current month
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionUsage"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GetUsage(ctx, ctx.Org.Organization.ID)
+}
+
+// ListActionRuns lists the workflow runs of the repositories of an organization
+func ListActionRuns(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runs organization orgListActionRuns
+	// ---
+	// summary: List the workflow runs of the repositories of an organization which the user can read
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: query
+	//   description: name of the repository of the runs
+	//   type: string
+	// - name: workflow
+	//   in: query
+	//   description: workflow file name of the runs, e.g. "build.yml"
+	//   type: string
+	// - name: status
+	//   in: query
+	//   description: status of the runs
+	//   type: string
+	//   enum: [unknown, waiting, running, success, failure, cancelled, skipped, blocked]
+	// - name: actor
+	//   in: query
+	//   description: username of the user who triggered the runs
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: only show the runs created on or after the date, e.g. "2024-10-01"
+	//   type: string
+	//   format: date
+	// - name: until
+	//   in: query
+	//   description: only show the runs created on or before the date, e.g. "2024-10-31"
+	//   type: string
+	//   format: date
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results, default maximum page size is 50
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRunsList"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !setting.Actions.Enabled {
+		ctx.NotFound()
+		return
+	}
+
+	runs, total, err := actions_service.SearchOrgRuns(ctx, ctx.Org.Organization.AsUser(), ctx.Doer, actions_service.SearchOrgRunsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		Repo:        ctx.FormTrim("repo"),
+		Workflow:    ctx.FormTrim("workflow"),
+		Status:      ctx.FormTrim("status"),
+		Actor:       ctx.FormTrim("actor"),
+		Since:       ctx.FormTrim("since"),
+		Until:       ctx.FormTrim("until"),
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusBadRequest, "SearchOrgRuns", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SearchOrgRuns", err)
+		}
+		return
+	}
+
+	res := &api.ActionWorkflowRunsResponse{
+		Entries:    make([]*api.ActionWorkflowRun, 0, len(runs)),
+		TotalCount: total,
+	}
+	for _, run := range runs {
+		convertedRun, err := convert.ToActionWorkflowRun(ctx, run, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionWorkflowRun", err)
+			return
+		}
+		res.Entries = append(res.Entries, convertedRun)
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
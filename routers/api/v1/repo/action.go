@@ -5,11 +5,17 @@ package repo
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
 	secret_model "code.gitea.io/gitea/models/secret"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
@@ -19,6 +25,8 @@ import (
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 	secret_service "code.gitea.io/gitea/services/secrets"
+
+	"github.com/nektos/act/pkg/model"
 )
 
 // ListActionsSecrets list an repo's actions secrets
@@ -121,7 +129,7 @@ func (Action) CreateOrUpdateSecret(ctx *context.APIContext) {
 
 	opt := web.GetForm(ctx).(*api.CreateOrUpdateSecretOption)
 
-	_, created, err := secret_service.CreateOrUpdateSecret(ctx, 0, repo.ID, ctx.PathParam("secretname"), opt.Data)
+	_, created, err := secret_service.CreateOrUpdateSecret(ctx, 0, repo.ID, 0, ctx.PathParam("secretname"), opt.Data)
 	if err != nil {
 		if errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusBadRequest, "CreateOrUpdateSecret", err)
@@ -507,6 +515,204 @@ func (Action) GetRegistrationToken(ctx *context.APIContext) {
 	shared.GetRegistrationToken(ctx, 0, ctx.Repo.Repository.ID)
 }
 
+// CreateRegistrationToken creates a new token to register repo runners
+func (Action) CreateRegistrationToken(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runners/registration-token repository repoCreateRunnerRegistrationToken
+	// ---
+	// summary: Create a new actions runner registration token for a repository's runners, the previous tokens are invalidated
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RegistrationToken"
+
+	shared.CreateRegistrationToken(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// ListRunners lists the repo runners
+func (Action) ListRunners(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runners repository repoListRunners
+	// ---
+	// summary: List a repository's actions runners
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerList"
+
+	shared.ListRunners(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// GetRunner gets one of the repo runners
+func (Action) GetRunner(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runners/{runner_id} repository repoGetRunner
+	// ---
+	// summary: Get a repository's actions runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunner"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetRunner(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// EditRunner edits one of the repo runners
+func (Action) EditRunner(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/actions/runners/{runner_id} repository repoEditRunner
+	// ---
+	// summary: Edit the description or the labels of a repository's actions runner
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionRunnerOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunner"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.EditRunner(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// DeleteRunner deletes one of the repo runners
+func (Action) DeleteRunner(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/runners/{runner_id} repository repoDeleteRunner
+	// ---
+	// summary: Delete a repository's actions runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: runner has been deleted
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteRunner(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// GenerateRunnerJITConfig creates an ephemeral repo runner and returns its just-in-time config
+func (Action) GenerateRunnerJITConfig(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runners/generate-jitconfig repository repoGenerateRunnerJITConfig
+	// ---
+	// summary: Create an ephemeral actions runner for a repository and get its just-in-time config
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/GenerateActionRunnerJITConfigOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRunnerJITConfig"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GenerateJITConfig(ctx, 0, ctx.Repo.Repository.ID)
+}
+
 var _ actions_service.API = new(Action)
 
 // Action implements actions_service.API
@@ -581,3 +787,380 @@ func ListActionTasks(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, &res)
 }
+
+// ListActionRuns list the workflow runs of a repository
+func ListActionRuns(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs repository ListActionRuns
+	// ---
+	// summary: List a repository's workflow runs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow
+	//   in: query
+	//   description: workflow file name of the runs, e.g. "build.yml"
+	//   type: string
+	// - name: status
+	//   in: query
+	//   description: status of the runs
+	//   type: string
+	//   enum: [unknown, waiting, running, success, failure, cancelled, skipped, blocked]
+	// - name: actor
+	//   in: query
+	//   description: username of the user who triggered the runs
+	//   type: string
+	// - name: branch
+	//   in: query
+	//   description: branch name of the runs
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results, default maximum page size is 50
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRunsList"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	opts := actions_model.FindRunOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		WorkflowID:  ctx.FormTrim("workflow"),
+	}
+
+	if statusName := ctx.FormTrim("status"); statusName != "" {
+		status, ok := actions_model.ParseStatus(statusName)
+		if !ok {
+			ctx.Error(http.StatusBadRequest, "ParseStatus", fmt.Sprintf("invalid status %q", statusName))
+			return
+		}
+		opts.Status = []actions_model.Status{status}
+	}
+
+	if actorName := ctx.FormTrim("actor"); actorName != "" {
+		actor, err := user_model.GetUserByName(ctx, actorName)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.NotFound("GetUserByName", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		opts.TriggerUserID = actor.ID
+	}
+
+	if branch := ctx.FormTrim("branch"); branch != "" {
+		opts.Ref = git.RefNameFromBranch(branch).String()
+	}
+
+	runs, total, err := db.FindAndCount[actions_model.ActionRun](ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindAndCount", err)
+		return
+	}
+
+	res := new(api.ActionWorkflowRunsResponse)
+	res.TotalCount = total
+
+	res.Entries = make([]*api.ActionWorkflowRun, len(runs))
+	for i := range runs {
+		runs[i].Repo = ctx.Repo.Repository
+		convertedRun, err := convert.ToActionWorkflowRun(ctx, runs[i], ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionWorkflowRun", err)
+			return
+		}
+		res.Entries[i] = convertedRun
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, &res)
+}
+
+// CancelActionRuns cancels the queued and running workflow runs of a branch, a pull request or a workflow
+func CancelActionRuns(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/cancel repository CancelActionRuns
+	// ---
+	// summary: Cancel the queued and running workflow runs of a branch, a pull request or a workflow
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CancelActionRunsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRunsList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opt := web.GetForm(ctx).(*api.CancelActionRunsOption)
+	opts := actions_service.CancelRunsOptions{
+		RepoID:     ctx.Repo.Repository.ID,
+		WorkflowID: strings.TrimSpace(opt.Workflow),
+	}
+	branch := strings.TrimSpace(opt.Branch)
+	if branch != "" && opt.PullRequest > 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "branch and pull_request can't be specified at the same time")
+		return
+	} else if branch == "" && opt.PullRequest <= 0 && opts.WorkflowID == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "at least one of branch, pull_request and workflow is required")
+		return
+	}
+	if branch != "" {
+		opts.Ref = git.RefNameFromBranch(branch).String()
+	} else if opt.PullRequest > 0 {
+		pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, opt.PullRequest)
+		if err != nil {
+			if issues_model.IsErrPullRequestNotExist(err) {
+				ctx.NotFound("GetPullRequestByIndex", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+			}
+			return
+		}
+		opts.Ref = pr.GetGitRefName()
+	}
+
+	runs, err := actions_service.CancelRuns(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CancelRuns", err)
+		return
+	}
+
+	res := &api.ActionWorkflowRunsResponse{
+		TotalCount: int64(len(runs)),
+		Entries:    make([]*api.ActionWorkflowRun, len(runs)),
+	}
+	for i := range runs {
+		runs[i].Repo = ctx.Repo.Repository
+		convertedRun, err := convert.ToActionWorkflowRun(ctx, runs[i], ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionWorkflowRun", err)
+			return
+		}
+		res.Entries[i] = convertedRun
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetActionRun get a workflow run of a repository
+func GetActionRun(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run} repository GetActionRun
+	// ---
+	// summary: Get a workflow run of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRun(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	res, err := convert.ToActionWorkflowRun(ctx, run, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionWorkflowRun", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// getActionRun returns the run specified by the path parameter "run" which belongs to the current repository
+func getActionRun(ctx *context.APIContext) *actions_model.ActionRun {
+	run, err := actions_model.GetRunByID(ctx, ctx.PathParamInt64("run"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByID", err)
+		}
+		return nil
+	}
+	if run.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return nil
+	}
+	run.Repo = ctx.Repo.Repository
+	return run
+}
+
+// DispatchActionWorkflow triggers a workflow_dispatch event of a workflow
+func DispatchActionWorkflow(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/workflows/{workflow_id}/dispatches repository DispatchActionWorkflow
+	// ---
+	// summary: Create a workflow dispatch event
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow_id
+	//   in: path
+	//   description: file name of the workflow, e.g. "build.yml"
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionWorkflowDispatch"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opt := web.GetForm(ctx).(*api.CreateActionWorkflowDispatch)
+	workflowID := ctx.PathParam("workflow_id")
+
+	// the ref could be a full ref name or a short branch/tag name
+	ref := opt.Ref
+	if !strings.HasPrefix(ref, "refs/") {
+		if ctx.Repo.GitRepo.IsBranchExist(ref) {
+			ref = git.RefNameFromBranch(ref).String()
+		} else if ctx.Repo.GitRepo.IsTagExist(ref) {
+			ref = git.RefNameFromTag(ref).String()
+		}
+	}
+
+	_, err := actions_service.DispatchActionWorkflow(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, workflowID, ref, func(cfg *model.WorkflowDispatch, inputs map[string]any) error {
+		return actions_service.ValidateWorkflowDispatchInputs(ctx, ctx.Repo.Repository, cfg, opt.Inputs, inputs)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.Error(http.StatusForbidden, "DispatchActionWorkflow", err)
+		case errors.Is(err, util.ErrNotExist):
+			ctx.Error(http.StatusNotFound, "DispatchActionWorkflow", err)
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "DispatchActionWorkflow", err)
+		default:
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// DownloadActionRunLogs downloads the logs of a workflow run
+func DownloadActionRunLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run}/logs repository DownloadActionRunLogs
+	// ---
+	// summary: Download the logs of all the jobs of a workflow run as a zip archive
+	// produces:
+	// - application/zip
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the zip archive of the logs
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRun(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.SetServeHeaders(&context.ServeHeaderOptions{
+		Filename:    actions_service.RunLogsZipName(run),
+		ContentType: "application/zip",
+		Disposition: "attachment",
+	})
+	if err := actions_service.WriteRunLogsZip(ctx, ctx.Resp, run, run.Attempt); err != nil {
+		log.Error("WriteRunLogsZip: %v", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"code.gitea.io/gitea/models"
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
 	"code.gitea.io/gitea/models/organization"
@@ -539,6 +540,10 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		return
 	}
 
+	if !validateRequiredWorkflows(ctx, form.RequiredWorkflows) {
+		return
+	}
+
 	var requiredApprovals int64
 	if form.RequiredApprovals > 0 {
 		requiredApprovals = form.RequiredApprovals
@@ -632,6 +637,7 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		EnableMergeWhitelist:          form.EnableMergeWhitelist,
 		EnableStatusCheck:             form.EnableStatusCheck,
 		StatusCheckContexts:           form.StatusCheckContexts,
+		RequiredWorkflows:             form.RequiredWorkflows,
 		EnableApprovalsWhitelist:      form.EnableApprovalsWhitelist,
 		RequiredApprovals:             requiredApprovals,
 		BlockOnRejectedReviews:        form.BlockOnRejectedReviews,
@@ -812,6 +818,13 @@ func EditBranchProtection(ctx *context.APIContext) {
 		protectBranch.StatusCheckContexts = form.StatusCheckContexts
 	}
 
+	if form.RequiredWorkflows != nil {
+		if !validateRequiredWorkflows(ctx, form.RequiredWorkflows) {
+			return
+		}
+		protectBranch.RequiredWorkflows = form.RequiredWorkflows
+	}
+
 	if form.RequiredApprovals != nil && *form.RequiredApprovals >= 0 {
 		protectBranch.RequiredApprovals = *form.RequiredApprovals
 	}
@@ -1080,3 +1093,14 @@ func DeleteBranchProtection(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// validateRequiredWorkflows responds with 422 if any of the required workflows is invalid
+func validateRequiredWorkflows(ctx *context.APIContext, requiredWorkflows []string) bool {
+	for _, workflow := range requiredWorkflows {
+		if !actions_model.IsValidRequiredWorkflow(workflow) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid required workflow %q", workflow))
+			return false
+		}
+	}
+	return true
+}
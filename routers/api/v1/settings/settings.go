@@ -83,3 +83,21 @@ func GetGeneralAttachmentSettings(ctx *context.APIContext) {
 		MaxSize:      setting.Attachment.MaxSize,
 	})
 }
+
+// GetGeneralActionsSettings returns instance's global settings for actions
+func GetGeneralActionsSettings(ctx *context.APIContext) {
+	// swagger:operation GET /settings/actions settings getGeneralActionsSettings
+	// ---
+	// summary: Get instance's global settings for actions, including the required versions of the runners
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GeneralActionsSettings"
+	ctx.JSON(http.StatusOK, api.GeneralActionsSettings{
+		Enabled:                  setting.Actions.Enabled,
+		MinRunnerVersion:         setting.Actions.MinRunnerVersion,
+		RecommendedRunnerVersion: setting.Actions.RecommendedRunnerVersion,
+		RejectOutdatedRunners:    setting.Actions.RejectOutdatedRunners,
+	})
+}
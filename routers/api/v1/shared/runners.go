@@ -4,12 +4,24 @@
 package shared
 
 import (
+	"encoding/base64"
 	"errors"
 	"net/http"
+	"slices"
+	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+
+	gouuid "github.com/google/uuid"
 )
 
 // RegistrationToken is response related to registration token
@@ -30,3 +42,183 @@ func GetRegistrationToken(ctx *context.APIContext, ownerID, repoID int64) {
 
 	ctx.JSON(http.StatusOK, RegistrationToken{Token: token.Token})
 }
+
+// CreateRegistrationToken creates a new registration token, the previous tokens of the scope are invalidated
+func CreateRegistrationToken(ctx *context.APIContext, ownerID, repoID int64) {
+	token, err := actions_model.NewRunnerToken(ctx, ownerID, repoID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, RegistrationToken{Token: token.Token})
+}
+
+// ListRunners lists the runners which belong to the scope, all runners are listed for the instance scope
+func ListRunners(ctx *context.APIContext, ownerID, repoID int64) {
+	runners, total, err := db.FindAndCount[actions_model.ActionRunner](ctx, actions_model.FindRunnerOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		Sort:        "newest",
+	})
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	res := &api.ActionRunnersResponse{
+		Entries:    make([]*api.ActionRunner, 0, len(runners)),
+		TotalCount: total,
+	}
+	for _, runner := range runners {
+		res.Entries = append(res.Entries, convert.ToActionRunner(runner))
+	}
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// normalizeRunnerLabels trims the labels and removes the duplicated ones
+func normalizeRunnerLabels(labels []string) ([]string, error) {
+	res := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			return nil, errors.New("label can't be empty")
+		}
+		if !slices.Contains(res, label) {
+			res = append(res, label)
+		}
+	}
+	return res, nil
+}
+
+// getRunner returns the runner of the scope, it responds 404 if the runner doesn't exist or doesn't belong to the scope
+func getRunner(ctx *context.APIContext, ownerID, repoID int64) *actions_model.ActionRunner {
+	runner, err := actions_model.GetRunnerByID(ctx, ctx.PathParamInt64("runner_id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	if !runner.Editable(ownerID, repoID) {
+		ctx.NotFound()
+		return nil
+	}
+	return runner
+}
+
+// GetRunner gets a runner of the scope
+func GetRunner(ctx *context.APIContext, ownerID, repoID int64) {
+	runner := getRunner(ctx, ownerID, repoID)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionRunner(runner))
+}
+
+// EditRunner edits the description and the labels of a runner of the scope
+func EditRunner(ctx *context.APIContext, ownerID, repoID int64) {
+	runner := getRunner(ctx, ownerID, repoID)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditActionRunnerOption)
+	var cols []string
+	if form.Description != nil {
+		runner.Description = *form.Description
+		cols = append(cols, "description")
+	}
+	if form.Labels != nil {
+		labels, err := normalizeRunnerLabels(*form.Labels)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "EditRunner", err)
+			return
+		}
+		runner.AgentLabels = labels
+		cols = append(cols, "agent_labels")
+	}
+	if len(cols) > 0 {
+		if err := actions_model.UpdateRunner(ctx, runner, cols...); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionRunner(runner))
+}
+
+// DeleteRunner deletes a runner of the scope
+func DeleteRunner(ctx *context.APIContext, ownerID, repoID int64) {
+	runner := getRunner(ctx, ownerID, repoID)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_model.DeleteRunner(ctx, runner.ID); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// jitConfig is the registration file of act_runner, so the runner can be started with it without registering
+type jitConfig struct {
+	Warning   string   `json:"WARNING"`
+	ID        int64    `json:"id"`
+	UUID      string   `json:"uuid"`
+	Name      string   `json:"name"`
+	Token     string   `json:"token"`
+	Address   string   `json:"address"`
+	Labels    []string `json:"labels"`
+	Ephemeral bool     `json:"ephemeral"`
+}
+
+// GenerateJITConfig creates an ephemeral runner of the scope and responds its just-in-time config
+func GenerateJITConfig(ctx *context.APIContext, ownerID, repoID int64) {
+	form := web.GetForm(ctx).(*api.GenerateActionRunnerJITConfigOption)
+	labels, err := normalizeRunnerLabels(form.Labels)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GenerateJITConfig", err)
+		return
+	}
+
+	runner := &actions_model.ActionRunner{
+		UUID:        gouuid.New().String(),
+		Name:        form.Name,
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		AgentLabels: labels,
+		Ephemeral:   true,
+	}
+	if err := runner.GenerateToken(); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if err := actions_model.CreateRunner(ctx, runner); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	content, err := json.Marshal(&jitConfig{
+		Warning:   "This file is automatically generated by Gitea. Please don't modify it.",
+		ID:        runner.ID,
+		UUID:      runner.UUID,
+		Name:      runner.Name,
+		Token:     runner.Token,
+		Address:   setting.AppURL,
+		Labels:    runner.AgentLabels,
+		Ephemeral: true,
+	})
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &api.ActionRunnerJITConfig{
+		Runner:           convert.ToActionRunner(runner),
+		EncodedJITConfig: base64.StdEncoding.EncodeToString(content),
+	})
+}
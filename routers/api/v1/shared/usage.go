@@ -0,0 +1,111 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"errors"
+	"net/http"
+
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+func toActionUsage(summary *actions_service.UsageSummary) *api.ActionUsage {
+	res := &api.ActionUsage{
+		Month:         summary.Month,
+		Jobs:          summary.Jobs,
+		Duration:      summary.Duration,
+		Minutes:       summary.Minutes,
+		SoftQuota:     summary.SoftQuota,
+		QuotaExceeded: summary.QuotaExceeded(),
+		Repositories:  make([]*api.ActionRepoUsage, 0, len(summary.Repos)),
+	}
+	for _, usage := range summary.Repos {
+		repoUsage := &api.ActionRepoUsage{
+			RepoID:   usage.RepoID,
+			Jobs:     usage.Jobs,
+			Duration: usage.Duration,
+			Minutes:  usage.Minutes,
+		}
+		if usage.Repo != nil {
+			repoUsage.RepoName = usage.Repo.FullName()
+		}
+		res.Repositories = append(res.Repositories, repoUsage)
+	}
+	return res
+}
+
+func toActionOwnerUsage(summary *actions_service.OwnerUsageSummary) *api.ActionOwnerUsage {
+	res := &api.ActionOwnerUsage{
+		OwnerID:       summary.OwnerID,
+		Jobs:          summary.Jobs,
+		Duration:      summary.Duration,
+		Minutes:       summary.Minutes,
+		SoftQuota:     summary.SoftQuota,
+		QuotaExceeded: summary.QuotaExceeded(),
+	}
+	if summary.Owner != nil {
+		res.OwnerName = summary.Owner.Name
+	}
+	return res
+}
+
+// parseUsageMonth reads the month from the query, it responds 422 if the month is invalid
+func parseUsageMonth(ctx *context.APIContext) (string, bool) {
+	month, err := actions_service.ParseUsageMonth(ctx.FormString("month"))
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "ParseUsageMonth", err)
+		return "", false
+	}
+	return month, true
+}
+
+// GetUsage gets the usage of actions of the owner in a month
+func GetUsage(ctx *context.APIContext, ownerID int64) {
+	month, ok := parseUsageMonth(ctx)
+	if !ok {
+		return
+	}
+	summary, err := actions_service.GetOwnerUsageSummary(ctx, ownerID, month)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toActionUsage(summary))
+}
+
+// ListOwnerUsages lists the usages of actions of all owners in a month
+func ListOwnerUsages(ctx *context.APIContext) {
+	month, ok := parseUsageMonth(ctx)
+	if !ok {
+		return
+	}
+	summaries, err := actions_service.ListOwnerUsageSummaries(ctx, month)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	res := make([]*api.ActionOwnerUsage, 0, len(summaries))
+	for _, summary := range summaries {
+		res = append(res, toActionOwnerUsage(summary))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// EditUsageQuota sets the soft quota of the actions minutes of the owner
+func EditUsageQuota(ctx *context.APIContext, ownerID int64) {
+	form := web.GetForm(ctx).(*api.EditActionUsageQuotaOption)
+	if err := actions_service.SetUsageSoftQuota(ctx, ownerID, form.SoftQuota); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "SetUsageSoftQuota", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
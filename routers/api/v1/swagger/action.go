@@ -32,3 +32,38 @@ type swaggerResponseVariableList struct {
 	// in:body
 	Body []api.ActionVariable `json:"body"`
 }
+
+// ActionRunner
+// swagger:response ActionRunner
+type swaggerResponseActionRunner struct {
+	// in:body
+	Body api.ActionRunner `json:"body"`
+}
+
+// ActionRunnerList
+// swagger:response ActionRunnerList
+type swaggerResponseActionRunnerList struct {
+	// in:body
+	Body api.ActionRunnersResponse `json:"body"`
+}
+
+// ActionRunnerJITConfig
+// swagger:response ActionRunnerJITConfig
+type swaggerResponseActionRunnerJITConfig struct {
+	// in:body
+	Body api.ActionRunnerJITConfig `json:"body"`
+}
+
+// ActionUsage
+// swagger:response ActionUsage
+type swaggerResponseActionUsage struct {
+	// in:body
+	Body api.ActionUsage `json:"body"`
+}
+
+// ActionOwnerUsageList
+// swagger:response ActionOwnerUsageList
+type swaggerResponseActionOwnerUsageList struct {
+	// in:body
+	Body []api.ActionOwnerUsage `json:"body"`
+}
@@ -205,4 +205,19 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	UpdateVariableOption api.UpdateVariableOption
+
+	// in:body
+	CreateActionWorkflowDispatch api.CreateActionWorkflowDispatch
+
+	// in:body
+	EditActionRunnerOption api.EditActionRunnerOption
+
+	// in:body
+	GenerateActionRunnerJITConfigOption api.GenerateActionRunnerJITConfigOption
+
+	// in:body
+	EditActionUsageQuotaOption api.EditActionUsageQuotaOption
+
+	// in:body
+	CancelActionRunsOption api.CancelActionRunsOption
 }
@@ -32,3 +32,10 @@ type swaggerResponseGeneralAttachmentSettings struct {
 	// in:body
 	Body api.GeneralAttachmentSettings `json:"body"`
 }
+
+// GeneralActionsSettings
+// swagger:response GeneralActionsSettings
+type swaggerResponseGeneralActionsSettings struct {
+	// in:body
+	Body api.GeneralActionsSettings `json:"body"`
+}
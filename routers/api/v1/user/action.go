@@ -12,6 +12,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/shared"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
@@ -49,7 +50,7 @@ func CreateOrUpdateSecret(ctx *context.APIContext) {
 
 	opt := web.GetForm(ctx).(*api.CreateOrUpdateSecretOption)
 
-	_, created, err := secret_service.CreateOrUpdateSecret(ctx, ctx.Doer.ID, 0, ctx.PathParam("secretname"), opt.Data)
+	_, created, err := secret_service.CreateOrUpdateSecret(ctx, ctx.Doer.ID, 0, 0, ctx.PathParam("secretname"), opt.Data)
 	if err != nil {
 		if errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusBadRequest, "CreateOrUpdateSecret", err)
@@ -351,3 +352,24 @@ func ListVariables(ctx *context.APIContext) {
 	ctx.SetTotalCountHeader(count)
 	ctx.JSON(http.StatusOK, variables)
 }
+
+// GetActionsUsage gets the usage of actions of the user in a month
+func GetActionsUsage(ctx *context.APIContext) {
+	// swagger:operation GET /user/actions/usage user getUserActionsUsage
+	// ---
+	// summary: Get the usage of actions of the user's repositories in a month
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: month
+	//   in: query
+	//   description: the month of the usage like "2024-10", defaults to the current month
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionUsage"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GetUsage(ctx, ctx.Doer.ID)
+}
@@ -24,3 +24,141 @@ func GetRegistrationToken(ctx *context.APIContext) {
 
 	shared.GetRegistrationToken(ctx, ctx.Doer.ID, 0)
 }
+
+// CreateRegistrationToken creates a new token to register user runners
+func CreateRegistrationToken(ctx *context.APIContext) {
+	// swagger:operation POST /user/actions/runners/registration-token user userCreateRunnerRegistrationToken
+	// ---
+	// summary: Create a new actions runner registration token for the user's runners, the previous tokens are invalidated
+	// produces:
+	// - application/json
+	// parameters:
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RegistrationToken"
+
+	shared.CreateRegistrationToken(ctx, ctx.Doer.ID, 0)
+}
+
+// ListRunners lists the user runners
+func ListRunners(ctx *context.APIContext) {
+	// swagger:operation GET /user/actions/runners user userListRunners
+	// ---
+	// summary: List the user's actions runners
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerList"
+
+	shared.ListRunners(ctx, ctx.Doer.ID, 0)
+}
+
+// GetRunner gets one of the user runners
+func GetRunner(ctx *context.APIContext) {
+	// swagger:operation GET /user/actions/runners/{runner_id} user userGetRunner
+	// ---
+	// summary: Get the user's actions runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunner"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetRunner(ctx, ctx.Doer.ID, 0)
+}
+
+// EditRunner edits one of the user runners
+func EditRunner(ctx *context.APIContext) {
+	// swagger:operation PATCH /user/actions/runners/{runner_id} user userEditRunner
+	// ---
+	// summary: Edit the description or the labels of the user's actions runner
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionRunnerOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunner"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.EditRunner(ctx, ctx.Doer.ID, 0)
+}
+
+// DeleteRunner deletes one of the user runners
+func DeleteRunner(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/actions/runners/{runner_id} user userDeleteRunner
+	// ---
+	// summary: Delete the user's actions runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: runner has been deleted
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteRunner(ctx, ctx.Doer.ID, 0)
+}
+
+// GenerateRunnerJITConfig creates an ephemeral user runner and returns its just-in-time config
+func GenerateRunnerJITConfig(ctx *context.APIContext) {
+	// swagger:operation POST /user/actions/runners/generate-jitconfig user userGenerateRunnerJITConfig
+	// ---
+	// summary: Create an ephemeral actions runner for the user and get its just-in-time config
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/GenerateActionRunnerJITConfigOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRunnerJITConfig"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.GenerateJITConfig(ctx, ctx.Doer.ID, 0)
+}
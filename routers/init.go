@@ -207,6 +207,10 @@ func NormalRoutes() *web.Router {
 		r.Mount(prefix, actions_router.ArtifactsRoutes(prefix))
 		prefix = actions_router.ArtifactV4RouteBase
 		r.Mount(prefix, actions_router.ArtifactsV4Routes(prefix))
+		prefix = actions_router.CacheRouteBase
+		r.Mount(prefix, actions_router.CacheRoutes(prefix))
+		prefix = actions_router.SummaryRouteBase
+		r.Mount(prefix, actions_router.SummaryRoutes(prefix))
 	}
 
 	r.NotFound(func(w http.ResponseWriter, req *http.Request) {
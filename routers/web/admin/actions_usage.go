@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
+	shared "code.gitea.io/gitea/routers/web/shared/actions"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/forms"
+)
+
+const tplActions base.TplName = "admin/actions"
+
+// ActionsUsage shows the usages of actions of all owners
+func ActionsUsage(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.usage")
+	ctx.Data["PageType"] = "usage"
+	ctx.Data["PageIsSharedSettingsUsage"] = true
+
+	shared.SetOwnerUsagesContext(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplActions)
+}
+
+// ActionsUsageQuotaPost sets the soft quota of the actions minutes of a user or an organization
+func ActionsUsageQuotaPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.AdminActionsUsageQuotaForm)
+	redirectURL := setting.AppSubURL + "/admin/actions/usage"
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(redirectURL)
+		return
+	}
+
+	owner, err := user_model.GetUserByName(ctx, form.Owner)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("actions.usage.owner_not_exist"))
+			ctx.Redirect(redirectURL)
+		} else {
+			ctx.ServerError("GetUserByName", err)
+		}
+		return
+	}
+
+	if err := actions_service.SetUsageSoftQuota(ctx, owner.ID, form.SoftQuota); err != nil {
+		ctx.ServerError("SetUsageSoftQuota", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("actions.usage.set_quota_success", owner.Name))
+	ctx.Redirect(redirectURL)
+}
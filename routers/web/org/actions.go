@@ -0,0 +1,82 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/util"
+	shared_user "code.gitea.io/gitea/routers/web/shared/user"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+const tplOrgActions base.TplName = "org/actions/list"
+
+// Actions renders the runs of the repositories of the organization which the user can read
+func Actions(ctx *context.Context) {
+	if !ctx.ContextUser.IsOrganization() {
+		ctx.NotFound("Actions", nil)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("actions.actions")
+	ctx.Data["PageIsOrgActions"] = true
+	if err := shared_user.RenderOrgHeader(ctx); err != nil {
+		ctx.ServerError("RenderOrgHeader", err)
+		return
+	}
+
+	page := ctx.FormInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	opts := actions_service.SearchOrgRunsOptions{
+		ListOptions: db.ListOptions{
+			Page:     page,
+			PageSize: convert.ToCorrectPageSize(ctx.FormInt("limit")),
+		},
+		Repo:     ctx.FormTrim("repo"),
+		Workflow: ctx.FormTrim("workflow"),
+		Status:   ctx.FormTrim("status"),
+		Actor:    ctx.FormTrim("actor"),
+		Since:    ctx.FormTrim("since"),
+		Until:    ctx.FormTrim("until"),
+	}
+	ctx.Data["Filter"] = opts
+	ctx.Data["IsFiltered"] = opts.Repo != "" || opts.Workflow != "" || opts.Status != "" || opts.Actor != "" || opts.Since != "" || opts.Until != ""
+
+	repos, err := actions_model.FindActionsAccessibleRepos(ctx, ctx.ContextUser.ID, ctx.Doer)
+	if err != nil {
+		ctx.ServerError("FindActionsAccessibleRepos", err)
+		return
+	}
+	ctx.Data["Repos"] = repos
+	ctx.Data["StatusInfoList"] = actions_model.GetStatusInfoList(ctx)
+	ctx.Data["ShowRepo"] = true
+
+	runs, total, err := actions_service.SearchOrgRuns(ctx, ctx.ContextUser, ctx.Doer, opts)
+	if err != nil {
+		if !errors.Is(err, util.ErrInvalidArgument) {
+			ctx.ServerError("SearchOrgRuns", err)
+			return
+		}
+		ctx.Flash.Error(err.Error(), true)
+	}
+	ctx.Data["Runs"] = runs
+
+	pager := context.NewPagination(int(total), opts.PageSize, opts.Page, 5)
+	pager.SetDefaultParams(ctx)
+	for _, param := range []string{"repo", "workflow", "status", "actor", "since", "until"} {
+		pager.AddParamString(param, ctx.FormTrim(param))
+	}
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplOrgActions)
+}
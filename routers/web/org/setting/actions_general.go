@@ -0,0 +1,60 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"net/http"
+
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/web"
+	shared "code.gitea.io/gitea/routers/web/shared/actions"
+	shared_user "code.gitea.io/gitea/routers/web/shared/user"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/forms"
+)
+
+// ActionsGeneralSettings render the general settings of actions for an organization
+func ActionsGeneralSettings(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.general")
+	ctx.Data["PageType"] = "general"
+	ctx.Data["PageIsSharedSettingsGeneral"] = true
+
+	if err := shared_user.LoadHeaderCount(ctx); err != nil {
+		ctx.ServerError("LoadHeaderCount", err)
+		return
+	}
+
+	allowed, err := actions_service.GetOwnerAllowedActions(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.ServerError("GetOwnerAllowedActions", err)
+		return
+	}
+	shared.SetAllowedActionsContext(ctx, allowed)
+
+	ctx.HTML(http.StatusOK, tplSettingsActions)
+}
+
+// ActionsGeneralSettingsPost response for updating the general settings of actions for an organization
+func ActionsGeneralSettingsPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.ActionsAllowedActionsForm)
+	redirectURL := ctx.Org.OrgLink + "/settings/actions/general"
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(redirectURL)
+		return
+	}
+
+	if err := actions_service.SetOwnerAllowedActions(ctx, ctx.Org.Organization.ID,
+		actions_module.AllowedActionsPolicy(form.AllowedActions),
+		actions_module.ParseAllowedActionsPatterns(form.AllowedActionsPatterns),
+	); err != nil {
+		ctx.ServerError("SetOwnerAllowedActions", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.general.update_success"))
+	ctx.Redirect(redirectURL)
+}
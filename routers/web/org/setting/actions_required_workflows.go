@@ -0,0 +1,93 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	shared_user "code.gitea.io/gitea/routers/web/shared/user"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/forms"
+)
+
+// ActionsRequiredWorkflows shows the workflows which are required to run in the repositories of the organization
+func ActionsRequiredWorkflows(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.required_workflows")
+	ctx.Data["PageType"] = "required_workflows"
+	ctx.Data["PageIsOrgSettingsRequiredWorkflows"] = true
+
+	if err := shared_user.LoadHeaderCount(ctx); err != nil {
+		ctx.ServerError("LoadHeaderCount", err)
+		return
+	}
+
+	workflows, err := db.Find[actions_model.ActionOrgRequiredWorkflow](ctx, actions_model.FindOrgRequiredWorkflowOptions{OrgID: ctx.Org.Organization.ID})
+	if err != nil {
+		ctx.ServerError("FindOrgRequiredWorkflows", err)
+		return
+	}
+	if err := actions_model.OrgRequiredWorkflowList(workflows).LoadRepos(ctx); err != nil {
+		ctx.ServerError("LoadRepos", err)
+		return
+	}
+	var repoIDs []int64
+	for _, w := range workflows {
+		repoIDs = append(repoIDs, w.RepoIDs...)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		ctx.ServerError("GetRepositoriesMapByIDs", err)
+		return
+	}
+	ctx.Data["RequiredWorkflows"] = workflows
+	ctx.Data["RequiredWorkflowRepos"] = repos
+
+	ctx.HTML(http.StatusOK, tplSettingsActions)
+}
+
+// ActionsRequiredWorkflowsCreate requires a workflow to run in the repositories of the organization
+func ActionsRequiredWorkflowsCreate(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.OrgRequiredWorkflowForm)
+	redirectURL := ctx.Org.OrgLink + "/settings/actions/required_workflows"
+
+	var repoNames []string
+	for _, name := range strings.FieldsFunc(form.RepoNames, func(r rune) bool { return r == ',' || r == '\n' || r == '\r' }) {
+		if name = strings.TrimSpace(name); name != "" {
+			repoNames = append(repoNames, name)
+		}
+	}
+	w, err := actions_service.CreateOrgRequiredWorkflow(ctx, ctx.Org.Organization.ID, strings.TrimSpace(form.RepoName), strings.TrimSpace(form.WorkflowID), repoNames)
+	if errors.Is(err, util.ErrInvalidArgument) {
+		ctx.JSONError(ctx.Tr("actions.required_workflows.creation.failed", err.Error()))
+		return
+	} else if err != nil {
+		ctx.ServerError("CreateOrgRequiredWorkflow", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.required_workflows.creation.success", w.RunWorkflowID()))
+	ctx.JSONRedirect(redirectURL)
+}
+
+// ActionsRequiredWorkflowsDelete stops requiring the workflow to run in the repositories of the organization
+func ActionsRequiredWorkflowsDelete(ctx *context.Context) {
+	if err := actions_model.DeleteOrgRequiredWorkflow(ctx, ctx.Org.Organization.ID, ctx.PathParamInt64("id")); errors.Is(err, util.ErrNotExist) {
+		ctx.NotFound("DeleteOrgRequiredWorkflow", err)
+		return
+	} else if err != nil {
+		ctx.ServerError("DeleteOrgRequiredWorkflow", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.required_workflows.deletion.success"))
+	ctx.JSONRedirect(ctx.Org.OrgLink + "/settings/actions/required_workflows")
+}
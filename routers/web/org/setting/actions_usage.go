@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/base"
+	shared "code.gitea.io/gitea/routers/web/shared/actions"
+	shared_user "code.gitea.io/gitea/routers/web/shared/user"
+	"code.gitea.io/gitea/services/context"
+)
+
+const tplSettingsActions base.TplName = "org/settings/actions"
+
+// ActionsUsage shows the usage of actions of the organization
+func ActionsUsage(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.usage")
+	ctx.Data["PageType"] = "usage"
+	ctx.Data["PageIsSharedSettingsUsage"] = true
+
+	if err := shared_user.LoadHeaderCount(ctx); err != nil {
+		ctx.ServerError("LoadHeaderCount", err)
+		return
+	}
+
+	shared.SetUsageContext(ctx, ctx.Org.Organization.ID)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplSettingsActions)
+}
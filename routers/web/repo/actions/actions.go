@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
-	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
@@ -17,13 +16,13 @@ import (
 	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/base"
-	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/web/repo"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 
@@ -39,6 +38,7 @@ const (
 type Workflow struct {
 	Entry  git.TreeEntry
 	ErrMsg string
+	Pinned bool
 }
 
 // MustEnableActions check if actions are enabled in settings
@@ -86,20 +86,11 @@ func List(ctx *context.Context) {
 			return
 		}
 
-		// Get all runner labels
-		runners, err := db.Find[actions_model.ActionRunner](ctx, actions_model.FindRunnerOptions{
-			RepoID:        ctx.Repo.Repository.ID,
-			IsOnline:      optional.Some(true),
-			WithAvailable: true,
-		})
+		runnerLabels, err := onlineRunnerLabels(ctx)
 		if err != nil {
 			ctx.ServerError("FindRunners", err)
 			return
 		}
-		allRunnerLabels := make(container.Set[string])
-		for _, r := range runners {
-			allRunnerLabels.AddMultiple(r.AgentLabels...)
-		}
 
 		workflows = make([]Workflow, 0, len(entries))
 		for _, entry := range entries {
@@ -109,54 +100,26 @@ func List(ctx *context.Context) {
 				ctx.ServerError("GetContentFromEntry", err)
 				return
 			}
-			wf, err := model.ReadWorkflow(bytes.NewReader(content))
-			if err != nil {
-				workflow.ErrMsg = ctx.Locale.TrString("actions.runs.invalid_workflow_helper", err.Error())
-				workflows = append(workflows, workflow)
-				continue
-			}
-			// The workflow must contain at least one job without "needs". Otherwise, a deadlock will occur and no jobs will be able to run.
-			hasJobWithoutNeeds := false
-			// Check whether have matching runner and a job without "needs"
-			emptyJobsNumber := 0
-			for _, j := range wf.Jobs {
-				if j == nil {
-					emptyJobsNumber++
-					continue
-				}
-				if !hasJobWithoutNeeds && len(j.Needs()) == 0 {
-					hasJobWithoutNeeds = true
-				}
-				runsOnList := j.RunsOn()
-				for _, ro := range runsOnList {
-					if strings.Contains(ro, "${{") {
-						// Skip if it contains expressions.
-						// The expressions could be very complex and could not be evaluated here,
-						// so just skip it, it's OK since it's just a tooltip message.
-						continue
-					}
-					if !allRunnerLabels.Contains(ro) {
-						workflow.ErrMsg = ctx.Locale.TrString("actions.runs.no_matching_online_runner_helper", ro)
-						break
-					}
-				}
-				if workflow.ErrMsg != "" {
-					break
-				}
-			}
-			if !hasJobWithoutNeeds {
-				workflow.ErrMsg = ctx.Locale.TrString("actions.runs.no_job_without_needs")
-			}
-			if emptyJobsNumber == len(wf.Jobs) {
-				workflow.ErrMsg = ctx.Locale.TrString("actions.runs.no_job")
+			if problem := firstLintProblem(actions.LintWorkflow(content, runnerLabels)); problem != nil {
+				workflow.ErrMsg = lintProblemMessage(ctx.Locale, problem)
 			}
 			workflows = append(workflows, workflow)
 
 			if workflow.Entry.Name() == workflowID {
-				curWorkflow = wf
+				if wf, err := model.ReadWorkflow(bytes.NewReader(content)); err == nil {
+					curWorkflow = wf
+				}
 			}
 		}
 	}
+	if ctx.IsSigned && len(workflows) > 0 {
+		pinned, err := actions_service.GetPinnedWorkflows(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID)
+		if err != nil {
+			ctx.ServerError("GetPinnedWorkflows", err)
+			return
+		}
+		sortPinnedWorkflows(workflows, pinned)
+	}
 	ctx.Data["workflows"] = workflows
 	ctx.Data["RepoLink"] = ctx.Repo.Repository.Link()
 
@@ -195,7 +158,6 @@ func List(ctx *context.Context) {
 					branches = util.SliceRemoveAll(branches, ctx.Repo.Repository.DefaultBranch)
 					branches = append([]string{ctx.Repo.Repository.DefaultBranch}, branches...)
 				}
-				ctx.Data["Branches"] = branches
 
 				tags, err := repo_model.GetTagNamesByRepoID(ctx, ctx.Repo.Repository.ID)
 				if err != nil {
@@ -203,6 +165,39 @@ func List(ctx *context.Context) {
 					return
 				}
 				ctx.Data["Tags"] = tags
+
+				memory, err := actions_service.GetDispatchMemory(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID, workflowID)
+				if err != nil {
+					ctx.ServerError("GetDispatchMemory", err)
+					return
+				}
+				// put the recently used branches on the top, the most recent one first
+				for i := len(memory.Refs) - 1; i >= 0; i-- {
+					if refName := git.RefName(memory.Refs[i]); refName.IsBranch() && slices.Contains(branches, refName.BranchName()) {
+						branches = util.SliceRemoveAll(branches, refName.BranchName())
+						branches = append([]string{refName.BranchName()}, branches...)
+					}
+				}
+				ctx.Data["Branches"] = branches
+
+				dispatchRef := selectDispatchRef(memory, workflowDispatchConfig.DefaultRef, branches, tags, ctx.Repo.Repository.DefaultBranch)
+				ctx.Data["DispatchRef"] = dispatchRef.String()
+				ctx.Data["DispatchRefName"] = dispatchRef.ShortName()
+
+				for i, input := range workflowDispatchConfig.Inputs {
+					if value, ok := memory.Inputs[input.Name]; ok {
+						workflowDispatchConfig.Inputs[i].Default = value
+					}
+				}
+
+				if slices.ContainsFunc(workflowDispatchConfig.Inputs, func(input WorkflowDispatchInput) bool { return input.Type == "environment" }) {
+					environments, err := db.Find[actions_model.ActionEnvironment](ctx, actions_model.FindEnvironmentsOptions{RepoID: ctx.Repo.Repository.ID})
+					if err != nil {
+						ctx.ServerError("FindEnvironments", err)
+						return
+					}
+					ctx.Data["Environments"] = environments
+				}
 			}
 		}
 	}
@@ -267,6 +262,25 @@ func List(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplListActions)
 }
 
+// sortPinnedWorkflows moves the pinned workflows to the top of the list in the order they were pinned, the others keep their order
+func sortPinnedWorkflows(workflows []Workflow, pinned []string) {
+	for i := range workflows {
+		workflows[i].Pinned = slices.Contains(pinned, workflows[i].Entry.Name())
+	}
+	slices.SortStableFunc(workflows, func(a, b Workflow) int {
+		ia, ib := slices.Index(pinned, a.Entry.Name()), slices.Index(pinned, b.Entry.Name())
+		switch {
+		case ia == ib:
+			return 0
+		case ia == -1:
+			return 1
+		case ib == -1:
+			return -1
+		}
+		return ia - ib
+	})
+}
+
 type WorkflowDispatchInput struct {
 	Name        string   `yaml:"name"`
 	Description string   `yaml:"description"`
@@ -277,7 +291,39 @@ type WorkflowDispatchInput struct {
 }
 
 type WorkflowDispatch struct {
-	Inputs []WorkflowDispatchInput
+	// DefaultRef is the ref selected in the dispatch form by default, it's set by the "default-ref" key under "workflow_dispatch",
+	// it could be a branch name, a tag name or a full ref name.
+	DefaultRef string
+	Inputs     []WorkflowDispatchInput
+}
+
+// selectDispatchRef returns the ref selected in the dispatch form by default, it's the ref of the user's last dispatch if it still exists,
+// or the default ref of the workflow, or the default branch of the repository.
+func selectDispatchRef(memory *actions_service.DispatchMemory, defaultRef string, branches, tags []string, defaultBranch string) git.RefName {
+	exists := func(refName git.RefName) bool {
+		return refName.IsBranch() && slices.Contains(branches, refName.BranchName()) ||
+			refName.IsTag() && slices.Contains(tags, refName.TagName())
+	}
+
+	for _, ref := range memory.Refs {
+		if refName := git.RefName(ref); exists(refName) {
+			return refName
+		}
+	}
+
+	if defaultRef != "" {
+		if refName := git.RefName(defaultRef); exists(refName) {
+			return refName
+		}
+		if slices.Contains(branches, defaultRef) {
+			return git.RefNameFromBranch(defaultRef)
+		}
+		if slices.Contains(tags, defaultRef) {
+			return git.RefNameFromTag(defaultRef)
+		}
+	}
+
+	return git.RefNameFromBranch(defaultBranch)
 }
 
 func workflowDispatchConfig(w *model.Workflow) *WorkflowDispatch {
@@ -317,6 +363,10 @@ func workflowDispatchConfig(w *model.Workflow) *WorkflowDispatch {
 			return &workflowDispatch
 		}
 
+		if defaultRefNode, found := workflowDispatchVal["default-ref"]; found {
+			decodeNode(defaultRefNode, &workflowDispatch.DefaultRef)
+		}
+
 		inputsNode, found := workflowDispatchVal["inputs"]
 		if !found || inputsNode.Kind != yaml.MappingNode {
 			return &workflowDispatch
@@ -40,7 +40,7 @@ func getWorkflowBadge(ctx *context.Context, workflowFile, branchName, event stri
 	extension := filepath.Ext(workflowFile)
 	workflowName := strings.TrimSuffix(workflowFile, extension)
 
-	run, err := actions_model.GetWorkflowLatestRun(ctx, ctx.Repo.Repository.ID, workflowFile, branchName, event)
+	run, err := actions_model.GetWorkflowLatestDoneRun(ctx, ctx.Repo.Repository.ID, workflowFile, branchName, event)
 	if err != nil {
 		if errors.Is(err, util.ErrNotExist) {
 			return badge.GenerateBadge(workflowName, "no status", badge.DefaultColor), nil
@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+const tplCompareRuns base.TplName = "repo/actions/compare"
+
+// CompareRuns compares the run with the run of the "base" index, or the previous run of the same workflow on the same ref
+func CompareRuns(ctx *context.Context) {
+	ctx.Data["PageIsActions"] = true
+	ctx.Data["Title"] = ctx.Tr("actions.runs.compare")
+
+	head, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, getRunIndex(ctx))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetRunByIndex", func(err error) bool { return errors.Is(err, util.ErrNotExist) }, err)
+		return
+	}
+	head.Repo = ctx.Repo.Repository
+
+	var base *actions_model.ActionRun
+	if baseIndex := ctx.FormInt64("base"); baseIndex > 0 {
+		base, err = actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, baseIndex)
+	} else {
+		base, err = actions_service.GetPreviousRun(ctx, head)
+	}
+	if errors.Is(err, util.ErrNotExist) {
+		ctx.Data["Head"] = head
+		ctx.HTML(http.StatusOK, tplCompareRuns)
+		return
+	} else if err != nil {
+		ctx.ServerError("GetBaseRun", err)
+		return
+	}
+	base.Repo = ctx.Repo.Repository
+
+	comparison, err := actions_service.CompareRuns(ctx, base, head)
+	if errors.Is(err, util.ErrInvalidArgument) {
+		ctx.Flash.Error(ctx.Tr("actions.runs.compare_different_workflows", base.WorkflowID, head.WorkflowID), true)
+		ctx.Data["Head"] = head
+		ctx.HTML(http.StatusOK, tplCompareRuns)
+		return
+	} else if err != nil {
+		ctx.ServerError("CompareRuns", err)
+		return
+	}
+	ctx.Data["Head"] = head
+	ctx.Data["Comparison"] = comparison
+	ctx.Data["Runs"] = []*actions_model.ActionRun{base, head}
+
+	ctx.HTML(http.StatusOK, tplCompareRuns)
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+const tplDeployments base.TplName = "repo/actions/deployments"
+
+// DeploymentEnvironment is an environment shown in the deployments dashboard
+type DeploymentEnvironment struct {
+	Name string
+	// Environment is nil if the environment is targeted by jobs but isn't configured in the settings
+	Environment *actions_model.ActionEnvironment
+	// LatestDeployment is nil if the environment has never been deployed successfully
+	LatestDeployment *actions_model.ActionRunJob
+}
+
+// Deployments lists the environments of the repository with their currently deployed refs, and the deployment history
+func Deployments(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.deployments")
+	ctx.Data["PageIsDeployments"] = true
+	curEnvironment := ctx.FormTrim("environment")
+	ctx.Data["CurEnvironment"] = curEnvironment
+
+	environments, err := getDeploymentEnvironments(ctx)
+	if err != nil {
+		ctx.ServerError("getDeploymentEnvironments", err)
+		return
+	}
+	ctx.Data["Environments"] = environments
+
+	page := ctx.FormInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	opts := actions_model.FindDeploymentsOptions{
+		ListOptions: db.ListOptions{
+			Page:     page,
+			PageSize: convert.ToCorrectPageSize(ctx.FormInt("limit")),
+		},
+		RepoID:      ctx.Repo.Repository.ID,
+		Environment: curEnvironment,
+	}
+	deployments, total, err := db.FindAndCount[actions_model.ActionRunJob](ctx, opts)
+	if err != nil {
+		ctx.ServerError("FindAndCount", err)
+		return
+	}
+	if err := actions_model.ActionJobList(deployments).LoadRuns(ctx, false); err != nil {
+		ctx.ServerError("LoadRuns", err)
+		return
+	}
+	ctx.Data["Deployments"] = deployments
+
+	jobs := slices.Clone(deployments)
+	for _, env := range environments {
+		if env.LatestDeployment != nil {
+			jobs = append(jobs, env.LatestDeployment)
+		}
+	}
+	runs := make(actions_model.RunList, 0, len(jobs))
+	for _, job := range jobs {
+		if job.Run != nil {
+			job.Run.Repo = ctx.Repo.Repository
+			runs = append(runs, job.Run)
+		}
+	}
+	if err := runs.LoadTriggerUser(ctx); err != nil {
+		ctx.ServerError("LoadTriggerUser", err)
+		return
+	}
+
+	pager := context.NewPagination(int(total), opts.PageSize, opts.Page, 5)
+	pager.SetDefaultParams(ctx)
+	pager.AddParamString("environment", curEnvironment)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplDeployments)
+}
+
+// getDeploymentEnvironments returns the configured environments and the environments targeted by the jobs, sorted by their names
+func getDeploymentEnvironments(ctx *context.Context) ([]*DeploymentEnvironment, error) {
+	repoID := ctx.Repo.Repository.ID
+	configured, err := db.Find[actions_model.ActionEnvironment](ctx, actions_model.FindEnvironmentsOptions{RepoID: repoID})
+	if err != nil {
+		return nil, err
+	}
+	names, err := actions_model.GetDeploymentEnvironmentNames(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	latestDeployments, err := actions_model.GetLatestDeployments(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	environments := make(map[string]*DeploymentEnvironment, len(configured)+len(names))
+	for _, env := range configured {
+		environments[env.Name] = &DeploymentEnvironment{Name: env.Name, Environment: env}
+	}
+	for _, name := range names {
+		if _, ok := environments[name]; !ok {
+			environments[name] = &DeploymentEnvironment{Name: name}
+		}
+	}
+
+	ret := make([]*DeploymentEnvironment, 0, len(environments))
+	for name, env := range environments {
+		env.LatestDeployment = latestDeployments[name]
+		ret = append(ret, env)
+	}
+	slices.SortFunc(ret, func(a, b *DeploymentEnvironment) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return ret, nil
+}
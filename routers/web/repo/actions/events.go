@@ -0,0 +1,110 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/modules/eventsource"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	actions_service "code.gitea.io/gitea/services/actions"
+	context_module "code.gitea.io/gitea/services/context"
+)
+
+// viewEventsFallbackInterval is the interval to reload the job when there are no signals,
+// it handles the updates which don't come from the runners (e.g. other jobs of the run are started)
+// and the updates handled by other instances of a cluster.
+const viewEventsFallbackInterval = 3 * time.Second
+
+// ViewEvents streams the state of the run and the logs of the job with server-sent events.
+// Unlike ViewPost, the logs of all the steps are pushed, so the client doesn't need to tell which steps are expanded.
+// The stream is closed with a "close" event when the run is done, or immediately if it is done already.
+func ViewEvents(ctx *context_module.Context) {
+	runIndex := getRunIndex(ctx)
+	jobIndex := ctx.PathParamInt64("job")
+
+	current, _ := getRunJobs(ctx, runIndex, jobIndex)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "text/event-stream")
+	ctx.Resp.Header().Set("Cache-Control", "no-cache")
+	ctx.Resp.Header().Set("Connection", "keep-alive")
+	ctx.Resp.Header().Set("X-Accel-Buffering", "no")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	ctx.Resp.Flush()
+
+	if current.Run.Status.IsDone() {
+		// nothing will be changed, let the client load the job in the usual way
+		writeViewEvent(ctx, &eventsource.Event{Name: "close"})
+		return
+	}
+
+	updates, unsubscribe := actions_service.SubscribeJobUpdates(current.ID)
+	defer unsubscribe()
+
+	ticker := time.NewTicker(viewEventsFallbackInterval)
+	defer ticker.Stop()
+
+	shutdownCtx := graceful.GetManager().ShutdownContext()
+
+	req := &ViewRequest{}
+	taskID := current.TaskID
+	for {
+		current, jobs, err := loadRunJobs(ctx, runIndex, jobIndex)
+		if err != nil {
+			log.Error("loadRunJobs: %v", err)
+			writeViewEvent(ctx, &eventsource.Event{Name: "close"})
+			return
+		}
+		if current.TaskID != taskID {
+			// the job has been rerun, the steps of the new task could be different
+			taskID = current.TaskID
+			req.LogCursors = nil
+		}
+		resp, err := getViewResponse(ctx, req, current, jobs, current.Run.Attempt)
+		if err != nil {
+			log.Error("getViewResponse: %v", err)
+			writeViewEvent(ctx, &eventsource.Event{Name: "close"})
+			return
+		}
+
+		// all the steps are treated as expanded, and the cursors move forward with the pushed logs
+		for len(req.LogCursors) < len(resp.State.CurrentJob.Steps) {
+			req.LogCursors = append(req.LogCursors, ViewLogCursor{Step: len(req.LogCursors), Expanded: true})
+		}
+		for _, stepLog := range resp.Logs.StepsLog {
+			req.LogCursors[stepLog.Step].Cursor = stepLog.Cursor
+		}
+
+		if !writeViewEvent(ctx, &eventsource.Event{Name: "state", Data: resp}) {
+			return
+		}
+		if resp.State.Run.Done {
+			writeViewEvent(ctx, &eventsource.Event{Name: "close"})
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-shutdownCtx.Done():
+			return
+		case <-updates:
+		case <-ticker.C:
+		}
+	}
+}
+
+func writeViewEvent(ctx *context_module.Context, event *eventsource.Event) bool {
+	if _, err := event.WriteTo(ctx.Resp); err != nil {
+		log.Debug("Unable to write to EventStream: %v", err)
+		return false
+	}
+	ctx.Resp.Flush()
+	return true
+}
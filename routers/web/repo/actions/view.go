@@ -18,25 +18,24 @@ import (
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
-	"code.gitea.io/gitea/models/perm"
-	access_model "code.gitea.io/gitea/models/perm/access"
+	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
-	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	actions_service "code.gitea.io/gitea/services/actions"
 	context_module "code.gitea.io/gitea/services/context"
-	"code.gitea.io/gitea/services/convert"
 
-	"github.com/nektos/act/pkg/jobparser"
 	"github.com/nektos/act/pkg/model"
 	"xorm.io/builder"
 )
@@ -59,19 +58,25 @@ func View(ctx *context_module.Context) {
 	ctx.Data["JobIndex"] = jobIndex
 	ctx.Data["ActionsURL"] = ctx.Repo.RepoLink + "/actions"
 
-	if getRunJobs(ctx, runIndex, jobIndex); ctx.Written() {
+	current, _ := getRunJobs(ctx, runIndex, jobIndex)
+	if ctx.Written() {
 		return
 	}
+	if attempt := ctx.FormInt64("attempt"); attempt > 0 && attempt < current.Run.Attempt {
+		ctx.Data["Attempt"] = attempt
+	}
 
 	ctx.HTML(http.StatusOK, tplViewActions)
 }
 
 type ViewRequest struct {
-	LogCursors []struct {
-		Step     int   `json:"step"`
-		Cursor   int64 `json:"cursor"`
-		Expanded bool  `json:"expanded"`
-	} `json:"logCursors"`
+	LogCursors []ViewLogCursor `json:"logCursors"`
+}
+
+type ViewLogCursor struct {
+	Step     int   `json:"step"`
+	Cursor   int64 `json:"cursor"`
+	Expanded bool  `json:"expanded"`
 }
 
 type ViewResponse struct {
@@ -83,18 +88,27 @@ type ViewResponse struct {
 			CanCancel         bool       `json:"canCancel"`
 			CanApprove        bool       `json:"canApprove"` // the run needs an approval and the doer has permission to approve
 			CanRerun          bool       `json:"canRerun"`
+			CanRerunFailed    bool       `json:"canRerunFailed"`
 			CanDeleteArtifact bool       `json:"canDeleteArtifact"`
 			Done              bool       `json:"done"`
 			WorkflowID        string     `json:"workflowID"`
 			WorkflowLink      string     `json:"workflowLink"`
 			IsSchedule        bool       `json:"isSchedule"`
+			Attempt           int64      `json:"attempt"`       // the attempt being viewed
+			LatestAttempt     int64      `json:"latestAttempt"` // the previous attempts are read-only
+			CancelReason      string     `json:"cancelReason"`  // the reason why the run was cancelled automatically
 			Jobs              []*ViewJob `json:"jobs"`
 			Commit            ViewCommit `json:"commit"`
+			// the protected environments which some jobs are waiting for
+			PendingDeployments []*ViewPendingDeployment `json:"pendingDeployments"`
+			Annotations        []*ViewAnnotation        `json:"annotations"`
+			Summaries          []*ViewSummary           `json:"summaries"`
 		} `json:"run"`
 		CurrentJob struct {
-			Title  string         `json:"title"`
-			Detail string         `json:"detail"`
-			Steps  []*ViewJobStep `json:"steps"`
+			Title      string              `json:"title"`
+			Detail     string              `json:"detail"`
+			Steps      []*ViewJobStep      `json:"steps"`
+			Containers []*ViewJobContainer `json:"containers"`
 		} `json:"currentJob"`
 	} `json:"state"`
 	Logs struct {
@@ -103,11 +117,41 @@ type ViewResponse struct {
 }
 
 type ViewJob struct {
-	ID       int64  `json:"id"`
-	Name     string `json:"name"`
-	Status   string `json:"status"`
-	CanRerun bool   `json:"canRerun"`
-	Duration string `json:"duration"`
+	ID       int64              `json:"id"`
+	JobID    string             `json:"jobId"`
+	Name     string             `json:"name"`
+	Status   string             `json:"status"`
+	CanRerun bool               `json:"canRerun"`
+	Duration string             `json:"duration"`
+	Matrix   []*ViewMatrixValue `json:"matrix,omitempty"` // the matrix combination which the job is expanded from
+	Caller   string             `json:"caller,omitempty"` // the job id of the caller job if the job is from a reusable workflow
+}
+
+type ViewMatrixValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type ViewPendingDeployment struct {
+	Environment string   `json:"environment"`
+	Jobs        []string `json:"jobs"`
+	CanReview   bool     `json:"canReview"`
+}
+
+type ViewAnnotation struct {
+	Level    string `json:"level"`
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	JobName  string `json:"jobName"`
+	JobLink  string `json:"jobLink"`
+	Location string `json:"location"` // like "src/app.js#L3", it's empty if the annotation isn't about a file
+	FileLink string `json:"fileLink"`
+}
+
+type ViewSummary struct {
+	JobName string `json:"jobName"`
+	JobLink string `json:"jobLink"`
+	Content string `json:"content"` // the rendered HTML of the summaries of the steps of the job
 }
 
 type ViewCommit struct {
@@ -133,6 +177,14 @@ type ViewJobStep struct {
 	Status   string `json:"status"`
 }
 
+// ViewJobContainer is the job container or a service container of the current job
+type ViewJobContainer struct {
+	Service string   `json:"service,omitempty"` // the name of the service, empty for the job container
+	Image   string   `json:"image"`
+	Ports   []string `json:"ports,omitempty"`
+	Status  string   `json:"status"`
+}
+
 type ViewStepLog struct {
 	Step    int                `json:"step"`
 	Cursor  int64              `json:"cursor"`
@@ -155,34 +207,204 @@ func ViewPost(ctx *context_module.Context) {
 	if ctx.Written() {
 		return
 	}
-	run := current.Run
-	if err := run.LoadAttributes(ctx); err != nil {
+
+	resp, err := getViewResponse(ctx, req, current, jobs, getRunAttempt(ctx, current.Run))
+	if err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// getViewAnnotations returns the annotations created by the jobs in the attempt
+func getViewAnnotations(ctx *context_module.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) ([]*ViewAnnotation, error) {
+	taskIDs := make([]int64, 0, len(jobs))
+	jobIndexes := make(map[int64]int, len(jobs))
+	for i, job := range jobs {
+		if job.TaskID != 0 {
+			taskIDs = append(taskIDs, job.TaskID)
+		}
+		jobIndexes[job.ID] = i
+	}
+	views := make([]*ViewAnnotation, 0) // marshal to '[]' instead of 'null' in json
+	if len(taskIDs) == 0 {
+		return views, nil
+	}
+
+	annotations, err := db.Find[actions_model.ActionAnnotation](ctx, actions_model.FindAnnotationOptions{RepoID: run.RepoID, TaskIDs: taskIDs})
+	if err != nil {
+		return nil, err
+	}
+	for _, annotation := range annotations {
+		jobIndex := jobIndexes[annotation.JobID]
+		view := &ViewAnnotation{
+			Level:   string(annotation.Level),
+			Title:   annotation.Title,
+			Message: annotation.Message,
+			JobName: jobs[jobIndex].Name,
+			JobLink: fmt.Sprintf("%s/jobs/%d", run.Link(), jobIndex),
+		}
+		if annotation.Path != "" {
+			view.Location = annotation.Path
+			view.FileLink = fmt.Sprintf("%s/src/commit/%s/%s", run.Repo.Link(), url.PathEscape(run.CommitSHA), util.PathEscapeSegments(annotation.Path))
+			if annotation.StartLine > 0 {
+				anchor := fmt.Sprintf("#L%d", annotation.StartLine)
+				if annotation.EndLine > annotation.StartLine {
+					anchor += fmt.Sprintf("-L%d", annotation.EndLine)
+				}
+				view.Location += anchor
+				view.FileLink += anchor
+			}
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// getViewSummaries returns the rendered step summaries of the jobs in the attempt, the summaries of the steps of a job are joined in order
+func getViewSummaries(ctx *context_module.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) ([]*ViewSummary, error) {
+	taskIDs := make([]int64, 0, len(jobs))
+	for _, job := range jobs {
+		if job.TaskID != 0 {
+			taskIDs = append(taskIDs, job.TaskID)
+		}
+	}
+	views := make([]*ViewSummary, 0) // marshal to '[]' instead of 'null' in json
+	if len(taskIDs) == 0 {
+		return views, nil
+	}
+
+	summaries, err := db.Find[actions_model.ActionStepSummary](ctx, actions_model.FindStepSummaryOptions{RepoID: run.RepoID, TaskIDs: taskIDs})
+	if err != nil {
+		return nil, err
+	}
+	contents := make(map[int64][]string, len(jobs))
+	for _, summary := range summaries {
+		if strings.TrimSpace(summary.Content) != "" {
+			contents[summary.JobID] = append(contents[summary.JobID], summary.Content)
+		}
+	}
+	for i, job := range jobs {
+		if len(contents[job.ID]) == 0 {
+			continue
+		}
+		content, err := markdown.RenderString(&markup.RenderContext{
+			Links: markup.Links{
+				Base: run.Repo.Link(),
+			},
+			Metas: run.Repo.ComposeMetas(ctx),
+			Repo:  run.Repo,
+			Ctx:   ctx,
+		}, strings.Join(contents[job.ID], "\n\n"))
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, &ViewSummary{
+			JobName: job.Name,
+			JobLink: fmt.Sprintf("%s/jobs/%d", run.Link(), i),
+			Content: string(content),
+		})
+	}
+	return views, nil
+}
+
+// getRunAttempt returns the attempt of the run to view, it's the latest attempt if the requested one is out of range
+func getRunAttempt(ctx *context_module.Context, run *actions_model.ActionRun) int64 {
+	if attempt := ctx.FormInt64("attempt"); attempt > 0 && attempt < run.Attempt {
+		return attempt
+	}
+	return run.Attempt
+}
+
+// getViewResponse returns the state of the run and the current job in the attempt, and the logs of the expanded steps after the cursors.
+// The previous attempts are read-only, so all the operations on the run are disabled for them.
+func getViewResponse(ctx *context_module.Context, req *ViewRequest, current *actions_model.ActionRunJob, jobs []*actions_model.ActionRunJob, attempt int64) (*ViewResponse, error) {
+	run := current.Run
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	isLatestAttempt := attempt >= run.Attempt
+	jobs, status, err := actions_model.GetRunAttemptJobs(ctx, run, jobs, attempt)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range jobs {
+		if v.ID == current.ID {
+			current = v
+			break
+		}
+	}
+	canWrite := isLatestAttempt && ctx.Repo.CanWrite(unit.TypeActions)
+
 	resp := &ViewResponse{}
 
 	resp.State.Run.Title = run.Title
 	resp.State.Run.Link = run.Link()
-	resp.State.Run.CanCancel = !run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
-	resp.State.Run.CanApprove = run.NeedApproval && ctx.Repo.CanWrite(unit.TypeActions)
-	resp.State.Run.CanRerun = run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
-	resp.State.Run.CanDeleteArtifact = run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
-	resp.State.Run.Done = run.Status.IsDone()
+	resp.State.Run.CanCancel = !status.IsDone() && canWrite
+	resp.State.Run.CanApprove = run.NeedApproval && canWrite
+	resp.State.Run.CanRerun = status.IsDone() && canWrite
+	resp.State.Run.CanRerunFailed = resp.State.Run.CanRerun && len(actions_service.GetFailedRerunJobs(jobs)) > 0
+	resp.State.Run.CanDeleteArtifact = status.IsDone() && canWrite
+	resp.State.Run.Done = status.IsDone()
 	resp.State.Run.WorkflowID = run.WorkflowID
 	resp.State.Run.WorkflowLink = run.WorkflowLink()
 	resp.State.Run.IsSchedule = run.IsSchedule()
+	resp.State.Run.Attempt = attempt
+	resp.State.Run.LatestAttempt = run.Attempt
 	resp.State.Run.Jobs = make([]*ViewJob, 0, len(jobs)) // marshal to '[]' instead fo 'null' in json
-	resp.State.Run.Status = run.Status.String()
+	resp.State.Run.Status = status.String()
+	if isLatestAttempt && status == actions_model.StatusCancelled && run.CancelReason != "" {
+		resp.State.Run.CancelReason = ctx.Locale.TrString("actions.runs.cancel_reason." + string(run.CancelReason))
+	}
 	for _, v := range jobs {
-		resp.State.Run.Jobs = append(resp.State.Run.Jobs, &ViewJob{
+		viewJob := &ViewJob{
 			ID:       v.ID,
+			JobID:    v.JobID,
 			Name:     v.Name,
 			Status:   v.Status.String(),
-			CanRerun: v.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions),
+			CanRerun: v.Status.IsDone() && canWrite,
 			Duration: v.Duration().String(),
-		})
+			Caller:   v.CallerJobID(),
+		}
+		matrix, err := actions_service.GetRunJobMatrix(v)
+		if err != nil {
+			// the matrix is only for display, so don't fail the whole view
+			log.Error("GetRunJobMatrix: %v", err)
+		}
+		for _, m := range matrix {
+			viewJob.Matrix = append(viewJob.Matrix, &ViewMatrixValue{Key: m.Key, Value: m.Value})
+		}
+		resp.State.Run.Jobs = append(resp.State.Run.Jobs, viewJob)
+	}
+
+	var deployments []*actions_service.PendingDeployment
+	if isLatestAttempt {
+		if deployments, err = actions_service.GetPendingDeployments(ctx, jobs); err != nil {
+			return nil, err
+		}
+	}
+	resp.State.Run.PendingDeployments = make([]*ViewPendingDeployment, 0, len(deployments)) // marshal to '[]' instead fo 'null' in json
+	for _, deployment := range deployments {
+		v := &ViewPendingDeployment{
+			Environment: deployment.Environment.Name,
+			CanReview:   ctx.Doer != nil && deployment.Environment.IsReviewer(ctx.Doer.ID),
+		}
+		for _, job := range deployment.Jobs {
+			v.Jobs = append(v.Jobs, job.Name)
+			if job.ID == current.ID {
+				resp.State.CurrentJob.Detail = ctx.Locale.TrString("actions.environments.waiting_for_approval", deployment.Environment.Name)
+			}
+		}
+		resp.State.Run.PendingDeployments = append(resp.State.Run.PendingDeployments, v)
+	}
+
+	if resp.State.Run.Annotations, err = getViewAnnotations(ctx, run, jobs); err != nil {
+		return nil, err
+	}
+	if resp.State.Run.Summaries, err = getViewSummaries(ctx, run, jobs); err != nil {
+		return nil, err
 	}
 
 	pusher := ViewUser{
@@ -202,27 +424,42 @@ func ViewPost(ctx *context_module.Context) {
 
 	var task *actions_model.ActionTask
 	if current.TaskID > 0 {
-		var err error
 		task, err = actions_model.GetTaskByID(ctx, current.TaskID)
 		if err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
+			return nil, err
 		}
 		task.Job = current
 		if err := task.LoadAttributes(ctx); err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
+			return nil, err
 		}
 	}
 
 	resp.State.CurrentJob.Title = current.Name
-	resp.State.CurrentJob.Detail = current.Status.LocaleString(ctx.Locale)
-	if run.NeedApproval {
+	if resp.State.CurrentJob.Detail == "" {
+		resp.State.CurrentJob.Detail = current.Status.LocaleString(ctx.Locale)
+	}
+	if run.NeedApproval && isLatestAttempt {
 		resp.State.CurrentJob.Detail = ctx.Locale.TrString("actions.need_approval_desc")
 	}
 	resp.State.CurrentJob.Steps = make([]*ViewJobStep, 0) // marshal to '[]' instead fo 'null' in json
 	resp.Logs.StepsLog = make([]*ViewStepLog, 0)          // marshal to '[]' instead fo 'null' in json
+	resp.State.CurrentJob.Containers = make([]*ViewJobContainer, 0)
 	if task != nil {
+		containers, err := actions_service.GetRunJobContainers(current)
+		if err != nil {
+			// the containers are only for display, so don't fail the whole view
+			log.Error("GetRunJobContainers: %v", err)
+		}
+		containerStatus := actions_service.GetJobContainerStatus(task)
+		for _, c := range containers {
+			resp.State.CurrentJob.Containers = append(resp.State.CurrentJob.Containers, &ViewJobContainer{
+				Service: c.Service,
+				Image:   c.Image,
+				Ports:   c.Ports,
+				Status:  string(containerStatus),
+			})
+		}
+
 		steps := actions.FullSteps(task)
 
 		for _, v := range steps {
@@ -277,10 +514,12 @@ func ViewPost(ctx *context_module.Context) {
 				length := step.LogLength - cursor.Cursor
 				offset := task.LogIndexes[index]
 				var err error
+				if err := actions_service.RehydrateLogs(ctx, task); err != nil {
+					return nil, err
+				}
 				logRows, err := actions.ReadLogs(ctx, task.LogInStorage, task.LogFilename, offset, length)
 				if err != nil {
-					ctx.Error(http.StatusInternalServerError, err.Error())
-					return
+					return nil, err
 				}
 
 				for i, row := range logRows {
@@ -301,7 +540,7 @@ func ViewPost(ctx *context_module.Context) {
 		}
 	}
 
-	ctx.JSON(http.StatusOK, resp)
+	return resp, nil
 }
 
 // Rerun will rerun jobs in the given run
@@ -320,25 +559,10 @@ func Rerun(ctx *context_module.Context) {
 		return
 	}
 
-	// can not rerun job when workflow is disabled
-	cfgUnit := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions)
-	cfg := cfgUnit.ActionsConfig()
-	if cfg.IsWorkflowDisabled(run.WorkflowID) {
-		ctx.JSONError(ctx.Locale.Tr("actions.workflow.disabled"))
+	if !prepareRunForRerun(ctx, run) {
 		return
 	}
 
-	// reset run's start and stop time when it is done
-	if run.Status.IsDone() {
-		run.PreviousDuration = run.Duration()
-		run.Started = 0
-		run.Stopped = 0
-		if err := actions_model.UpdateRun(ctx, run, "started", "stopped", "previous_duration"); err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
-	}
-
 	job, jobs := getRunJobs(ctx, runIndex, jobIndex)
 	if ctx.Written() {
 		return
@@ -353,6 +577,11 @@ func Rerun(ctx *context_module.Context) {
 				return
 			}
 		}
+		actions_service.NotifyWorkflowRunRequested(ctx, run.ID)
+		actions_service.NotifyWorkflowJobsStatusUpdate(ctx, jobs...)
+		if err := actions_service.EmitJobsIfReady(run.ID); err != nil {
+			log.Error("EmitJobsIfReady: %v", err)
+		}
 		ctx.JSON(http.StatusOK, struct{}{})
 		return
 	}
@@ -367,10 +596,102 @@ func Rerun(ctx *context_module.Context) {
 			return
 		}
 	}
+	actions_service.NotifyWorkflowRunRequested(ctx, run.ID)
+	actions_service.NotifyWorkflowJobsStatusUpdate(ctx, rerunJobs...)
+	if err := actions_service.EmitJobsIfReady(run.ID); err != nil {
+		log.Error("EmitJobsIfReady: %v", err)
+	}
 
 	ctx.JSON(http.StatusOK, struct{}{})
 }
 
+// RerunFailed will rerun the failed or cancelled jobs in the given run and the jobs depending on them,
+// the succeeded jobs are kept as they are
+func RerunFailed(ctx *context_module.Context) {
+	runIndex := getRunIndex(ctx)
+
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	// a new attempt mustn't be started while the jobs of the current one are still running
+	if !run.Status.IsDone() {
+		ctx.JSONError(ctx.Locale.Tr("actions.runs.not_done"))
+		return
+	}
+
+	_, jobs := getRunJobs(ctx, runIndex, 0)
+	if ctx.Written() {
+		return
+	}
+
+	rerunJobs := actions_service.GetFailedRerunJobs(jobs)
+	if len(rerunJobs) == 0 {
+		ctx.JSONError(ctx.Locale.Tr("actions.runs.no_failed_jobs"))
+		return
+	}
+
+	if !prepareRunForRerun(ctx, run) {
+		return
+	}
+
+	rerunJobsIDSet := make(container.Set[string], len(rerunJobs))
+	for _, j := range rerunJobs {
+		rerunJobsIDSet.Add(j.JobID)
+	}
+	for _, j := range rerunJobs {
+		// the job should be blocked if any of its needs will be rerun
+		shouldBlock := false
+		for _, need := range j.Needs {
+			if rerunJobsIDSet.Contains(need) {
+				shouldBlock = true
+				break
+			}
+		}
+		if err := rerunJob(ctx, j, shouldBlock); err != nil {
+			ctx.Error(http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	actions_service.NotifyWorkflowRunRequested(ctx, run.ID)
+	actions_service.NotifyWorkflowJobsStatusUpdate(ctx, rerunJobs...)
+	if err := actions_service.EmitJobsIfReady(run.ID); err != nil {
+		log.Error("EmitJobsIfReady: %v", err)
+	}
+
+	ctx.JSON(http.StatusOK, struct{}{})
+}
+
+// prepareRunForRerun checks whether the run could be rerun, starts a new attempt of the run and resets its start and stop time,
+// it returns false if the response has been written
+func prepareRunForRerun(ctx *context_module.Context, run *actions_model.ActionRun) bool {
+	// can not rerun job when workflow is disabled
+	cfgUnit := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions)
+	cfg := cfgUnit.ActionsConfig()
+	if cfg.IsWorkflowDisabled(run.WorkflowID) {
+		ctx.JSONError(ctx.Locale.Tr("actions.workflow.disabled"))
+		return false
+	}
+
+	// the tasks created from now on belong to the new attempt, the tasks and the artifacts of the previous attempts are kept
+	run.Attempt++
+	cols := []string{"attempt"}
+	// reset run's start and stop time when it is done
+	if run.Status.IsDone() {
+		run.PreviousDuration = run.Duration()
+		run.Started = 0
+		run.Stopped = 0
+		run.CancelReason = ""
+		cols = append(cols, "started", "stopped", "previous_duration", "cancel_reason")
+	}
+	if err := actions_model.UpdateRun(ctx, run, cols...); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return false
+	}
+	return true
+}
+
 func rerunJob(ctx *context_module.Context, job *actions_model.ActionRunJob, shouldBlock bool) error {
 	status := job.Status
 	if !status.IsDone() {
@@ -379,14 +700,16 @@ func rerunJob(ctx *context_module.Context, job *actions_model.ActionRunJob, shou
 
 	job.TaskID = 0
 	job.Status = actions_model.StatusWaiting
-	if shouldBlock {
+	// the job emitter decides whether the jobs with concurrency groups or environments could start
+	if shouldBlock || job.ConcurrencyGroup != "" || job.Environment != "" {
 		job.Status = actions_model.StatusBlocked
 	}
 	job.Started = 0
 	job.Stopped = 0
+	job.EnvironmentApprovedBy = 0
 
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
-		_, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped")
+		_, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped", "environment_approved_by")
 		return err
 	}); err != nil {
 		return err
@@ -396,35 +719,75 @@ func rerunJob(ctx *context_module.Context, job *actions_model.ActionRunJob, shou
 	return nil
 }
 
-func Logs(ctx *context_module.Context) {
+// RunLogs downloads the logs of all the jobs in the given run as a zip archive
+func RunLogs(ctx *context_module.Context) {
 	runIndex := getRunIndex(ctx)
-	jobIndex := ctx.PathParamInt64("job")
 
-	job, _ := getRunJobs(ctx, runIndex, jobIndex)
-	if ctx.Written() {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
 	}
-	if job.TaskID == 0 {
-		ctx.Error(http.StatusNotFound, "job is not started")
-		return
+
+	ctx.SetServeHeaders(&context_module.ServeHeaderOptions{
+		Filename:    actions_service.RunLogsZipName(run),
+		ContentType: "application/zip",
+		Disposition: "attachment",
+	})
+	if err := actions_service.WriteRunLogsZip(ctx, ctx.Resp, run, getRunAttempt(ctx, run)); err != nil {
+		log.Error("WriteRunLogsZip: %v", err)
 	}
+}
 
-	err := job.LoadRun(ctx)
+// getJobTask returns the job and the task it ran in the viewed attempt of the run,
+// it responds with an error if the job hasn't been started or the logs have been cleaned up.
+func getJobTask(ctx *context_module.Context) (*actions_model.ActionRunJob, *actions_model.ActionTask) {
+	job, jobs := getRunJobs(ctx, getRunIndex(ctx), ctx.PathParamInt64("job"))
+	if ctx.Written() {
+		return nil, nil
+	}
+	jobs, _, err := actions_model.GetRunAttemptJobs(ctx, job.Run, jobs, getRunAttempt(ctx, job.Run))
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
-		return
+		return nil, nil
+	}
+	for _, v := range jobs {
+		if v.ID == job.ID {
+			job = v
+			break
+		}
+	}
+	if job.TaskID == 0 {
+		ctx.Error(http.StatusNotFound, "job is not started")
+		return nil, nil
 	}
 
 	task, err := actions_model.GetTaskByID(ctx, job.TaskID)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
-		return
+		return nil, nil
 	}
 	if task.LogExpired {
 		ctx.Error(http.StatusNotFound, "logs have been cleaned up")
+		return nil, nil
+	}
+	return job, task
+}
+
+func Logs(ctx *context_module.Context) {
+	job, task := getJobTask(ctx)
+	if ctx.Written() {
 		return
 	}
 
+	if err := actions_service.RehydrateLogs(ctx, task); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
 	reader, err := actions.OpenLogs(ctx, task.LogInStorage, task.LogFilename)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
@@ -445,6 +808,43 @@ func Logs(ctx *context_module.Context) {
 	})
 }
 
+// logSearchLimit is the max number of the matches returned by a search in the logs of a job
+const logSearchLimit = 1000
+
+type LogSearchResponse struct {
+	Matches   []*LogSearchMatch `json:"matches"`
+	Truncated bool              `json:"truncated"` // there are more matches than the returned ones
+}
+
+type LogSearchMatch struct {
+	Step int   `json:"step"`
+	Line int64 `json:"line"` // the index of the line in the logs of the step, starting at 1
+}
+
+// LogsSearch returns the lines matching the query in the logs of a job, so the viewer can jump between them
+func LogsSearch(ctx *context_module.Context) {
+	job, task := getJobTask(ctx)
+	if ctx.Written() {
+		return
+	}
+	task.Job = job
+	if err := task.LoadAttributes(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	matches, truncated, err := actions_service.SearchTaskLogs(ctx, task, ctx.FormTrim("q"), logSearchLimit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := &LogSearchResponse{Matches: make([]*LogSearchMatch, 0, len(matches)), Truncated: truncated}
+	for _, match := range matches {
+		resp.Matches = append(resp.Matches, &LogSearchMatch{Step: match.Step, Line: match.Line})
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
 func Cancel(ctx *context_module.Context) {
 	runIndex := getRunIndex(ctx)
 
@@ -482,10 +882,57 @@ func Cancel(ctx *context_module.Context) {
 	}
 
 	actions_service.CreateCommitStatus(ctx, jobs...)
+	actions_service.NotifyWorkflowJobsStatusUpdate(ctx, jobs...)
+
+	// let the runs waiting for the concurrency groups of the run continue
+	if err := actions_service.EmitJobsIfReady(jobs[0].RunID); err != nil {
+		log.Error("EmitJobsIfReady: %v", err)
+	}
 
 	ctx.JSON(http.StatusOK, struct{}{})
 }
 
+// CancelRuns cancels the queued and running runs of a branch, a pull request or a workflow
+func CancelRuns(ctx *context_module.Context) {
+	opts := actions_service.CancelRunsOptions{
+		RepoID:     ctx.Repo.Repository.ID,
+		WorkflowID: ctx.FormTrim("workflow"),
+	}
+	branch, pullIndex := ctx.FormTrim("branch"), ctx.FormInt64("pull_request")
+	if branch != "" && pullIndex > 0 {
+		ctx.Flash.Error(ctx.Tr("actions.runs.cancel_runs_invalid"))
+		ctx.JSONRedirect("")
+		return
+	}
+	if branch != "" {
+		opts.Ref = git.RefNameFromBranch(branch).String()
+	} else if pullIndex > 0 {
+		pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, pullIndex)
+		if err != nil {
+			if issues_model.IsErrPullRequestNotExist(err) {
+				ctx.NotFound("GetPullRequestByIndex", err)
+			} else {
+				ctx.ServerError("GetPullRequestByIndex", err)
+			}
+			return
+		}
+		opts.Ref = pr.GetGitRefName()
+	}
+
+	runs, err := actions_service.CancelRuns(ctx, opts)
+	if err != nil {
+		ctx.ServerError("CancelRuns", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.runs.cancel_runs_success", len(runs)))
+	redirect := ctx.Repo.RepoLink + "/actions"
+	if opts.WorkflowID != "" {
+		redirect += "?workflow=" + url.QueryEscape(opts.WorkflowID)
+	}
+	ctx.JSONRedirect(redirect)
+}
+
 func Approve(ctx *context_module.Context) {
 	runIndex := getRunIndex(ctx)
 
@@ -502,8 +949,12 @@ func Approve(ctx *context_module.Context) {
 		if err := actions_model.UpdateRun(ctx, run, "need_approval", "approved_by"); err != nil {
 			return err
 		}
+		if run.Status.IsBlocked() {
+			// the run is waiting for its concurrency group
+			return nil
+		}
 		for _, job := range jobs {
-			if len(job.Needs) == 0 && job.Status.IsBlocked() {
+			if len(job.Needs) == 0 && job.ConcurrencyGroup == "" && job.Environment == "" && job.Status.IsBlocked() {
 				job.Status = actions_model.StatusWaiting
 				_, err := actions_model.UpdateRunJob(ctx, job, nil, "status")
 				if err != nil {
@@ -518,6 +969,49 @@ func Approve(ctx *context_module.Context) {
 	}
 
 	actions_service.CreateCommitStatus(ctx, jobs...)
+	actions_service.NotifyWorkflowJobsStatusUpdate(ctx, jobs...)
+
+	// the jobs with concurrency groups or environments are left to the job emitter
+	if err := actions_service.EmitJobsIfReady(run.ID); err != nil {
+		log.Error("EmitJobsIfReady: %v", err)
+	}
+
+	ctx.JSON(http.StatusOK, struct{}{})
+}
+
+// ApproveDeployment approves the jobs of the run which are waiting for the protected environment
+func ApproveDeployment(ctx *context_module.Context) {
+	reviewDeployment(ctx, true)
+}
+
+// RejectDeployment rejects and cancels the jobs of the run which are waiting for the protected environment
+func RejectDeployment(ctx *context_module.Context) {
+	reviewDeployment(ctx, false)
+}
+
+func reviewDeployment(ctx *context_module.Context, approve bool) {
+	runIndex := getRunIndex(ctx)
+
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+		} else {
+			ctx.Error(http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if err := actions_service.ReviewPendingDeployment(ctx, ctx.Doer, run.ID, ctx.FormString("environment"), approve); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, err.Error())
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, err.Error())
+		} else {
+			ctx.Error(http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
 
 	ctx.JSON(http.StatusOK, struct{}{})
 }
@@ -526,7 +1020,7 @@ func Approve(ctx *context_module.Context) {
 // Any error will be written to the ctx.
 // It never returns a nil job of an empty jobs, if the jobIndex is out of range, it will be treated as 0.
 func getRunJobs(ctx *context_module.Context, runIndex, jobIndex int64) (*actions_model.ActionRunJob, []*actions_model.ActionRunJob) {
-	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
+	current, jobs, err := loadRunJobs(ctx, runIndex, jobIndex)
 	if err != nil {
 		if errors.Is(err, util.ErrNotExist) {
 			ctx.Error(http.StatusNotFound, err.Error())
@@ -535,15 +1029,22 @@ func getRunJobs(ctx *context_module.Context, runIndex, jobIndex int64) (*actions
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return nil, nil
 	}
+	return current, jobs
+}
+
+// loadRunJobs loads the jobs of the run, and returns the job with the index or the first job if the index is out of range
+func loadRunJobs(ctx *context_module.Context, runIndex, jobIndex int64) (*actions_model.ActionRunJob, []*actions_model.ActionRunJob, error) {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
+	if err != nil {
+		return nil, nil, err
+	}
 	run.Repo = ctx.Repo.Repository
 	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
 	if err != nil {
-		ctx.Error(http.StatusInternalServerError, err.Error())
-		return nil, nil
+		return nil, nil, err
 	}
 	if len(jobs) == 0 {
-		ctx.Error(http.StatusNotFound)
-		return nil, nil
+		return nil, nil, util.NewNotExistErrorf("run %d has no jobs", run.ID)
 	}
 
 	for _, v := range jobs {
@@ -551,9 +1052,95 @@ func getRunJobs(ctx *context_module.Context, runIndex, jobIndex int64) (*actions
 	}
 
 	if jobIndex >= 0 && jobIndex < int64(len(jobs)) {
-		return jobs[jobIndex], jobs
+		return jobs[jobIndex], jobs, nil
 	}
-	return jobs[0], jobs
+	return jobs[0], jobs, nil
+}
+
+type GraphViewResponse struct {
+	Nodes []*GraphViewNode `json:"nodes"`
+	Edges []*GraphViewEdge `json:"edges"`
+}
+
+type GraphViewNode struct {
+	Index  int    `json:"index"` // the index of the job in the run, it's used in the link of the job
+	JobID  string `json:"jobId"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Level  int    `json:"level"`
+}
+
+// GraphViewEdge means the job at index To needs the job at index From
+type GraphViewEdge struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// GraphView returns the dependency graph of the jobs of a run
+func GraphView(ctx *context_module.Context) {
+	_, jobs := getRunJobs(ctx, getRunIndex(ctx), -1)
+	if ctx.Written() {
+		return
+	}
+
+	nodes, edges := actions_service.BuildJobGraph(jobs)
+	resp := &GraphViewResponse{
+		Nodes: make([]*GraphViewNode, 0, len(nodes)),
+		Edges: make([]*GraphViewEdge, 0, len(edges)),
+	}
+	for _, node := range nodes {
+		resp.Nodes = append(resp.Nodes, &GraphViewNode{
+			Index:  node.Index,
+			JobID:  node.Job.JobID,
+			Name:   node.Job.Name,
+			Status: node.Job.Status.String(),
+			Level:  node.Level,
+		})
+	}
+	for _, edge := range edges {
+		resp.Edges = append(resp.Edges, &GraphViewEdge{From: edge.From, To: edge.To})
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+type TimingViewResponse struct {
+	Steps []*TimingViewStep `json:"steps"`
+}
+
+type TimingViewStep struct {
+	JobIndex int    `json:"jobIndex"` // the index of the job in the run, it's used in the link of the job
+	JobName  string `json:"jobName"`
+	StepName string `json:"stepName"`
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Seconds  int64  `json:"seconds"`
+}
+
+// TimingView returns the steps of the jobs of a run sorted by the elapsed time
+func TimingView(ctx *context_module.Context) {
+	_, jobs := getRunJobs(ctx, getRunIndex(ctx), -1)
+	if ctx.Written() {
+		return
+	}
+
+	timings, err := actions_service.GetStepTimings(ctx, jobs)
+	if err != nil {
+		ctx.ServerError("GetStepTimings", err)
+		return
+	}
+	resp := &TimingViewResponse{Steps: make([]*TimingViewStep, 0, len(timings))}
+	for _, timing := range timings {
+		duration := timing.Step.Duration()
+		resp.Steps = append(resp.Steps, &TimingViewStep{
+			JobIndex: timing.JobIndex,
+			JobName:  timing.Job.Name,
+			StepName: timing.Step.Name,
+			Status:   timing.Step.Status.String(),
+			Duration: duration.String(),
+			Seconds:  int64(duration.Seconds()),
+		})
+	}
+	ctx.JSON(http.StatusOK, resp)
 }
 
 type ArtifactsViewResponse struct {
@@ -561,9 +1148,11 @@ type ArtifactsViewResponse struct {
 }
 
 type ArtifactsViewItem struct {
-	Name   string `json:"name"`
-	Size   int64  `json:"size"`
-	Status string `json:"status"`
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	SizeText    string `json:"sizeText"`
+	Status      string `json:"status"`
+	ExpiredUnix int64  `json:"expiredUnix"` // the artifact never expires if it's 0
 }
 
 func ArtifactsView(ctx *context_module.Context) {
@@ -577,7 +1166,7 @@ func ArtifactsView(ctx *context_module.Context) {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
 	}
-	artifacts, err := actions_model.ListUploadedArtifactsMeta(ctx, run.ID)
+	artifacts, err := actions_model.ListUploadedArtifactsMeta(ctx, run.ID, getRunAttempt(ctx, run))
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
@@ -591,9 +1180,11 @@ func ArtifactsView(ctx *context_module.Context) {
 			status = "expired"
 		}
 		artifactsResponse.Artifacts = append(artifactsResponse.Artifacts, &ArtifactsViewItem{
-			Name:   art.ArtifactName,
-			Size:   art.FileSize,
-			Status: status,
+			Name:        art.ArtifactName,
+			Size:        art.FileSize,
+			SizeText:    base.FileSize(art.FileSize),
+			Status:      status,
+			ExpiredUnix: int64(art.ExpiredUnix),
 		})
 	}
 	ctx.JSON(http.StatusOK, artifactsResponse)
@@ -615,7 +1206,7 @@ func ArtifactsDeleteView(ctx *context_module.Context) {
 		}, err)
 		return
 	}
-	if err = actions_model.SetArtifactNeedDelete(ctx, run.ID, artifactName); err != nil {
+	if err = actions_model.SetArtifactNeedDelete(ctx, run.ID, run.Attempt, artifactName); err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -638,6 +1229,7 @@ func ArtifactsDownloadView(ctx *context_module.Context) {
 
 	artifacts, err := db.Find[actions_model.ActionArtifact](ctx, actions_model.FindArtifactsOptions{
 		RunID:        run.ID,
+		RunAttempt:   getRunAttempt(ctx, run),
 		ArtifactName: artifactName,
 	})
 	if err != nil {
@@ -754,93 +1346,51 @@ func disableOrEnableWorkflowFile(ctx *context_module.Context, isEnable bool) {
 	ctx.JSONRedirect(redirectURL)
 }
 
-func Run(ctx *context_module.Context) {
-	redirectURL := fmt.Sprintf("%s/actions?workflow=%s&actor=%s&status=%s", ctx.Repo.RepoLink, url.QueryEscape(ctx.FormString("workflow")),
-		url.QueryEscape(ctx.FormString("actor")), url.QueryEscape(ctx.FormString("status")))
+func PinWorkflow(ctx *context_module.Context) {
+	setWorkflowPinned(ctx, true)
+}
 
-	workflowID := ctx.FormString("workflow")
-	if len(workflowID) == 0 {
+func UnpinWorkflow(ctx *context_module.Context) {
+	setWorkflowPinned(ctx, false)
+}
+
+func setWorkflowPinned(ctx *context_module.Context, pinned bool) {
+	workflow := ctx.FormString("workflow")
+	if len(workflow) == 0 {
 		ctx.ServerError("workflow", nil)
 		return
 	}
 
-	ref := ctx.FormString("ref")
-	if len(ref) == 0 {
-		ctx.ServerError("ref", nil)
+	if err := actions_service.SetWorkflowPinned(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID, workflow, pinned); err != nil {
+		ctx.ServerError("SetWorkflowPinned", err)
 		return
 	}
 
-	// can not rerun job when workflow is disabled
-	cfgUnit := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions)
-	cfg := cfgUnit.ActionsConfig()
-	if cfg.IsWorkflowDisabled(workflowID) {
-		ctx.Flash.Error(ctx.Tr("actions.workflow.disabled"))
-		ctx.Redirect(redirectURL)
-		return
-	}
+	redirectURL := fmt.Sprintf("%s/actions?workflow=%s&actor=%s&status=%s", ctx.Repo.RepoLink, url.QueryEscape(ctx.FormString("cur_workflow")),
+		url.QueryEscape(ctx.FormString("actor")), url.QueryEscape(ctx.FormString("status")))
+	ctx.JSONRedirect(redirectURL)
+}
 
-	// get target commit of run from specified ref
-	refName := git.RefName(ref)
-	var runTargetCommit *git.Commit
-	var err error
-	if refName.IsTag() {
-		runTargetCommit, err = ctx.Repo.GitRepo.GetTagCommit(refName.TagName())
-	} else if refName.IsBranch() {
-		runTargetCommit, err = ctx.Repo.GitRepo.GetBranchCommit(refName.BranchName())
-	} else {
-		ctx.Flash.Error(ctx.Tr("form.git_ref_name_error", ref))
-		ctx.Redirect(redirectURL)
-		return
-	}
-	if err != nil {
-		ctx.Flash.Error(ctx.Tr("form.target_ref_not_exist", ref))
-		ctx.Redirect(redirectURL)
-		return
-	}
+func Run(ctx *context_module.Context) {
+	redirectURL := fmt.Sprintf("%s/actions?workflow=%s&actor=%s&status=%s", ctx.Repo.RepoLink, url.QueryEscape(ctx.FormString("workflow")),
+		url.QueryEscape(ctx.FormString("actor")), url.QueryEscape(ctx.FormString("status")))
 
-	// get workflow entry from default branch commit
-	defaultBranchCommit, err := ctx.Repo.GitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch)
-	if err != nil {
-		ctx.Error(http.StatusInternalServerError, err.Error())
-		return
-	}
-	entries, err := actions.ListWorkflows(defaultBranchCommit)
-	if err != nil {
-		ctx.Error(http.StatusInternalServerError, err.Error())
+	workflowID := ctx.FormString("workflow")
+	if len(workflowID) == 0 {
+		ctx.ServerError("workflow", nil)
 		return
 	}
 
-	// find workflow from commit
-	var workflows []*jobparser.SingleWorkflow
-	for _, entry := range entries {
-		if entry.Name() == workflowID {
-			content, err := actions.GetContentFromEntry(entry)
-			if err != nil {
-				ctx.Error(http.StatusInternalServerError, err.Error())
-				return
-			}
-			workflows, err = jobparser.Parse(content)
-			if err != nil {
-				ctx.ServerError("workflow", err)
-				return
-			}
-			break
-		}
-	}
-
-	if len(workflows) == 0 {
-		ctx.Flash.Error(ctx.Tr("actions.workflow.not_found", workflowID))
-		ctx.Redirect(redirectURL)
+	ref := ctx.FormString("ref")
+	if len(ref) == 0 {
+		ctx.ServerError("ref", nil)
 		return
 	}
 
-	// get inputs from post
-	workflow := &model.Workflow{
-		RawOn: workflows[0].RawOn,
-	}
-	inputs := make(map[string]any)
-	if workflowDispatch := workflow.WorkflowDispatchConfig(); workflowDispatch != nil {
-		for name, config := range workflowDispatch.Inputs {
+	var provided map[string]string
+	_, err := actions_service.DispatchActionWorkflow(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, workflowID, ref, func(cfg *model.WorkflowDispatch, inputs map[string]any) error {
+		provided = make(map[string]string, len(cfg.Inputs))
+		for name, config := range cfg.Inputs {
 			value := ctx.Req.PostForm.Get(name)
 			if config.Type == "boolean" {
 				// https://www.w3.org/TR/html401/interact/forms.html
@@ -848,68 +1398,54 @@ func Run(ctx *context_module.Context) {
 				// Checkboxes (and radio buttons) are on/off switches that may be toggled by the user.
 				// A switch is "on" when the control element's checked attribute is set.
 				// When a form is submitted, only "on" checkbox controls can become successful.
-				inputs[name] = strconv.FormatBool(value == "on")
+				provided[name] = strconv.FormatBool(value == "on")
 			} else if value != "" {
-				inputs[name] = value
-			} else {
-				inputs[name] = config.Default
+				// the default values are used for the empty inputs
+				provided[name] = value
 			}
 		}
-	}
-
-	// ctx.Req.PostForm -> WorkflowDispatchPayload.Inputs -> ActionRun.EventPayload -> runner: ghc.Event
-	// https://docs.github.com/en/actions/learn-github-actions/contexts#github-context
-	// https://docs.github.com/en/webhooks/webhook-events-and-payloads#workflow_dispatch
-	workflowDispatchPayload := &api.WorkflowDispatchPayload{
-		Workflow:   workflowID,
-		Ref:        ref,
-		Repository: convert.ToRepo(ctx, ctx.Repo.Repository, access_model.Permission{AccessMode: perm.AccessModeNone}),
-		Inputs:     inputs,
-		Sender:     convert.ToUserWithAccessMode(ctx, ctx.Doer, perm.AccessModeNone),
-	}
-	var eventPayload []byte
-	if eventPayload, err = workflowDispatchPayload.JSONPayload(); err != nil {
-		ctx.ServerError("JSONPayload", err)
-		return
-	}
-
-	run := &actions_model.ActionRun{
-		Title:             strings.SplitN(runTargetCommit.CommitMessage, "\n", 2)[0],
-		RepoID:            ctx.Repo.Repository.ID,
-		OwnerID:           ctx.Repo.Repository.OwnerID,
-		WorkflowID:        workflowID,
-		TriggerUserID:     ctx.Doer.ID,
-		Ref:               ref,
-		CommitSHA:         runTargetCommit.ID.String(),
-		IsForkPullRequest: false,
-		Event:             "workflow_dispatch",
-		TriggerEvent:      "workflow_dispatch",
-		EventPayload:      string(eventPayload),
-		Status:            actions_model.StatusWaiting,
-	}
-
-	// cancel running jobs of the same workflow
-	if err := actions_model.CancelPreviousJobs(
-		ctx,
-		run.RepoID,
-		run.Ref,
-		run.WorkflowID,
-		run.Event,
-	); err != nil {
-		log.Error("CancelRunningJobs: %v", err)
-	}
-
-	// Insert the action run and its associated jobs into the database
-	if err := actions_model.InsertRun(ctx, run, workflows); err != nil {
-		ctx.ServerError("workflow", err)
+		return actions_service.ValidateWorkflowDispatchInputs(ctx, ctx.Repo.Repository, cfg, provided, inputs)
+	})
+	if err != nil {
+		var inputErr *actions_service.WorkflowInputError
+		switch {
+		case errors.As(err, &inputErr):
+			switch inputErr.Reason {
+			case actions_service.WorkflowInputMissing:
+				ctx.Flash.Error(ctx.Tr("actions.workflow.input_missing", inputErr.Input))
+			case actions_service.WorkflowInputNotBoolean:
+				ctx.Flash.Error(ctx.Tr("actions.workflow.input_not_boolean", inputErr.Input))
+			case actions_service.WorkflowInputNotNumber:
+				ctx.Flash.Error(ctx.Tr("actions.workflow.input_not_number", inputErr.Input))
+			case actions_service.WorkflowInputNotChoice:
+				ctx.Flash.Error(ctx.Tr("actions.workflow.input_not_choice", inputErr.Input, strings.Join(inputErr.Options, ", ")))
+			case actions_service.WorkflowInputNotEnvironment:
+				ctx.Flash.Error(ctx.Tr("actions.workflow.input_not_environment", inputErr.Input))
+			default:
+				ctx.Flash.Error(ctx.Tr("actions.workflow.input_unexpected", inputErr.Input))
+			}
+		case errors.Is(err, actions_service.ErrWorkflowDisabled):
+			ctx.Flash.Error(ctx.Tr("actions.workflow.disabled"))
+		case errors.Is(err, actions_service.ErrInvalidTargetRef):
+			ctx.Flash.Error(ctx.Tr("form.git_ref_name_error", ref))
+		case errors.Is(err, actions_service.ErrTargetRefMissing):
+			ctx.Flash.Error(ctx.Tr("form.target_ref_not_exist", ref))
+		case errors.Is(err, actions_service.ErrWorkflowNotFound):
+			ctx.Flash.Error(ctx.Tr("actions.workflow.not_found", workflowID))
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Flash.Error(ctx.Tr("actions.workflow.no_workflow_dispatch", workflowID))
+		default:
+			ctx.ServerError("DispatchActionWorkflow", err)
+			return
+		}
+		ctx.Redirect(redirectURL)
 		return
 	}
 
-	alljobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
-	if err != nil {
-		log.Error("FindRunJobs: %v", err)
+	// remember what the user submitted to pre-fill the dispatch form next time
+	if err := actions_service.RememberDispatch(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID, workflowID, ref, provided); err != nil {
+		log.Error("RememberDispatch: %v", err)
 	}
-	actions_service.CreateCommitStatus(ctx, alljobs...)
 
 	ctx.Flash.Success(ctx.Tr("actions.workflow.run_success", workflowID))
 	ctx.Redirect(redirectURL)
@@ -0,0 +1,219 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/translation"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/forms"
+	files_service "code.gitea.io/gitea/services/repository/files"
+)
+
+const (
+	tplNewWorkflow base.TplName = "repo/actions/workflow_new"
+
+	// newWorkflowDir is the directory of the workflows created by the editor
+	newWorkflowDir = ".gitea/workflows"
+
+	defaultNewWorkflowContent = `name: CI
+on:
+  push:
+  pull_request:
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - run: echo "Hello, Gitea Actions!"
+`
+)
+
+// onlineRunnerLabels returns the labels of the online runners available to the repository
+func onlineRunnerLabels(ctx *context.Context) (container.Set[string], error) {
+	runners, err := db.Find[actions_model.ActionRunner](ctx, actions_model.FindRunnerOptions{
+		RepoID:        ctx.Repo.Repository.ID,
+		IsOnline:      optional.Some(true),
+		WithAvailable: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	labels := make(container.Set[string])
+	for _, r := range runners {
+		labels.AddMultiple(r.AgentLabels...)
+	}
+	return labels, nil
+}
+
+// firstLintProblem returns the first error, or the first warning if there is no error
+func firstLintProblem(problems []*actions.LintProblem) *actions.LintProblem {
+	for _, p := range problems {
+		if p.Severity == actions.LintSeverityError {
+			return p
+		}
+	}
+	if len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+func lintProblemMessage(locale translation.Locale, p *actions.LintProblem) string {
+	switch p.Kind {
+	case actions.LintInvalidWorkflow:
+		return locale.TrString("actions.runs.invalid_workflow_helper", p.Arg)
+	case actions.LintNoMatchingRunner:
+		return locale.TrString("actions.runs.no_matching_online_runner_helper", p.Arg)
+	case actions.LintNoJob:
+		return locale.TrString("actions.runs.no_job")
+	case actions.LintNoJobWithoutNeeds:
+		return locale.TrString("actions.runs.no_job_without_needs")
+	case actions.LintUnknownKey:
+		return locale.TrString("actions.workflow.lint.unknown_key", p.Arg)
+	case actions.LintInvalidCron:
+		return locale.TrString("actions.workflow.lint.invalid_cron", p.Arg)
+	case actions.LintContainerNoImage:
+		return locale.TrString("actions.workflow.lint.container_no_image", p.Arg)
+	case actions.LintInvalidContainerPort:
+		return locale.TrString("actions.workflow.lint.invalid_container_port", p.Arg)
+	case actions.LintUnsupportedContainerOption:
+		return locale.TrString("actions.workflow.lint.unsupported_container_option", p.Arg)
+	}
+	return string(p.Kind)
+}
+
+// WorkflowLintProblem is a problem found in a workflow shown in the editor
+type WorkflowLintProblem struct {
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func lintWorkflowContent(ctx *context.Context, content string) ([]*WorkflowLintProblem, bool, error) {
+	runnerLabels, err := onlineRunnerLabels(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	problems := actions.LintWorkflow([]byte(content), runnerLabels)
+	res := make([]*WorkflowLintProblem, 0, len(problems))
+	for _, p := range problems {
+		res = append(res, &WorkflowLintProblem{
+			Line:     p.Line,
+			Column:   p.Column,
+			Severity: string(p.Severity),
+			Message:  lintProblemMessage(ctx.Locale, p),
+		})
+	}
+	return res, actions.HasLintErrors(problems), nil
+}
+
+func prepareNewWorkflow(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.workflow.new")
+	ctx.Data["PageIsActions"] = true
+	ctx.Data["WorkflowDir"] = newWorkflowDir
+	ctx.Data["BranchName"] = ctx.Repo.Repository.DefaultBranch
+	ctx.Data["LintLink"] = ctx.Repo.RepoLink + "/actions/workflows/lint"
+}
+
+// NewWorkflow renders the editor to create a new workflow
+func NewWorkflow(ctx *context.Context) {
+	prepareNewWorkflow(ctx)
+	ctx.Data["file_name"] = "ci.yml"
+	ctx.Data["content"] = defaultNewWorkflowContent
+	ctx.HTML(http.StatusOK, tplNewWorkflow)
+}
+
+// NewWorkflowPost validates the new workflow and commits it to the default branch
+func NewWorkflowPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.NewWorkflowForm)
+	prepareNewWorkflow(ctx)
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplNewWorkflow)
+		return
+	}
+
+	fileName := strings.TrimSpace(form.FileName)
+	if strings.ContainsAny(fileName, `/\`) || !actions.IsWorkflow(path.Join(newWorkflowDir, fileName)) {
+		ctx.Data["Err_FileName"] = true
+		ctx.RenderWithErr(ctx.Tr("actions.workflow.invalid_file_name", fileName), tplNewWorkflow, form)
+		return
+	}
+
+	problems, hasErrors, err := lintWorkflowContent(ctx, form.Content)
+	if err != nil {
+		ctx.ServerError("lintWorkflowContent", err)
+		return
+	}
+	if hasErrors {
+		ctx.Data["Problems"] = problems
+		ctx.RenderWithErr(ctx.Tr("actions.workflow.lint.has_errors"), tplNewWorkflow, form)
+		return
+	}
+
+	treePath := path.Join(newWorkflowDir, fileName)
+	message := strings.TrimSpace(form.CommitSummary)
+	if message == "" {
+		message = ctx.Locale.TrString("repo.editor.add", treePath)
+	}
+	if body := strings.TrimSpace(form.CommitMessage); body != "" {
+		message += "\n\n" + body
+	}
+
+	branch := ctx.Repo.Repository.DefaultBranch
+	if _, err := files_service.ChangeRepoFiles(ctx, ctx.Repo.Repository, ctx.Doer, &files_service.ChangeRepoFilesOptions{
+		Files: []*files_service.ChangeRepoFile{
+			{
+				Operation:     "create",
+				TreePath:      treePath,
+				ContentReader: strings.NewReader(strings.ReplaceAll(form.Content, "\r", "")),
+			},
+		},
+		Message:   message,
+		OldBranch: branch,
+		NewBranch: branch,
+		Dates: &files_service.CommitDateOptions{
+			Author:    time.Now(),
+			Committer: time.Now(),
+		},
+	}); err != nil {
+		if models.IsErrRepoFileAlreadyExists(err) {
+			ctx.Data["Err_FileName"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.editor.file_already_exists", treePath), tplNewWorkflow, form)
+		} else if models.IsErrUserCannotCommit(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.editor.cannot_commit_to_protected_branch", branch), tplNewWorkflow, form)
+		} else {
+			ctx.ServerError("ChangeRepoFiles", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.workflow.new_success", fileName))
+	ctx.Redirect(ctx.Repo.RepoLink + "/actions?workflow=" + url.QueryEscape(fileName))
+}
+
+// LintWorkflow checks the content of a workflow for the editor
+func LintWorkflow(ctx *context.Context) {
+	problems, _, err := lintWorkflowContent(ctx, ctx.FormString("content"))
+	if err != nil {
+		ctx.ServerError("lintWorkflowContent", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]any{"problems": problems})
+}
@@ -199,19 +199,18 @@ func httpBase(ctx *context.Context) *serviceHandler {
 					return nil
 				}
 
-				if task.IsForkPullRequest {
-					if accessMode > perm.AccessModeRead {
-						ctx.PlainText(http.StatusForbidden, "User permission denied")
-						return nil
-					}
-					environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, perm.AccessModeRead))
-				} else {
-					if accessMode > perm.AccessModeWrite {
-						ctx.PlainText(http.StatusForbidden, "User permission denied")
-						return nil
-					}
-					environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, perm.AccessModeWrite))
+				tokenPerms, err := actions_model.GetTaskTokenPermissions(ctx, task)
+				if err != nil {
+					ctx.ServerError("GetTaskTokenPermissions", err)
+					return nil
+				}
+				unitType := util.Iif(isWiki, unit.TypeWiki, unit.TypeCode)
+				tokenMode := tokenPerms.UnitAccessModes()[unitType]
+				if accessMode > tokenMode {
+					ctx.PlainText(http.StatusForbidden, "User permission denied")
+					return nil
 				}
+				environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, tokenMode))
 			} else {
 				p, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
 				if err != nil {
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/models"
+	actions_model "code.gitea.io/gitea/models/actions"
 	activities_model "code.gitea.io/gitea/models/activities"
 	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
@@ -31,6 +32,7 @@ import (
 	issue_template "code.gitea.io/gitea/modules/issue/template"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/utils"
@@ -488,7 +490,48 @@ func PrepareViewPullInfo(ctx *context.Context, issue *issues_model.Issue) *git.C
 			}
 			return false
 		}
-		ctx.Data["RequiredStatusCheckState"] = pull_service.MergeRequiredContextsCommitStatus(commitStatuses, pb.StatusCheckContexts)
+		requiredState := pull_service.MergeRequiredContextsCommitStatus(commitStatuses, pb.StatusCheckContexts)
+		if len(pb.RequiredWorkflows) > 0 {
+			workflowStates, err := actions_model.GetRequiredWorkflowStates(ctx, repo.ID, sha, pb.RequiredWorkflows)
+			if err != nil {
+				ctx.ServerError("GetRequiredWorkflowStates", err)
+				return nil
+			}
+			for _, state := range workflowStates {
+				if state.Run != nil {
+					state.Run.Repo = repo
+				}
+			}
+			ctx.Data["RequiredWorkflowStates"] = workflowStates
+			// without any status check pattern, only the required workflows are checked
+			if workflowState := actions_model.MergeRequiredWorkflowStates(workflowStates); len(pb.StatusCheckContexts) == 0 || workflowState.NoBetterThan(requiredState) {
+				requiredState = workflowState
+			}
+		}
+		ctx.Data["RequiredStatusCheckState"] = requiredState
+	}
+
+	// the required workflows of the organization are checked even if the status checks aren't enabled
+	orgWorkflowStates, err := actions_model.GetOrgRequiredWorkflowStates(ctx, repo, sha)
+	if err != nil {
+		ctx.ServerError("GetOrgRequiredWorkflowStates", err)
+		return nil
+	}
+	if len(orgWorkflowStates) > 0 {
+		for _, state := range orgWorkflowStates {
+			state.Run.Repo = repo
+		}
+		requiredState := api.CommitStatusSuccess
+		if pb != nil && pb.EnableStatusCheck {
+			requiredState = ctx.Data["RequiredStatusCheckState"].(api.CommitStatusState)
+		}
+		if workflowState := actions_model.MergeRequiredWorkflowStates(orgWorkflowStates); workflowState.NoBetterThan(requiredState) {
+			requiredState = workflowState
+		}
+		workflowStates, _ := ctx.Data["RequiredWorkflowStates"].([]*actions_model.RequiredWorkflowState)
+		ctx.Data["RequiredWorkflowStates"] = append(workflowStates, orgWorkflowStates...)
+		ctx.Data["RequiredStatusCheckState"] = requiredState
+		ctx.Data["EnableStatusCheck"] = true
 	}
 
 	ctx.Data["HeadBranchMovedOn"] = headBranchSha != sha
@@ -769,6 +812,22 @@ func viewPullFiles(ctx *context.Context, specifiedStartCommit, specifiedEndCommi
 		return
 	}
 
+	if ctx.Repo.CanRead(unit.TypeActions) {
+		annotations, err := db.Find[actions_model.ActionAnnotation](ctx, actions_model.FindAnnotationOptions{
+			RepoID:    ctx.Repo.Repository.ID,
+			CommitSHA: endCommitID,
+		})
+		if err != nil {
+			ctx.ServerError("FindAnnotations", err)
+			return
+		}
+		if err := actions_model.AnnotationList(annotations).LoadRuns(ctx); err != nil {
+			ctx.ServerError("LoadRuns", err)
+			return
+		}
+		diff.LoadAnnotations(annotations)
+	}
+
 	for _, file := range diff.Files {
 		for _, section := range file.Sections {
 			for _, line := range section.Lines {
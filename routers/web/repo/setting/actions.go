@@ -0,0 +1,73 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
+	shared "code.gitea.io/gitea/routers/web/shared/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/forms"
+)
+
+const tplRepoActionsGeneral base.TplName = "repo/settings/actions"
+
+// ActionsGeneralSettings render the general settings of actions for a repository
+func ActionsGeneralSettings(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.general")
+	ctx.Data["PageType"] = "general"
+	ctx.Data["PageIsSharedSettingsGeneral"] = true
+
+	cfg := ctx.Repo.Repository.MustGetUnit(ctx, unit_model.TypeActions).ActionsConfig()
+	ctx.Data["ActionsConfig"] = cfg
+	ctx.Data["DefaultRunRetentionDays"] = setting.Actions.RunRetentionDays
+	ctx.Data["ApprovalPolicy"] = string(cfg.GetApprovalPolicy())
+	shared.SetAllowedActionsContext(ctx, actions_module.NewAllowedActions(cfg.AllowedActions, cfg.AllowedActionsPatterns))
+
+	ctx.HTML(http.StatusOK, tplRepoActionsGeneral)
+}
+
+// ActionsGeneralSettingsPost response for updating the general settings of actions for a repository
+func ActionsGeneralSettingsPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.ActionsGeneralSettingsForm)
+	redirectURL := ctx.Repo.RepoLink + "/settings/actions/general"
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(redirectURL)
+		return
+	}
+
+	actionsUnit, err := ctx.Repo.Repository.GetUnit(ctx, unit_model.TypeActions)
+	if err != nil {
+		ctx.ServerError("GetUnit", err)
+		return
+	}
+	cfg := actionsUnit.ActionsConfig()
+	cfg.RunRetentionDays = form.RunRetentionDays
+	cfg.ApprovalPolicy = repo_model.ActionsApprovalPolicy(form.ApprovalPolicy)
+	// "all" is stored as empty, which means no more restrictions than the instance and the owner
+	cfg.AllowedActions, cfg.AllowedActionsPatterns = "", nil
+	switch actions_module.AllowedActionsPolicy(form.AllowedActions) {
+	case actions_module.AllowedActionsLocal:
+		cfg.AllowedActions = form.AllowedActions
+	case actions_module.AllowedActionsSelected:
+		cfg.AllowedActions = form.AllowedActions
+		cfg.AllowedActionsPatterns = actions_module.ParseAllowedActionsPatterns(form.AllowedActionsPatterns)
+	}
+
+	if err := repo_model.UpdateRepoUnit(ctx, actionsUnit); err != nil {
+		ctx.ServerError("UpdateRepoUnit", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.general.update_success"))
+	ctx.Redirect(redirectURL)
+}
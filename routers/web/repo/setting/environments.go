@@ -0,0 +1,180 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	shared "code.gitea.io/gitea/routers/web/shared/secrets"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/forms"
+)
+
+const tplRepoEnvironments base.TplName = "repo/settings/actions"
+
+// Environments render the deployment environments of a repository
+func Environments(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.environments")
+	ctx.Data["PageType"] = "environments"
+	ctx.Data["PageIsSharedSettingsEnvironments"] = true
+
+	envs, err := db.Find[actions_model.ActionEnvironment](ctx, actions_model.FindEnvironmentsOptions{RepoID: ctx.Repo.Repository.ID})
+	if err != nil {
+		ctx.ServerError("FindEnvironments", err)
+		return
+	}
+	ctx.Data["Environments"] = envs
+
+	ctx.HTML(http.StatusOK, tplRepoEnvironments)
+}
+
+// EnvironmentsPost response for creating a deployment environment
+func EnvironmentsPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.CreateEnvironmentForm)
+
+	if ctx.HasError() {
+		ctx.JSONError(ctx.GetErrMsg())
+		return
+	}
+
+	env, err := actions_service.CreateEnvironment(ctx, ctx.Repo.Repository.ID, form.Name)
+	if err != nil {
+		if errors.Is(err, util.ErrAlreadyExist) {
+			ctx.JSONError(ctx.Tr("actions.environments.creation.already_exists", form.Name))
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.JSONError(ctx.Tr("actions.environments.creation.invalid_name", form.Name))
+		} else {
+			ctx.ServerError("CreateEnvironment", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.environments.creation.success", env.Name))
+	ctx.JSONRedirect(environmentLink(ctx, env))
+}
+
+func getEnvironment(ctx *context.Context) *actions_model.ActionEnvironment {
+	env, err := actions_model.GetEnvironmentByID(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64("environment_id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound("GetEnvironmentByID", err)
+		} else {
+			ctx.ServerError("GetEnvironmentByID", err)
+		}
+		return nil
+	}
+	return env
+}
+
+func environmentLink(ctx *context.Context, env *actions_model.ActionEnvironment) string {
+	return fmt.Sprintf("%s/settings/actions/environments/%d", ctx.Repo.RepoLink, env.ID)
+}
+
+// Environment render the reviewers and the secrets of a deployment environment
+func Environment(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("actions.environments")
+	ctx.Data["PageType"] = "environment"
+	ctx.Data["PageIsSharedSettingsEnvironments"] = true
+	ctx.Data["Environment"] = env
+	ctx.Data["SecretsLink"] = environmentLink(ctx, env) + "/secrets"
+
+	reviewers, err := user_model.GetUserNamesByIDs(ctx, env.ReviewerIDs)
+	if err != nil {
+		ctx.ServerError("GetUserNamesByIDs", err)
+		return
+	}
+	ctx.Data["Reviewers"] = strings.Join(reviewers, ", ")
+
+	shared.SetSecretsContext(ctx, 0, ctx.Repo.Repository.ID, env.ID)
+	if ctx.Written() {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplRepoEnvironments)
+}
+
+// EnvironmentReviewersPost response for updating the required reviewers of a deployment environment
+func EnvironmentReviewersPost(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+	form := web.GetForm(ctx).(*forms.EditEnvironmentReviewersForm)
+	redirectURL := environmentLink(ctx, env)
+
+	var names []string
+	for _, name := range strings.Split(form.Reviewers, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if err := actions_service.UpdateEnvironmentReviewers(ctx, ctx.Repo.Repository, env, names); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Flash.Error(ctx.Tr("actions.environments.reviewers.invalid", err.Error()))
+			ctx.Redirect(redirectURL)
+		} else {
+			ctx.ServerError("UpdateEnvironmentReviewers", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.environments.reviewers.update_success"))
+	ctx.Redirect(redirectURL)
+}
+
+// EnvironmentDelete response for deleting a deployment environment with its secrets
+func EnvironmentDelete(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_service.DeleteEnvironment(ctx, env); err != nil {
+		ctx.ServerError("DeleteEnvironment", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.environments.deletion.success", env.Name))
+	ctx.JSONRedirect(ctx.Repo.RepoLink + "/settings/actions/environments")
+}
+
+// EnvironmentSecretsPost response for adding a secret to a deployment environment
+func EnvironmentSecretsPost(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.JSONError(ctx.GetErrMsg())
+		return
+	}
+
+	shared.PerformSecretsPost(ctx, 0, ctx.Repo.Repository.ID, env.ID, environmentLink(ctx, env))
+}
+
+// EnvironmentSecretsDelete response for deleting a secret of a deployment environment
+func EnvironmentSecretsDelete(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	shared.PerformSecretsDelete(ctx, 0, ctx.Repo.Repository.ID, env.ID, environmentLink(ctx, env))
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	git_model "code.gitea.io/gitea/models/git"
 	"code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/perm"
@@ -81,6 +82,7 @@ func SettingsProtectedBranch(c *context.Context) {
 	c.Data["merge_whitelist_users"] = strings.Join(base.Int64sToStrings(rule.MergeWhitelistUserIDs), ",")
 	c.Data["approvals_whitelist_users"] = strings.Join(base.Int64sToStrings(rule.ApprovalsWhitelistUserIDs), ",")
 	c.Data["status_check_contexts"] = strings.Join(rule.StatusCheckContexts, "\n")
+	c.Data["required_workflows"] = strings.Join(rule.RequiredWorkflows, "\n")
 	contexts, _ := git_model.FindRepoRecentCommitStatusContexts(c, c.Repo.Repository.ID, 7*24*time.Hour) // Find last week status check contexts
 	c.Data["recent_status_checks"] = contexts
 
@@ -227,15 +229,31 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 			}
 			validPatterns = append(validPatterns, trimmed)
 		}
-		if len(validPatterns) == 0 {
+		workflows := strings.Split(strings.ReplaceAll(f.RequiredWorkflows, "\r", "\n"), "\n")
+		requiredWorkflows := make([]string, 0, len(workflows))
+		for _, workflow := range workflows {
+			trimmed := strings.TrimSpace(workflow)
+			if trimmed == "" {
+				continue
+			}
+			if !actions_model.IsValidRequiredWorkflow(trimmed) {
+				ctx.Flash.Error(ctx.Tr("repo.settings.protect_invalid_required_workflow", workflow))
+				ctx.Redirect(fmt.Sprintf("%s/settings/branches/edit?rule_name=%s", ctx.Repo.RepoLink, url.QueryEscape(protectBranch.RuleName)))
+				return
+			}
+			requiredWorkflows = append(requiredWorkflows, trimmed)
+		}
+		if len(validPatterns) == 0 && len(requiredWorkflows) == 0 {
 			// if status check is enabled, patterns slice is not allowed to be empty
 			ctx.Flash.Error(ctx.Tr("repo.settings.protect_no_valid_status_check_patterns"))
 			ctx.Redirect(fmt.Sprintf("%s/settings/branches/edit?rule_name=%s", ctx.Repo.RepoLink, url.QueryEscape(protectBranch.RuleName)))
 			return
 		}
 		protectBranch.StatusCheckContexts = validPatterns
+		protectBranch.RequiredWorkflows = requiredWorkflows
 	} else {
 		protectBranch.StatusCheckContexts = nil
+		protectBranch.RequiredWorkflows = nil
 	}
 
 	protectBranch.RequiredApprovals = f.RequiredApprovals
@@ -87,7 +87,7 @@ func Secrets(ctx *context.Context) {
 		ctx.Data["DisableSSH"] = setting.SSH.Disabled
 	}
 
-	shared.SetSecretsContext(ctx, sCtx.OwnerID, sCtx.RepoID)
+	shared.SetSecretsContext(ctx, sCtx.OwnerID, sCtx.RepoID, 0)
 	if ctx.Written() {
 		return
 	}
@@ -110,6 +110,7 @@ func SecretsPost(ctx *context.Context) {
 		ctx,
 		sCtx.OwnerID,
 		sCtx.RepoID,
+		0,
 		sCtx.RedirectLink,
 	)
 }
@@ -124,6 +125,7 @@ func SecretsDelete(ctx *context.Context) {
 		ctx,
 		sCtx.OwnerID,
 		sCtx.RepoID,
+		0,
 		sCtx.RedirectLink,
 	)
 }
@@ -569,6 +569,7 @@ func SettingsPost(ctx *context.Context) {
 			units = append(units, repo_model.RepoUnit{
 				RepoID: repo.ID,
 				Type:   unit_model.TypeActions,
+				Config: repo.MustGetUnit(ctx, unit_model.TypeActions).ActionsConfig(),
 			})
 		} else if !unit_model.TypeActions.UnitGlobalDisabled() {
 			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypeActions)
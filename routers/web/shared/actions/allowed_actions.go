@@ -0,0 +1,17 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/context"
+)
+
+// SetAllowedActionsContext sets the allowed actions policy to edit, and the policy of the instance which can't be relaxed
+func SetAllowedActionsContext(ctx *context.Context, allowed *actions_module.AllowedActions) {
+	ctx.Data["AllowedActions"] = string(allowed.Policy)
+	ctx.Data["AllowedActionsPatterns"] = allowed.Patterns
+	ctx.Data["InstanceAllowedActions"] = actions_module.NewAllowedActions(setting.Actions.AllowedActions, setting.Actions.AllowedActionsPatterns)
+}
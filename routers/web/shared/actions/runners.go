@@ -5,10 +5,12 @@ package actions
 
 import (
 	"errors"
+	"slices"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/context"
@@ -49,12 +51,21 @@ func RunnersList(ctx *context.Context, opts actions_model.FindRunnerOptions) {
 	ctx.Data["RunnerOwnerID"] = opts.OwnerID
 	ctx.Data["RunnerRepoID"] = opts.RepoID
 	ctx.Data["SortType"] = opts.Sort
+	ctx.Data["HasOutdatedRunners"] = slices.ContainsFunc(runners, (*actions_model.ActionRunner).IsOutdated)
+	setRunnerVersionData(ctx)
 
 	pager := context.NewPagination(int(count), opts.PageSize, opts.Page, 5)
 
 	ctx.Data["Page"] = pager
 }
 
+// setRunnerVersionData sets the required versions of the runners to show the warnings of the outdated runners
+func setRunnerVersionData(ctx *context.Context) {
+	ctx.Data["MinRunnerVersion"] = setting.Actions.MinRunnerVersion
+	ctx.Data["RecommendedRunnerVersion"] = setting.Actions.RecommendedRunnerVersion
+	ctx.Data["RejectOutdatedRunners"] = setting.Actions.RejectOutdatedRunners
+}
+
 // RunnerDetails prepares data for runners edit page
 func RunnerDetails(ctx *context.Context, page int, runnerID, ownerID, repoID int64) {
 	runner, err := actions_model.GetRunnerByID(ctx, runnerID)
@@ -73,6 +84,7 @@ func RunnerDetails(ctx *context.Context, page int, runnerID, ownerID, repoID int
 	}
 
 	ctx.Data["Runner"] = runner
+	setRunnerVersionData(ctx)
 
 	opts := actions_model.FindTaskOptions{
 		ListOptions: db.ListOptions{
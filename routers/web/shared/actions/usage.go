@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+// usageMonthsCount is the count of the recent months which could be selected to show the usage
+const usageMonthsCount = 12
+
+// setUsageMonths reads the month to show from the query, and sets the recent months for selection
+func setUsageMonths(ctx *context.Context) (string, bool) {
+	month, err := actions_service.ParseUsageMonth(ctx.FormString("month"))
+	if err != nil {
+		ctx.NotFound("ParseUsageMonth", err)
+		return "", false
+	}
+
+	months := make([]string, 0, usageMonthsCount)
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	for i := 0; i < usageMonthsCount; i++ {
+		months = append(months, actions_model.UsageMonth(start.AddDate(0, -i, 0)))
+	}
+	ctx.Data["UsageMonth"] = month
+	ctx.Data["UsageMonths"] = months
+	return month, true
+}
+
+// SetUsageContext sets the usage of actions of the owner in the month of the query
+func SetUsageContext(ctx *context.Context, ownerID int64) {
+	month, ok := setUsageMonths(ctx)
+	if !ok {
+		return
+	}
+	summary, err := actions_service.GetOwnerUsageSummary(ctx, ownerID, month)
+	if err != nil {
+		ctx.ServerError("GetOwnerUsageSummary", err)
+		return
+	}
+	ctx.Data["Usage"] = summary
+}
+
+// SetOwnerUsagesContext sets the usages of actions of all owners in the month of the query
+func SetOwnerUsagesContext(ctx *context.Context) {
+	month, ok := setUsageMonths(ctx)
+	if !ok {
+		return
+	}
+	summaries, err := actions_service.ListOwnerUsageSummaries(ctx, month)
+	if err != nil {
+		ctx.ServerError("ListOwnerUsageSummaries", err)
+		return
+	}
+	ctx.Data["OwnerUsages"] = summaries
+}
@@ -791,6 +791,8 @@ func registerRoutes(m *web.Router) {
 			m.Get("", admin.RedirectToDefaultSetting)
 			addSettingsRunnersRoutes()
 			addSettingsVariablesRoutes()
+			m.Get("/usage", admin.ActionsUsage)
+			m.Post("/usage/quota", web.Bind(forms.AdminActionsUsageQuotaForm{}), admin.ActionsUsageQuotaPost)
 		})
 	}, adminReq, ctxDataSet("EnableOAuth2", setting.OAuth2.Enabled, "EnablePackages", setting.Packages.Enabled))
 	// ***** END: Admin *****
@@ -937,9 +939,17 @@ func registerRoutes(m *web.Router) {
 
 				m.Group("/actions", func() {
 					m.Get("", org_setting.RedirectToDefaultSetting)
+					m.Combo("/general").Get(org_setting.ActionsGeneralSettings).
+						Post(web.Bind(forms.ActionsAllowedActionsForm{}), org_setting.ActionsGeneralSettingsPost)
 					addSettingsRunnersRoutes()
 					addSettingsSecretsRoutes()
 					addSettingsVariablesRoutes()
+					m.Get("/usage", org_setting.ActionsUsage)
+					m.Group("/required_workflows", func() {
+						m.Get("", org_setting.ActionsRequiredWorkflows)
+						m.Post("/new", web.Bind(forms.OrgRequiredWorkflowForm{}), org_setting.ActionsRequiredWorkflowsCreate)
+						m.Post("/{id}/delete", org_setting.ActionsRequiredWorkflowsDelete)
+					})
 				}, actions.MustEnableActions)
 
 				m.Methods("GET,POST", "/delete", org.SettingsDelete)
@@ -1037,6 +1047,10 @@ func registerRoutes(m *web.Router) {
 		m.Group("", func() {
 			m.Get("/code", user.CodeSearch)
 		}, reqUnitAccess(unit.TypeCode, perm.AccessModeRead, false), individualPermsChecker)
+
+		if setting.Actions.Enabled {
+			m.Get("/actions", org.Actions)
+		}
 	}, ignSignIn, context.UserAssignmentWeb(), context.OrgAssignment())
 	// end "/{username}/-": packages, projects, code
 
@@ -1118,9 +1132,22 @@ func registerRoutes(m *web.Router) {
 		})
 		m.Group("/actions", func() {
 			m.Get("", repo_setting.RedirectToDefaultSetting)
+			m.Combo("/general").Get(repo_setting.ActionsGeneralSettings).
+				Post(web.Bind(forms.ActionsGeneralSettingsForm{}), repo_setting.ActionsGeneralSettingsPost)
 			addSettingsRunnersRoutes()
 			addSettingsSecretsRoutes()
 			addSettingsVariablesRoutes()
+			m.Group("/environments", func() {
+				m.Combo("").Get(repo_setting.Environments).
+					Post(web.Bind(forms.CreateEnvironmentForm{}), repo_setting.EnvironmentsPost)
+				m.Group("/{environment_id}", func() {
+					m.Get("", repo_setting.Environment)
+					m.Post("/reviewers", web.Bind(forms.EditEnvironmentReviewersForm{}), repo_setting.EnvironmentReviewersPost)
+					m.Post("/delete", repo_setting.EnvironmentDelete)
+					m.Post("/secrets", web.Bind(forms.AddSecretForm{}), repo_setting.EnvironmentSecretsPost)
+					m.Post("/secrets/delete", repo_setting.EnvironmentSecretsDelete)
+				})
+			})
 		}, actions.MustEnableActions)
 		// the follow handler must be under "settings", otherwise this incomplete repo can't be accessed
 		m.Group("/migrate", func() {
@@ -1391,7 +1418,15 @@ func registerRoutes(m *web.Router) {
 		m.Get("", actions.List)
 		m.Post("/disable", reqRepoAdmin, actions.DisableWorkflowFile)
 		m.Post("/enable", reqRepoAdmin, actions.EnableWorkflowFile)
+		m.Post("/pin", reqSignIn, actions.PinWorkflow)
+		m.Post("/unpin", reqSignIn, actions.UnpinWorkflow)
 		m.Post("/run", reqRepoAdmin, actions.Run)
+		m.Post("/cancel", reqRepoActionsWriter, context.RepoMustNotBeArchived(), actions.CancelRuns)
+		m.Group("/workflows", func() {
+			m.Combo("/new").Get(actions.NewWorkflow).
+				Post(web.Bind(forms.NewWorkflowForm{}), actions.NewWorkflowPost)
+			m.Post("/lint", actions.LintWorkflow)
+		}, reqRepoCodeWriter, context.RepoMustNotBeArchived())
 
 		m.Group("/runs/{run}", func() {
 			m.Combo("").
@@ -1401,15 +1436,24 @@ func registerRoutes(m *web.Router) {
 				m.Combo("").
 					Get(actions.View).
 					Post(web.Bind(actions.ViewRequest{}), actions.ViewPost)
+				m.Get("/events", actions.ViewEvents)
 				m.Post("/rerun", reqRepoActionsWriter, actions.Rerun)
 				m.Get("/logs", actions.Logs)
+				m.Get("/logs/search", actions.LogsSearch)
 			})
+			m.Get("/logs", actions.RunLogs)
 			m.Post("/cancel", reqRepoActionsWriter, actions.Cancel)
 			m.Post("/approve", reqRepoActionsWriter, actions.Approve)
+			m.Post("/deployments/approve", reqSignIn, actions.ApproveDeployment)
+			m.Post("/deployments/reject", reqSignIn, actions.RejectDeployment)
+			m.Get("/graph", actions.GraphView)
+			m.Get("/timing", actions.TimingView)
+			m.Get("/compare", actions.CompareRuns)
 			m.Get("/artifacts", actions.ArtifactsView)
 			m.Get("/artifacts/{artifact_name}", actions.ArtifactsDownloadView)
 			m.Delete("/artifacts/{artifact_name}", actions.ArtifactsDeleteView)
 			m.Post("/rerun", reqRepoActionsWriter, actions.Rerun)
+			m.Post("/rerun-failed", reqRepoActionsWriter, actions.RerunFailed)
 		})
 		m.Group("/workflows/{workflow_name}", func() {
 			m.Get("/badge.svg", actions.GetWorkflowBadge)
@@ -1417,6 +1461,8 @@ func registerRoutes(m *web.Router) {
 	}, ignSignIn, context.RepoAssignment, reqRepoActionsReader, actions.MustEnableActions)
 	// end "/{username}/{reponame}/actions"
 
+	m.Get("/{username}/{reponame}/deployments", ignSignIn, context.RepoAssignment, reqRepoActionsReader, actions.MustEnableActions, actions.Deployments)
+
 	m.Group("/{username}/{reponame}/wiki", func() {
 		m.Combo("").
 			Get(repo.Wiki).
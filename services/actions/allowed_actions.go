@@ -0,0 +1,189 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/nektos/act/pkg/jobparser"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DisallowedAction is an action used by a job but not allowed by the policy of the scope
+type DisallowedAction struct {
+	Uses  string
+	Scope string // "instance", "organization" or "repository"
+}
+
+// GetOwnerAllowedActions returns the allowed actions policy of the owner
+func GetOwnerAllowedActions(ctx context.Context, ownerID int64) (*actions_module.AllowedActions, error) {
+	policy, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsAllowedActions)
+	if err != nil {
+		return nil, err
+	}
+	patterns, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsAllowedActionsPatterns)
+	if err != nil {
+		return nil, err
+	}
+	return actions_module.NewAllowedActions(policy, actions_module.ParseAllowedActionsPatterns(patterns)), nil
+}
+
+// SetOwnerAllowedActions sets the allowed actions policy of the owner, the patterns are only kept for the "selected" policy
+func SetOwnerAllowedActions(ctx context.Context, ownerID int64, policy actions_module.AllowedActionsPolicy, patterns []string) error {
+	switch policy {
+	case actions_module.AllowedActionsAll, actions_module.AllowedActionsLocal:
+		patterns = nil
+	case actions_module.AllowedActionsSelected:
+	default:
+		return util.NewInvalidArgumentErrorf("unknown allowed actions policy %q", policy)
+	}
+
+	if policy == actions_module.AllowedActionsAll {
+		if err := user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsAllowedActions); err != nil {
+			return err
+		}
+	} else if err := user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsAllowedActions, string(policy)); err != nil {
+		return err
+	}
+	if len(patterns) == 0 {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsAllowedActionsPatterns)
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsAllowedActionsPatterns, strings.Join(patterns, "\n"))
+}
+
+type scopedAllowedActions struct {
+	scope   string
+	allowed *actions_module.AllowedActions
+}
+
+// FindDisallowedActions returns the actions and the reusable workflow used by the job which aren't allowed by the instance, the owner or the repository
+func FindDisallowedActions(ctx context.Context, job *actions_model.ActionRunJob) ([]*DisallowedAction, error) {
+	if err := job.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	repo := job.Run.Repo
+
+	ownerAllowed, err := GetOwnerAllowedActions(ctx, repo.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("GetOwnerAllowedActions: %w", err)
+	}
+	policies := []scopedAllowedActions{
+		{"instance", actions_module.NewAllowedActions(setting.Actions.AllowedActions, setting.Actions.AllowedActionsPatterns)},
+		{"organization", ownerAllowed},
+	}
+	if actionsUnit, err := repo.GetUnit(ctx, unit_model.TypeActions); err == nil {
+		cfg := actionsUnit.ActionsConfig()
+		policies = append(policies, scopedAllowedActions{"repository", actions_module.NewAllowedActions(cfg.AllowedActions, cfg.AllowedActionsPatterns)})
+	} else if !repo_model.IsErrUnitTypeNotExist(err) {
+		return nil, fmt.Errorf("GetUnit: %w", err)
+	}
+
+	workflows, err := jobparser.Parse(job.WorkflowPayload)
+	if err != nil {
+		return nil, fmt.Errorf("parse workflow of job %d: %w", job.ID, err)
+	} else if len(workflows) != 1 {
+		return nil, fmt.Errorf("workflow of job %d: not single workflow", job.ID)
+	}
+	_, workflowJob := workflows[0].Job()
+
+	// a job calling a reusable workflow has no steps, the workflow is checked like an action
+	uses := make([]string, 0, len(workflowJob.Steps)+1)
+	if workflowJob.Uses != "" {
+		uses = append(uses, workflowJob.Uses)
+	}
+	for _, step := range workflowJob.Steps {
+		if step.Uses != "" {
+			uses = append(uses, step.Uses)
+		}
+	}
+
+	var disallowed []*DisallowedAction
+	for _, u := range uses {
+		for _, policy := range policies {
+			if !policy.allowed.IsAllowed(u) {
+				disallowed = append(disallowed, &DisallowedAction{Uses: u, Scope: policy.scope})
+				break
+			}
+		}
+	}
+	return disallowed, nil
+}
+
+// RejectTaskOfDisallowedActions fails the task without handing it to a runner, the disallowed actions are explained in its logs.
+// It should be called in the transaction creating the task, and NotifyRejectedTask should be called once the transaction is committed.
+func RejectTaskOfDisallowedActions(ctx context.Context, task *actions_model.ActionTask, disallowed []*DisallowedAction) error {
+	now := timestamppb.Now()
+	rows := make([]*runnerv1.LogRow, 0, len(disallowed)+1)
+	rows = append(rows, &runnerv1.LogRow{Time: now, Content: "The job uses actions which aren't allowed by the actions policy, it won't be run:"})
+	for _, v := range disallowed {
+		rows = append(rows, &runnerv1.LogRow{Time: now, Content: fmt.Sprintf("- %s is not allowed by the %s", v.Uses, v.Scope)})
+	}
+
+	ns, err := actions_module.WriteLogs(ctx, task.LogFilename, 0, rows)
+	if err != nil {
+		return fmt.Errorf("write logs: %w", err)
+	}
+	task.LogLength = int64(len(rows))
+	task.LogIndexes = nil
+	task.LogSize = 0
+	for _, n := range ns {
+		task.LogIndexes = append(task.LogIndexes, task.LogSize)
+		task.LogSize += int64(n)
+	}
+	remove, err := actions_module.TransferLogs(ctx, task.LogFilename)
+	if err != nil {
+		return fmt.Errorf("transfer logs: %w", err)
+	}
+	task.LogInStorage = true
+	if err := actions_model.UpdateTask(ctx, task, "log_indexes", "log_length", "log_size", "log_in_storage"); err != nil {
+		return err
+	}
+	remove()
+
+	// none of the steps has run, so the logs belong to the "Set up job" step
+	state := &runnerv1.TaskState{
+		Id:        task.ID,
+		Result:    runnerv1.Result_RESULT_FAILURE,
+		StartedAt: now,
+		StoppedAt: now,
+	}
+	for _, step := range task.Steps {
+		state.Steps = append(state.Steps, &runnerv1.StepState{Id: step.Index, Result: runnerv1.Result_RESULT_SKIPPED})
+	}
+	_, err = actions_model.UpdateTaskByState(ctx, state)
+	return err
+}
+
+// NotifyRejectedTask notifies the job of the task rejected by RejectTaskOfDisallowedActions has been done, and emits the jobs needing it
+func NotifyRejectedTask(ctx context.Context, task *actions_model.ActionTask) error {
+	NotifyJobUpdated(task.JobID)
+
+	job, err := actions_model.GetRunJobByID(ctx, task.JobID)
+	if err != nil {
+		return err
+	}
+	if err := job.LoadAttributes(ctx); err != nil {
+		return err
+	}
+	if job.Run.ScheduleID == 0 {
+		CreateCommitStatus(ctx, job)
+	}
+	NotifyWorkflowJobsStatusUpdate(ctx, job)
+	if err := EmitJobsIfReady(job.RunID); err != nil {
+		log.Error("Emit ready jobs of run %d: %v", job.RunID, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/util"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+)
+
+// CreateAnnotationsFromLogs creates the annotations from the workflow commands like "::error file=app.js,line=1::Missing semicolon"
+// in the rows of the logs of the task, index is the index of the first row in the logs.
+// A task creates no more than actions_model.MaxAnnotationsPerTask annotations, the others are ignored.
+func CreateAnnotationsFromLogs(ctx context.Context, task *actions_model.ActionTask, rows []*runnerv1.LogRow, index int64) error {
+	var annotations []*actions_model.ActionAnnotation
+	for i, row := range rows {
+		annotation, ok := actions_module.ParseAnnotation(row.Content)
+		if !ok {
+			continue
+		}
+		path, _ := util.SplitStringAtByteN(annotation.File, 500)
+		title, _ := util.SplitStringAtByteN(annotation.Title, 255)
+		annotations = append(annotations, &actions_model.ActionAnnotation{
+			RepoID:    task.RepoID,
+			TaskID:    task.ID,
+			CommitSHA: task.CommitSHA,
+			Level:     actions_model.AnnotationLevel(annotation.Level),
+			Path:      path,
+			StartLine: annotation.Line,
+			EndLine:   annotation.EndLine,
+			Title:     title,
+			Message:   annotation.Message,
+			LogIndex:  index + int64(i),
+		})
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	count, err := actions_model.CountTaskAnnotations(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	if count >= actions_model.MaxAnnotationsPerTask {
+		return nil
+	}
+	if remaining := actions_model.MaxAnnotationsPerTask - count; int64(len(annotations)) > remaining {
+		annotations = annotations[:remaining]
+	}
+
+	job, err := actions_model.GetRunJobByID(ctx, task.JobID)
+	if err != nil {
+		return err
+	}
+	for _, annotation := range annotations {
+		annotation.RunID = job.RunID
+		annotation.JobID = job.ID
+	}
+	return db.Insert(ctx, annotations)
+}
@@ -0,0 +1,242 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// GetCacheRefsOfRun returns the refs whose caches could be restored by the run, in the order of precedence.
+// Like GitHub, a run could restore the caches created for its own ref, for the base branch if it's triggered by a pull request,
+// and for the default branch, so that the caches created by untrusted refs could never be restored by others.
+func GetCacheRefsOfRun(ctx context.Context, run *actions_model.ActionRun) ([]string, error) {
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	refs := []string{run.Ref}
+	if payload, err := run.GetPullRequestEventPayload(); err == nil && payload.PullRequest != nil && payload.PullRequest.Base != nil {
+		refs = append(refs, git.BranchPrefix+payload.PullRequest.Base.Ref)
+	}
+	refs = append(refs, git.BranchPrefix+run.Repo.DefaultBranch)
+
+	ret := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref != "" && !slices.Contains(ret, ref) {
+			ret = append(ret, ref)
+		}
+	}
+	return ret, nil
+}
+
+// ErrCacheChunksIncomplete is returned when committing a cache whose chunks don't cover the whole size
+var ErrCacheChunksIncomplete = errors.New("cache chunks are incomplete")
+
+func cacheChunksDir(cacheID int64) string {
+	return fmt.Sprintf("cache/tmp/%d", cacheID)
+}
+
+type cacheChunk struct {
+	Path  string
+	Start int64
+	End   int64
+}
+
+func listCacheChunks(cacheID int64) ([]*cacheChunk, error) {
+	dir := cacheChunksDir(cacheID)
+	var chunks []*cacheChunk
+	if err := storage.ActionsArtifacts.IterateObjects(dir, func(fpath string, _ storage.Object) error {
+		// only the basename is reliable, no matter the subdirectory setting in storage config
+		chunk := &cacheChunk{Path: dir + "/" + path.Base(fpath)}
+		if _, err := fmt.Sscanf(path.Base(fpath), "%d-%d.chunk", &chunk.Start, &chunk.End); err != nil {
+			return fmt.Errorf("parse chunk %s: %w", fpath, err)
+		}
+		chunks = append(chunks, chunk)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].Start < chunks[j].Start
+	})
+	return chunks, nil
+}
+
+func deleteCacheChunks(cacheID int64) error {
+	chunks, err := listCacheChunks(cacheID)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if err := storage.ActionsArtifacts.Delete(chunk.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveCacheChunk saves the uploaded bytes from start to end (inclusive) of an incomplete cache
+func SaveCacheChunk(cache *actions_model.ActionCache, start, end int64, r io.Reader) error {
+	if cache.Complete {
+		return fmt.Errorf("cache %d is already committed", cache.ID)
+	}
+	if start < 0 || end < start {
+		return fmt.Errorf("invalid chunk range %d-%d", start, end)
+	}
+	chunkPath := fmt.Sprintf("%s/%d-%d.chunk", cacheChunksDir(cache.ID), start, end)
+	written, err := storage.ActionsArtifacts.Save(chunkPath, r, end-start+1)
+	if err != nil {
+		return fmt.Errorf("save chunk: %w", err)
+	}
+	if written != end-start+1 {
+		_ = storage.ActionsArtifacts.Delete(chunkPath)
+		return fmt.Errorf("chunk size %d doesn't match range %d-%d", written, start, end)
+	}
+	return nil
+}
+
+// CommitCache merges the uploaded chunks of the cache, and makes it available for restoring.
+// The least recently used caches of the repository will be evicted if the quota is exceeded.
+func CommitCache(ctx context.Context, cache *actions_model.ActionCache, size int64) error {
+	if cache.Complete {
+		return fmt.Errorf("cache %d is already committed", cache.ID)
+	}
+	chunks, err := listCacheChunks(cache.ID)
+	if err != nil {
+		return fmt.Errorf("list chunks: %w", err)
+	}
+
+	// chunks could be uploaded repeatedly, only the continuous ones are needed
+	readers := make([]io.Reader, 0, len(chunks))
+	defer func() {
+		for _, r := range readers {
+			_ = r.(io.Closer).Close()
+		}
+	}()
+	next := int64(0)
+	for _, chunk := range chunks {
+		if chunk.Start != next {
+			continue
+		}
+		f, err := storage.ActionsArtifacts.Open(chunk.Path)
+		if err != nil {
+			return fmt.Errorf("open chunk %s: %w", chunk.Path, err)
+		}
+		readers = append(readers, f)
+		next = chunk.End + 1
+	}
+	if next != size {
+		return ErrCacheChunksIncomplete
+	}
+
+	cache.StoragePath = fmt.Sprintf("cache/%d/%d", cache.RepoID, cache.ID)
+	if _, err := storage.ActionsArtifacts.Save(cache.StoragePath, io.MultiReader(readers...), size); err != nil {
+		return fmt.Errorf("save cache: %w", err)
+	}
+	if err := deleteCacheChunks(cache.ID); err != nil {
+		log.Error("Failed to delete chunks of cache %d: %v", cache.ID, err)
+	}
+
+	cache.Size = size
+	cache.Complete = true
+	cache.UsedUnix = timeutil.TimeStampNow()
+	if err := actions_model.UpdateCache(ctx, cache, "storage_path", "size", "complete", "used_unix"); err != nil {
+		return err
+	}
+	return EvictCaches(ctx, cache.RepoID)
+}
+
+// DeleteCache removes the cache or its uploaded chunks from storage and deletes its record
+func DeleteCache(ctx context.Context, cache *actions_model.ActionCache) error {
+	if err := RemoveCacheFiles(cache); err != nil {
+		return err
+	}
+	return actions_model.DeleteCache(ctx, cache.ID)
+}
+
+// RemoveCacheFiles removes the cache or its uploaded chunks from storage, the record of the cache is kept
+func RemoveCacheFiles(cache *actions_model.ActionCache) error {
+	if cache.StoragePath != "" {
+		if err := storage.ActionsArtifacts.Delete(cache.StoragePath); err != nil {
+			return fmt.Errorf("delete cache %d from storage: %w", cache.ID, err)
+		}
+	} else if err := deleteCacheChunks(cache.ID); err != nil {
+		return fmt.Errorf("delete chunks of cache %d: %w", cache.ID, err)
+	}
+	return nil
+}
+
+// EvictCaches deletes the least recently used caches of the repository until the total size doesn't exceed the quota
+func EvictCaches(ctx context.Context, repoID int64) error {
+	if setting.Actions.CacheRepoQuota < 0 {
+		return nil
+	}
+	size, err := actions_model.GetCachesSizeOfRepo(ctx, repoID)
+	if err != nil {
+		return fmt.Errorf("get size of caches: %w", err)
+	}
+	if size <= setting.Actions.CacheRepoQuota {
+		return nil
+	}
+
+	caches, err := db.Find[actions_model.ActionCache](ctx, actions_model.FindCachesOptions{
+		RepoID:   repoID,
+		Complete: optional.Some(true),
+	})
+	if err != nil {
+		return fmt.Errorf("find caches: %w", err)
+	}
+	for _, cache := range caches {
+		if size <= setting.Actions.CacheRepoQuota {
+			break
+		}
+		if err := DeleteCache(ctx, cache); err != nil {
+			return err
+		}
+		size -= cache.Size
+		log.Trace("Evicted cache %d of repository %d", cache.ID, repoID)
+	}
+	return nil
+}
+
+// CleanupCaches removes the caches which haven't been used for the retention days,
+// and the incomplete caches which haven't been committed for a day.
+func CleanupCaches(ctx context.Context) error {
+	count := 0
+	for _, opts := range []actions_model.FindCachesOptions{
+		{UsedBefore: timeutil.TimeStampNow().AddDuration(-time.Duration(setting.Actions.CacheRetentionDays) * 24 * time.Hour)},
+		{UsedBefore: timeutil.TimeStampNow().AddDuration(-24 * time.Hour), Complete: optional.Some(false)},
+	} {
+		caches, err := db.Find[actions_model.ActionCache](ctx, opts)
+		if err != nil {
+			return fmt.Errorf("find caches: %w", err)
+		}
+		for _, cache := range caches {
+			if err := DeleteCache(ctx, cache); err != nil {
+				log.Error("Failed to delete cache %d: %v", cache.ID, err)
+				// do not return error here, continue to next cache
+				continue
+			}
+			count++
+		}
+	}
+
+	log.Info("Removed %d caches", count)
+	return nil
+}
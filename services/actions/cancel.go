@@ -0,0 +1,92 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// CancelRunsOptions are the options to find the runs to cancel, the runs of all refs or workflows are cancelled if they're empty
+type CancelRunsOptions struct {
+	RepoID     int64
+	Ref        string
+	WorkflowID string
+	// Reason is recorded on the runs if they're cancelled automatically
+	Reason actions_model.RunCancelReason
+}
+
+// CancelRuns cancels all the queued and running runs matching the options, and returns the cancelled runs
+func CancelRuns(ctx context.Context, opts CancelRunsOptions) ([]*actions_model.ActionRun, error) {
+	runs, err := db.Find[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+		RepoID:     opts.RepoID,
+		Ref:        opts.Ref,
+		WorkflowID: opts.WorkflowID,
+		Status:     []actions_model.Status{actions_model.StatusWaiting, actions_model.StatusRunning, actions_model.StatusBlocked},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return runs, nil
+	}
+
+	var cancelledJobs []*actions_model.ActionRunJob
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		for _, run := range runs {
+			if opts.Reason != "" {
+				run.CancelReason = opts.Reason
+				if err := actions_model.UpdateRun(ctx, run, "cancel_reason"); err != nil {
+					return err
+				}
+			}
+			jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+			if err != nil {
+				return err
+			}
+			if err := actions_model.CancelJobs(ctx, jobs); err != nil {
+				return err
+			}
+			cancelledJobs = append(cancelledJobs, jobs...)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	CreateCommitStatus(ctx, cancelledJobs...)
+	NotifyWorkflowJobsStatusUpdate(ctx, cancelledJobs...)
+
+	for i, run := range runs {
+		// let the runs waiting for the concurrency groups of the run continue
+		if err := EmitJobsIfReady(run.ID); err != nil {
+			log.Error("EmitJobsIfReady: %v", err)
+		}
+		// reload the run to get the updated status
+		if runs[i], err = actions_model.GetRunByID(ctx, run.ID); err != nil {
+			return nil, err
+		}
+	}
+	return runs, nil
+}
+
+// cancelStaleRuns cancels the queued and running runs of a ref which won't be needed anymore,
+// e.g. the ref of a deleted branch or of a closed pull request
+func cancelStaleRuns(ctx context.Context, repoID int64, ref string, reason actions_model.RunCancelReason) {
+	runs, err := CancelRuns(ctx, CancelRunsOptions{
+		RepoID: repoID,
+		Ref:    ref,
+		Reason: reason,
+	})
+	if err != nil {
+		log.Error("CancelRuns [repo_id: %d, ref: %s]: %v", repoID, ref, err)
+		return
+	}
+	if len(runs) > 0 {
+		log.Trace("cancelled %d runs of ref %s in repo %d: %s", len(runs), ref, repoID, reason)
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"time"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -16,7 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/timeutil"
 )
 
-// Cleanup removes expired actions logs, data and artifacts
+// Cleanup removes expired actions logs, data, artifacts and caches
 func Cleanup(ctx context.Context) error {
 	// clean up expired artifacts
 	if err := CleanupArtifacts(ctx); err != nil {
@@ -28,6 +31,21 @@ func Cleanup(ctx context.Context) error {
 		return fmt.Errorf("cleanup logs: %w", err)
 	}
 
+	// move old logs to the cold storage
+	if err := MoveLogsToColdStorage(ctx); err != nil {
+		return fmt.Errorf("move logs to cold storage: %w", err)
+	}
+
+	// clean up old runs
+	if err := CleanupRuns(ctx); err != nil {
+		return fmt.Errorf("cleanup runs: %w", err)
+	}
+
+	// clean up unused caches
+	if err := CleanupCaches(ctx); err != nil {
+		return fmt.Errorf("cleanup caches: %w", err)
+	}
+
 	return nil
 }
 
@@ -101,7 +119,7 @@ func CleanupLogs(ctx context.Context) error {
 			return fmt.Errorf("find old tasks: %w", err)
 		}
 		for _, task := range tasks {
-			if err := actions_module.RemoveLogs(ctx, task.LogInStorage, task.LogFilename); err != nil {
+			if err := actions_module.RemoveTaskLogs(ctx, task); err != nil {
 				log.Error("Failed to remove log %s (in storage %v) of task %v: %v", task.LogFilename, task.LogInStorage, task.ID, err)
 				// do not return error here, continue to next task
 				continue
@@ -124,3 +142,85 @@ func CleanupLogs(ctx context.Context) error {
 	log.Info("Removed %d logs", count)
 	return nil
 }
+
+const deleteRunBatchSize = 100
+
+// CleanupRuns removes finished runs which are older than the retention time of their repositories,
+// including the jobs, tasks, logs and artifacts of the runs.
+func CleanupRuns(ctx context.Context) error {
+	repoIDs, err := actions_model.GetRepoIDsWithRuns(ctx)
+	if err != nil {
+		return fmt.Errorf("find repositories with runs: %w", err)
+	}
+
+	count := 0
+	for _, repoID := range repoIDs {
+		repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+		if err != nil {
+			log.Error("Failed to get repository %d: %v", repoID, err)
+			continue
+		}
+		retentionDays := repo.MustGetUnit(ctx, unit.TypeActions).ActionsConfig().GetRunRetentionDays()
+		if retentionDays <= 0 {
+			continue
+		}
+		olderThan := timeutil.TimeStampNow().AddDuration(-time.Duration(retentionDays) * 24 * time.Hour)
+
+		// page by the last seen ID, the runs failed to be deleted would be found again otherwise
+		var afterID int64
+		for {
+			runs, err := actions_model.FindOldRunsToCleanup(ctx, repoID, olderThan, afterID, deleteRunBatchSize)
+			if err != nil {
+				return fmt.Errorf("find old runs: %w", err)
+			}
+			for _, run := range runs {
+				afterID = run.ID
+				run.Repo = repo
+				if err := DeleteRun(ctx, run); err != nil {
+					log.Error("Failed to delete run %d of repository %d: %v", run.ID, repoID, err)
+					// do not return error here, continue to next run
+					continue
+				}
+				count++
+				log.Trace("Removed run %d of repository %d", run.ID, repoID)
+			}
+			if len(runs) < deleteRunBatchSize {
+				break
+			}
+		}
+	}
+
+	log.Info("Removed %d runs", count)
+	return nil
+}
+
+// DeleteRun removes the logs and artifacts of a run from storage, then deletes the run and its records
+func DeleteRun(ctx context.Context, run *actions_model.ActionRun) error {
+	tasks, err := db.Find[actions_model.ActionTask](ctx, actions_model.FindTaskOptions{RunID: run.ID})
+	if err != nil {
+		return fmt.Errorf("find tasks: %w", err)
+	}
+	for _, task := range tasks {
+		if task.LogExpired || task.LogFilename == "" {
+			continue
+		}
+		if err := actions_module.RemoveTaskLogs(ctx, task); err != nil {
+			return fmt.Errorf("remove log %s of task %d: %w", task.LogFilename, task.ID, err)
+		}
+	}
+
+	artifacts, err := db.Find[actions_model.ActionArtifact](ctx, actions_model.FindArtifactsOptions{RunID: run.ID})
+	if err != nil {
+		return fmt.Errorf("find artifacts: %w", err)
+	}
+	for _, artifact := range artifacts {
+		if artifact.Status == int64(actions_model.ArtifactStatusExpired) || artifact.Status == int64(actions_model.ArtifactStatusDeleted) {
+			continue
+		}
+		if err := storage.ActionsArtifacts.Delete(artifact.StoragePath); err != nil {
+			return fmt.Errorf("delete artifact %d: %w", artifact.ID, err)
+		}
+	}
+
+	return actions_model.DeleteRun(ctx, run)
+}
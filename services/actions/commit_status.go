@@ -15,7 +15,6 @@ import (
 	actions_module "code.gitea.io/gitea/modules/actions"
 	git "code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
-	api "code.gitea.io/gitea/modules/structs"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 	commitstatus_service "code.gitea.io/gitea/services/repository/commitstatus"
 
@@ -84,7 +83,7 @@ func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) er
 		runName = wfs[0].Name
 	}
 	ctxname := fmt.Sprintf("%s / %s (%s)", runName, job.Name, event)
-	state := toCommitStatus(job.Status)
+	state := job.Status.AsCommitStatusState()
 	if statuses, _, err := git_model.GetLatestCommitStatus(ctx, repo.ID, sha, db.ListOptionsAll); err == nil {
 		for _, v := range statuses {
 			if v.Context == ctxname {
@@ -142,19 +141,6 @@ func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) er
 	return nil
 }
 
-func toCommitStatus(status actions_model.Status) api.CommitStatusState {
-	switch status {
-	case actions_model.StatusSuccess, actions_model.StatusSkipped:
-		return api.CommitStatusSuccess
-	case actions_model.StatusFailure, actions_model.StatusCancelled:
-		return api.CommitStatusFailure
-	case actions_model.StatusWaiting, actions_model.StatusBlocked, actions_model.StatusRunning:
-		return api.CommitStatusPending
-	default:
-		return api.CommitStatusError
-	}
-}
-
 func getIndexOfJob(ctx context.Context, job *actions_model.ActionRunJob) (int, error) {
 	// TODO: store job index as a field in ActionRunJob to avoid this
 	jobs, err := actions_model.GetRunJobsByRunID(ctx, job.RunID)
@@ -0,0 +1,214 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"gopkg.in/yaml.v3"
+)
+
+// rawConcurrency is the `concurrency` of a workflow or a job, it could be a string of the group name,
+// or a mapping with `group` and `cancel-in-progress`.
+// See https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#concurrency
+type rawConcurrency struct {
+	Group            string `yaml:"group"`
+	CancelInProgress string `yaml:"cancel-in-progress"`
+}
+
+func (c *rawConcurrency) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&c.Group)
+	}
+	type plain rawConcurrency
+	return node.Decode((*plain)(c))
+}
+
+// evaluate returns the concurrency group and whether to cancel the in-progress runs or jobs of the group
+func (c *rawConcurrency) evaluate(evaluator *jobparser.ExpressionEvaluator) (string, bool) {
+	group := evaluator.Interpolate(c.Group)
+	cancel, _ := strconv.ParseBool(evaluator.Interpolate(c.CancelInProgress))
+	return group, cancel
+}
+
+// cancelJobConcurrencyGroup cancels the in-progress jobs of the concurrency group except the jobs of the run
+func cancelJobConcurrencyGroup(ctx context.Context, run *actions_model.ActionRun, group string) ([]*actions_model.ActionRunJob, error) {
+	jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{
+		RepoID:           run.RepoID,
+		ConcurrencyGroup: group,
+		Statuses:         []actions_model.Status{actions_model.StatusRunning, actions_model.StatusWaiting, actions_model.StatusBlocked},
+	})
+	if err != nil {
+		return nil, err
+	}
+	jobs = slices.DeleteFunc(jobs, func(job *actions_model.ActionRunJob) bool {
+		return job.RunID == run.ID
+	})
+	return jobs, actions_model.CancelJobs(ctx, jobs)
+}
+
+// prepareRunConcurrency cancels the runs of the concurrency group which shouldn't continue because of the new run,
+// and reports whether the new run has to wait for the in-progress run of the group.
+func prepareRunConcurrency(ctx context.Context, run *actions_model.ActionRun) ([]*actions_model.ActionRunJob, bool, error) {
+	runs, err := db.Find[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+		RepoID:           run.RepoID,
+		ConcurrencyGroup: run.ConcurrencyGroup,
+		Status:           []actions_model.Status{actions_model.StatusRunning, actions_model.StatusWaiting, actions_model.StatusBlocked},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var cancelledJobs []*actions_model.ActionRunJob
+	blocked := false
+	for _, r := range runs {
+		// only one run could be pending in a concurrency group, the newer one replaces the older one
+		if !run.ConcurrencyCancel && !r.Status.IsBlocked() {
+			blocked = true
+			continue
+		}
+		jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: r.ID})
+		if err != nil {
+			return nil, false, err
+		}
+		if err := actions_model.CancelJobs(ctx, jobs); err != nil {
+			return nil, false, err
+		}
+		cancelledJobs = append(cancelledJobs, jobs...)
+	}
+	return cancelledJobs, blocked, nil
+}
+
+// getBusyConcurrencyGroups returns the concurrency groups of the blocked jobs which have waiting or running jobs
+func getBusyConcurrencyGroups(ctx context.Context, jobs []*actions_model.ActionRunJob) (container.Set[string], error) {
+	busy := container.Set[string]{}
+	for _, job := range jobs {
+		if job.ConcurrencyGroup == "" || !job.Status.IsBlocked() || busy.Contains(job.ConcurrencyGroup) {
+			continue
+		}
+		count, err := db.Count[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{
+			RepoID:           job.RepoID,
+			ConcurrencyGroup: job.ConcurrencyGroup,
+			Statuses:         []actions_model.Status{actions_model.StatusWaiting, actions_model.StatusRunning},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			busy.Add(job.ConcurrencyGroup)
+		}
+	}
+	return busy, nil
+}
+
+// releaseConcurrency lets the runs and jobs waiting for the concurrency groups continue when the run or its jobs are done
+func releaseConcurrency(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error {
+	groups := container.Set[string]{}
+	for _, job := range jobs {
+		if job.ConcurrencyGroup != "" && job.Status.IsDone() {
+			groups.Add(job.ConcurrencyGroup)
+		}
+	}
+	for group := range groups {
+		if err := releaseJobConcurrencyGroup(ctx, run.RepoID, group); err != nil {
+			return err
+		}
+	}
+
+	if run.ConcurrencyGroup != "" && run.Status.IsDone() {
+		return releaseRunConcurrencyGroup(ctx, run.RepoID, run.ConcurrencyGroup)
+	}
+	return nil
+}
+
+// releaseRunConcurrencyGroup unblocks the pending run of the concurrency group if there is no in-progress run
+func releaseRunConcurrencyGroup(ctx context.Context, repoID int64, group string) error {
+	runs, err := db.Find[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+		RepoID:           repoID,
+		ConcurrencyGroup: group,
+		Status:           []actions_model.Status{actions_model.StatusRunning, actions_model.StatusWaiting, actions_model.StatusBlocked},
+	})
+	if err != nil {
+		return err
+	}
+
+	var next *actions_model.ActionRun
+	for _, r := range runs {
+		if !r.Status.IsBlocked() {
+			// the group is still in progress
+			return nil
+		}
+		if next == nil || r.ID < next.ID {
+			next = r
+		}
+	}
+	if next == nil {
+		return nil
+	}
+
+	next.Status = actions_model.StatusWaiting
+	if err := actions_model.UpdateRun(ctx, next, "status"); err != nil {
+		return err
+	}
+	return EmitJobsIfReady(next.ID)
+}
+
+// releaseJobConcurrencyGroup emits the run of the earliest blocked job which is only waiting for the concurrency group.
+// The jobs which are still waiting for their needs will be emitted when the needs are done.
+func releaseJobConcurrencyGroup(ctx context.Context, repoID int64, group string) error {
+	jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{
+		RepoID:           repoID,
+		ConcurrencyGroup: group,
+		Statuses:         []actions_model.Status{actions_model.StatusWaiting, actions_model.StatusRunning, actions_model.StatusBlocked},
+	})
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(jobs, func(a, b *actions_model.ActionRunJob) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for _, job := range jobs {
+		if !job.Status.IsBlocked() {
+			// the group is still in progress
+			return nil
+		}
+	}
+
+	for _, job := range jobs {
+		if err := job.LoadRun(ctx); err != nil {
+			return err
+		}
+		if job.Run.NeedApproval || job.Run.Status.IsBlocked() {
+			continue
+		}
+		runJobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: job.RunID})
+		if err != nil {
+			return err
+		}
+		if isJobNeedsDone(job, runJobs) {
+			return EmitJobsIfReady(job.RunID)
+		}
+	}
+	return nil
+}
+
+func isJobNeedsDone(job *actions_model.ActionRunJob, runJobs []*actions_model.ActionRunJob) bool {
+	for _, need := range job.Needs {
+		for _, j := range runJobs {
+			if j.JobID == need && !j.Status.IsDone() {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestEvaluateRawConcurrency(t *testing.T) {
+	content := `
+concurrency: ${{ github.workflow }}-${{ github.ref }}
+jobs:
+  deploy:
+    concurrency:
+      group: deploy-${{ matrix.env }}-${{ vars.REGION }}
+      cancel-in-progress: ${{ github.ref != 'refs/heads/main' }}
+  test:
+    runs-on: ubuntu-latest
+`
+	raw := &rawWorkflowAttributes{}
+	assert.NoError(t, yaml.Unmarshal([]byte(content), raw))
+	assert.Nil(t, raw.Jobs["test"].Concurrency)
+
+	gitCtx := &model.GithubContext{Workflow: "deploy.yml", Ref: "refs/heads/feature"}
+	vars := map[string]string{"REGION": "eu"}
+
+	group, cancel := raw.Concurrency.evaluate(newWorkflowEvaluator("", nil, gitCtx, vars))
+	assert.Equal(t, "deploy.yml-refs/heads/feature", group)
+	assert.False(t, cancel)
+
+	group, cancel = raw.Jobs["deploy"].Concurrency.evaluate(newWorkflowEvaluator("deploy", map[string]any{"env": "prod"}, gitCtx, vars))
+	assert.Equal(t, "deploy-prod-eu", group)
+	assert.True(t, cancel)
+
+	gitCtx.Ref = "refs/heads/main"
+	_, cancel = raw.Jobs["deploy"].Concurrency.evaluate(newWorkflowEvaluator("deploy", map[string]any{"env": "prod"}, gitCtx, vars))
+	assert.False(t, cancel)
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// maxRecentDispatchRefs is the max number of the refs remembered for the dispatches of a workflow
+const maxRecentDispatchRefs = 5
+
+// DispatchMemory is what a user submitted for the recent dispatches of a workflow, it's used to pre-fill the dispatch form
+type DispatchMemory struct {
+	Refs   []string          `json:"refs"`   // the recently used refs, the most recent one first
+	Inputs map[string]string `json:"inputs"` // the inputs of the last dispatch
+}
+
+func dispatchMemoryKey(repoID int64, workflowID string) string {
+	// the setting keys must be lowercase
+	return fmt.Sprintf("actions.dispatch.%d.%s", repoID, strings.ToLower(workflowID))
+}
+
+// GetDispatchMemory returns what the user submitted for the recent dispatches of the workflow,
+// it's empty if the user hasn't dispatched the workflow before.
+func GetDispatchMemory(ctx context.Context, userID, repoID int64, workflowID string) (*DispatchMemory, error) {
+	memory := &DispatchMemory{}
+	value, err := user_model.GetUserSetting(ctx, userID, dispatchMemoryKey(repoID, workflowID))
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return memory, nil
+	}
+	if err := json.Unmarshal([]byte(value), memory); err != nil {
+		// it's only a convenience, so forget the broken memory instead of failing
+		log.Warn("unmarshal the dispatch memory of workflow %q in repo %d for user %d: %v", workflowID, repoID, userID, err)
+		return &DispatchMemory{}, nil
+	}
+	return memory, nil
+}
+
+// RememberDispatch remembers the ref and the inputs the user submitted to dispatch the workflow,
+// the inputs left empty aren't remembered so they keep following the defaults of the workflow.
+func RememberDispatch(ctx context.Context, userID, repoID int64, workflowID, ref string, inputs map[string]string) error {
+	memory, err := GetDispatchMemory(ctx, userID, repoID, workflowID)
+	if err != nil {
+		return err
+	}
+
+	memory.Refs = slices.DeleteFunc(memory.Refs, func(r string) bool { return r == ref })
+	memory.Refs = append([]string{ref}, memory.Refs...)
+	if len(memory.Refs) > maxRecentDispatchRefs {
+		memory.Refs = memory.Refs[:maxRecentDispatchRefs]
+	}
+	memory.Inputs = inputs
+
+	value, err := json.Marshal(memory)
+	if err != nil {
+		return err
+	}
+	return user_model.SetUserSetting(ctx, userID, dispatchMemoryKey(repoID, workflowID), string(value))
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	secret_model "code.gitea.io/gitea/models/secret"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rawEnvironment is the `environment` of a job, it could be a string of the name, or a mapping with `name` and `url`.
+// See https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#jobsjob_idenvironment
+type rawEnvironment struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+func (e *rawEnvironment) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&e.Name)
+	}
+	type plain rawEnvironment
+	return node.Decode((*plain)(e))
+}
+
+var ErrEnvironmentAlreadyExist = util.NewAlreadyExistErrorf("environment already exists")
+
+func validateEnvironmentName(name string) error {
+	if name == "" || strings.TrimSpace(name) != name || len(name) > 255 || strings.ContainsAny(name, "\r\n\t") {
+		return util.NewInvalidArgumentErrorf("invalid environment name %q", name)
+	}
+	return nil
+}
+
+// CreateEnvironment creates an environment for the repository
+func CreateEnvironment(ctx context.Context, repoID int64, name string) (*actions_model.ActionEnvironment, error) {
+	if err := validateEnvironmentName(name); err != nil {
+		return nil, err
+	}
+	if _, err := actions_model.GetEnvironmentByName(ctx, repoID, name); err == nil {
+		return nil, ErrEnvironmentAlreadyExist
+	} else if !errors.Is(err, util.ErrNotExist) {
+		return nil, err
+	}
+	return actions_model.InsertEnvironment(ctx, repoID, name)
+}
+
+// UpdateEnvironmentReviewers sets the reviewers of the environment by their names,
+// the reviewers must be able to read the actions of the repository.
+func UpdateEnvironmentReviewers(ctx context.Context, repo *repo_model.Repository, env *actions_model.ActionEnvironment, reviewerNames []string) error {
+	reviewerIDs := make([]int64, 0, len(reviewerNames))
+	for _, name := range reviewerNames {
+		u, err := user_model.GetUserByName(ctx, name)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				return util.NewInvalidArgumentErrorf("user %q does not exist", name)
+			}
+			return err
+		}
+		if u.IsOrganization() {
+			return util.NewInvalidArgumentErrorf("%q is an organization", name)
+		}
+		perm, err := access_model.GetUserRepoPermission(ctx, repo, u)
+		if err != nil {
+			return err
+		}
+		if !perm.CanRead(unit.TypeActions) {
+			return util.NewInvalidArgumentErrorf("user %q has no access to the actions of the repository", name)
+		}
+		if !slices.Contains(reviewerIDs, u.ID) {
+			reviewerIDs = append(reviewerIDs, u.ID)
+		}
+	}
+	env.ReviewerIDs = reviewerIDs
+	return actions_model.UpdateEnvironment(ctx, env, "reviewer_i_ds")
+}
+
+// DeleteEnvironment deletes the environment with its secrets
+func DeleteEnvironment(ctx context.Context, env *actions_model.ActionEnvironment) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.DeleteByBean(ctx, &secret_model.Secret{RepoID: env.RepoID, EnvironmentID: env.ID}); err != nil {
+			return err
+		}
+		return actions_model.DeleteEnvironment(ctx, env.ID)
+	})
+}
+
+// getUnapprovedJobs returns the IDs of the blocked jobs which target protected environments but haven't been approved
+func getUnapprovedJobs(ctx context.Context, jobs []*actions_model.ActionRunJob) (container.Set[int64], error) {
+	unapproved := container.Set[int64]{}
+	envs := map[string]*actions_model.ActionEnvironment{}
+	for _, job := range jobs {
+		if job.Environment == "" || job.EnvironmentApprovedBy > 0 || !job.Status.IsBlocked() {
+			continue
+		}
+		env, ok := envs[job.Environment]
+		if !ok {
+			var err error
+			env, err = actions_model.GetEnvironmentByName(ctx, job.RepoID, job.Environment)
+			if err != nil && !errors.Is(err, util.ErrNotExist) {
+				return nil, err
+			}
+			envs[job.Environment] = env
+		}
+		if env != nil && env.IsProtected() {
+			unapproved.Add(job.ID)
+		}
+	}
+	return unapproved, nil
+}
+
+// PendingDeployment is a protected environment which some jobs of a run are waiting for
+type PendingDeployment struct {
+	Environment *actions_model.ActionEnvironment
+	Jobs        []*actions_model.ActionRunJob
+}
+
+// GetPendingDeployments returns the protected environments which the blocked jobs of the run are waiting for approval
+func GetPendingDeployments(ctx context.Context, jobs []*actions_model.ActionRunJob) ([]*PendingDeployment, error) {
+	unapproved, err := getUnapprovedJobs(ctx, jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*PendingDeployment
+	for _, job := range jobs {
+		if !unapproved.Contains(job.ID) {
+			continue
+		}
+		var deployment *PendingDeployment
+		for _, d := range ret {
+			if d.Environment.Name == job.Environment {
+				deployment = d
+				break
+			}
+		}
+		if deployment == nil {
+			env, err := actions_model.GetEnvironmentByName(ctx, job.RepoID, job.Environment)
+			if err != nil {
+				return nil, err
+			}
+			deployment = &PendingDeployment{Environment: env}
+			ret = append(ret, deployment)
+		}
+		deployment.Jobs = append(deployment.Jobs, job)
+	}
+	return ret, nil
+}
+
+// ReviewPendingDeployment approves or rejects the jobs of the run which are waiting for the protected environment,
+// the rejected jobs are cancelled.
+func ReviewPendingDeployment(ctx context.Context, doer *user_model.User, runID int64, envName string, approve bool) error {
+	if doer == nil {
+		return util.NewPermissionDeniedErrorf("only the signed-in reviewers can review deployments")
+	}
+	jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: runID})
+	if err != nil {
+		return err
+	}
+	deployments, err := GetPendingDeployments(ctx, jobs)
+	if err != nil {
+		return err
+	}
+
+	var deployment *PendingDeployment
+	for _, d := range deployments {
+		if d.Environment.Name == envName {
+			deployment = d
+			break
+		}
+	}
+	if deployment == nil {
+		return util.NewNotExistErrorf("no jobs are waiting for environment %q", envName)
+	}
+	if !deployment.Environment.IsReviewer(doer.ID) {
+		return util.NewPermissionDeniedErrorf("user is not a reviewer of environment %q", envName)
+	}
+
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if !approve {
+			return actions_model.CancelJobs(ctx, deployment.Jobs)
+		}
+		for _, job := range deployment.Jobs {
+			job.EnvironmentApprovedBy = doer.ID
+			if _, err := actions_model.UpdateRunJob(ctx, job, nil, "environment_approved_by"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if !approve {
+		CreateCommitStatus(ctx, deployment.Jobs...)
+		NotifyWorkflowJobsStatusUpdate(ctx, deployment.Jobs...)
+	}
+	if err := EmitJobsIfReady(runID); err != nil {
+		log.Error("EmitJobsIfReady: %v", err)
+	}
+	return nil
+}
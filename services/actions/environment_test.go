@@ -0,0 +1,49 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestUnmarshalRawEnvironment(t *testing.T) {
+	content := `
+jobs:
+  staging:
+    environment: staging
+  production:
+    environment:
+      name: production-${{ matrix.region }}
+      url: https://example.com
+  test:
+    runs-on: ubuntu-latest
+`
+	raw := &rawWorkflowAttributes{}
+	assert.NoError(t, yaml.Unmarshal([]byte(content), raw))
+	assert.Equal(t, &rawEnvironment{Name: "staging"}, raw.Jobs["staging"].Environment)
+	assert.Equal(t, &rawEnvironment{Name: "production-${{ matrix.region }}", URL: "https://example.com"}, raw.Jobs["production"].Environment)
+	assert.Nil(t, raw.Jobs["test"].Environment)
+
+	evaluator := newWorkflowEvaluator("production", map[string]any{"region": "eu"}, nil, nil)
+	assert.Equal(t, "production-eu", evaluator.Interpolate(raw.Jobs["production"].Environment.Name))
+}
+
+func TestValidateEnvironmentName(t *testing.T) {
+	assert.NoError(t, validateEnvironmentName("production"))
+	assert.NoError(t, validateEnvironmentName("github-pages"))
+	assert.Error(t, validateEnvironmentName(""))
+	assert.Error(t, validateEnvironmentName(" production"))
+	assert.Error(t, validateEnvironmentName("prod\nuction"))
+}
+
+func TestReviewPendingDeploymentWithoutDoer(t *testing.T) {
+	err := ReviewPendingDeployment(context.Background(), nil, 1, "production", true)
+	assert.ErrorIs(t, err, util.ErrPermissionDenied)
+}
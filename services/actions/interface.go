@@ -25,4 +25,16 @@ type API interface {
 	UpdateVariable(*context.APIContext)
 	// GetRegistrationToken get registration token
 	GetRegistrationToken(*context.APIContext)
+	// CreateRegistrationToken create a new registration token
+	CreateRegistrationToken(*context.APIContext)
+	// ListRunners list runners
+	ListRunners(*context.APIContext)
+	// GetRunner get a runner
+	GetRunner(*context.APIContext)
+	// EditRunner edit a runner
+	EditRunner(*context.APIContext)
+	// DeleteRunner delete a runner
+	DeleteRunner(*context.APIContext)
+	// GenerateRunnerJITConfig generate the just-in-time config of an ephemeral runner
+	GenerateRunnerJITConfig(*context.APIContext)
 }
@@ -0,0 +1,101 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"sort"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"gopkg.in/yaml.v3"
+)
+
+// JobContainerStatus is the status of the containers of a job, which is derived from the states of the steps reported by the runner
+type JobContainerStatus string
+
+const (
+	// JobContainerWaiting means the job hasn't been picked by a runner
+	JobContainerWaiting JobContainerStatus = "waiting"
+	// JobContainerStarting means the runner is pulling the images and waiting for the containers to be healthy
+	JobContainerStarting JobContainerStatus = "starting"
+	// JobContainerHealthy means the containers have started and the steps are running
+	JobContainerHealthy JobContainerStatus = "healthy"
+	// JobContainerFailed means the runner failed to start the containers
+	JobContainerFailed JobContainerStatus = "failed"
+	// JobContainerStopped means the job is done and the containers have been removed
+	JobContainerStopped JobContainerStatus = "stopped"
+)
+
+// JobContainer is the job container or a service container declared by a job
+type JobContainer struct {
+	Service string // the name of the service, empty for the job container
+	Image   string
+	Ports   []string
+}
+
+// GetRunJobContainers returns the `container` and the `services` of the job, the job container comes first
+func GetRunJobContainers(job *actions_model.ActionRunJob) ([]*JobContainer, error) {
+	if len(job.WorkflowPayload) == 0 {
+		return nil, nil
+	}
+	swfs, err := jobparser.Parse(job.WorkflowPayload)
+	if err != nil {
+		return nil, fmt.Errorf("parse workflow payload of job %d: %w", job.ID, err)
+	} else if len(swfs) != 1 {
+		return nil, fmt.Errorf("job %d has %d workflows in payload", job.ID, len(swfs))
+	}
+	_, wfJob := swfs[0].Job()
+
+	var ret []*JobContainer
+	jobContainer := &jobparser.ContainerSpec{}
+	switch wfJob.RawContainer.Kind {
+	case yaml.ScalarNode:
+		err = wfJob.RawContainer.Decode(&jobContainer.Image)
+	case yaml.MappingNode:
+		err = wfJob.RawContainer.Decode(jobContainer)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode container of job %d: %w", job.ID, err)
+	}
+	// an empty image means the job runs without a container
+	if jobContainer.Image != "" {
+		ret = append(ret, &JobContainer{Image: jobContainer.Image, Ports: jobContainer.Ports})
+	}
+	services := make([]*JobContainer, 0, len(wfJob.Services))
+	for name, c := range wfJob.Services {
+		if c == nil {
+			continue
+		}
+		services = append(services, &JobContainer{Service: name, Image: c.Image, Ports: c.Ports})
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Service < services[j].Service
+	})
+	return append(ret, services...), nil
+}
+
+// GetJobContainerStatus returns the status of the containers of the task, task could be nil if the job hasn't been picked.
+// The runner starts the containers and waits for them to be healthy in "Set up job", so its state is the one of the containers.
+func GetJobContainerStatus(task *actions_model.ActionTask) JobContainerStatus {
+	if task == nil {
+		return JobContainerWaiting
+	}
+	steps := actions_module.FullSteps(task)
+	if len(steps) == 0 {
+		return JobContainerStarting
+	}
+	preStep := steps[0]
+	switch {
+	case task.Status.IsFailure() && preStep.Status.IsFailure():
+		return JobContainerFailed
+	case task.Status.IsDone():
+		return JobContainerStopped
+	case preStep.Status.IsSuccess():
+		return JobContainerHealthy
+	}
+	return JobContainerStarting
+}
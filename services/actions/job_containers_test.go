@@ -0,0 +1,102 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRunJobContainers(t *testing.T) {
+	content := `
+name: test
+on: push
+jobs:
+  mapping:
+    runs-on: ubuntu-latest
+    container:
+      image: node:20
+      ports: [8080]
+    services:
+      redis:
+        image: redis:7
+      db:
+        image: mysql:8
+        ports: ["3306:3306"]
+    steps:
+      - run: npm test
+  scalar:
+    runs-on: ubuntu-latest
+    container: alpine:3
+    steps:
+      - run: echo ok
+  none:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ok
+`
+	swfs, err := jobparser.Parse([]byte(content))
+	assert.NoError(t, err)
+	assert.Len(t, swfs, 3)
+
+	jobs := map[string]*actions_model.ActionRunJob{}
+	for _, swf := range swfs {
+		id, _ := swf.Job()
+		payload, err := swf.Marshal()
+		assert.NoError(t, err)
+		jobs[id] = &actions_model.ActionRunJob{WorkflowPayload: payload}
+	}
+
+	containers, err := GetRunJobContainers(jobs["mapping"])
+	assert.NoError(t, err)
+	assert.Equal(t, []*JobContainer{
+		{Image: "node:20", Ports: []string{"8080"}},
+		{Service: "db", Image: "mysql:8", Ports: []string{"3306:3306"}},
+		{Service: "redis", Image: "redis:7"},
+	}, containers)
+
+	containers, err = GetRunJobContainers(jobs["scalar"])
+	assert.NoError(t, err)
+	assert.Equal(t, []*JobContainer{{Image: "alpine:3"}}, containers)
+
+	containers, err = GetRunJobContainers(jobs["none"])
+	assert.NoError(t, err)
+	assert.Empty(t, containers)
+}
+
+func TestGetJobContainerStatus(t *testing.T) {
+	steps := func(statuses ...actions_model.Status) []*actions_model.ActionTaskStep {
+		ret := make([]*actions_model.ActionTaskStep, 0, len(statuses))
+		for i, status := range statuses {
+			ret = append(ret, &actions_model.ActionTaskStep{Index: int64(i), Status: status})
+		}
+		return ret
+	}
+
+	assert.Equal(t, JobContainerWaiting, GetJobContainerStatus(nil))
+	assert.Equal(t, JobContainerStarting, GetJobContainerStatus(&actions_model.ActionTask{
+		Status: actions_model.StatusRunning,
+		Steps:  steps(actions_model.StatusWaiting, actions_model.StatusWaiting),
+	}))
+	assert.Equal(t, JobContainerHealthy, GetJobContainerStatus(&actions_model.ActionTask{
+		Status: actions_model.StatusRunning,
+		Steps:  steps(actions_model.StatusSuccess, actions_model.StatusRunning),
+	}))
+	assert.Equal(t, JobContainerFailed, GetJobContainerStatus(&actions_model.ActionTask{
+		Status: actions_model.StatusFailure,
+		Steps:  steps(actions_model.StatusSkipped, actions_model.StatusSkipped),
+	}))
+	assert.Equal(t, JobContainerStopped, GetJobContainerStatus(&actions_model.ActionTask{
+		Status: actions_model.StatusFailure,
+		Steps:  steps(actions_model.StatusSuccess, actions_model.StatusFailure),
+	}))
+	assert.Equal(t, JobContainerStopped, GetJobContainerStatus(&actions_model.ActionTask{
+		Status: actions_model.StatusCancelled,
+		Steps:  steps(actions_model.StatusWaiting, actions_model.StatusWaiting),
+	}))
+}
@@ -10,7 +10,9 @@ import (
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/queue"
 
 	"github.com/nektos/act/pkg/jobparser"
@@ -45,17 +47,34 @@ func jobEmitterQueueHandler(items ...*jobUpdate) []*jobUpdate {
 }
 
 func checkJobsOfRun(ctx context.Context, runID int64) error {
+	run, err := actions_model.GetRunByID(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run.NeedApproval || run.Status.IsBlocked() {
+		// the jobs will be emitted after the run is approved, or the concurrency group of the run is released
+		return nil
+	}
 	jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: runID})
 	if err != nil {
 		return err
 	}
+	busyGroups, err := getBusyConcurrencyGroups(ctx, jobs)
+	if err != nil {
+		return err
+	}
+	unapprovedJobs, err := getUnapprovedJobs(ctx, jobs)
+	if err != nil {
+		return err
+	}
+	var updatedJobs []*actions_model.ActionRunJob
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
 		idToJobs := make(map[string][]*actions_model.ActionRunJob, len(jobs))
 		for _, job := range jobs {
 			idToJobs[job.JobID] = append(idToJobs[job.JobID], job)
 		}
 
-		updates := newJobStatusResolver(jobs).Resolve()
+		updates := newJobStatusResolver(jobs, busyGroups, unapprovedJobs).Resolve()
 		for _, job := range jobs {
 			if status, ok := updates[job.ID]; ok {
 				job.Status = status
@@ -64,6 +83,7 @@ func checkJobsOfRun(ctx context.Context, runID int64) error {
 				} else if n != 1 {
 					return fmt.Errorf("no affected for updating blocked job %v", job.ID)
 				}
+				updatedJobs = append(updatedJobs, job)
 			}
 		}
 		return nil
@@ -71,16 +91,29 @@ func checkJobsOfRun(ctx context.Context, runID int64) error {
 		return err
 	}
 	CreateCommitStatus(ctx, jobs...)
-	return nil
+	NotifyWorkflowJobsStatusUpdate(ctx, updatedJobs...)
+
+	// reload the run since its status could be changed by the jobs
+	if run, err = actions_model.GetRunByID(ctx, runID); err != nil {
+		return err
+	}
+	if run.Status.IsDone() {
+		if err := notifyWorkflowRunCompleted(ctx, run, jobs); err != nil {
+			log.Error("notifyWorkflowRunCompleted for run %d: %v", run.ID, err)
+		}
+	}
+	return releaseConcurrency(ctx, run, jobs)
 }
 
 type jobStatusResolver struct {
-	statuses map[int64]actions_model.Status
-	needs    map[int64][]int64
-	jobMap   map[int64]*actions_model.ActionRunJob
+	statuses       map[int64]actions_model.Status
+	needs          map[int64][]int64
+	jobMap         map[int64]*actions_model.ActionRunJob
+	busyGroups     container.Set[string] // the concurrency groups which have waiting or running jobs
+	unapprovedJobs container.Set[int64]  // the jobs which are waiting for the approval of protected environments
 }
 
-func newJobStatusResolver(jobs actions_model.ActionJobList) *jobStatusResolver {
+func newJobStatusResolver(jobs actions_model.ActionJobList, busyGroups container.Set[string], unapprovedJobs container.Set[int64]) *jobStatusResolver {
 	idToJobs := make(map[string][]*actions_model.ActionRunJob, len(jobs))
 	jobMap := make(map[int64]*actions_model.ActionRunJob)
 	for _, job := range jobs {
@@ -98,10 +131,15 @@ func newJobStatusResolver(jobs actions_model.ActionJobList) *jobStatusResolver {
 			}
 		}
 	}
+	if busyGroups == nil {
+		busyGroups = container.Set[string]{}
+	}
 	return &jobStatusResolver{
-		statuses: statuses,
-		needs:    needs,
-		jobMap:   jobMap,
+		statuses:       statuses,
+		needs:          needs,
+		jobMap:         jobMap,
+		busyGroups:     busyGroups,
+		unapprovedJobs: unapprovedJobs,
 	}
 }
 
@@ -136,27 +174,40 @@ func (r *jobStatusResolver) resolve() map[int64]actions_model.Status {
 				allSucceed = false
 			}
 		}
-		if allDone {
-			if allSucceed {
-				ret[id] = actions_model.StatusWaiting
-			} else {
-				// Check if the job has an "if" condition
-				hasIf := false
-				if wfJobs, _ := jobparser.Parse(r.jobMap[id].WorkflowPayload); len(wfJobs) == 1 {
-					_, wfJob := wfJobs[0].Job()
-					hasIf = len(wfJob.If.Value) > 0
-				}
+		if !allDone {
+			continue
+		}
 
-				if hasIf {
-					// act_runner will check the "if" condition
-					ret[id] = actions_model.StatusWaiting
-				} else {
-					// If the "if" condition is empty and not all dependent jobs completed successfully,
-					// the job should be skipped.
-					ret[id] = actions_model.StatusSkipped
+		status := actions_model.StatusWaiting
+		if !allSucceed {
+			// Check if the job has an "if" condition
+			hasIf := false
+			if wfJobs, _ := jobparser.Parse(r.jobMap[id].WorkflowPayload); len(wfJobs) == 1 {
+				_, wfJob := wfJobs[0].Job()
+				hasIf = len(wfJob.If.Value) > 0
+			}
+
+			if !hasIf {
+				// If the "if" condition is empty and not all dependent jobs completed successfully,
+				// the job should be skipped. Otherwise act_runner will check the "if" condition.
+				status = actions_model.StatusSkipped
+			}
+		}
+
+		if status == actions_model.StatusWaiting {
+			if r.unapprovedJobs.Contains(id) {
+				// the job targets a protected environment, it will be emitted after it is approved
+				continue
+			}
+			if group := r.jobMap[id].ConcurrencyGroup; group != "" {
+				// only one job of the concurrency group could be in progress
+				if r.busyGroups.Contains(group) {
+					continue
 				}
+				r.busyGroups.Add(group)
 			}
 		}
+		ret[id] = status
 	}
 	return ret
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/container"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -129,8 +130,41 @@ jobs:
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r := newJobStatusResolver(tt.jobs)
+			r := newJobStatusResolver(tt.jobs, nil, nil)
 			assert.Equal(t, tt.want, r.Resolve())
 		})
 	}
 }
+
+func Test_jobStatusResolver_ResolveConcurrency(t *testing.T) {
+	jobs := actions_model.ActionJobList{
+		{ID: 1, JobID: "1", Status: actions_model.StatusBlocked, Needs: []string{}, ConcurrencyGroup: "deploy"},
+		{ID: 2, JobID: "2", Status: actions_model.StatusBlocked, Needs: []string{}, ConcurrencyGroup: "deploy"},
+		{ID: 3, JobID: "3", Status: actions_model.StatusBlocked, Needs: []string{}, ConcurrencyGroup: "test"},
+	}
+
+	// only one job of a group could start
+	got := newJobStatusResolver(jobs, nil, nil).Resolve()
+	assert.Len(t, got, 2)
+	assert.Equal(t, actions_model.StatusWaiting, got[3])
+	assert.NotEqual(t, got[1] == actions_model.StatusWaiting, got[2] == actions_model.StatusWaiting)
+
+	// the group has an in-progress job
+	got = newJobStatusResolver(jobs, container.SetOf("deploy"), nil).Resolve()
+	assert.Equal(t, map[int64]actions_model.Status{3: actions_model.StatusWaiting}, got)
+}
+
+func Test_jobStatusResolver_ResolveUnapproved(t *testing.T) {
+	jobs := actions_model.ActionJobList{
+		{ID: 1, JobID: "1", Status: actions_model.StatusSuccess, Needs: []string{}},
+		{ID: 2, JobID: "2", Status: actions_model.StatusBlocked, Needs: []string{"1"}, Environment: "production"},
+		{ID: 3, JobID: "3", Status: actions_model.StatusBlocked, Needs: []string{"2"}},
+	}
+
+	// the job waiting for the approval stays blocked, so do the jobs need it
+	got := newJobStatusResolver(jobs, nil, container.SetOf[int64](2)).Resolve()
+	assert.Empty(t, got)
+
+	got = newJobStatusResolver(jobs, nil, nil).Resolve()
+	assert.Equal(t, map[int64]actions_model.Status{2: actions_model.StatusWaiting}, got)
+}
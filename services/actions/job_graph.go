@@ -0,0 +1,71 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	actions_model "code.gitea.io/gitea/models/actions"
+)
+
+// JobGraphNode is a job in the dependency graph of a run
+type JobGraphNode struct {
+	Job *actions_model.ActionRunJob
+	// Index is the index of the job in the jobs of the run
+	Index int
+	// Level is 0 for the jobs which need no other jobs, otherwise it's one more than the max level of the jobs it needs
+	Level int
+}
+
+// JobGraphEdge means the job at index To needs the job at index From
+type JobGraphEdge struct {
+	From int
+	To   int
+}
+
+// BuildJobGraph builds the dependency graph of the jobs of a run with their `needs`.
+// A job could need several jobs with the same job id, they are expanded from a matrix.
+func BuildJobGraph(jobs []*actions_model.ActionRunJob) ([]*JobGraphNode, []*JobGraphEdge) {
+	indexes := make(map[string][]int, len(jobs))
+	for i, job := range jobs {
+		indexes[job.JobID] = append(indexes[job.JobID], i)
+	}
+
+	var edges []*JobGraphEdge
+	needs := make([][]int, len(jobs))
+	for i, job := range jobs {
+		for _, need := range job.Needs {
+			for _, from := range indexes[need] {
+				needs[i] = append(needs[i], from)
+				edges = append(edges, &JobGraphEdge{From: from, To: i})
+			}
+		}
+	}
+
+	levels := make([]int, len(jobs))
+	resolved := make([]bool, len(jobs))
+	visiting := make([]bool, len(jobs))
+	var resolveLevel func(i int) int
+	resolveLevel = func(i int) int {
+		if resolved[i] {
+			return levels[i]
+		}
+		if visiting[i] {
+			// it shouldn't happen since the workflow has been validated, but don't loop forever
+			return 0
+		}
+		visiting[i] = true
+		level := 0
+		for _, from := range needs[i] {
+			level = max(level, resolveLevel(from)+1)
+		}
+		visiting[i] = false
+		levels[i], resolved[i] = level, true
+		return level
+	}
+
+	nodes := make([]*JobGraphNode, 0, len(jobs))
+	for i, job := range jobs {
+		nodes = append(nodes, &JobGraphNode{Job: job, Index: i, Level: resolveLevel(i)})
+	}
+	return nodes, edges
+}
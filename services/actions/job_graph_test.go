@@ -0,0 +1,46 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildJobGraph(t *testing.T) {
+	jobs := []*actions_model.ActionRunJob{
+		{JobID: "lint"},
+		{JobID: "build"},
+		{JobID: "build"},
+		{JobID: "test", Needs: []string{"build"}},
+		{JobID: "release", Needs: []string{"lint", "test"}},
+		{JobID: "notify", Needs: []string{"release", "unknown"}},
+	}
+	nodes, edges := BuildJobGraph(jobs)
+
+	levels := make([]int, 0, len(nodes))
+	for i, node := range nodes {
+		assert.Equal(t, i, node.Index)
+		assert.Same(t, jobs[i], node.Job)
+		levels = append(levels, node.Level)
+	}
+	assert.Equal(t, []int{0, 0, 0, 1, 2, 3}, levels)
+	assert.Equal(t, []*JobGraphEdge{
+		{From: 1, To: 3},
+		{From: 2, To: 3},
+		{From: 0, To: 4},
+		{From: 3, To: 4},
+		{From: 4, To: 5},
+	}, edges)
+
+	// the cycle is not expected, but it shouldn't loop forever
+	nodes, _ = BuildJobGraph([]*actions_model.ActionRunJob{
+		{JobID: "a", Needs: []string{"b"}},
+		{JobID: "b", Needs: []string{"a"}},
+	})
+	assert.Len(t, nodes, 2)
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"sync"
+)
+
+// jobUpdates dispatches the signals of job updates to the subscribers in this process,
+// it is used to push the live logs of a job without polling.
+var jobUpdates = struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[chan struct{}]struct{}
+}{
+	subscribers: make(map[int64]map[chan struct{}]struct{}),
+}
+
+// SubscribeJobUpdates returns a channel which receives a signal when the task of the job has been updated by the runner,
+// the returned function must be called to unsubscribe when the channel is no longer used.
+// Signals could be merged, so the subscriber should load the latest data of the job after receiving a signal.
+func SubscribeJobUpdates(jobID int64) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	jobUpdates.mu.Lock()
+	if jobUpdates.subscribers[jobID] == nil {
+		jobUpdates.subscribers[jobID] = make(map[chan struct{}]struct{})
+	}
+	jobUpdates.subscribers[jobID][ch] = struct{}{}
+	jobUpdates.mu.Unlock()
+
+	return ch, func() {
+		jobUpdates.mu.Lock()
+		defer jobUpdates.mu.Unlock()
+		delete(jobUpdates.subscribers[jobID], ch)
+		if len(jobUpdates.subscribers[jobID]) == 0 {
+			delete(jobUpdates.subscribers, jobID)
+		}
+	}
+}
+
+// NotifyJobUpdated sends a signal to the subscribers of the job, it never blocks
+func NotifyJobUpdated(jobID int64) {
+	jobUpdates.mu.Lock()
+	defer jobUpdates.mu.Unlock()
+	for ch := range jobUpdates.subscribers[jobID] {
+		select {
+		case ch <- struct{}{}:
+		default: // there is a pending signal already
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobUpdates(t *testing.T) {
+	ch1, unsubscribe1 := SubscribeJobUpdates(1)
+	ch2, unsubscribe2 := SubscribeJobUpdates(2)
+	defer unsubscribe2()
+
+	// signals are merged and never block
+	NotifyJobUpdated(1)
+	NotifyJobUpdated(1)
+	assert.Len(t, ch1, 1)
+	assert.Empty(t, ch2)
+	<-ch1
+
+	unsubscribe1()
+	NotifyJobUpdated(1)
+	assert.Empty(t, ch1)
+	assert.NotContains(t, jobUpdates.subscribers, int64(1))
+}
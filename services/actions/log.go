@@ -0,0 +1,126 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+)
+
+// RunLogsZipName returns the name of the zip archive of the logs of the run, e.g. "build-12-logs.zip" for the run 12 of "build.yml"
+func RunLogsZipName(run *actions_model.ActionRun) string {
+	return fmt.Sprintf("%s-%d-logs.zip", strings.TrimSuffix(run.WorkflowID, path.Ext(run.WorkflowID)), run.Index)
+}
+
+// WriteRunLogsZip writes the logs of all the jobs in the attempt of the run into a zip archive, one file per job.
+// The jobs which haven't been started or whose logs have expired are skipped.
+func WriteRunLogsZip(ctx context.Context, w io.Writer, run *actions_model.ActionRun, attempt int64) error {
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return fmt.Errorf("GetRunJobsByRunID: %w", err)
+	}
+	if jobs, _, err = actions_model.GetRunAttemptJobs(ctx, run, jobs, attempt); err != nil {
+		return fmt.Errorf("GetRunAttemptJobs: %w", err)
+	}
+
+	writer := zip.NewWriter(w)
+	defer writer.Close()
+
+	for i, job := range jobs {
+		if job.TaskID == 0 {
+			continue
+		}
+		task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+		if err != nil {
+			return fmt.Errorf("GetTaskByID: %w", err)
+		}
+		if task.LogExpired || task.LogFilename == "" {
+			continue
+		}
+		if err := writeTaskLogToZip(ctx, writer, fmt.Sprintf("%d_%s.log", i, sanitizeLogFileName(job.Name)), task); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func writeTaskLogToZip(ctx context.Context, writer *zip.Writer, name string, task *actions_model.ActionTask) error {
+	if err := RehydrateLogs(ctx, task); err != nil {
+		return fmt.Errorf("RehydrateLogs: %w", err)
+	}
+	reader, err := actions_module.OpenLogs(ctx, task.LogInStorage, task.LogFilename)
+	if err != nil {
+		return fmt.Errorf("OpenLogs: %w", err)
+	}
+	defer reader.Close()
+
+	fw, err := writer.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: task.Updated.AsLocalTime(),
+	})
+	if err != nil {
+		return fmt.Errorf("CreateHeader: %w", err)
+	}
+	if _, err := io.Copy(fw, reader); err != nil {
+		return fmt.Errorf("copy log of task %d: %w", task.ID, err)
+	}
+	return nil
+}
+
+var logFileNameReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_")
+
+func sanitizeLogFileName(name string) string {
+	return logFileNameReplacer.Replace(name)
+}
+
+// LogMatch is a log line of a task which matches a search
+type LogMatch struct {
+	Step int   // the index of the step in the full steps of the task, see actions_module.FullSteps
+	Line int64 // the line number in the logs of the step, starting at 1
+}
+
+// SearchTaskLogs searches the logs of the task for the lines containing the keyword, ignoring the case.
+// At most limit matches are returned in the order of the lines, the second return value reports whether there are more.
+// The steps of the task must have been loaded.
+func SearchTaskLogs(ctx context.Context, task *actions_model.ActionTask, keyword string, limit int) ([]*LogMatch, bool, error) {
+	matches := make([]*LogMatch, 0)
+	keyword = strings.ToLower(keyword)
+	if keyword == "" || task.LogExpired || task.LogFilename == "" {
+		return matches, false, nil
+	}
+
+	if err := RehydrateLogs(ctx, task); err != nil {
+		return nil, false, fmt.Errorf("RehydrateLogs: %w", err)
+	}
+
+	for i, step := range actions_module.FullSteps(task) {
+		// the logs of the step could be not written yet if the task is still running
+		if step.LogLength <= 0 || step.LogIndex >= int64(len(task.LogIndexes)) {
+			continue
+		}
+		rows, err := actions_module.ReadLogs(ctx, task.LogInStorage, task.LogFilename, task.LogIndexes[step.LogIndex], step.LogLength)
+		if err != nil {
+			return nil, false, fmt.Errorf("ReadLogs: %w", err)
+		}
+		for j, row := range rows {
+			if !strings.Contains(strings.ToLower(row.Content), keyword) {
+				continue
+			}
+			if len(matches) >= limit {
+				return matches, true, nil
+			}
+			matches = append(matches, &LogMatch{Step: i, Line: int64(j) + 1})
+		}
+	}
+	return matches, false, nil
+}
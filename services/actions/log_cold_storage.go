@@ -0,0 +1,85 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const moveLogBatchSize = 100
+
+// MoveLogsToColdStorage moves the logs which are older than the configured days to the cold storage, the runs and the tasks are kept
+func MoveLogsToColdStorage(ctx context.Context) error {
+	if setting.Actions.LogColdStorageDays <= 0 {
+		return nil
+	}
+	olderThan := timeutil.TimeStampNow().AddDuration(-time.Duration(setting.Actions.LogColdStorageDays) * 24 * time.Hour)
+
+	count := 0
+	// page by the last seen ID, the tasks whose logs failed to be moved would be found again otherwise
+	var afterID int64
+	for {
+		tasks, err := actions_model.FindOldTasksToMoveLogs(ctx, olderThan, afterID, moveLogBatchSize)
+		if err != nil {
+			return fmt.Errorf("find old tasks: %w", err)
+		}
+		for _, task := range tasks {
+			afterID = task.ID
+			filename, remove, err := actions_module.TransferLogsToColdStorage(task.LogFilename)
+			if err != nil {
+				log.Error("Failed to move log %s of task %v to cold storage: %v", task.LogFilename, task.ID, err)
+				// do not return error here, continue to next task
+				continue
+			}
+			task.LogFilename = filename
+			task.LogInColdStorage = true
+			if err := actions_model.UpdateTask(ctx, task, "log_filename", "log_in_cold_storage"); err != nil {
+				log.Error("Failed to update task %v: %v", task.ID, err)
+				// the task still refers to the logs in the storage, so drop the copy in the cold storage instead
+				if err := storage.ActionsLogCold.Delete(filename); err != nil {
+					log.Warn("cold storage delete %q: %v", filename, err)
+				}
+				// do not return error here, continue to next task
+				continue
+			}
+			remove()
+			count++
+			log.Trace("Moved log %s of task %v to cold storage", task.LogFilename, task.ID)
+		}
+		if len(tasks) < moveLogBatchSize {
+			break
+		}
+	}
+
+	log.Info("Moved %d logs to cold storage", count)
+	return nil
+}
+
+// RehydrateLogs moves the logs of the task back from the cold storage if they are there, so they can be read
+func RehydrateLogs(ctx context.Context, task *actions_model.ActionTask) error {
+	if !task.LogInColdStorage || task.LogExpired {
+		return nil
+	}
+	if err := actions_module.TransferLogsFromColdStorage(task.LogFilename); err != nil {
+		// the logs could have been rehydrated by another request
+		if latest, e := actions_model.GetTaskByID(ctx, task.ID); e == nil && !latest.LogInColdStorage {
+			task.LogInColdStorage = false
+			task.LogRehydrated = latest.LogRehydrated
+			return nil
+		}
+		return err
+	}
+	task.LogInColdStorage = false
+	task.LogRehydrated = timeutil.TimeStampNow()
+	return actions_model.UpdateTask(ctx, task, "log_in_cold_storage", "log_rehydrated")
+}
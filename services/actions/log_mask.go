@@ -0,0 +1,57 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/json"
+	secret_module "code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// InitTaskLogMasks saves the values of the secrets given to the task, so they are masked in the logs uploaded by the runner
+func InitTaskLogMasks(ctx context.Context, task *actions_model.ActionTask, secrets map[string]string) error {
+	values := make([]string, 0, len(secrets))
+	for _, v := range secrets {
+		values = append(values, v)
+	}
+	if err := SetTaskLogMasker(task, actions_module.NewLogMasker(values...)); err != nil {
+		return err
+	}
+	return actions_model.UpdateTask(ctx, task, "log_masks")
+}
+
+// GetTaskLogMasker returns the masker of the secret values of the task
+func GetTaskLogMasker(task *actions_model.ActionTask) (*actions_module.LogMasker, error) {
+	if task.LogMasks == "" {
+		return actions_module.NewLogMasker(), nil
+	}
+	data, err := secret_module.DecryptSecret(setting.SecretKey, task.LogMasks)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return nil, err
+	}
+	return actions_module.NewLogMasker(values...), nil
+}
+
+// SetTaskLogMasker sets the values of the masker to the task, the caller should update the "log_masks" column of the task
+func SetTaskLogMasker(task *actions_model.ActionTask, masker *actions_module.LogMasker) error {
+	values := masker.Values()
+	if len(values) == 0 {
+		task.LogMasks = ""
+		return nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	task.LogMasks, err = secret_module.EncryptSecret(setting.SecretKey, string(data))
+	return err
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunLogsZipName(t *testing.T) {
+	cases := []struct {
+		workflowID string
+		expected   string
+	}{
+		{"build.yml", "build-12-logs.zip"},
+		{"build.yaml", "build-12-logs.zip"},
+		{"build.release.yml", "build.release-12-logs.zip"},
+		{"build", "build-12-logs.zip"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, RunLogsZipName(&actions_model.ActionRun{WorkflowID: c.workflowID, Index: 12}), c.workflowID)
+	}
+}
+
+func TestSanitizeLogFileName(t *testing.T) {
+	assert.Equal(t, "build (linux_amd64)", sanitizeLogFileName("build (linux/amd64)"))
+	assert.Equal(t, "test_ windows_x64", sanitizeLogFileName(`test: windows\x64`))
+}
@@ -6,6 +6,7 @@ package actions
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	perm_model "code.gitea.io/gitea/models/perm"
@@ -105,6 +106,10 @@ func (n *actionsNotifier) IssueChangeStatus(ctx context.Context, doer *user_mode
 			log.Error("LoadPullRequest: %v", err)
 			return
 		}
+		// the runs of a closed pull request are stale, cancel them before the closed event triggers new runs
+		if isClosed {
+			cancelStaleRuns(ctx, issue.RepoID, issue.PullRequest.GetGitRefName(), actions_model.RunCancelReasonPullRequestClosed)
+		}
 		// Merge pull request calls issue.changeStatus so we need to handle separately.
 		apiPullRequest := &api.PullRequestPayload{
 			Index:       issue.Index,
@@ -567,6 +572,10 @@ func (n *actionsNotifier) CreateRef(ctx context.Context, pusher *user_model.User
 func (n *actionsNotifier) DeleteRef(ctx context.Context, pusher *user_model.User, repo *repo_model.Repository, refFullName git.RefName) {
 	ctx = withMethod(ctx, "DeleteRef")
 
+	if refFullName.IsBranch() {
+		cancelStaleRuns(ctx, repo.ID, refFullName.String(), actions_model.RunCancelReasonBranchDeleted)
+	}
+
 	apiPusher := convert.ToUser(ctx, pusher, nil)
 	apiRepo := convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm_model.AccessModeNone})
 
@@ -197,11 +197,24 @@ func notify(ctx context.Context, input *notifyInput) error {
 			continue
 		}
 
+		if canRun, err := canRunCommandWorkflow(ctx, input, wf); err != nil {
+			return err
+		} else if !canRun {
+			log.Trace("repo %s: user %d can't trigger workflow %s with comment commands", input.Repo.RepoPath(), input.Doer.ID, wf.EntryName)
+			continue
+		}
+
 		if wf.TriggerEvent.Name != actions_module.GithubEventPullRequestTarget {
 			detectedWorkflows = append(detectedWorkflows, wf)
 		}
 	}
 
+	requiredWorkflows, err := detectOrgRequiredWorkflows(ctx, input, gitRepo, commit)
+	if err != nil {
+		return err
+	}
+	detectedWorkflows = append(detectedWorkflows, requiredWorkflows...)
+
 	if input.PullRequest != nil {
 		// detect pull_request_target workflows
 		baseRef := git.BranchPrefix + input.PullRequest.BaseBranch
@@ -233,6 +246,22 @@ func notify(ctx context.Context, input *notifyInput) error {
 	return handleWorkflows(ctx, detectedWorkflows, commit, input, ref.String())
 }
 
+// canRunCommandWorkflow reports whether the doer can trigger the workflow,
+// the workflows filtered by comment commands can only be triggered by the users who can write the actions of the repository.
+func canRunCommandWorkflow(ctx context.Context, input *notifyInput, wf *actions_module.DetectedWorkflow) (bool, error) {
+	if _, ok := wf.TriggerEvent.Acts()["commands"]; !ok {
+		return true, nil
+	}
+	if input.Doer.IsRestricted {
+		return false, nil
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, input.Repo, input.Doer)
+	if err != nil {
+		return false, fmt.Errorf("GetUserRepoPermission: %w", err)
+	}
+	return perm.CanWrite(unit_model.TypeActions), nil
+}
+
 func skipWorkflows(input *notifyInput, commit *git.Commit) bool {
 	// skip workflow runs with a configured skip-ci string in commit message or pr title if the event is push or pull_request(_sync)
 	// https://docs.github.com/en/actions/managing-workflow-runs/skipping-workflow-runs
@@ -329,9 +358,16 @@ func handleWorkflows(
 			continue
 		}
 
-		// cancel running jobs if the event is push or pull_request_sync
-		if run.Event == webhook_module.HookEventPush ||
-			run.Event == webhook_module.HookEventPullRequestSync {
+		attributes, err := evaluateWorkflowAttributes(ctx, run, dwf.Content, jobs)
+		if err != nil {
+			log.Error("evaluateWorkflowAttributes: %v", err)
+			continue
+		}
+
+		// cancel running jobs if the event is push or pull_request_sync,
+		// the workflows with a concurrency group manage the previous runs by themselves
+		if run.ConcurrencyGroup == "" && (run.Event == webhook_module.HookEventPush ||
+			run.Event == webhook_module.HookEventPullRequestSync) {
 			if err := actions_model.CancelPreviousJobs(
 				ctx,
 				run.RepoID,
@@ -343,7 +379,7 @@ func handleWorkflows(
 			}
 		}
 
-		if err := actions_model.InsertRun(ctx, run, jobs); err != nil {
+		if err := insertRun(ctx, run, jobs, attributes); err != nil {
 			log.Error("InsertRun: %v", err)
 			continue
 		}
@@ -426,6 +462,20 @@ func ifNeedApproval(ctx context.Context, run *actions_model.ActionRun, repo *rep
 		return false, nil
 	}
 
+	// need approval every time if the repository requires approval for all outside collaborators
+	if repo.MustGetUnit(ctx, unit_model.TypeActions).ActionsConfig().GetApprovalPolicy() == repo_model.ActionsApprovalPolicyAllOutside {
+		log.Trace("need approval because user %d can't write and the repository requires approval for all outside collaborators", user.ID)
+		return true, nil
+	}
+
+	// don't need approval if the user has contributed to the repository
+	if merged, err := issues_model.HasMergedPullRequestInRepo(ctx, repo.ID, user.ID); err != nil {
+		return false, fmt.Errorf("HasMergedPullRequestInRepo: %w", err)
+	} else if merged {
+		log.Trace("do not need approval because user %d has merged pull requests", user.ID)
+		return false, nil
+	}
+
 	// don't need approval if the user has been approved before
 	if count, err := db.Count[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
 		RepoID:        repo.ID,
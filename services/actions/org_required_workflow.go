@@ -0,0 +1,118 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+)
+
+// CreateOrgRequiredWorkflow requires the workflow in the repository of the organization to run in the repositories,
+// or in all repositories of the organization if repoNames is empty
+func CreateOrgRequiredWorkflow(ctx context.Context, orgID int64, repoName, workflowID string, repoNames []string) (*actions_model.ActionOrgRequiredWorkflow, error) {
+	repo, err := repo_model.GetRepositoryByName(ctx, orgID, repoName)
+	if repo_model.IsErrRepoNotExist(err) {
+		return nil, util.NewInvalidArgumentErrorf("repository %q does not exist", repoName)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !actions_module.IsWorkflow(".gitea/workflows/" + workflowID) {
+		return nil, util.NewInvalidArgumentErrorf("%q is not a workflow file", workflowID)
+	}
+	if _, err := getDefaultBranchWorkflowContent(ctx, repo, workflowID); err != nil {
+		return nil, err
+	}
+
+	var repoIDs []int64
+	for _, name := range repoNames {
+		r, err := repo_model.GetRepositoryByName(ctx, orgID, name)
+		if repo_model.IsErrRepoNotExist(err) {
+			return nil, util.NewInvalidArgumentErrorf("repository %q does not exist", name)
+		} else if err != nil {
+			return nil, err
+		}
+		repoIDs = append(repoIDs, r.ID)
+	}
+
+	w := &actions_model.ActionOrgRequiredWorkflow{
+		OrgID:      orgID,
+		RepoID:     repo.ID,
+		WorkflowID: workflowID,
+		RepoIDs:    repoIDs,
+		Repo:       repo,
+	}
+	return w, db.Insert(ctx, w)
+}
+
+// getDefaultBranchWorkflowContent returns the content of the workflow in the default branch of the repository
+func getDefaultBranchWorkflowContent(ctx context.Context, repo *repo_model.Repository, workflowID string) ([]byte, error) {
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if git.IsErrNotExist(err) {
+		return nil, util.NewInvalidArgumentErrorf("repository %q has no default branch", repo.Name)
+	} else if err != nil {
+		return nil, err
+	}
+	entries, err := actions_module.ListWorkflows(commit)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Name() == workflowID {
+			return actions_module.GetContentFromEntry(entry)
+		}
+	}
+	return nil, util.NewInvalidArgumentErrorf("workflow %q does not exist in the default branch of repository %q", workflowID, repo.Name)
+}
+
+// detectOrgRequiredWorkflows returns the required workflows of the organization which match the push or pull request event of the repository,
+// the workflows are read from the default branches of the repositories which they are in.
+func detectOrgRequiredWorkflows(ctx context.Context, input *notifyInput, gitRepo *git.Repository, commit *git.Commit) ([]*actions_module.DetectedWorkflow, error) {
+	switch input.Event {
+	case webhook_module.HookEventPush, webhook_module.HookEventPullRequest, webhook_module.HookEventPullRequestSync:
+	default:
+		return nil, nil
+	}
+
+	requiredWorkflows, err := actions_model.GetOrgRequiredWorkflowsForRepo(ctx, input.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("GetOrgRequiredWorkflowsForRepo: %w", err)
+	}
+	var detectedWorkflows []*actions_module.DetectedWorkflow
+	for _, w := range requiredWorkflows {
+		content, err := getDefaultBranchWorkflowContent(ctx, w.Repo, w.WorkflowID)
+		if err != nil {
+			log.Warn("ignore required workflow %s of org %d: %v", w.RunWorkflowID(), w.OrgID, err)
+			continue
+		}
+		workflows, err := actions_module.DetectWorkflowContent(gitRepo, commit, w.RunWorkflowID(), content, input.Event, input.Payload)
+		if err != nil {
+			log.Warn("ignore invalid required workflow %s of org %d: %v", w.RunWorkflowID(), w.OrgID, err)
+			continue
+		}
+		for _, wf := range workflows {
+			// the pull_request_target workflows run for the base branch, which is only meaningful for the workflows in the repository
+			if wf.TriggerEvent.Name != actions_module.GithubEventPullRequestTarget {
+				detectedWorkflows = append(detectedWorkflows, wf)
+			}
+		}
+	}
+	return detectedWorkflows, nil
+}
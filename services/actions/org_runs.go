@@ -0,0 +1,92 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// SearchOrgRunsOptions are the filters of the runs of an organization, the empty filters are ignored
+type SearchOrgRunsOptions struct {
+	db.ListOptions
+	Repo     string // the name of the repository
+	Workflow string // the workflow file name, e.g. "build.yml"
+	Status   string // the name of the status, e.g. "success"
+	Actor    string // the name of the user who triggered the runs
+	Since    string // the runs created on or after the date, in "2006-01-02" format
+	Until    string // the runs created on or before the date, in "2006-01-02" format
+}
+
+// SearchOrgRuns returns the runs of the repositories of the organization which the doer can read.
+// It returns an invalid argument error if a filter is invalid, and no runs if the repository or the actor doesn't exist,
+// so the private repositories aren't revealed.
+func SearchOrgRuns(ctx context.Context, org, doer *user_model.User, opts SearchOrgRunsOptions) (actions_model.RunList, int64, error) {
+	findOpts := actions_model.FindRunOptions{
+		ListOptions: opts.ListOptions,
+		WorkflowID:  opts.Workflow,
+		RepoCond:    actions_model.ActionsAccessibleRepoCond(org.ID, doer),
+	}
+
+	if opts.Status != "" {
+		status, ok := actions_model.ParseStatus(opts.Status)
+		if !ok {
+			return nil, 0, util.NewInvalidArgumentErrorf("invalid status %q", opts.Status)
+		}
+		findOpts.Status = []actions_model.Status{status}
+	}
+	if opts.Since != "" {
+		since, err := time.ParseInLocation(time.DateOnly, opts.Since, setting.DefaultUILocation)
+		if err != nil {
+			return nil, 0, util.NewInvalidArgumentErrorf("invalid date %q", opts.Since)
+		}
+		findOpts.CreatedAfter = timeutil.TimeStamp(since.Unix())
+	}
+	if opts.Until != "" {
+		until, err := time.ParseInLocation(time.DateOnly, opts.Until, setting.DefaultUILocation)
+		if err != nil {
+			return nil, 0, util.NewInvalidArgumentErrorf("invalid date %q", opts.Until)
+		}
+		findOpts.CreatedBefore = timeutil.TimeStamp(until.AddDate(0, 0, 1).Unix())
+	}
+
+	if opts.Repo != "" {
+		repo, err := repo_model.GetRepositoryByName(ctx, org.ID, opts.Repo)
+		if repo_model.IsErrRepoNotExist(err) {
+			return nil, 0, nil
+		} else if err != nil {
+			return nil, 0, err
+		}
+		findOpts.RepoID = repo.ID
+	}
+	if opts.Actor != "" {
+		actor, err := user_model.GetUserByName(ctx, opts.Actor)
+		if user_model.IsErrUserNotExist(err) {
+			return nil, 0, nil
+		} else if err != nil {
+			return nil, 0, err
+		}
+		findOpts.TriggerUserID = actor.ID
+	}
+
+	runs, total, err := db.FindAndCount[actions_model.ActionRun](ctx, findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := actions_model.RunList(runs).LoadRepos(ctx); err != nil {
+		return nil, 0, err
+	}
+	if err := actions_model.RunList(runs).LoadTriggerUser(ctx); err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
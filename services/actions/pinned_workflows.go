@@ -0,0 +1,58 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+)
+
+func pinnedWorkflowsKey(repoID int64) string {
+	return fmt.Sprintf("actions.pinned_workflows.%d", repoID)
+}
+
+// GetPinnedWorkflows returns the ids of the workflows the user pinned in the repository, in the order they were pinned
+func GetPinnedWorkflows(ctx context.Context, userID, repoID int64) ([]string, error) {
+	value, err := user_model.GetUserSetting(ctx, userID, pinnedWorkflowsKey(repoID))
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return nil, nil
+	}
+	var workflowIDs []string
+	if err := json.Unmarshal([]byte(value), &workflowIDs); err != nil {
+		// it's only a convenience, so forget the broken pins instead of failing
+		log.Warn("unmarshal the pinned workflows in repo %d for user %d: %v", repoID, userID, err)
+		return nil, nil
+	}
+	return workflowIDs, nil
+}
+
+// SetWorkflowPinned pins the workflow to the top of the workflow list of the repository for the user, or unpins it
+func SetWorkflowPinned(ctx context.Context, userID, repoID int64, workflowID string, pinned bool) error {
+	workflowIDs, err := GetPinnedWorkflows(ctx, userID, repoID)
+	if err != nil {
+		return err
+	}
+
+	workflowIDs = slices.DeleteFunc(workflowIDs, func(id string) bool { return id == workflowID })
+	if pinned {
+		workflowIDs = append(workflowIDs, workflowID)
+	}
+	if len(workflowIDs) == 0 {
+		return user_model.DeleteUserSetting(ctx, userID, pinnedWorkflowsKey(repoID))
+	}
+
+	value, err := json.Marshal(workflowIDs)
+	if err != nil {
+		return err
+	}
+	return user_model.SetUserSetting(ctx, userID, pinnedWorkflowsKey(repoID), string(value))
+}
@@ -14,6 +14,28 @@ func GetAllRerunJobs(job *actions_model.ActionRunJob, allJobs []*actions_model.A
 	rerunJobsIDSet := make(container.Set[string])
 	rerunJobsIDSet.Add(job.JobID)
 
+	return appendDependentJobs(rerunJobs, rerunJobsIDSet, allJobs)
+}
+
+// GetFailedRerunJobs get all failed or cancelled jobs and the jobs depending on them, the succeeded jobs are kept
+func GetFailedRerunJobs(allJobs []*actions_model.ActionRunJob) []*actions_model.ActionRunJob {
+	rerunJobs := make([]*actions_model.ActionRunJob, 0, len(allJobs))
+	rerunJobsIDSet := make(container.Set[string])
+	for _, j := range allJobs {
+		if j.Status == actions_model.StatusFailure || j.Status == actions_model.StatusCancelled {
+			rerunJobs = append(rerunJobs, j)
+			rerunJobsIDSet.Add(j.JobID)
+		}
+	}
+	if len(rerunJobs) == 0 {
+		return nil
+	}
+
+	return appendDependentJobs(rerunJobs, rerunJobsIDSet, allJobs)
+}
+
+// appendDependentJobs appends the jobs which directly or indirectly need the jobs in rerunJobs
+func appendDependentJobs(rerunJobs []*actions_model.ActionRunJob, rerunJobsIDSet container.Set[string], allJobs []*actions_model.ActionRunJob) []*actions_model.ActionRunJob {
 	for {
 		found := false
 		for _, j := range allJobs {
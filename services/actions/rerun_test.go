@@ -46,3 +46,17 @@ func TestGetAllRerunJobs(t *testing.T) {
 		assert.ElementsMatch(t, tc.rerunJobs, rerunJobs)
 	}
 }
+
+func TestGetFailedRerunJobs(t *testing.T) {
+	job1 := &actions_model.ActionRunJob{JobID: "job1", Status: actions_model.StatusSuccess}
+	job2 := &actions_model.ActionRunJob{JobID: "job2", Status: actions_model.StatusFailure, Needs: []string{"job1"}}
+	job3 := &actions_model.ActionRunJob{JobID: "job3", Status: actions_model.StatusSkipped, Needs: []string{"job2"}}
+	job4 := &actions_model.ActionRunJob{JobID: "job4", Status: actions_model.StatusSuccess, Needs: []string{"job1"}}
+	job5 := &actions_model.ActionRunJob{JobID: "job5", Status: actions_model.StatusCancelled}
+
+	assert.ElementsMatch(t,
+		[]*actions_model.ActionRunJob{job2, job3, job5},
+		GetFailedRerunJobs([]*actions_model.ActionRunJob{job1, job2, job3, job4, job5}),
+	)
+	assert.Empty(t, GetFailedRerunJobs([]*actions_model.ActionRunJob{job1, job4}))
+}
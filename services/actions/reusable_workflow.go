@@ -0,0 +1,433 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// maxReusableWorkflowDepth is the max depth of the nested reusable workflows, it's the same as GitHub
+const maxReusableWorkflowDepth = 4
+
+var (
+	// expressionRegexp matches the expressions in the values of a workflow
+	expressionRegexp = regexp.MustCompile(`(?s)\$\{\{(.*?)\}\}`)
+	// callContextRegexp matches the properties of the contexts which are provided by the caller job
+	callContextRegexp = regexp.MustCompile(`(^|[^\w.])(inputs|secrets)\.([\w-]+)`)
+)
+
+// reusableWorkflowCall is the inputs and the secrets passed to a reusable workflow by the caller job
+type reusableWorkflowCall struct {
+	// inputs contains the literals of the input values which could be used in expressions
+	inputs map[string]string
+	// secrets contains the expressions of the secrets with upper case names, it's nil if the secrets are inherited
+	secrets map[string]string
+}
+
+// parseReusableWorkflowUses parses the `uses` of a job which calls a reusable workflow,
+// it's `./{path}` for a workflow in the same repository, or `{owner}/{repo}/{path}@{ref}` for the other repositories.
+func parseReusableWorkflowUses(uses string) (owner, repo, path, ref string, err error) {
+	if strings.HasPrefix(uses, "./") {
+		path = strings.TrimPrefix(uses, "./")
+	} else {
+		var fullPath string
+		var ok bool
+		if fullPath, ref, ok = strings.Cut(uses, "@"); !ok || ref == "" {
+			return "", "", "", "", util.NewInvalidArgumentErrorf("reusable workflow %q has no ref", uses)
+		}
+		parts := strings.SplitN(fullPath, "/", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return "", "", "", "", util.NewInvalidArgumentErrorf("invalid reusable workflow %q", uses)
+		}
+		owner, repo, path = parts[0], parts[1], parts[2]
+	}
+	if !actions.IsWorkflow(path) {
+		return "", "", "", "", util.NewInvalidArgumentErrorf("reusable workflow %q is not in a workflow directory", uses)
+	}
+	return owner, repo, path, ref, nil
+}
+
+// loadReusableWorkflow loads the content of the reusable workflow called by `uses`,
+// the workflows in the same repository are loaded from the given commit.
+// It returns the repository and the commit which the workflow is loaded from.
+func loadReusableWorkflow(ctx context.Context, repo *repo_model.Repository, sha, uses string) (*repo_model.Repository, string, []byte, error) {
+	ownerName, repoName, path, ref, err := parseReusableWorkflowUses(uses)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if ownerName != "" {
+		calledRepo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("GetRepositoryByOwnerAndName: %w", err)
+		}
+		if err := calledRepo.LoadOwner(ctx); err != nil {
+			return nil, "", nil, err
+		}
+		// the workflows of the other owners could be called only if they are public
+		if calledRepo.OwnerID != repo.OwnerID && (calledRepo.IsPrivate || !calledRepo.Owner.Visibility.IsPublic()) {
+			return nil, "", nil, util.NewPermissionDeniedErrorf("reusable workflow %q is not accessible", uses)
+		}
+		repo, sha = calledRepo, ref
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+	commit, err := gitRepo.GetCommit(sha)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("GetCommit %s of %s: %w", sha, repo.FullName(), err)
+	}
+	entry, err := commit.GetTreeEntryByPath(path)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("GetTreeEntryByPath %s of %s: %w", path, repo.FullName(), err)
+	}
+	content, err := actions.GetContentFromEntry(entry)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return repo, commit.ID.String(), content, nil
+}
+
+// isReusableWorkflowCaller returns whether the job calls a reusable workflow
+func isReusableWorkflowCaller(swf *jobparser.SingleWorkflow) bool {
+	_, job := swf.Job()
+	return job != nil && job.Uses != ""
+}
+
+// expandReusableWorkflows replaces the jobs which call reusable workflows with the jobs of the called workflows.
+// The called jobs are named as `{caller job id}/{called job id}`, they depend on the needs of the caller job,
+// and the jobs which need the caller job depend on all of the called jobs.
+// The returned attributes has the same order as the returned jobs, the called jobs only have the token permissions,
+// see evaluateCalledTokenPermissions.
+func expandReusableWorkflows(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow, attributes []*jobAttributes) ([]*jobparser.SingleWorkflow, []*jobAttributes, error) {
+	if !slices.ContainsFunc(jobs, isReusableWorkflowCaller) {
+		return jobs, attributes, nil
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, nil, err
+	}
+	vars, err := actions_model.GetVariablesOfRun(ctx, run)
+	if err != nil {
+		return nil, nil, err
+	}
+	gitCtx := generateGitContext(run)
+	if len(attributes) == 0 {
+		attributes = make([]*jobAttributes, len(jobs))
+	}
+	jobs, attributes, err = expandCallerJobs(ctx, run.Repo, gitCtx.Sha, gitCtx, vars, jobs, attributes, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !slices.ContainsFunc(attributes, func(attrs *jobAttributes) bool { return attrs != nil }) {
+		return jobs, nil, nil
+	}
+	return jobs, attributes, nil
+}
+
+func expandCallerJobs(ctx context.Context, repo *repo_model.Repository, sha string, gitCtx *model.GithubContext, vars map[string]string,
+	jobs []*jobparser.SingleWorkflow, attributes []*jobAttributes, depth int,
+) ([]*jobparser.SingleWorkflow, []*jobAttributes, error) {
+	ret := make([]*jobparser.SingleWorkflow, 0, len(jobs))
+	retAttributes := make([]*jobAttributes, 0, len(jobs))
+	calledJobIDs := make(map[string][]string)
+	for i, swf := range jobs {
+		id, job := swf.Job()
+		if job.Uses == "" {
+			ret = append(ret, swf)
+			retAttributes = append(retAttributes, attributes[i])
+			continue
+		}
+		if depth > maxReusableWorkflowDepth {
+			return nil, nil, util.NewInvalidArgumentErrorf("job %q: reusable workflows can be nested at most %d levels", id, maxReusableWorkflowDepth)
+		}
+
+		calledRepo, calledSha, content, err := loadReusableWorkflow(ctx, repo, sha, job.Uses)
+		if err != nil {
+			return nil, nil, fmt.Errorf("job %q: %w", id, err)
+		}
+		call, err := newReusableWorkflowCall(id, job, content, newWorkflowEvaluator(id, getJobMatrix(job), gitCtx, vars))
+		if err != nil {
+			return nil, nil, fmt.Errorf("job %q: %w", id, err)
+		}
+		content, err = call.apply(content)
+		if err != nil {
+			return nil, nil, fmt.Errorf("job %q: %w", id, err)
+		}
+		calledJobs, err := jobparser.Parse(content, jobparser.WithVars(vars))
+		if err != nil {
+			return nil, nil, fmt.Errorf("job %q: jobparser.Parse: %w", id, err)
+		}
+		calledAttributes, err := evaluateCalledTokenPermissions(content, calledJobs, attributes[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("job %q: %w", id, err)
+		}
+		calledJobs, calledAttributes, err = expandCallerJobs(ctx, calledRepo, calledSha, gitCtx, vars, calledJobs, calledAttributes, depth+1)
+		if err != nil {
+			return nil, nil, fmt.Errorf("job %q: %w", id, err)
+		}
+
+		for j, calledSwf := range calledJobs {
+			calledID, calledJob := calledSwf.Job()
+			fullID := id + actions_model.ReusableWorkflowJobSeparator + calledID
+			calledJob.Name = job.Name + " / " + calledJob.Name
+			if needs := calledJob.Needs(); len(needs) > 0 {
+				for i := range needs {
+					needs[i] = id + actions_model.ReusableWorkflowJobSeparator + needs[i]
+				}
+				calledJob.RawNeeds = encodeNeeds(needs)
+			} else {
+				// the first jobs of the called workflow run after the needs of the caller job, and only if the caller job should run
+				calledJob.RawNeeds = job.RawNeeds
+				if job.If.Value != "" {
+					calledJob.If = yaml.Node{Kind: yaml.ScalarNode, Value: combineIfConditions(job.If.Value, calledJob.If.Value)}
+				}
+			}
+			if err := calledSwf.SetJob(fullID, calledJob); err != nil {
+				return nil, nil, fmt.Errorf("SetJob: %w", err)
+			}
+			if !slices.Contains(calledJobIDs[id], fullID) {
+				calledJobIDs[id] = append(calledJobIDs[id], fullID)
+			}
+			ret = append(ret, calledSwf)
+			retAttributes = append(retAttributes, calledAttributes[j])
+		}
+	}
+
+	// the jobs which need a caller job should wait for all jobs of the called workflow
+	for _, swf := range ret {
+		id, job := swf.Job()
+		needs := job.Needs()
+		if !slices.ContainsFunc(needs, func(need string) bool { return calledJobIDs[need] != nil }) {
+			continue
+		}
+		newNeeds := make([]string, 0, len(needs))
+		for _, need := range needs {
+			if calledIDs, ok := calledJobIDs[need]; ok {
+				newNeeds = append(newNeeds, calledIDs...)
+			} else {
+				newNeeds = append(newNeeds, need)
+			}
+		}
+		job.RawNeeds = encodeNeeds(newNeeds)
+		if err := swf.SetJob(id, job); err != nil {
+			return nil, nil, fmt.Errorf("SetJob: %w", err)
+		}
+	}
+	return ret, retAttributes, nil
+}
+
+// evaluateCalledTokenPermissions returns the attributes of the jobs of a called workflow, which only have the token permissions.
+// The permissions declared by the called workflow or its jobs can't exceed the ones of the caller job,
+// and the called jobs which don't declare any permissions have the ones of the caller job.
+func evaluateCalledTokenPermissions(content []byte, calledJobs []*jobparser.SingleWorkflow, callerAttrs *jobAttributes) ([]*jobAttributes, error) {
+	raw := &rawWorkflowAttributes{}
+	if err := yaml.Unmarshal(content, raw); err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal: %w", err)
+	}
+	var callerPerms actions_model.TokenPermissions
+	if callerAttrs != nil {
+		callerPerms = callerAttrs.TokenPermissions
+	}
+
+	ret := make([]*jobAttributes, len(calledJobs))
+	for i, swf := range calledJobs {
+		id, _ := swf.Job()
+		perms := raw.Permissions
+		if jobPerms := raw.Jobs[id].Permissions; jobPerms != nil {
+			perms = jobPerms
+		}
+		switch {
+		case perms != nil:
+			limit := callerPerms
+			if limit == nil {
+				limit = actions_model.DefaultTokenPermissions()
+			}
+			ret[i] = &jobAttributes{TokenPermissions: perms.permissions.Intersect(limit)}
+		case callerPerms != nil:
+			ret[i] = &jobAttributes{TokenPermissions: callerPerms}
+		}
+	}
+	return ret, nil
+}
+
+// newReusableWorkflowCall evaluates the inputs and the secrets passed by the caller job,
+// the default values of the inputs defined by the called workflow are used if they are not passed.
+func newReusableWorkflowCall(id string, job *jobparser.Job, content []byte, evaluator *jobparser.ExpressionEvaluator) (*reusableWorkflowCall, error) {
+	workflow, err := model.ReadWorkflow(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("model.ReadWorkflow: %w", err)
+	}
+	if !slices.Contains(workflow.On(), "workflow_call") {
+		return nil, util.NewInvalidArgumentErrorf("workflow %q is not triggered by workflow_call", job.Uses)
+	}
+	config := workflow.WorkflowCallConfig()
+
+	call := &reusableWorkflowCall{inputs: make(map[string]string)}
+	for name := range job.With {
+		if _, ok := config.Inputs[name]; !ok {
+			return nil, util.NewInvalidArgumentErrorf("input %q is not defined by workflow %q", name, job.Uses)
+		}
+	}
+	for name, input := range config.Inputs {
+		value, ok := job.With[name]
+		if !ok {
+			if input.Required {
+				return nil, util.NewInvalidArgumentErrorf("input %q of workflow %q is required", name, job.Uses)
+			}
+			value = input.Default
+		}
+		if str, ok := value.(string); ok {
+			value = evaluator.Interpolate(str)
+		}
+		call.inputs[name] = expressionLiteral(input.Type, value)
+	}
+
+	switch job.RawSecrets.Kind {
+	case 0: // no secrets are passed
+		call.secrets = map[string]string{}
+	case yaml.ScalarNode:
+		if job.RawSecrets.Value != "inherit" {
+			return nil, util.NewInvalidArgumentErrorf("invalid secrets of job %q: %s", id, job.RawSecrets.Value)
+		}
+	default:
+		var secrets map[string]string
+		if err := job.RawSecrets.Decode(&secrets); err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid secrets of job %q: %v", id, err)
+		}
+		call.secrets = make(map[string]string, len(secrets))
+		for name, value := range secrets {
+			expr := strings.TrimSpace(value)
+			if m := expressionRegexp.FindStringSubmatch(expr); m != nil && m[0] == expr {
+				expr = "(" + strings.TrimSpace(m[1]) + ")"
+			} else {
+				expr = expressionLiteral("string", value)
+			}
+			call.secrets[strings.ToUpper(name)] = expr
+		}
+	}
+	return call, nil
+}
+
+// apply replaces the inputs and the secrets in the expressions of the called workflow with the values passed by the caller job
+func (call *reusableWorkflowCall) apply(content []byte) ([]byte, error) {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(content, node); err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal: %w", err)
+	}
+	call.applyToNode(node)
+	return yaml.Marshal(node)
+}
+
+func (call *reusableWorkflowCall) applyToNode(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		node.Value = expressionRegexp.ReplaceAllStringFunc(node.Value, func(s string) string {
+			return "${{" + call.applyToExpression(s[3:len(s)-2]) + "}}"
+		})
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			// the conditions could be expressions without `${{ }}`
+			if key.Value == "if" && value.Kind == yaml.ScalarNode && !strings.Contains(value.Value, "${{") {
+				value.Value = call.applyToExpression(value.Value)
+				continue
+			}
+			call.applyToNode(value)
+		}
+	default:
+		for _, v := range node.Content {
+			call.applyToNode(v)
+		}
+	}
+}
+
+func (call *reusableWorkflowCall) applyToExpression(expr string) string {
+	return callContextRegexp.ReplaceAllStringFunc(expr, func(s string) string {
+		m := callContextRegexp.FindStringSubmatch(s)
+		prefix, context, name := m[1], m[2], m[3]
+		if context == "inputs" {
+			if literal, ok := call.inputs[name]; ok {
+				return prefix + literal
+			}
+			return prefix + "null"
+		}
+		upperName := strings.ToUpper(name)
+		if call.secrets == nil || upperName == "GITHUB_TOKEN" || upperName == "GITEA_TOKEN" {
+			return s
+		}
+		if secret, ok := call.secrets[upperName]; ok {
+			return prefix + secret
+		}
+		// the secrets which are not passed are empty in the called workflow
+		return prefix + "''"
+	})
+}
+
+// expressionLiteral returns the literal of the value which could be used in expressions
+func expressionLiteral(typ string, value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case int, int64, uint64, float64:
+		return fmt.Sprint(v)
+	case string:
+		switch typ {
+		case "boolean":
+			if b, err := strconv.ParseBool(v); err == nil {
+				return strconv.FormatBool(b)
+			}
+		case "number":
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				return v
+			}
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	default:
+		return expressionLiteral("string", fmt.Sprint(v))
+	}
+}
+
+// combineIfConditions returns the condition which is true only if both of the conditions are true
+func combineIfConditions(callerIf, jobIf string) string {
+	trim := func(s string) string {
+		s = strings.TrimSpace(s)
+		if m := expressionRegexp.FindStringSubmatch(s); m != nil && m[0] == s {
+			return strings.TrimSpace(m[1])
+		}
+		return s
+	}
+	jobIf = trim(jobIf)
+	if jobIf == "" {
+		jobIf = "success()"
+	}
+	return "(" + trim(callerIf) + ") && (" + jobIf + ")"
+}
+
+func encodeNeeds(needs []string) yaml.Node {
+	node := yaml.Node{Kind: yaml.SequenceNode}
+	for _, need := range needs {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: need})
+	}
+	return node
+}
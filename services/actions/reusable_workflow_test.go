@@ -0,0 +1,195 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	perm_model "code.gitea.io/gitea/models/perm"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReusableWorkflowUses(t *testing.T) {
+	owner, repo, path, ref, err := parseReusableWorkflowUses("./.gitea/workflows/build.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "", ".gitea/workflows/build.yml", ""}, []string{owner, repo, path, ref})
+
+	owner, repo, path, ref, err = parseReusableWorkflowUses("user2/ci/.github/workflows/build.yaml@v1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user2", "ci", ".github/workflows/build.yaml", "v1"}, []string{owner, repo, path, ref})
+
+	for _, uses := range []string{
+		"user2/ci/.github/workflows/build.yml",
+		"user2/.github/workflows/build.yml@v1",
+		"user2/ci/build.yml@v1",
+		"./build.yml",
+	} {
+		_, _, _, _, err = parseReusableWorkflowUses(uses)
+		assert.Error(t, err, uses)
+	}
+}
+
+func TestReusableWorkflowCall(t *testing.T) {
+	called := []byte(`
+on:
+  workflow_call:
+    inputs:
+      target:
+        type: string
+        required: true
+      debug:
+        type: boolean
+        default: false
+jobs:
+  deploy:
+    if: inputs.debug || github.event_name == 'push'
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ inputs.target }} ${{ github.event.inputs.target }}
+      - run: deploy --token ${{ secrets.deploy_token }} --key ${{ secrets.OTHER }} --gitea ${{ secrets.GITEA_TOKEN }}
+`)
+	parseCaller := func(content string) *jobparser.Job {
+		swfs, err := jobparser.Parse([]byte(content))
+		require.NoError(t, err)
+		_, job := swfs[0].Job()
+		return job
+	}
+	evaluator := newWorkflowEvaluator("call", nil, nil, nil)
+
+	job := parseCaller(`
+on: push
+jobs:
+  call:
+    uses: ./.gitea/workflows/deploy.yml
+    with:
+      target: "it's ${{ 'prod' }}"
+    secrets:
+      deploy_token: ${{ secrets.PROD_TOKEN }}
+`)
+	call, err := newReusableWorkflowCall("call", job, called, evaluator)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"target": "'it''s prod'", "debug": "false"}, call.inputs)
+	assert.Equal(t, map[string]string{"DEPLOY_TOKEN": "(secrets.PROD_TOKEN)"}, call.secrets)
+
+	content, err := call.apply(called)
+	assert.NoError(t, err)
+	swfs, err := jobparser.Parse(content)
+	assert.NoError(t, err)
+	_, calledJob := swfs[0].Job()
+	assert.Equal(t, "false || github.event_name == 'push'", calledJob.If.Value)
+	assert.Equal(t, "echo ${{ 'it''s prod' }} ${{ github.event.inputs.target }}", calledJob.Steps[0].Run)
+	assert.Equal(t, "deploy --token ${{ (secrets.PROD_TOKEN) }} --key ${{ '' }} --gitea ${{ secrets.GITEA_TOKEN }}", calledJob.Steps[1].Run)
+
+	// the secrets are inherited
+	job = parseCaller(`
+on: push
+jobs:
+  call:
+    uses: ./.gitea/workflows/deploy.yml
+    with:
+      target: prod
+      debug: true
+    secrets: inherit
+`)
+	call, err = newReusableWorkflowCall("call", job, called, evaluator)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"target": "'prod'", "debug": "true"}, call.inputs)
+	assert.Nil(t, call.secrets)
+	assert.Equal(t, "deploy --token ${{ secrets.deploy_token }}", call.applyToExpression("deploy --token ${{ secrets.deploy_token }}"))
+
+	// the required input is missing
+	job = parseCaller(`
+on: push
+jobs:
+  call:
+    uses: ./.gitea/workflows/deploy.yml
+`)
+	_, err = newReusableWorkflowCall("call", job, called, evaluator)
+	assert.ErrorContains(t, err, `input "target" of workflow "./.gitea/workflows/deploy.yml" is required`)
+
+	// the input is not defined
+	job = parseCaller(`
+on: push
+jobs:
+  call:
+    uses: ./.gitea/workflows/deploy.yml
+    with:
+      target: prod
+      unknown: value
+`)
+	_, err = newReusableWorkflowCall("call", job, called, evaluator)
+	assert.ErrorContains(t, err, `input "unknown" is not defined`)
+
+	// the workflow can't be called
+	_, err = newReusableWorkflowCall("call", job, []byte("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"), evaluator)
+	assert.ErrorContains(t, err, "is not triggered by workflow_call")
+}
+
+func TestEvaluateCalledTokenPermissions(t *testing.T) {
+	content := []byte(`
+on: workflow_call
+permissions:
+  contents: read
+  packages: write
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make build
+  release:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: write
+      issues: write
+    steps:
+      - run: make release
+`)
+	calledJobs, err := jobparser.Parse(content)
+	require.NoError(t, err)
+	require.Len(t, calledJobs, 2)
+
+	// the permissions declared by the called workflow are limited by the ones of the caller job
+	attributes, err := evaluateCalledTokenPermissions(content, calledJobs, &jobAttributes{TokenPermissions: actions_model.TokenPermissions{
+		actions_model.TokenScopeContents: perm_model.AccessModeWrite,
+		actions_model.TokenScopePackages: perm_model.AccessModeRead,
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, actions_model.TokenPermissions{
+		actions_model.TokenScopeContents: perm_model.AccessModeRead,
+		actions_model.TokenScopePackages: perm_model.AccessModeRead,
+	}, attributes[0].TokenPermissions)
+	assert.Equal(t, actions_model.TokenPermissions{
+		actions_model.TokenScopeContents: perm_model.AccessModeWrite,
+		actions_model.TokenScopeIssues:   perm_model.AccessModeNone,
+	}, attributes[1].TokenPermissions)
+
+	// the caller job without permissions has the default ones
+	attributes, err = evaluateCalledTokenPermissions(content, calledJobs, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, actions_model.TokenPermissions{
+		actions_model.TokenScopeContents: perm_model.AccessModeRead,
+		actions_model.TokenScopePackages: perm_model.AccessModeWrite,
+	}, attributes[0].TokenPermissions)
+
+	// the called jobs without permissions have the ones of the caller job
+	content = []byte("on: workflow_call\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n")
+	calledJobs, err = jobparser.Parse(content)
+	require.NoError(t, err)
+	callerPerms := actions_model.TokenPermissions{actions_model.TokenScopeContents: perm_model.AccessModeRead}
+	attributes, err = evaluateCalledTokenPermissions(content, calledJobs, &jobAttributes{TokenPermissions: callerPerms})
+	assert.NoError(t, err)
+	assert.Equal(t, callerPerms, attributes[0].TokenPermissions)
+	attributes, err = evaluateCalledTokenPermissions(content, calledJobs, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, attributes[0])
+}
+
+func TestCombineIfConditions(t *testing.T) {
+	assert.Equal(t, "(github.ref == 'refs/heads/main') && (success())", combineIfConditions("${{ github.ref == 'refs/heads/main' }}", ""))
+	assert.Equal(t, "(always()) && (inputs.debug)", combineIfConditions("always()", " ${{ inputs.debug }} "))
+}
@@ -0,0 +1,288 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// rawWorkflowAttributes is used to read the attributes of the workflow and the jobs which are handled by Gitea itself,
+// the job-level ones are dropped by jobparser so they have to be read from the workflow content.
+type rawWorkflowAttributes struct {
+	Concurrency *rawConcurrency `yaml:"concurrency"`
+	Permissions *rawPermissions `yaml:"permissions"`
+	Jobs        map[string]struct {
+		Concurrency *rawConcurrency `yaml:"concurrency"`
+		Environment *rawEnvironment `yaml:"environment"`
+		Permissions *rawPermissions `yaml:"permissions"`
+	} `yaml:"jobs"`
+}
+
+// jobAttributes is the evaluated attributes of a job
+type jobAttributes struct {
+	ConcurrencyGroup  string
+	ConcurrencyCancel bool
+	Environment       string
+	TokenPermissions  actions_model.TokenPermissions // nil means the default permissions
+}
+
+// evaluateWorkflowAttributes sets the workflow-level concurrency of the run, and returns the evaluated attributes
+// of the jobs, the returned slice has the same order as the jobs and contains nil if a job has no attributes.
+func evaluateWorkflowAttributes(ctx context.Context, run *actions_model.ActionRun, content []byte, jobs []*jobparser.SingleWorkflow) ([]*jobAttributes, error) {
+	raw := &rawWorkflowAttributes{}
+	if err := yaml.Unmarshal(content, raw); err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal: %w", err)
+	}
+	hasJobAttributes := raw.Permissions != nil
+	for _, job := range raw.Jobs {
+		if job.Concurrency != nil || job.Environment != nil || job.Permissions != nil {
+			hasJobAttributes = true
+			break
+		}
+	}
+	if raw.Concurrency == nil && !hasJobAttributes {
+		return nil, nil
+	}
+
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	vars, err := actions_model.GetVariablesOfRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	gitCtx := generateGitContext(run)
+
+	if raw.Concurrency != nil {
+		evaluator := newWorkflowEvaluator("", nil, gitCtx, vars)
+		run.ConcurrencyGroup, run.ConcurrencyCancel = raw.Concurrency.evaluate(evaluator)
+	}
+	if !hasJobAttributes {
+		return nil, nil
+	}
+
+	ret := make([]*jobAttributes, len(jobs))
+	for i, swf := range jobs {
+		id, job := swf.Job()
+		rawJob := raw.Jobs[id]
+		attrs := &jobAttributes{}
+		// the job-level permissions replace the workflow-level ones entirely
+		if rawJob.Permissions != nil {
+			attrs.TokenPermissions = rawJob.Permissions.permissions
+		} else if raw.Permissions != nil {
+			attrs.TokenPermissions = raw.Permissions.permissions
+		}
+		if rawJob.Concurrency == nil && rawJob.Environment == nil {
+			if attrs.TokenPermissions != nil {
+				ret[i] = attrs
+			}
+			continue
+		}
+		evaluator := newWorkflowEvaluator(id, getJobMatrix(job), gitCtx, vars)
+		if rawJob.Concurrency != nil {
+			attrs.ConcurrencyGroup, attrs.ConcurrencyCancel = rawJob.Concurrency.evaluate(evaluator)
+		}
+		if rawJob.Environment != nil {
+			attrs.Environment = evaluator.Interpolate(rawJob.Environment.Name)
+		}
+		if attrs.ConcurrencyGroup != "" || attrs.Environment != "" || attrs.TokenPermissions != nil {
+			ret[i] = attrs
+		}
+	}
+	return ret, nil
+}
+
+// getJobMatrix returns the matrix combination which the job is expanded from, or nil if the job has no matrix.
+// jobparser has encoded the combination as a matrix with single values.
+func getJobMatrix(job *jobparser.Job) map[string]any {
+	var rawMatrix map[string][]any
+	if err := job.Strategy.RawMatrix.Decode(&rawMatrix); err != nil || len(rawMatrix) == 0 {
+		return nil
+	}
+	matrix := make(map[string]any, len(rawMatrix))
+	for k, v := range rawMatrix {
+		if len(v) > 0 {
+			matrix[k] = v[0]
+		}
+	}
+	return matrix
+}
+
+// MatrixValue is a value of the matrix combination which a job is expanded from
+type MatrixValue struct {
+	Key   string
+	Value string
+}
+
+// GetRunJobMatrix returns the matrix combination which the job is expanded from, sorted by the keys
+func GetRunJobMatrix(job *actions_model.ActionRunJob) ([]*MatrixValue, error) {
+	if len(job.WorkflowPayload) == 0 {
+		return nil, nil
+	}
+	swfs, err := jobparser.Parse(job.WorkflowPayload)
+	if err != nil {
+		return nil, fmt.Errorf("parse workflow payload of job %d: %w", job.ID, err)
+	} else if len(swfs) != 1 {
+		return nil, fmt.Errorf("job %d has %d workflows in payload", job.ID, len(swfs))
+	}
+	_, wfJob := swfs[0].Job()
+	matrix := getJobMatrix(wfJob)
+	if len(matrix) == 0 {
+		return nil, nil
+	}
+
+	ret := make([]*MatrixValue, 0, len(matrix))
+	for k, v := range matrix {
+		ret = append(ret, &MatrixValue{Key: k, Value: fmt.Sprint(v)})
+	}
+	slices.SortFunc(ret, func(a, b *MatrixValue) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return ret, nil
+}
+
+func newWorkflowEvaluator(jobID string, matrix map[string]any, gitCtx *model.GithubContext, vars map[string]string) *jobparser.ExpressionEvaluator {
+	// the results of the needs are unknown when the run is created
+	results := map[string]*jobparser.JobResult{jobID: {}}
+	return jobparser.NewExpressionEvaluator(jobparser.NewInterpeter(jobID, &model.Job{}, matrix, gitCtx, results, vars))
+}
+
+// generateGitContext generates the part of the github context which is known when the run is created,
+// it should be kept consistent with the task context sent to the runners.
+func generateGitContext(run *actions_model.ActionRun) *model.GithubContext {
+	event := map[string]any{}
+	_ = json.Unmarshal([]byte(run.EventPayload), &event)
+
+	eventName := run.TriggerEvent
+	if eventName == "" {
+		eventName = run.Event.Event()
+	}
+
+	baseRef := ""
+	headRef := ""
+	ref := run.Ref
+	sha := run.CommitSHA
+	if pullPayload, err := run.GetPullRequestEventPayload(); err == nil && pullPayload.PullRequest != nil && pullPayload.PullRequest.Base != nil && pullPayload.PullRequest.Head != nil {
+		baseRef = pullPayload.PullRequest.Base.Ref
+		headRef = pullPayload.PullRequest.Head.Ref
+		if run.TriggerEvent == actions_module.GithubEventPullRequestTarget {
+			ref = git.BranchPrefix + pullPayload.PullRequest.Base.Name
+			sha = pullPayload.PullRequest.Base.Sha
+		}
+	}
+	refName := git.RefName(ref)
+
+	return &model.GithubContext{
+		Event:           event,
+		EventName:       eventName,
+		Workflow:        run.WorkflowID,
+		Actor:           run.TriggerUser.Name,
+		Repository:      run.Repo.OwnerName + "/" + run.Repo.Name,
+		RepositoryOwner: run.Repo.OwnerName,
+		Sha:             sha,
+		Ref:             ref,
+		RefName:         refName.ShortName(),
+		RefType:         refName.RefType(),
+		HeadRef:         headRef,
+		BaseRef:         baseRef,
+	}
+}
+
+// insertRun inserts the run and its jobs with the attributes returned by evaluateWorkflowAttributes.
+// The jobs which call reusable workflows are replaced with the jobs of the called workflows before inserting.
+// If there is an in-progress run in the same concurrency group, it will be cancelled if `cancel-in-progress` is set,
+// otherwise the new run will be blocked until the in-progress run is done, and the pending run of the group will be cancelled.
+func insertRun(ctx context.Context, run *actions_model.ActionRun, jobs []*jobparser.SingleWorkflow, attributes []*jobAttributes) error {
+	jobs, attributes, err := expandReusableWorkflows(ctx, run, jobs, attributes)
+	if err != nil {
+		return fmt.Errorf("expandReusableWorkflows: %w", err)
+	}
+
+	var cancelledJobs []*actions_model.ActionRunJob
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if run.ConcurrencyGroup != "" {
+			cancelled, blocked, err := prepareRunConcurrency(ctx, run)
+			if err != nil {
+				return err
+			}
+			cancelledJobs = append(cancelledJobs, cancelled...)
+			if blocked {
+				run.Status = actions_model.StatusBlocked
+			}
+		}
+
+		if err := actions_model.InsertRun(ctx, run, jobs); err != nil {
+			return err
+		}
+		if len(attributes) == 0 {
+			return nil
+		}
+
+		runJobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
+		if err != nil {
+			return err
+		}
+		// the jobs are inserted in the same order as the parsed jobs
+		slices.SortFunc(runJobs, func(a, b *actions_model.ActionRunJob) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+		for i, job := range runJobs {
+			attrs := attributes[i]
+			if attrs == nil {
+				continue
+			}
+			if attrs.ConcurrencyGroup != "" && attrs.ConcurrencyCancel {
+				cancelled, err := cancelJobConcurrencyGroup(ctx, run, attrs.ConcurrencyGroup)
+				if err != nil {
+					return err
+				}
+				cancelledJobs = append(cancelledJobs, cancelled...)
+			}
+			job.ConcurrencyGroup = attrs.ConcurrencyGroup
+			job.ConcurrencyCancel = attrs.ConcurrencyCancel
+			job.Environment = attrs.Environment
+			if attrs.TokenPermissions != nil {
+				job.TokenPermissions = attrs.TokenPermissions.ToDB()
+			}
+			if err := actions_model.SetRunJobAttributes(ctx, job); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	CreateCommitStatus(ctx, cancelledJobs...)
+	NotifyWorkflowJobsStatusUpdate(ctx, cancelledJobs...)
+
+	NotifyWorkflowRunRequested(ctx, run.ID)
+	if runJobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID}); err != nil {
+		log.Error("FindRunJobs: %v", err)
+	} else {
+		NotifyWorkflowJobsStatusUpdate(ctx, runJobs...)
+	}
+
+	// the jobs with a concurrency group or an environment are blocked, let the job emitter decide whether they can start
+	if len(attributes) > 0 && !run.Status.IsBlocked() {
+		if err := EmitJobsIfReady(run.ID); err != nil {
+			log.Error("EmitJobsIfReady: %v", err)
+		}
+	}
+	return nil
+}
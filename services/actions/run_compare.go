@@ -0,0 +1,263 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// RunComparison is the difference between two runs of the same workflow
+type RunComparison struct {
+	Base *actions_model.ActionRun
+	Head *actions_model.ActionRun
+	Jobs []*JobComparison
+	// WorkflowDiff is nil if the workflow file is the same in both runs or it can't be read
+	WorkflowDiff []*WorkflowDiffLine
+}
+
+// JobComparison is a job matched in two runs by its id and name, one of the jobs is nil if it's only in one run
+type JobComparison struct {
+	JobID string
+	Name  string
+	Base  *actions_model.ActionRunJob
+	Head  *actions_model.ActionRunJob
+	// Steps are the steps whose status changed
+	Steps []*StepComparison
+}
+
+// StepComparison is a step matched in two jobs by its name
+type StepComparison struct {
+	Name string
+	Base *actions_model.ActionTaskStep
+	Head *actions_model.ActionTaskStep
+}
+
+// WorkflowDiffLine is a line of the diff of the workflow file
+type WorkflowDiffLine struct {
+	Type string // "add", "del" or "same"
+	Text string
+}
+
+// DurationDelta returns how much longer the head job takes than the base job, it's zero if the job isn't in both runs
+func (c *JobComparison) DurationDelta() time.Duration {
+	if c.Base == nil || c.Head == nil {
+		return 0
+	}
+	return c.Head.Duration() - c.Base.Duration()
+}
+
+// NewlyFailing returns whether the job fails in the head run but not in the base run
+func (c *JobComparison) NewlyFailing() bool {
+	return c.Head != nil && c.Head.Status.IsFailure() && (c.Base == nil || !c.Base.Status.IsFailure())
+}
+
+// Fixed returns whether the job fails in the base run but succeeds in the head run
+func (c *JobComparison) Fixed() bool {
+	return c.Base != nil && c.Head != nil && c.Base.Status.IsFailure() && c.Head.Status.IsSuccess()
+}
+
+// GetPreviousRun returns the latest run of the same workflow on the same ref before the run
+func GetPreviousRun(ctx context.Context, run *actions_model.ActionRun) (*actions_model.ActionRun, error) {
+	var prev actions_model.ActionRun
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", run.RepoID).
+		And("workflow_id = ?", run.WorkflowID).
+		And("ref = ?", run.Ref).
+		And("`index` < ?", run.Index).
+		Desc("`index`").
+		Get(&prev)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("no previous run of workflow %s on %s before run %d", run.WorkflowID, run.Ref, run.Index)
+	}
+	return &prev, nil
+}
+
+// CompareRuns compares the jobs and the workflow files of two runs of the same workflow in the repository of the runs
+func CompareRuns(ctx context.Context, base, head *actions_model.ActionRun) (*RunComparison, error) {
+	if base.RepoID != head.RepoID || base.WorkflowID != head.WorkflowID {
+		return nil, util.NewInvalidArgumentErrorf("run %d and run %d aren't runs of the same workflow", base.Index, head.Index)
+	}
+
+	baseJobs, err := actions_model.GetRunJobsByRunID(ctx, base.ID)
+	if err != nil {
+		return nil, err
+	}
+	headJobs, err := actions_model.GetRunJobsByRunID(ctx, head.ID)
+	if err != nil {
+		return nil, err
+	}
+	steps := make(map[int64][]*actions_model.ActionTaskStep)
+	for _, job := range slices.Concat(baseJobs, headJobs) {
+		if job.TaskID == 0 {
+			continue
+		}
+		if steps[job.TaskID], err = actions_model.GetTaskStepsByTaskID(ctx, job.TaskID); err != nil {
+			return nil, err
+		}
+	}
+
+	comparison := &RunComparison{
+		Base: base,
+		Head: head,
+		Jobs: CompareRunJobs(baseJobs, headJobs, steps),
+	}
+	if base.CommitSHA != head.CommitSHA {
+		if comparison.WorkflowDiff, err = diffRunWorkflows(ctx, base, head); err != nil {
+			return nil, err
+		}
+	}
+	return comparison, nil
+}
+
+// CompareRunJobs matches the jobs of two runs in the order of the head run, the jobs only in the base run are appended.
+// The steps of the jobs are looked up by their task ids in steps.
+func CompareRunJobs(baseJobs, headJobs []*actions_model.ActionRunJob, steps map[int64][]*actions_model.ActionTaskStep) []*JobComparison {
+	key := func(job *actions_model.ActionRunJob) string {
+		return job.JobID + "\x00" + job.Name
+	}
+	baseByKey := make(map[string]*actions_model.ActionRunJob, len(baseJobs))
+	for _, job := range baseJobs {
+		baseByKey[key(job)] = job
+	}
+
+	comparisons := make([]*JobComparison, 0, len(headJobs))
+	matched := make(map[string]bool, len(headJobs))
+	for _, job := range headJobs {
+		base := baseByKey[key(job)]
+		matched[key(job)] = true
+		comparisons = append(comparisons, &JobComparison{
+			JobID: job.JobID,
+			Name:  job.Name,
+			Base:  base,
+			Head:  job,
+			Steps: compareSteps(jobSteps(base, steps), jobSteps(job, steps)),
+		})
+	}
+	for _, job := range baseJobs {
+		if !matched[key(job)] {
+			comparisons = append(comparisons, &JobComparison{
+				JobID: job.JobID,
+				Name:  job.Name,
+				Base:  job,
+			})
+		}
+	}
+	return comparisons
+}
+
+func jobSteps(job *actions_model.ActionRunJob, steps map[int64][]*actions_model.ActionTaskStep) []*actions_model.ActionTaskStep {
+	if job == nil || job.TaskID == 0 {
+		return nil
+	}
+	return steps[job.TaskID]
+}
+
+// compareSteps returns the steps whose status changed, the steps are matched by their names in the order of the head steps
+func compareSteps(baseSteps, headSteps []*actions_model.ActionTaskStep) []*StepComparison {
+	if len(baseSteps) == 0 || len(headSteps) == 0 {
+		return nil
+	}
+	baseByName := make(map[string]*actions_model.ActionTaskStep, len(baseSteps))
+	for _, step := range baseSteps {
+		if _, ok := baseByName[step.Name]; !ok {
+			baseByName[step.Name] = step
+		}
+	}
+	var changed []*StepComparison
+	for _, step := range headSteps {
+		base := baseByName[step.Name]
+		if base == nil || base.Status != step.Status {
+			changed = append(changed, &StepComparison{Name: step.Name, Base: base, Head: step})
+		}
+	}
+	return changed
+}
+
+// diffRunWorkflows returns the line diff of the workflow file between the commits of the runs,
+// it returns nil if the file is the same or isn't in the repository, e.g. the required workflows of the organization.
+func diffRunWorkflows(ctx context.Context, base, head *actions_model.ActionRun) ([]*WorkflowDiffLine, error) {
+	if strings.Contains(head.WorkflowID, "/") {
+		return nil, nil
+	}
+	if err := head.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	gitRepo, err := gitrepo.OpenRepository(ctx, head.Repo)
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	baseContent, err := getCommitWorkflowContent(gitRepo, base.CommitSHA, base.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	headContent, err := getCommitWorkflowContent(gitRepo, head.CommitSHA, head.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	if baseContent == headContent {
+		return nil, nil
+	}
+	return DiffWorkflowLines(baseContent, headContent), nil
+}
+
+// getCommitWorkflowContent returns the content of the workflow in the commit, it's empty if the commit or the workflow doesn't exist
+func getCommitWorkflowContent(gitRepo *git.Repository, sha, workflowID string) (string, error) {
+	commit, err := gitRepo.GetCommit(sha)
+	if git.IsErrNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	entries, err := actions_module.ListWorkflows(commit)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.Name() == workflowID {
+			content, err := actions_module.GetContentFromEntry(entry)
+			return string(content), err
+		}
+	}
+	return "", nil
+}
+
+// DiffWorkflowLines returns the line diff between two versions of a workflow file
+func DiffWorkflowLines(base, head string) []*WorkflowDiffLine {
+	dmp := diffmatchpatch.New()
+	baseChars, headChars, lines := dmp.DiffLinesToChars(base, head)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(baseChars, headChars, false), lines)
+
+	var result []*WorkflowDiffLine
+	for _, diff := range diffs {
+		typ := "same"
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			typ = "add"
+		case diffmatchpatch.DiffDelete:
+			typ = "del"
+		}
+		for _, line := range strings.SplitAfter(diff.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			result = append(result, &WorkflowDiffLine{Type: typ, Text: strings.TrimSuffix(line, "\n")})
+		}
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareRunJobs(t *testing.T) {
+	newJob := func(jobID, name string, taskID int64, status actions_model.Status, seconds int64) *actions_model.ActionRunJob {
+		return &actions_model.ActionRunJob{JobID: jobID, Name: name, TaskID: taskID, Status: status, Started: 1000, Stopped: 1000 + timeutil.TimeStamp(seconds)}
+	}
+	baseJobs := []*actions_model.ActionRunJob{
+		newJob("build", "build", 1, actions_model.StatusSuccess, 60),
+		newJob("test", "test", 2, actions_model.StatusFailure, 30),
+		newJob("lint", "lint", 3, actions_model.StatusSuccess, 10),
+	}
+	headJobs := []*actions_model.ActionRunJob{
+		newJob("build", "build", 11, actions_model.StatusFailure, 90),
+		newJob("test", "test", 12, actions_model.StatusSuccess, 20),
+		newJob("deploy", "deploy", 0, actions_model.StatusBlocked, 0),
+	}
+	steps := map[int64][]*actions_model.ActionTaskStep{
+		1: {
+			{Name: "checkout", Status: actions_model.StatusSuccess},
+			{Name: "compile", Status: actions_model.StatusSuccess},
+		},
+		11: {
+			{Name: "checkout", Status: actions_model.StatusSuccess},
+			{Name: "compile", Status: actions_model.StatusFailure},
+			{Name: "upload", Status: actions_model.StatusSkipped},
+		},
+	}
+
+	comparisons := CompareRunJobs(baseJobs, headJobs, steps)
+	if assert.Len(t, comparisons, 4) {
+		build := comparisons[0]
+		assert.Equal(t, "build", build.Name)
+		assert.True(t, build.NewlyFailing())
+		assert.False(t, build.Fixed())
+		assert.Equal(t, 30*time.Second, build.DurationDelta())
+		if assert.Len(t, build.Steps, 2) {
+			assert.Equal(t, "compile", build.Steps[0].Name)
+			assert.Equal(t, actions_model.StatusSuccess, build.Steps[0].Base.Status)
+			assert.Equal(t, actions_model.StatusFailure, build.Steps[0].Head.Status)
+			assert.Equal(t, "upload", build.Steps[1].Name)
+			assert.Nil(t, build.Steps[1].Base)
+		}
+
+		test := comparisons[1]
+		assert.True(t, test.Fixed())
+		assert.False(t, test.NewlyFailing())
+		assert.Equal(t, -10*time.Second, test.DurationDelta())
+		assert.Empty(t, test.Steps)
+
+		deploy := comparisons[2]
+		assert.Nil(t, deploy.Base)
+		assert.Zero(t, deploy.DurationDelta())
+
+		lint := comparisons[3]
+		assert.Equal(t, "lint", lint.Name)
+		assert.Nil(t, lint.Head)
+		assert.False(t, lint.NewlyFailing())
+	}
+}
+
+func TestDiffWorkflowLines(t *testing.T) {
+	base := "on: push\njobs:\n  build:\n    runs-on: ubuntu-22.04\n"
+	head := "on: push\njobs:\n  build:\n    runs-on: ubuntu-24.04\n"
+
+	lines := DiffWorkflowLines(base, head)
+	actual := make([]string, 0, len(lines))
+	for _, line := range lines {
+		actual = append(actual, line.Type+" "+line.Text)
+	}
+	assert.Equal(t, []string{
+		"same on: push",
+		"same jobs:",
+		"same   build:",
+		"del     runs-on: ubuntu-22.04",
+		"add     runs-on: ubuntu-24.04",
+	}, actual)
+}
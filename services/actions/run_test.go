@@ -0,0 +1,59 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRunJobMatrix(t *testing.T) {
+	content := `
+name: test
+on: push
+jobs:
+  build:
+    runs-on: ${{ matrix.os }}
+    strategy:
+      matrix:
+        os: [ubuntu-latest, windows-latest]
+        go: ["1.22"]
+    steps:
+      - run: go build
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make lint
+`
+	swfs, err := jobparser.Parse([]byte(content))
+	assert.NoError(t, err)
+	assert.Len(t, swfs, 3)
+
+	var jobs []*actions_model.ActionRunJob
+	for _, swf := range swfs {
+		payload, err := swf.Marshal()
+		assert.NoError(t, err)
+		jobs = append(jobs, &actions_model.ActionRunJob{WorkflowPayload: payload})
+	}
+
+	matrix, err := GetRunJobMatrix(jobs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []*MatrixValue{{Key: "go", Value: "1.22"}, {Key: "os", Value: "ubuntu-latest"}}, matrix)
+
+	matrix, err = GetRunJobMatrix(jobs[1])
+	assert.NoError(t, err)
+	assert.Equal(t, []*MatrixValue{{Key: "go", Value: "1.22"}, {Key: "os", Value: "windows-latest"}}, matrix)
+
+	matrix, err = GetRunJobMatrix(jobs[2])
+	assert.NoError(t, err)
+	assert.Nil(t, matrix)
+
+	matrix, err = GetRunJobMatrix(&actions_model.ActionRunJob{})
+	assert.NoError(t, err)
+	assert.Nil(t, matrix)
+}
@@ -144,8 +144,13 @@ func CreateScheduleTask(ctx context.Context, cron *actions_model.ActionSchedule)
 		return err
 	}
 
+	attributes, err := evaluateWorkflowAttributes(ctx, run, cron.Content, workflows)
+	if err != nil {
+		return err
+	}
+
 	// Insert the action run and its associated jobs into the database
-	if err := actions_model.InsertRun(ctx, run, workflows); err != nil {
+	if err := insertRun(ctx, run, workflows, attributes); err != nil {
 		return err
 	}
 
@@ -0,0 +1,72 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/log"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// NotifyWorkflowJobsStatusUpdate notifies the status updates of the jobs, and the start of their runs when the jobs are the first ones to run.
+// Every status of a job is notified only once, so it could be called for the jobs whose statuses may not have changed.
+// It won't return an error but log it, because it's not critical.
+func NotifyWorkflowJobsStatusUpdate(ctx context.Context, jobs ...*actions_model.ActionRunJob) {
+	for _, job := range jobs {
+		if err := notifyWorkflowJobStatusUpdate(ctx, job.ID); err != nil {
+			log.Error("Failed to notify the status update of job %d: %v", job.ID, err)
+		}
+	}
+}
+
+func notifyWorkflowJobStatusUpdate(ctx context.Context, jobID int64) error {
+	// reload the job since the given one could be stale, e.g. a job is cancelled by stopping its task
+	job, err := actions_model.GetRunJobByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status.IsBlocked() {
+		// the blocked jobs are notified once they are queued or completed
+		return nil
+	}
+	if notified, err := actions_model.SetRunJobStatusNotified(ctx, job); err != nil || !notified {
+		return err
+	}
+
+	if err := job.LoadAttributes(ctx); err != nil {
+		return err
+	}
+	var task *actions_model.ActionTask
+	if job.TaskID != 0 {
+		if task, err = actions_model.GetTaskByID(ctx, job.TaskID); err != nil {
+			return err
+		}
+	}
+	notify_service.WorkflowJobStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job, task)
+
+	if job.Status.IsRunning() {
+		if notified, err := actions_model.SetRunStartNotified(ctx, job.Run); err != nil || !notified {
+			return err
+		}
+		notify_service.WorkflowRunStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job.Run)
+	}
+	return nil
+}
+
+// NotifyWorkflowRunRequested notifies that a run or a new attempt of a run is requested.
+// It won't return an error but log it, because it's not critical.
+func NotifyWorkflowRunRequested(ctx context.Context, runID int64) {
+	run, err := actions_model.GetRunByID(ctx, runID)
+	if err != nil {
+		log.Error("Failed to get run %d: %v", runID, err)
+		return
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		log.Error("Failed to load the attributes of run %d: %v", runID, err)
+		return
+	}
+	notify_service.WorkflowRunStatusUpdate(ctx, run.Repo, run.TriggerUser, run)
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"sort"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+)
+
+// StepTiming is a step of a job in a run with its elapsed time
+type StepTiming struct {
+	Job  *actions_model.ActionRunJob
+	Step *actions_model.ActionTaskStep
+	// JobIndex is the index of the job in the jobs of the run
+	JobIndex int
+}
+
+// GetStepTimings returns the started steps of the latest attempts of the jobs, sorted by the elapsed time in descending order
+func GetStepTimings(ctx context.Context, jobs []*actions_model.ActionRunJob) ([]*StepTiming, error) {
+	taskIDs := make([]int64, 0, len(jobs))
+	jobIndexes := make(map[int64]int, len(jobs))
+	for i, job := range jobs {
+		if job.TaskID > 0 {
+			taskIDs = append(taskIDs, job.TaskID)
+			jobIndexes[job.TaskID] = i
+		}
+	}
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+
+	var steps []*actions_model.ActionTaskStep
+	if err := db.GetEngine(ctx).In("task_id", taskIDs).Where("started > 0").Find(&steps); err != nil {
+		return nil, err
+	}
+
+	timings := make([]*StepTiming, 0, len(steps))
+	for _, step := range steps {
+		i := jobIndexes[step.TaskID]
+		timings = append(timings, &StepTiming{Job: jobs[i], Step: step, JobIndex: i})
+	}
+	SortStepTimings(timings)
+	return timings, nil
+}
+
+// SortStepTimings sorts the steps by the elapsed time in descending order, the steps with the same elapsed time are kept in the order of the jobs and the steps
+func SortStepTimings(timings []*StepTiming) {
+	sort.Slice(timings, func(i, j int) bool {
+		di, dj := timings[i].Step.Duration(), timings[j].Step.Duration()
+		if di != dj {
+			return di > dj
+		}
+		if timings[i].JobIndex != timings[j].JobIndex {
+			return timings[i].JobIndex < timings[j].JobIndex
+		}
+		return timings[i].Step.Index < timings[j].Step.Index
+	})
+}
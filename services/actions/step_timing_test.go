@@ -0,0 +1,41 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortStepTimings(t *testing.T) {
+	newTiming := func(jobIndex int, stepIndex int64, seconds timeutil.TimeStamp) *StepTiming {
+		return &StepTiming{
+			JobIndex: jobIndex,
+			Step: &actions_model.ActionTaskStep{
+				Index:   stepIndex,
+				Status:  actions_model.StatusSuccess,
+				Started: 1000,
+				Stopped: 1000 + seconds,
+			},
+		}
+	}
+	timings := []*StepTiming{
+		newTiming(0, 0, 5),
+		newTiming(0, 1, 30),
+		newTiming(1, 0, 5),
+		newTiming(1, 1, 60),
+		newTiming(0, 2, 5),
+	}
+	SortStepTimings(timings)
+
+	actual := make([][2]int64, 0, len(timings))
+	for _, timing := range timings {
+		actual = append(actual, [2]int64{int64(timing.JobIndex), timing.Step.Index})
+	}
+	assert.Equal(t, [][2]int64{{1, 1}, {0, 1}, {0, 0}, {0, 2}, {1, 0}}, actual)
+}
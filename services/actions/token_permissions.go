@@ -0,0 +1,48 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	actions_model "code.gitea.io/gitea/models/actions"
+	perm_model "code.gitea.io/gitea/models/perm"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rawPermissions is the `permissions` of a workflow or a job, it could be `read-all`, `write-all`,
+// or a mapping of the scopes to `read`, `write` or `none`. The unknown scopes are ignored, and the unknown modes mean no access.
+// See https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#permissions
+type rawPermissions struct {
+	permissions actions_model.TokenPermissions
+}
+
+func (p *rawPermissions) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var s string
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+		switch s {
+		case "read-all":
+			p.permissions = actions_model.NewTokenPermissions(perm_model.AccessModeRead)
+		case "write-all":
+			p.permissions = actions_model.NewTokenPermissions(perm_model.AccessModeWrite)
+		default:
+			p.permissions = actions_model.TokenPermissions{}
+		}
+		return nil
+	}
+
+	var m map[string]string
+	if err := node.Decode(&m); err != nil {
+		return err
+	}
+	p.permissions = make(actions_model.TokenPermissions, len(m))
+	for k, v := range m {
+		if scope := actions_model.TokenScope(k); scope.IsValid() {
+			p.permissions[scope] = perm_model.ParseAccessMode(v, perm_model.AccessModeRead, perm_model.AccessModeWrite)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	perm_model "code.gitea.io/gitea/models/perm"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestUnmarshalRawPermissions(t *testing.T) {
+	content := `
+permissions: read-all
+jobs:
+  release:
+    permissions:
+      contents: write
+      issues: read
+      pull-requests: none
+      id-token: write
+      packages: admin
+  locked:
+    permissions: {}
+  test:
+    runs-on: ubuntu-latest
+`
+	raw := &rawWorkflowAttributes{}
+	assert.NoError(t, yaml.Unmarshal([]byte(content), raw))
+	assert.Equal(t, actions_model.NewTokenPermissions(perm_model.AccessModeRead), raw.Permissions.permissions)
+	assert.Equal(t, actions_model.TokenPermissions{
+		actions_model.TokenScopeContents:     perm_model.AccessModeWrite,
+		actions_model.TokenScopeIssues:       perm_model.AccessModeRead,
+		actions_model.TokenScopePullRequests: perm_model.AccessModeNone,
+		actions_model.TokenScopePackages:     perm_model.AccessModeNone,
+	}, raw.Jobs["release"].Permissions.permissions)
+	assert.Equal(t, actions_model.TokenPermissions{}, raw.Jobs["locked"].Permissions.permissions)
+	assert.Nil(t, raw.Jobs["test"].Permissions)
+
+	raw = &rawWorkflowAttributes{}
+	assert.NoError(t, yaml.Unmarshal([]byte("permissions: write-all"), raw))
+	assert.Equal(t, actions_model.NewTokenPermissions(perm_model.AccessModeWrite), raw.Permissions.permissions)
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// UsageSummary is the usage of actions of an owner in a month
+type UsageSummary struct {
+	Month    string
+	Jobs     int64
+	Duration int64
+	Minutes  int64
+	// SoftQuota is the soft quota of the minutes per month, 0 means no quota.
+	// Exceeding the soft quota doesn't stop the jobs from running, it's only for notice.
+	SoftQuota int64
+	Repos     []*RepoUsage
+}
+
+// RepoUsage is the usage of actions of a repository in a month, Repo is nil if the repository has been deleted
+type RepoUsage struct {
+	*actions_model.ActionUsage
+	Repo *repo_model.Repository
+}
+
+// OwnerUsageSummary is the usage of actions of an owner in a month, Owner is nil if the owner has been deleted
+type OwnerUsageSummary struct {
+	*actions_model.OwnerUsage
+	Owner     *user_model.User
+	SoftQuota int64
+}
+
+// QuotaExceeded returns whether the minutes exceed the soft quota
+func (s *UsageSummary) QuotaExceeded() bool {
+	return s.SoftQuota > 0 && s.Minutes > s.SoftQuota
+}
+
+// QuotaExceeded returns whether the minutes exceed the soft quota
+func (s *OwnerUsageSummary) QuotaExceeded() bool {
+	return s.SoftQuota > 0 && s.Minutes > s.SoftQuota
+}
+
+// ParseUsageMonth validates the month like "2024-10", the current month is returned if it's empty
+func ParseUsageMonth(month string) (string, error) {
+	if month == "" {
+		return actions_model.UsageMonth(time.Now()), nil
+	}
+	if _, err := time.ParseInLocation(actions_model.UsageMonthLayout, month, time.Local); err != nil {
+		return "", util.NewInvalidArgumentErrorf("invalid month %q, it should be like %q", month, actions_model.UsageMonthLayout)
+	}
+	return month, nil
+}
+
+// GetUsageSoftQuota returns the soft quota of the minutes per month of the owner, 0 means no quota
+func GetUsageSoftQuota(ctx context.Context, ownerID int64) (int64, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsUsageSoftQuota)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// SetUsageSoftQuota sets the soft quota of the minutes per month of the owner, 0 means no quota
+func SetUsageSoftQuota(ctx context.Context, ownerID, minutes int64) error {
+	if minutes < 0 {
+		return util.NewInvalidArgumentErrorf("soft quota can't be negative")
+	}
+	if minutes == 0 {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsUsageSoftQuota)
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsUsageSoftQuota, strconv.FormatInt(minutes, 10))
+}
+
+// GetOwnerUsageSummary returns the usage of the repositories of the owner in the month
+func GetOwnerUsageSummary(ctx context.Context, ownerID int64, month string) (*UsageSummary, error) {
+	usages, err := db.Find[actions_model.ActionUsage](ctx, actions_model.FindUsageOptions{
+		OwnerID: ownerID,
+		Month:   month,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find usages: %w", err)
+	}
+
+	repoIDs := make(container.Set[int64], len(usages))
+	for _, usage := range usages {
+		repoIDs.Add(usage.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs.Values())
+	if err != nil {
+		return nil, fmt.Errorf("get repositories: %w", err)
+	}
+
+	summary := &UsageSummary{
+		Month: month,
+		Repos: make([]*RepoUsage, 0, len(usages)),
+	}
+	for _, usage := range usages {
+		summary.Jobs += usage.Jobs
+		summary.Duration += usage.Duration
+		summary.Minutes += usage.Minutes
+		summary.Repos = append(summary.Repos, &RepoUsage{ActionUsage: usage, Repo: repos[usage.RepoID]})
+	}
+	if summary.SoftQuota, err = GetUsageSoftQuota(ctx, ownerID); err != nil {
+		return nil, fmt.Errorf("get soft quota: %w", err)
+	}
+	return summary, nil
+}
+
+// ListOwnerUsageSummaries returns the usages of all owners in the month, the owners which consume more minutes come first
+func ListOwnerUsageSummaries(ctx context.Context, month string) ([]*OwnerUsageSummary, error) {
+	usages, err := actions_model.SumUsageByOwner(ctx, month)
+	if err != nil {
+		return nil, fmt.Errorf("sum usages: %w", err)
+	}
+
+	ownerIDs := make([]int64, 0, len(usages))
+	for _, usage := range usages {
+		ownerIDs = append(ownerIDs, usage.OwnerID)
+	}
+	owners, err := user_model.GetUsersByIDs(ctx, ownerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get owners: %w", err)
+	}
+	ownersMap := make(map[int64]*user_model.User, len(owners))
+	for _, owner := range owners {
+		ownersMap[owner.ID] = owner
+	}
+
+	summaries := make([]*OwnerUsageSummary, 0, len(usages))
+	for _, usage := range usages {
+		quota, err := GetUsageSoftQuota(ctx, usage.OwnerID)
+		if err != nil {
+			return nil, fmt.Errorf("get soft quota: %w", err)
+		}
+		summaries = append(summaries, &OwnerUsageSummary{
+			OwnerUsage: usage,
+			Owner:      ownersMap[usage.OwnerID],
+			SoftQuota:  quota,
+		})
+	}
+	return summaries, nil
+}
@@ -0,0 +1,265 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/convert"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+var (
+	ErrWorkflowDisabled = util.NewPermissionDeniedErrorf("workflow is disabled")
+	ErrWorkflowNotFound = util.NewNotExistErrorf("workflow not found")
+	ErrInvalidTargetRef = util.NewInvalidArgumentErrorf("target ref is neither a branch nor a tag")
+	ErrTargetRefMissing = util.NewNotExistErrorf("target ref does not exist")
+)
+
+// DispatchActionWorkflow triggers a workflow_dispatch run of the workflow on the given ref.
+// The workflow file is read from the default branch, and processInputs fills the inputs of the run
+// according to the workflow_dispatch config of the workflow.
+func DispatchActionWorkflow(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, gitRepo *git.Repository,
+	workflowID, ref string, processInputs func(cfg *model.WorkflowDispatch, inputs map[string]any) error,
+) (*actions_model.ActionRun, error) {
+	if repo.MustGetUnit(ctx, unit.TypeActions).ActionsConfig().IsWorkflowDisabled(workflowID) {
+		return nil, ErrWorkflowDisabled
+	}
+
+	// get target commit of run from specified ref
+	refName := git.RefName(ref)
+	var runTargetCommit *git.Commit
+	var err error
+	if refName.IsTag() {
+		runTargetCommit, err = gitRepo.GetTagCommit(refName.TagName())
+	} else if refName.IsBranch() {
+		runTargetCommit, err = gitRepo.GetBranchCommit(refName.BranchName())
+	} else {
+		return nil, ErrInvalidTargetRef
+	}
+	if err != nil {
+		return nil, ErrTargetRefMissing
+	}
+
+	// get workflow entry from default branch commit
+	defaultBranchCommit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := actions.ListWorkflows(defaultBranchCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	// find workflow from commit
+	var content []byte
+	var workflows []*jobparser.SingleWorkflow
+	for _, entry := range entries {
+		if entry.Name() == workflowID {
+			content, err = actions.GetContentFromEntry(entry)
+			if err != nil {
+				return nil, err
+			}
+			workflows, err = jobparser.Parse(content)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	if len(workflows) == 0 {
+		return nil, ErrWorkflowNotFound
+	}
+
+	// get inputs of the run
+	workflow := &model.Workflow{
+		RawOn: workflows[0].RawOn,
+	}
+	inputs := make(map[string]any)
+	workflowDispatch := workflow.WorkflowDispatchConfig()
+	if workflowDispatch == nil {
+		return nil, util.NewInvalidArgumentErrorf("workflow %q has no workflow_dispatch trigger", workflowID)
+	}
+	if err := processInputs(workflowDispatch, inputs); err != nil {
+		return nil, err
+	}
+
+	// inputs -> WorkflowDispatchPayload.Inputs -> ActionRun.EventPayload -> runner: ghc.Event
+	// https://docs.github.com/en/actions/learn-github-actions/contexts#github-context
+	// https://docs.github.com/en/webhooks/webhook-events-and-payloads#workflow_dispatch
+	workflowDispatchPayload := &api.WorkflowDispatchPayload{
+		Workflow:   workflowID,
+		Ref:        ref,
+		Repository: convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeNone}),
+		Inputs:     inputs,
+		Sender:     convert.ToUserWithAccessMode(ctx, doer, perm.AccessModeNone),
+	}
+	eventPayload, err := workflowDispatchPayload.JSONPayload()
+	if err != nil {
+		return nil, fmt.Errorf("JSONPayload: %w", err)
+	}
+
+	run := &actions_model.ActionRun{
+		Title:             strings.SplitN(runTargetCommit.CommitMessage, "\n", 2)[0],
+		RepoID:            repo.ID,
+		OwnerID:           repo.OwnerID,
+		WorkflowID:        workflowID,
+		TriggerUserID:     doer.ID,
+		Ref:               ref,
+		CommitSHA:         runTargetCommit.ID.String(),
+		IsForkPullRequest: false,
+		Event:             "workflow_dispatch",
+		TriggerEvent:      "workflow_dispatch",
+		EventPayload:      string(eventPayload),
+		Status:            actions_model.StatusWaiting,
+	}
+
+	attributes, err := evaluateWorkflowAttributes(ctx, run, content, workflows)
+	if err != nil {
+		return nil, fmt.Errorf("evaluateWorkflowAttributes: %w", err)
+	}
+
+	// cancel running jobs of the same workflow, unless the workflow has a concurrency group
+	if run.ConcurrencyGroup == "" {
+		if err := actions_model.CancelPreviousJobs(
+			ctx,
+			run.RepoID,
+			run.Ref,
+			run.WorkflowID,
+			run.Event,
+		); err != nil {
+			log.Error("CancelRunningJobs: %v", err)
+		}
+	}
+
+	// Insert the action run and its associated jobs into the database
+	if err := insertRun(ctx, run, workflows, attributes); err != nil {
+		return nil, fmt.Errorf("InsertRun: %w", err)
+	}
+
+	alljobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
+	if err != nil {
+		log.Error("FindRunJobs: %v", err)
+	}
+	CreateCommitStatus(ctx, alljobs...)
+
+	return run, nil
+}
+
+// WorkflowInputErrorReason is the reason why an input of a dispatched workflow is invalid
+type WorkflowInputErrorReason string
+
+const (
+	WorkflowInputUnexpected     WorkflowInputErrorReason = "unexpected"
+	WorkflowInputMissing        WorkflowInputErrorReason = "missing"
+	WorkflowInputNotBoolean     WorkflowInputErrorReason = "boolean"
+	WorkflowInputNotNumber      WorkflowInputErrorReason = "number"
+	WorkflowInputNotChoice      WorkflowInputErrorReason = "choice"
+	WorkflowInputNotEnvironment WorkflowInputErrorReason = "environment"
+)
+
+// WorkflowInputError represents an invalid input of a dispatched workflow
+type WorkflowInputError struct {
+	Input   string
+	Reason  WorkflowInputErrorReason
+	Options []string // the options of the choice input
+}
+
+func (err *WorkflowInputError) Error() string {
+	switch err.Reason {
+	case WorkflowInputUnexpected:
+		return fmt.Sprintf("unexpected input %q", err.Input)
+	case WorkflowInputMissing:
+		return fmt.Sprintf("required input %q is missing", err.Input)
+	case WorkflowInputNotChoice:
+		return fmt.Sprintf("input %q must be one of %s", err.Input, strings.Join(err.Options, ", "))
+	case WorkflowInputNotEnvironment:
+		return fmt.Sprintf("input %q must be an environment of the repository", err.Input)
+	default:
+		return fmt.Sprintf("input %q must be a %s", err.Input, err.Reason)
+	}
+}
+
+func (err *WorkflowInputError) Unwrap() error {
+	return util.ErrInvalidArgument
+}
+
+// ValidateWorkflowDispatchInputs checks the provided inputs against the workflow_dispatch config of a workflow,
+// and fills the inputs of the run, the default values are used for the inputs which are not provided.
+// The values of the environment inputs must be the environments of the repository.
+func ValidateWorkflowDispatchInputs(ctx context.Context, repo *repo_model.Repository, cfg *model.WorkflowDispatch, provided map[string]string, inputs map[string]any) error {
+	for name := range provided {
+		if _, ok := cfg.Inputs[name]; !ok {
+			return &WorkflowInputError{Input: name, Reason: WorkflowInputUnexpected}
+		}
+	}
+
+	var environments []string
+	for name, config := range cfg.Inputs {
+		value, ok := provided[name]
+		if !ok {
+			if config.Required && config.Default == "" {
+				return &WorkflowInputError{Input: name, Reason: WorkflowInputMissing}
+			}
+			inputs[name] = config.Default
+			continue
+		}
+
+		switch config.Type {
+		case "boolean":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return &WorkflowInputError{Input: name, Reason: WorkflowInputNotBoolean}
+			}
+			inputs[name] = strconv.FormatBool(b)
+		case "number":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return &WorkflowInputError{Input: name, Reason: WorkflowInputNotNumber}
+			}
+			inputs[name] = value
+		case "choice":
+			if !slices.Contains(config.Options, value) {
+				return &WorkflowInputError{Input: name, Reason: WorkflowInputNotChoice, Options: config.Options}
+			}
+			inputs[name] = value
+		case "environment":
+			if environments == nil {
+				envs, err := db.Find[actions_model.ActionEnvironment](ctx, actions_model.FindEnvironmentsOptions{RepoID: repo.ID})
+				if err != nil {
+					return fmt.Errorf("FindEnvironments: %w", err)
+				}
+				environments = make([]string, 0, len(envs))
+				for _, env := range envs {
+					environments = append(environments, env.Name)
+				}
+			}
+			if !slices.Contains(environments, value) {
+				return &WorkflowInputError{Input: name, Reason: WorkflowInputNotEnvironment}
+			}
+			inputs[name] = value
+		default:
+			inputs[name] = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/services/convert"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// maxWorkflowRunChainDepth is the max levels of the workflows chained by workflow_run events,
+// it also prevents the workflows from triggering each other endlessly.
+// See https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#workflow_run
+const maxWorkflowRunChainDepth = 3
+
+// notifyWorkflowRunCompleted notifies the completion of an attempt of a run,
+// and triggers the workflow_run workflows of the repository
+func notifyWorkflowRunCompleted(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error {
+	if notified, err := actions_model.SetRunCompletionNotified(ctx, run); err != nil || !notified {
+		return err
+	}
+
+	if err := run.LoadAttributes(ctx); err != nil {
+		return err
+	}
+	notify_service.WorkflowRunStatusUpdate(ctx, run.Repo, run.TriggerUser, run)
+
+	depth, err := getWorkflowRunChainDepth(ctx, run)
+	if err != nil {
+		return err
+	}
+	if depth >= maxWorkflowRunChainDepth {
+		log.Trace("run %d has been chained by %d workflow_run events, ignore its completion", run.ID, depth)
+		return nil
+	}
+
+	apiRun, err := convert.ToActionWorkflowRun(ctx, run, nil)
+	if err != nil {
+		return err
+	}
+	permission, err := access_model.GetUserRepoPermission(ctx, run.Repo, run.TriggerUser)
+	if err != nil {
+		return err
+	}
+
+	newNotifyInput(run.Repo, run.TriggerUser, webhook_module.HookEventWorkflowRun).
+		WithPayload(&api.WorkflowRunPayload{
+			Action:      api.HookWorkflowCompleted,
+			Workflow:    &api.PayloadWorkflow{Name: run.WorkflowName(jobs)},
+			WorkflowRun: apiRun,
+			Repository:  convert.ToRepo(ctx, run.Repo, permission),
+			Sender:      convert.ToUser(ctx, run.TriggerUser, nil),
+		}).
+		Notify(withMethod(ctx, "WorkflowRunCompleted"))
+	return nil
+}
+
+// getWorkflowRunChainDepth returns how many workflow_run events have been chained to trigger the run
+func getWorkflowRunChainDepth(ctx context.Context, run *actions_model.ActionRun) (int, error) {
+	depth := 0
+	for run.Event == webhook_module.HookEventWorkflowRun && depth < maxWorkflowRunChainDepth {
+		depth++
+		payload := &api.WorkflowRunPayload{}
+		if err := json.Unmarshal([]byte(run.EventPayload), payload); err != nil {
+			return 0, err
+		}
+		if payload.WorkflowRun == nil {
+			break
+		}
+		var err error
+		if run, err = actions_model.GetRunByID(ctx, payload.WorkflowRun.ID); err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				// the triggering run has been deleted
+				break
+			}
+			return 0, err
+		}
+	}
+	return depth, nil
+}
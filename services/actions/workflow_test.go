@@ -0,0 +1,51 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWorkflowDispatchInputs(t *testing.T) {
+	cfg := &model.WorkflowDispatch{
+		Inputs: map[string]model.WorkflowDispatchInput{
+			"name":    {Required: true},
+			"debug":   {Type: "boolean", Default: "false"},
+			"env":     {Type: "choice", Options: []string{"staging", "production"}, Default: "staging"},
+			"retries": {Type: "number", Default: "3"},
+			"comment": {},
+		},
+	}
+
+	inputs := make(map[string]any)
+	assert.NoError(t, ValidateWorkflowDispatchInputs(nil, nil, cfg, map[string]string{"name": "gitea", "debug": "1", "env": "production", "retries": "1.5"}, inputs))
+	assert.Equal(t, map[string]any{"name": "gitea", "debug": "true", "env": "production", "retries": "1.5", "comment": ""}, inputs)
+
+	inputs = make(map[string]any)
+	assert.NoError(t, ValidateWorkflowDispatchInputs(nil, nil, cfg, map[string]string{"name": "gitea"}, inputs))
+	assert.Equal(t, map[string]any{"name": "gitea", "debug": "false", "env": "staging", "retries": "3", "comment": ""}, inputs)
+
+	for _, c := range []struct {
+		provided map[string]string
+		reason   WorkflowInputErrorReason
+	}{
+		{map[string]string{}, WorkflowInputMissing},
+		{map[string]string{"name": "gitea", "unknown": "value"}, WorkflowInputUnexpected},
+		{map[string]string{"name": "gitea", "debug": "maybe"}, WorkflowInputNotBoolean},
+		{map[string]string{"name": "gitea", "retries": "three"}, WorkflowInputNotNumber},
+		{map[string]string{"name": "gitea", "env": "dev"}, WorkflowInputNotChoice},
+	} {
+		err := ValidateWorkflowDispatchInputs(nil, nil, cfg, c.provided, make(map[string]any))
+		assert.ErrorIs(t, err, util.ErrInvalidArgument)
+		var inputErr *WorkflowInputError
+		if assert.ErrorAs(t, err, &inputErr) {
+			assert.Equal(t, c.reason, inputErr.Reason)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/perm"
@@ -101,7 +102,23 @@ func determineAccessMode(ctx *Base, pkg *Package, doer *user_model.User) (perm.A
 		return perm.AccessModeNone, nil
 	}
 
-	// TODO: ActionUser permission check
+	// the token of an actions job accesses the packages of the owner of its repository as granted by the `packages` permission of the job
+	if doer.IsActions() {
+		if taskID, ok := ctx.Data["ActionsTaskID"].(int64); ok {
+			task, err := actions_model.GetTaskByID(ctx, taskID)
+			if err != nil {
+				return perm.AccessModeNone, err
+			}
+			if task.OwnerID == pkg.Owner.ID {
+				tokenPerms, err := actions_model.GetTaskTokenPermissions(ctx, task)
+				if err != nil {
+					return perm.AccessModeNone, err
+				}
+				return tokenPerms[actions_model.TokenScopePackages], nil
+			}
+		}
+	}
+
 	accessMode := perm.AccessModeNone
 	if pkg.Owner.IsOrganization() {
 		org := organization.OrgFromUser(pkg.Owner)
@@ -6,6 +6,7 @@ package convert
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -173,6 +174,7 @@ func ToBranchProtection(ctx context.Context, bp *git_model.ProtectedBranch, repo
 		MergeWhitelistTeams:           mergeWhitelistTeams,
 		EnableStatusCheck:             bp.EnableStatusCheck,
 		StatusCheckContexts:           bp.StatusCheckContexts,
+		RequiredWorkflows:             bp.RequiredWorkflows,
 		RequiredApprovals:             bp.RequiredApprovals,
 		EnableApprovalsWhitelist:      bp.EnableApprovalsWhitelist,
 		ApprovalsWhitelistUsernames:   approvalsWhitelistUsernames,
@@ -227,6 +229,150 @@ func ToActionTask(ctx context.Context, t *actions_model.ActionTask) (*api.Action
 	}, nil
 }
 
+// ToActionWorkflowRun convert a actions_model.ActionRun to an api.ActionWorkflowRun
+func ToActionWorkflowRun(ctx context.Context, run *actions_model.ActionRun, doer *user_model.User) (*api.ActionWorkflowRun, error) {
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	res := &api.ActionWorkflowRun{
+		ID:                run.ID,
+		RunNumber:         run.Index,
+		RunAttempt:        run.Attempt,
+		DisplayTitle:      run.Title,
+		WorkflowID:        run.WorkflowID,
+		Event:             run.TriggerEvent,
+		Status:            run.Status.String(),
+		HeadBranch:        run.PrettyRef(),
+		HeadSHA:           run.CommitSHA,
+		IsForkPullRequest: run.IsForkPullRequest,
+		NeedApproval:      run.NeedApproval,
+		Actor:             ToUser(ctx, run.TriggerUser, doer),
+		URL:               fmt.Sprintf("%s/actions/runs/%d", run.Repo.APIURL(), run.ID),
+		HTMLURL:           run.HTMLURL(),
+		CreatedAt:         run.Created.AsLocalTime(),
+		UpdatedAt:         run.Updated.AsLocalTime(),
+	}
+	if run.Started > 0 {
+		startedAt := run.Started.AsLocalTime()
+		res.StartedAt = &startedAt
+	}
+	if run.Stopped > 0 {
+		completedAt := run.Stopped.AsLocalTime()
+		res.CompletedAt = &completedAt
+	}
+	if run.Status.IsDone() {
+		res.Conclusion = run.Status.String()
+		res.CancelReason = string(run.CancelReason)
+	}
+	return res, nil
+}
+
+// ToActionWorkflowJob convert a actions_model.ActionRunJob to an api.ActionWorkflowJob,
+// the task is the latest task of the job, it's nil if the job hasn't been picked by a runner
+func ToActionWorkflowJob(ctx context.Context, job *actions_model.ActionRunJob, task *actions_model.ActionTask) (*api.ActionWorkflowJob, error) {
+	if err := job.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	run := job.Run
+
+	// the jobs are indexed by their positions in the run in the web UI
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	jobIndex := 0
+	for i, j := range jobs {
+		if j.ID == job.ID {
+			jobIndex = i
+			break
+		}
+	}
+
+	res := &api.ActionWorkflowJob{
+		ID:           job.ID,
+		RunID:        run.ID,
+		RunURL:       fmt.Sprintf("%s/actions/runs/%d", run.Repo.APIURL(), run.ID),
+		RunAttempt:   run.Attempt,
+		Name:         job.Name,
+		WorkflowName: run.WorkflowName([]*actions_model.ActionRunJob{job}),
+		HeadBranch:   run.PrettyRef(),
+		HeadSHA:      job.CommitSHA,
+		Status:       job.Status.String(),
+		Labels:       job.RunsOn,
+		Steps:        []*api.ActionWorkflowStep{},
+		URL:          fmt.Sprintf("%s/actions/jobs/%d", run.Repo.APIURL(), job.ID),
+		HTMLURL:      fmt.Sprintf("%s/jobs/%d", run.HTMLURL(), jobIndex),
+		CreatedAt:    job.Created.AsLocalTime(),
+	}
+	if res.Labels == nil {
+		res.Labels = []string{}
+	}
+	if job.Status.IsDone() {
+		res.Conclusion = job.Status.String()
+	}
+	if job.Started > 0 {
+		res.StartedAt = job.Started.AsLocalTime()
+	}
+	if job.Stopped > 0 {
+		res.CompletedAt = job.Stopped.AsLocalTime()
+	}
+
+	if task != nil {
+		if task.Steps == nil {
+			if task.Steps, err = actions_model.GetTaskStepsByTaskID(ctx, task.ID); err != nil {
+				return nil, err
+			}
+		}
+		for _, step := range task.Steps {
+			apiStep := &api.ActionWorkflowStep{
+				Name:   step.Name,
+				Number: step.Index + 1,
+				Status: step.Status.String(),
+			}
+			if step.Status.IsDone() {
+				apiStep.Conclusion = step.Status.String()
+			}
+			if step.Started > 0 {
+				apiStep.StartedAt = step.Started.AsLocalTime()
+			}
+			if step.Stopped > 0 {
+				apiStep.CompletedAt = step.Stopped.AsLocalTime()
+			}
+			res.Steps = append(res.Steps, apiStep)
+		}
+
+		res.RunnerID = task.RunnerID
+		if runner, err := actions_model.GetRunnerByID(ctx, task.RunnerID); err == nil {
+			res.RunnerName = runner.Name
+		} else if !errors.Is(err, util.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// ToActionRunner convert a actions_model.ActionRunner to an api.ActionRunner
+func ToActionRunner(runner *actions_model.ActionRunner) *api.ActionRunner {
+	res := &api.ActionRunner{
+		ID:          runner.ID,
+		Name:        runner.Name,
+		Description: runner.Description,
+		Status:      runner.StatusName(),
+		Version:     runner.Version,
+		Labels:      runner.AgentLabels,
+		Ephemeral:   runner.Ephemeral,
+		Created:     runner.Created.AsLocalTime(),
+	}
+	if res.Labels == nil {
+		res.Labels = []string{}
+	}
+	if runner.LastOnline > 0 {
+		res.LastOnline = runner.LastOnline.AsLocalTime()
+	}
+	return res
+}
+
 // ToVerification convert a git.Commit.Signature to an api.PayloadCommitVerification
 func ToVerification(ctx context.Context, c *git.Commit) *api.PayloadCommitVerification {
 	verif := asymkey_model.ParseCommitWithSignature(ctx, c)
@@ -71,3 +71,15 @@ func (f *AdminDashboardForm) Validate(req *http.Request, errs binding.Errors) bi
 	ctx := context.GetValidateContext(req)
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+// AdminActionsUsageQuotaForm form for setting the soft quota of the actions minutes of an owner
+type AdminActionsUsageQuotaForm struct {
+	Owner     string `binding:"Required"`
+	SoftQuota int64  `binding:"Min(0)"`
+}
+
+// Validate validates form fields
+func (f *AdminActionsUsageQuotaForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -53,6 +53,19 @@ func (f *UpdateOrgSettingForm) Validate(req *http.Request, errs binding.Errors)
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// OrgRequiredWorkflowForm form for requiring a workflow in the repositories of an organization
+type OrgRequiredWorkflowForm struct {
+	RepoName   string `binding:"Required;MaxSize(100)"`
+	WorkflowID string `binding:"Required;MaxSize(255)"`
+	RepoNames  string // the repositories which run the workflow, separated by commas or line breaks, all repositories if it's empty
+}
+
+// Validate validates the fields
+func (f *OrgRequiredWorkflowForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // ___________
 // \__    ___/___ _____    _____
 //   |    |_/ __ \\__  \  /     \
@@ -204,6 +204,7 @@ type ProtectBranchForm struct {
 	MergeWhitelistTeams           string
 	EnableStatusCheck             bool
 	StatusCheckContexts           string
+	RequiredWorkflows             string
 	RequiredApprovals             int64
 	EnableApprovalsWhitelist      bool
 	ApprovalsWhitelistUsers       string
@@ -255,6 +256,8 @@ type WebhookForm struct {
 	Wiki                     bool
 	Repository               bool
 	Package                  bool
+	WorkflowRun              bool
+	WorkflowJob              bool
 	Active                   bool
 	BranchFilter             string `binding:"GlobPattern"`
 	AuthorizationHeader      string
@@ -740,6 +743,20 @@ func (f *EditRepoFileForm) Validate(req *http.Request, errs binding.Errors) bind
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// NewWorkflowForm form for creating a workflow with the workflow editor
+type NewWorkflowForm struct {
+	FileName      string `binding:"Required;MaxSize(255)"`
+	Content       string `binding:"Required"`
+	CommitSummary string `binding:"MaxSize(100)"`
+	CommitMessage string
+}
+
+// Validate validates the fields
+func (f *NewWorkflowForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // EditPreviewDiffForm form for changing preview diff
 type EditPreviewDiffForm struct {
 	Content string
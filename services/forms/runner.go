@@ -22,3 +22,51 @@ func (f *EditRunnerForm) Validate(req *http.Request, errs binding.Errors) bindin
 	ctx := context.GetValidateContext(req)
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+// ActionsGeneralSettingsForm form for updating the general settings of actions for a repository
+type ActionsGeneralSettingsForm struct {
+	RunRetentionDays       int64  `binding:"Range(0,36500)"`
+	ApprovalPolicy         string `binding:"In(first_time,all_outside)"`
+	AllowedActions         string `binding:"In(all,local,selected)"`
+	AllowedActionsPatterns string
+}
+
+// Validate validates form fields
+func (f *ActionsGeneralSettingsForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ActionsAllowedActionsForm form for updating the allowed actions policy of an organization
+type ActionsAllowedActionsForm struct {
+	AllowedActions         string `binding:"Required;In(all,local,selected)"`
+	AllowedActionsPatterns string
+}
+
+// Validate validates form fields
+func (f *ActionsAllowedActionsForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// CreateEnvironmentForm form for creating a deployment environment of a repository
+type CreateEnvironmentForm struct {
+	Name string `binding:"Required;MaxSize(255)"`
+}
+
+// Validate validates form fields
+func (f *CreateEnvironmentForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// EditEnvironmentReviewersForm form for editing the required reviewers of a deployment environment
+type EditEnvironmentReviewersForm struct {
+	Reviewers string
+}
+
+// Validate validates form fields
+func (f *EditEnvironmentReviewersForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
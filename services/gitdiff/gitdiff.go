@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -81,6 +82,7 @@ type DiffLine struct {
 	Type        DiffLineType
 	Content     string
 	Comments    []*issues_model.Comment
+	Annotations []*actions_model.ActionAnnotation
 	SectionInfo *DiffLineSectionInfo
 }
 
@@ -491,6 +493,35 @@ func (diff *Diff) LoadComments(ctx context.Context, issue *issues_model.Issue, c
 	return nil
 }
 
+// LoadAnnotations attaches the annotations about the files of the diff to the last lines they cover on the new side,
+// the annotations which aren't about a line of the diff are ignored
+func (diff *Diff) LoadAnnotations(annotations []*actions_model.ActionAnnotation) {
+	fileAnnotations := make(map[string][]*actions_model.ActionAnnotation)
+	for _, annotation := range annotations {
+		if annotation.Path != "" && annotation.EndLine > 0 {
+			fileAnnotations[annotation.Path] = append(fileAnnotations[annotation.Path], annotation)
+		}
+	}
+	for _, file := range diff.Files {
+		annotations, ok := fileAnnotations[file.Name]
+		if !ok {
+			continue
+		}
+		for _, section := range file.Sections {
+			for _, line := range section.Lines {
+				if line.RightIdx <= 0 || line.Type == DiffLineSection {
+					continue
+				}
+				for _, annotation := range annotations {
+					if annotation.EndLine == int64(line.RightIdx) {
+						line.Annotations = append(line.Annotations, annotation)
+					}
+				}
+			}
+		}
+	}
+}
+
 const cmdDiffHead = "diff --git "
 
 // ParsePatch builds a Diff object from a io.Reader and some parameters.
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/unittest"
@@ -614,6 +615,19 @@ func TestDiff_LoadCommentsWithOutdated(t *testing.T) {
 	assert.Len(t, diff.Files[0].Sections[0].Lines[0].Comments, 3)
 }
 
+func TestDiff_LoadAnnotations(t *testing.T) {
+	diff := setupDefaultDiff()
+	annotations := []*actions_model.ActionAnnotation{
+		{ID: 1, Path: "README.md", StartLine: 2, EndLine: 4},
+		{ID: 2, Path: "README.md", StartLine: 4, EndLine: 4},
+		{ID: 3, Path: "README.md", StartLine: 3, EndLine: 3},
+		{ID: 4, Path: "main.go", StartLine: 4, EndLine: 4},
+		{ID: 5, Path: "README.md"},
+	}
+	diff.LoadAnnotations(annotations)
+	assert.Equal(t, annotations[:2], diff.Files[0].Sections[0].Lines[0].Annotations)
+}
+
 func TestDiffLine_CanComment(t *testing.T) {
 	assert.False(t, (&DiffLine{Type: DiffLineSection}).CanComment())
 	assert.False(t, (&DiffLine{Type: DiffLineAdd, Comments: []*issues_model.Comment{{Content: "bla"}}}).CanComment())
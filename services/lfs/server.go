@@ -520,10 +520,12 @@ func authenticate(ctx *context.Context, repository *repo_model.Repository, autho
 			return false
 		}
 
-		if task.IsForkPullRequest {
-			return accessMode <= perm.AccessModeRead
+		tokenPerms, err := actions_model.GetTaskTokenPermissions(ctx, task)
+		if err != nil {
+			log.Error("Unable to GetTaskTokenPermissions for task[%d] Error: %v", taskID, err)
+			return false
 		}
-		return accessMode <= perm.AccessModeWrite
+		return accessMode <= tokenPerms.UnitAccessModes()[unit.TypeCode]
 	}
 
 	// ctx.IsSigned is unnecessary here, this will be checked in perm.CanAccess
@@ -7,6 +7,7 @@ package pull
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -107,7 +108,24 @@ func IsPullCommitStatusPass(ctx context.Context, pr *issues_model.PullRequest) (
 		return false, errors.Wrap(err, "GetLatestCommitStatus")
 	}
 	if pb == nil || !pb.EnableStatusCheck {
-		return true, nil
+		// the required workflows of the organization are checked even if the status checks aren't enabled
+		if err := pr.LoadBaseRepo(ctx); err != nil {
+			return false, errors.Wrap(err, "LoadBaseRepo")
+		}
+		if workflows, err := actions_model.GetOrgRequiredWorkflowsForRepo(ctx, pr.BaseRepo); err != nil {
+			return false, errors.Wrap(err, "GetOrgRequiredWorkflowsForRepo")
+		} else if len(workflows) == 0 {
+			return true, nil
+		}
+		sha, err := getPullRequestHeadCommitID(ctx, pr)
+		if err != nil {
+			return false, err
+		}
+		state, err := getOrgRequiredWorkflowsState(ctx, pr, sha)
+		if err != nil {
+			return false, err
+		}
+		return state.IsSuccess(), nil
 	}
 
 	state, err := GetPullRequestCommitStatusState(ctx, pr)
@@ -117,14 +135,25 @@ func IsPullCommitStatusPass(ctx context.Context, pr *issues_model.PullRequest) (
 	return state.IsSuccess(), nil
 }
 
-// GetPullRequestCommitStatusState returns pull request merged commit status state
-func GetPullRequestCommitStatusState(ctx context.Context, pr *issues_model.PullRequest) (structs.CommitStatusState, error) {
+// getOrgRequiredWorkflowsState returns the worst state of the required workflows of the organization which have run for the head commit of the pull request
+func getOrgRequiredWorkflowsState(ctx context.Context, pr *issues_model.PullRequest, sha string) (structs.CommitStatusState, error) {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return "", errors.Wrap(err, "LoadBaseRepo")
+	}
+	workflowStates, err := actions_model.GetOrgRequiredWorkflowStates(ctx, pr.BaseRepo, sha)
+	if err != nil {
+		return "", errors.Wrap(err, "GetOrgRequiredWorkflowStates")
+	}
+	return actions_model.MergeRequiredWorkflowStates(workflowStates), nil
+}
+
+// getPullRequestHeadCommitID returns the head commit of the pull request, it fails if the head branch doesn't exist
+func getPullRequestHeadCommitID(ctx context.Context, pr *issues_model.PullRequest) (string, error) {
 	// Ensure HeadRepo is loaded
 	if err := pr.LoadHeadRepo(ctx); err != nil {
 		return "", errors.Wrap(err, "LoadHeadRepo")
 	}
 
-	// check if all required status checks are successful
 	headGitRepo, closer, err := gitrepo.RepositoryFromContextOrOpen(ctx, pr.HeadRepo)
 	if err != nil {
 		return "", errors.Wrap(err, "OpenRepository")
@@ -138,12 +167,16 @@ func GetPullRequestCommitStatusState(ctx context.Context, pr *issues_model.PullR
 		return "", errors.New("Head branch does not exist, can not merge")
 	}
 
-	var sha string
 	if pr.Flow == issues_model.PullRequestFlowGithub {
-		sha, err = headGitRepo.GetBranchCommitID(pr.HeadBranch)
-	} else {
-		sha, err = headGitRepo.GetRefCommitID(pr.GetGitRefName())
+		return headGitRepo.GetBranchCommitID(pr.HeadBranch)
 	}
+	return headGitRepo.GetRefCommitID(pr.GetGitRefName())
+}
+
+// GetPullRequestCommitStatusState returns pull request merged commit status state
+func GetPullRequestCommitStatusState(ctx context.Context, pr *issues_model.PullRequest) (structs.CommitStatusState, error) {
+	// check if all required status checks are successful
+	sha, err := getPullRequestHeadCommitID(ctx, pr)
 	if err != nil {
 		return "", err
 	}
@@ -165,6 +198,26 @@ func GetPullRequestCommitStatusState(ctx context.Context, pr *issues_model.PullR
 	if pb != nil {
 		requiredContexts = pb.StatusCheckContexts
 	}
+	state := MergeRequiredContextsCommitStatus(commitStatuses, requiredContexts)
+
+	if pb != nil && len(pb.RequiredWorkflows) > 0 {
+		workflowStates, err := actions_model.GetRequiredWorkflowStates(ctx, pr.BaseRepo.ID, sha, pb.RequiredWorkflows)
+		if err != nil {
+			return "", errors.Wrap(err, "GetRequiredWorkflowStates")
+		}
+		// without any status check pattern, only the required workflows are checked
+		if workflowState := actions_model.MergeRequiredWorkflowStates(workflowStates); len(requiredContexts) == 0 || workflowState.NoBetterThan(state) {
+			state = workflowState
+		}
+	}
+
+	orgWorkflowState, err := getOrgRequiredWorkflowsState(ctx, pr, sha)
+	if err != nil {
+		return "", err
+	}
+	if orgWorkflowState.NoBetterThan(state) {
+		state = orgWorkflowState
+	}
 
-	return MergeRequiredContextsCommitStatus(commitStatuses, requiredContexts), nil
+	return state, nil
 }
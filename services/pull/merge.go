@@ -436,10 +436,8 @@ func CheckPullBranchProtections(ctx context.Context, pr *issues_model.PullReques
 	if err != nil {
 		return fmt.Errorf("LoadProtectedBranch: %v", err)
 	}
-	if pb == nil {
-		return nil
-	}
 
+	// the required workflows of the organization are checked even if the branch isn't protected
 	isPass, err := IsPullCommitStatusPass(ctx, pr)
 	if err != nil {
 		return err
@@ -450,6 +448,10 @@ func CheckPullBranchProtections(ctx context.Context, pr *issues_model.PullReques
 		}
 	}
 
+	if pb == nil {
+		return nil
+	}
+
 	if !issues_model.HasEnoughApprovals(ctx, pb, pr) {
 		return models.ErrDisallowedToMerge{
 			Reason: "Does not have enough approvals",
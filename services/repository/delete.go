@@ -27,6 +27,7 @@ import (
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/storage"
+	actions_service "code.gitea.io/gitea/services/actions"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 
 	"xorm.io/builder"
@@ -66,6 +67,12 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		return fmt.Errorf("list actions artifacts of repo %v: %w", repoID, err)
 	}
 
+	// Query the caches of this repo, they will be needed after they have been deleted to remove cache files in ObjectStorage
+	caches, err := db.Find[actions_model.ActionCache](ctx, actions_model.FindCachesOptions{RepoID: repoID})
+	if err != nil {
+		return fmt.Errorf("list actions caches of repo %v: %w", repoID, err)
+	}
+
 	// In case owner is a organization, we have to change repo specific teams
 	// if ignoreOrgTeams is not true
 	var org *user_model.User
@@ -164,6 +171,11 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		&actions_model.ActionSchedule{RepoID: repoID},
 		&actions_model.ActionArtifact{RepoID: repoID},
 		&actions_model.ActionRunnerToken{RepoID: repoID},
+		&actions_model.ActionAnnotation{RepoID: repoID},
+		&actions_model.ActionStepSummary{RepoID: repoID},
+		&actions_model.ActionOrgRequiredWorkflow{RepoID: repoID},
+		&actions_model.ActionEnvironment{RepoID: repoID},
+		&actions_model.ActionCache{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %w", err)
 	}
@@ -329,7 +341,7 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 
 	// Finally, delete action logs after the actions have already been deleted to avoid new log files
 	for _, task := range tasks {
-		err := actions_module.RemoveLogs(ctx, task.LogInStorage, task.LogFilename)
+		err := actions_module.RemoveTaskLogs(ctx, task)
 		if err != nil {
 			log.Error("remove log file %q: %v", task.LogFilename, err)
 			// go on
@@ -344,6 +356,14 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		}
 	}
 
+	// delete actions caches and their uploaded chunks in ObjectStorage after the repo have already been deleted
+	for _, cache := range caches {
+		if err := actions_service.RemoveCacheFiles(cache); err != nil {
+			log.Error("remove cache files: %v", err)
+			// go on
+		}
+	}
+
 	return nil
 }
 
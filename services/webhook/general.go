@@ -307,6 +307,62 @@ func getPackagePayloadInfo(p *api.PackagePayload, linkFormatter linkFormatter, w
 	return text, color
 }
 
+// workflowConclusionColor returns the color of the conclusion of a workflow run or a workflow job
+func workflowConclusionColor(conclusion string) int {
+	switch conclusion {
+	case "success":
+		return greenColor
+	case "failure":
+		return redColor
+	default:
+		return greyColor
+	}
+}
+
+func getWorkflowRunPayloadInfo(p *api.WorkflowRunPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	runLink := linkFormatter(p.WorkflowRun.HTMLURL, fmt.Sprintf("%s #%d", p.Workflow.Name, p.WorkflowRun.RunNumber))
+
+	switch p.Action {
+	case api.HookWorkflowRequested:
+		text = fmt.Sprintf("[%s] Workflow run %s requested", repoLink, runLink)
+		color = yellowColor
+	case api.HookWorkflowInProgress:
+		text = fmt.Sprintf("[%s] Workflow run %s started", repoLink, runLink)
+		color = yellowColor
+	case api.HookWorkflowCompleted:
+		text = fmt.Sprintf("[%s] Workflow run %s completed: %s", repoLink, runLink, p.WorkflowRun.Conclusion)
+		color = workflowConclusionColor(p.WorkflowRun.Conclusion)
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getWorkflowJobPayloadInfo(p *api.WorkflowJobPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	jobLink := linkFormatter(p.WorkflowJob.HTMLURL, p.WorkflowJob.WorkflowName+" / "+p.WorkflowJob.Name)
+
+	switch p.Action {
+	case api.HookWorkflowQueued:
+		text = fmt.Sprintf("[%s] Workflow job %s queued", repoLink, jobLink)
+		color = yellowColor
+	case api.HookWorkflowInProgress:
+		text = fmt.Sprintf("[%s] Workflow job %s started", repoLink, jobLink)
+		color = yellowColor
+	case api.HookWorkflowCompleted:
+		text = fmt.Sprintf("[%s] Workflow job %s completed: %s", repoLink, jobLink, p.WorkflowJob.Conclusion)
+		color = workflowConclusionColor(p.WorkflowJob.Conclusion)
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
 // ToHook convert models.Webhook to api.Hook
 // This function is not part of the convert package to prevent an import cycle
 func ToHook(repoLink string, w *webhook_model.Webhook) (*api.Hook, error) {
@@ -333,6 +333,55 @@ func packageTestPayload() *api.PackagePayload {
 	}
 }
 
+func workflowRunTestPayload() *api.WorkflowRunPayload {
+	return &api.WorkflowRunPayload{
+		Action: api.HookWorkflowCompleted,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		Workflow: &api.PayloadWorkflow{
+			Name: "CI",
+		},
+		WorkflowRun: &api.ActionWorkflowRun{
+			ID:         1,
+			RunNumber:  1,
+			Status:     "completed",
+			Conclusion: "success",
+			HTMLURL:    "http://localhost:3000/test/repo/actions/runs/1",
+		},
+	}
+}
+
+func workflowJobTestPayload() *api.WorkflowJobPayload {
+	return &api.WorkflowJobPayload{
+		Action: api.HookWorkflowCompleted,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		WorkflowJob: &api.ActionWorkflowJob{
+			ID:           1,
+			RunID:        1,
+			Name:         "build",
+			WorkflowName: "CI",
+			Status:       "completed",
+			Conclusion:   "success",
+			HTMLURL:      "http://localhost:3000/test/repo/actions/runs/1/jobs/0",
+		},
+	}
+}
+
 func TestGetIssuesPayloadInfo(t *testing.T) {
 	p := issueTestPayload()
 
@@ -639,3 +688,97 @@ func TestGetIssueCommentPayloadInfo(t *testing.T) {
 		assert.Equal(t, c.color, color, "case %d", i)
 	}
 }
+
+func TestGetWorkflowRunPayloadInfo(t *testing.T) {
+	p := workflowRunTestPayload()
+
+	cases := []struct {
+		action     api.HookWorkflowAction
+		conclusion string
+		text       string
+		color      int
+	}{
+		{
+			api.HookWorkflowRequested,
+			"",
+			"[test/repo] Workflow run CI #1 requested by user1",
+			yellowColor,
+		},
+		{
+			api.HookWorkflowInProgress,
+			"",
+			"[test/repo] Workflow run CI #1 started by user1",
+			yellowColor,
+		},
+		{
+			api.HookWorkflowCompleted,
+			"success",
+			"[test/repo] Workflow run CI #1 completed: success by user1",
+			greenColor,
+		},
+		{
+			api.HookWorkflowCompleted,
+			"failure",
+			"[test/repo] Workflow run CI #1 completed: failure by user1",
+			redColor,
+		},
+		{
+			api.HookWorkflowCompleted,
+			"cancelled",
+			"[test/repo] Workflow run CI #1 completed: cancelled by user1",
+			greyColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		p.WorkflowRun.Conclusion = c.conclusion
+		text, color := getWorkflowRunPayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
+
+func TestGetWorkflowJobPayloadInfo(t *testing.T) {
+	p := workflowJobTestPayload()
+
+	cases := []struct {
+		action     api.HookWorkflowAction
+		conclusion string
+		text       string
+		color      int
+	}{
+		{
+			api.HookWorkflowQueued,
+			"",
+			"[test/repo] Workflow job CI / build queued by user1",
+			yellowColor,
+		},
+		{
+			api.HookWorkflowInProgress,
+			"",
+			"[test/repo] Workflow job CI / build started by user1",
+			yellowColor,
+		},
+		{
+			api.HookWorkflowCompleted,
+			"success",
+			"[test/repo] Workflow job CI / build completed: success by user1",
+			greenColor,
+		},
+		{
+			api.HookWorkflowCompleted,
+			"failure",
+			"[test/repo] Workflow job CI / build completed: failure by user1",
+			redColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		p.WorkflowJob.Conclusion = c.conclusion
+		text, color := getWorkflowJobPayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
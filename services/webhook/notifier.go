@@ -6,6 +6,7 @@ package webhook
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/perm"
@@ -887,3 +888,73 @@ func notifyPackage(ctx context.Context, sender *user_model.User, pd *packages_mo
 		log.Error("PrepareWebhooks: %v", err)
 	}
 }
+
+func (m *webhookNotifier) WorkflowRunStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, run *actions_model.ActionRun) {
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		log.Error("GetRunJobsByRunID: %v", err)
+		return
+	}
+	apiRun, err := convert.ToActionWorkflowRun(ctx, run, nil)
+	if err != nil {
+		log.Error("ToActionWorkflowRun: %v", err)
+		return
+	}
+
+	action := api.HookWorkflowRequested
+	if run.Status.IsDone() {
+		action = api.HookWorkflowCompleted
+	} else if run.Status.IsRunning() {
+		action = api.HookWorkflowInProgress
+	}
+
+	if err := PrepareWebhooks(ctx, EventSource{Repository: repo}, webhook_module.HookEventWorkflowRun, &api.WorkflowRunPayload{
+		Action:       action,
+		Workflow:     &api.PayloadWorkflow{Name: run.WorkflowName(jobs)},
+		WorkflowRun:  apiRun,
+		Repository:   convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeOwner}),
+		Organization: workflowPayloadOrganization(ctx, repo),
+		Sender:       convert.ToUser(ctx, sender, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+func (m *webhookNotifier) WorkflowJobStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, job *actions_model.ActionRunJob, task *actions_model.ActionTask) {
+	var action api.HookWorkflowAction
+	switch {
+	case job.Status.IsDone():
+		action = api.HookWorkflowCompleted
+	case job.Status.IsRunning():
+		action = api.HookWorkflowInProgress
+	case job.Status.IsWaiting():
+		action = api.HookWorkflowQueued
+	default:
+		// the blocked jobs are notified once they are queued or completed
+		return
+	}
+
+	apiJob, err := convert.ToActionWorkflowJob(ctx, job, task)
+	if err != nil {
+		log.Error("ToActionWorkflowJob: %v", err)
+		return
+	}
+
+	if err := PrepareWebhooks(ctx, EventSource{Repository: repo}, webhook_module.HookEventWorkflowJob, &api.WorkflowJobPayload{
+		Action:       action,
+		WorkflowJob:  apiJob,
+		Repository:   convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeOwner}),
+		Organization: workflowPayloadOrganization(ctx, repo),
+		Sender:       convert.ToUser(ctx, sender, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+// workflowPayloadOrganization returns the organization of the repository for the payloads of workflows, it's nil if the owner isn't an organization
+func workflowPayloadOrganization(ctx context.Context, repo *repo_model.Repository) *api.User {
+	if owner := repo.MustOwner(ctx); owner.IsOrganization() {
+		return convert.ToUser(ctx, owner, nil)
+	}
+	return nil
+}
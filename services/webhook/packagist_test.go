@@ -116,6 +116,22 @@ func TestPackagistPayload(t *testing.T) {
 		require.Equal(t, pl, PackagistPayload{})
 	})
 
+	t.Run("WorkflowRun", func(t *testing.T) {
+		p := workflowRunTestPayload()
+
+		pl, err := pc.WorkflowRun(p)
+		require.NoError(t, err)
+		require.Equal(t, pl, PackagistPayload{})
+	})
+
+	t.Run("WorkflowJob", func(t *testing.T) {
+		p := workflowJobTestPayload()
+
+		pl, err := pc.WorkflowJob(p)
+		require.NoError(t, err)
+		require.Equal(t, pl, PackagistPayload{})
+	})
+
 	t.Run("Wiki", func(t *testing.T) {
 		p := wikiTestPayload()
 
@@ -126,6 +126,24 @@ func TestSlackPayload(t *testing.T) {
 		assert.Equal(t, "Package created: <http://localhost:3000/user1/-/packages/container/GiteaContainer/latest|GiteaContainer:latest> by <https://try.gitea.io/user1|user1>", pl.Text)
 	})
 
+	t.Run("WorkflowRun", func(t *testing.T) {
+		p := workflowRunTestPayload()
+
+		pl, err := sc.WorkflowRun(p)
+		require.NoError(t, err)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Workflow run <http://localhost:3000/test/repo/actions/runs/1|CI #1> completed: success by <https://try.gitea.io/user1|user1>", pl.Text)
+	})
+
+	t.Run("WorkflowJob", func(t *testing.T) {
+		p := workflowJobTestPayload()
+
+		pl, err := sc.WorkflowJob(p)
+		require.NoError(t, err)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Workflow job <http://localhost:3000/test/repo/actions/runs/1/jobs/0|CI / build> completed: success by <https://try.gitea.io/user1|user1>", pl.Text)
+	})
+
 	t.Run("Wiki", func(t *testing.T) {
 		p := wikiTestPayload()
 
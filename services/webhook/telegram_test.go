@@ -143,6 +143,24 @@ good job`, pl.Message)
 		assert.Equal(t, `Package created: <a href="http://localhost:3000/user1/-/packages/container/GiteaContainer/latest" rel="nofollow">GiteaContainer:latest</a> by <a href="https://try.gitea.io/user1" rel="nofollow">user1</a>`, pl.Message)
 	})
 
+	t.Run("WorkflowRun", func(t *testing.T) {
+		p := workflowRunTestPayload()
+
+		pl, err := tc.WorkflowRun(p)
+		require.NoError(t, err)
+
+		assert.Equal(t, `[<a href="http://localhost:3000/test/repo" rel="nofollow">test/repo</a>] Workflow run <a href="http://localhost:3000/test/repo/actions/runs/1" rel="nofollow">CI #1</a> completed: success by <a href="https://try.gitea.io/user1" rel="nofollow">user1</a>`, pl.Message)
+	})
+
+	t.Run("WorkflowJob", func(t *testing.T) {
+		p := workflowJobTestPayload()
+
+		pl, err := tc.WorkflowJob(p)
+		require.NoError(t, err)
+
+		assert.Equal(t, `[<a href="http://localhost:3000/test/repo" rel="nofollow">test/repo</a>] Workflow job <a href="http://localhost:3000/test/repo/actions/runs/1/jobs/0" rel="nofollow">CI / build</a> completed: success by <a href="https://try.gitea.io/user1" rel="nofollow">user1</a>`, pl.Message)
+	})
+
 	t.Run("Wiki", func(t *testing.T) {
 		p := wikiTestPayload()
 
@@ -0,0 +1,130 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	actions_service "code.gitea.io/gitea/services/actions"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsAllowedActions(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		repo := createActionsTestRepo(t, user2, "actions-allowed-actions", ".gitea/workflows/build.yml",
+			"on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/checkout@v4\n      - uses: evil/action@v1\n      - run: make build\n")
+
+		// only the actions matching the patterns are allowed in the repository
+		settingsURL := fmt.Sprintf("/%s/%s/settings/actions/general", user2.Name, repo.Name)
+		req := NewRequestWithValues(t, "POST", settingsURL, map[string]string{
+			"_csrf":                    GetCSRF(t, session, settingsURL),
+			"approval_policy":          string(repo_model.ActionsApprovalPolicyFirstTime),
+			"allowed_actions":          "selected",
+			"allowed_actions_patterns": "actions/*\n\ndocker://alpine:*",
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+		repo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: repo.ID})
+		cfg := repo.MustGetUnit(db.DefaultContext, unit_model.TypeActions).ActionsConfig()
+		assert.Equal(t, "selected", cfg.AllowedActions)
+		assert.Equal(t, []string{"actions/*", "docker://alpine:*"}, cfg.AllowedActionsPatterns)
+		resp := session.MakeRequest(t, NewRequest(t, "GET", settingsURL), http.StatusOK)
+		assert.Equal(t, "actions/*\ndocker://alpine:*", NewHTMLParser(t, resp.Body).Find("#allowed_actions_patterns").Text())
+
+		// the job using a disallowed action isn't handed to the runner
+		client := newTestRunnerClient(t, u, "allowed-actions-runner", repo.ID)
+		fetchResp, err := client.FetchTask(context.Background(), connect.NewRequest(&runnerv1.FetchTaskRequest{}))
+		assert.NoError(t, err)
+		assert.Nil(t, fetchResp.Msg.Task)
+
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Index: 1})
+		assert.Equal(t, actions_model.StatusFailure, run.Status)
+		job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID})
+		assert.Equal(t, actions_model.StatusFailure, job.Status)
+		task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: job.TaskID})
+		assert.Equal(t, actions_model.StatusFailure, task.Status)
+		assert.True(t, task.LogInStorage)
+
+		resp = session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/1/jobs/0/logs", user2.Name, repo.Name)), http.StatusOK)
+		logs := resp.Body.String()
+		assert.Contains(t, logs, "evil/action@v1 is not allowed by the repository")
+		assert.NotContains(t, logs, "actions/checkout@v4")
+	})
+}
+
+func TestActionsAllowedActionsOfOwnerAndInstance(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		org3 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+		session := loginUser(t, user2.Name)
+
+		settingsURL := fmt.Sprintf("/org/%s/settings/actions/general", org3.Name)
+		session.MakeRequest(t, NewRequest(t, "GET", settingsURL), http.StatusOK)
+		req := NewRequestWithValues(t, "POST", settingsURL, map[string]string{
+			"_csrf":                    GetCSRF(t, session, settingsURL),
+			"allowed_actions":          "local",
+			"allowed_actions_patterns": "ignored/*",
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+		allowed, err := actions_service.GetOwnerAllowedActions(db.DefaultContext, org3.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, actions_module.AllowedActionsLocal, allowed.Policy)
+		assert.Empty(t, allowed.Patterns)
+
+		repo := createActionsTestRepo(t, org3, "actions-allowed-actions-org", ".gitea/workflows/build.yml",
+			"on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/checkout@v4\n      - uses: ./.gitea/actions/build\n")
+		job := &actions_model.ActionRunJob{}
+		has, err := db.GetEngine(db.DefaultContext).Where("repo_id=?", repo.ID).Get(job)
+		assert.NoError(t, err)
+		assert.True(t, has)
+
+		disallowed, err := actions_service.FindDisallowedActions(db.DefaultContext, job)
+		assert.NoError(t, err)
+		if assert.Len(t, disallowed, 1) {
+			assert.Equal(t, "actions/checkout@v4", disallowed[0].Uses)
+			assert.Equal(t, "organization", disallowed[0].Scope)
+		}
+
+		// the instance policy is checked first
+		defer test.MockVariableValue(&setting.Actions.AllowedActions, "selected")()
+		defer test.MockVariableValue(&setting.Actions.AllowedActionsPatterns, []string{"owner/*"})()
+		disallowed, err = actions_service.FindDisallowedActions(db.DefaultContext, job)
+		assert.NoError(t, err)
+		if assert.Len(t, disallowed, 1) {
+			assert.Equal(t, "instance", disallowed[0].Scope)
+		}
+
+		// the reusable workflow called by a job is checked like an action
+		reusableJob := *job
+		reusableJob.WorkflowPayload = []byte("on: push\njobs:\n  deploy:\n    uses: owner/workflows/.gitea/workflows/deploy.yml@v1\n")
+		disallowed, err = actions_service.FindDisallowedActions(db.DefaultContext, &reusableJob)
+		assert.NoError(t, err)
+		if assert.Len(t, disallowed, 1) {
+			assert.Equal(t, "owner/workflows/.gitea/workflows/deploy.yml@v1", disallowed[0].Uses)
+			assert.Equal(t, "organization", disallowed[0].Scope)
+		}
+
+		assert.NoError(t, actions_service.SetOwnerAllowedActions(db.DefaultContext, org3.ID, actions_module.AllowedActionsAll, nil))
+		allowed, err = actions_service.GetOwnerAllowedActions(db.DefaultContext, org3.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, actions_module.AllowedActionsAll, allowed.Policy)
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	actions_web "code.gitea.io/gitea/routers/web/repo/actions"
+	actions_service "code.gitea.io/gitea/services/actions"
+	pull_service "code.gitea.io/gitea/services/pull"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsAnnotations(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		repo := createActionsTestRepo(t, user2, "actions-annotations", ".gitea/workflows/lint.yml",
+			"on: pull_request\njobs:\n  lint:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make lint\n")
+
+		_, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{Operation: "create", TreePath: "src/app.js", ContentReader: strings.NewReader("let a = 1\nlet b = 2\nlet c = 3\n")},
+			},
+			Message:   "add app",
+			OldBranch: repo.DefaultBranch,
+			NewBranch: "feature",
+			Author:    &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Committer: &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+		})
+		assert.NoError(t, err)
+		pullIssue := &issues_model.Issue{
+			RepoID:   repo.ID,
+			Title:    "add app",
+			PosterID: user2.ID,
+			Poster:   user2,
+			IsPull:   true,
+		}
+		pullRequest := &issues_model.PullRequest{
+			HeadRepoID: repo.ID,
+			BaseRepoID: repo.ID,
+			HeadBranch: "feature",
+			BaseBranch: repo.DefaultBranch,
+			HeadRepo:   repo,
+			BaseRepo:   repo,
+			Type:       issues_model.PullRequestGitea,
+		}
+		assert.NoError(t, pull_service.NewPullRequest(git.DefaultContext, repo, pullIssue, nil, nil, pullRequest, nil))
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Ref: pullRequest.GetGitRefName()})
+		job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "lint"})
+
+		task := &actions_model.ActionTask{
+			JobID:      job.ID,
+			Attempt:    1,
+			RunAttempt: 1,
+			Status:     actions_model.StatusFailure,
+			RepoID:     repo.ID,
+			OwnerID:    repo.OwnerID,
+			CommitSHA:  job.CommitSHA,
+			TokenHash:  "annotations",
+		}
+		assert.NoError(t, db.Insert(db.DefaultContext, task))
+		job.TaskID = task.ID
+		_, err = actions_model.UpdateRunJob(db.DefaultContext, job, nil, "task_id")
+		assert.NoError(t, err)
+
+		var rows []*runnerv1.LogRow
+		for _, line := range []string{
+			"make lint",
+			"::error file=./src/app.js,line=2,title=no-var::b is never used",
+			"::warning file=src/app.js,line=1,endLine=3::Missing semicolons",
+			"::notice::Linted 1 file",
+		} {
+			rows = append(rows, &runnerv1.LogRow{Content: line})
+		}
+		assert.NoError(t, actions_service.CreateAnnotationsFromLogs(db.DefaultContext, task, rows, 0))
+
+		runURL := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.Index)
+		req := NewRequestWithJSON(t, "POST", runURL+"/jobs/0", &actions_web.ViewRequest{})
+		req.Header.Add("X-Csrf-Token", GetCSRF(t, session, runURL))
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		view := &actions_web.ViewResponse{}
+		DecodeJSON(t, resp, view)
+		fileLink := fmt.Sprintf("/%s/%s/src/commit/%s/src/app.js", user2.Name, repo.Name, run.CommitSHA)
+		assert.Equal(t, []*actions_web.ViewAnnotation{
+			{Level: "error", Title: "no-var", Message: "b is never used", JobName: "lint", JobLink: runURL + "/jobs/0", Location: "src/app.js#L2", FileLink: fileLink + "#L2"},
+			{Level: "warning", Message: "Missing semicolons", JobName: "lint", JobLink: runURL + "/jobs/0", Location: "src/app.js#L1-L3", FileLink: fileLink + "#L1-L3"},
+			{Level: "notice", Message: "Linted 1 file", JobName: "lint", JobLink: runURL + "/jobs/0"},
+		}, view.State.Run.Annotations)
+
+		// the annotations about the lines of the diff are shown below the last lines they cover
+		resp = session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/pulls/%d/files", user2.Name, repo.Name, pullIssue.Index)), http.StatusOK)
+		messages := NewHTMLParser(t, resp.Body).doc.Find("tr.diff-annotations .diff-annotation-message").Map(func(_ int, s *goquery.Selection) string {
+			return s.Text()
+		})
+		assert.Equal(t, []string{"b is never used", "Missing semicolons"}, messages)
+
+		// a task can't create too many annotations
+		rows = rows[:0]
+		for i := 0; i < actions_model.MaxAnnotationsPerTask; i++ {
+			rows = append(rows, &runnerv1.LogRow{Content: fmt.Sprintf("::warning::warning %d", i)})
+		}
+		assert.NoError(t, actions_service.CreateAnnotationsFromLogs(db.DefaultContext, task, rows, 4))
+		count, err := actions_model.CountTaskAnnotations(db.DefaultContext, task.ID)
+		assert.NoError(t, err)
+		assert.EqualValues(t, actions_model.MaxAnnotationsPerTask, count)
+	})
+}
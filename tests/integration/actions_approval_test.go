@@ -0,0 +1,112 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsApprovalPolicy(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2}) // owner of the base repo
+		user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4}) // owner of the forked repo
+
+		baseRepo := createActionsTestRepo(t, user2, "actions-approval", ".gitea/workflows/pr.yml",
+			"name: test\non: pull_request\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo helloworld\n")
+		forkedRepo, err := repo_service.ForkRepository(git.DefaultContext, user2, user4, repo_service.ForkRepoOptions{
+			BaseRepo: baseRepo,
+			Name:     "forked-actions-approval",
+		})
+		assert.NoError(t, err)
+
+		createPullFromFork := func(branch string) *actions_model.ActionRun {
+			_, err := files_service.ChangeRepoFiles(git.DefaultContext, forkedRepo, user4, &files_service.ChangeRepoFilesOptions{
+				Files: []*files_service.ChangeRepoFile{
+					{
+						Operation:     "create",
+						TreePath:      branch + ".txt",
+						ContentReader: strings.NewReader(branch),
+					},
+				},
+				Message:   "add " + branch,
+				OldBranch: baseRepo.DefaultBranch,
+				NewBranch: branch,
+				Author:    &files_service.IdentityOptions{Name: user4.Name, Email: user4.Email},
+				Committer: &files_service.IdentityOptions{Name: user4.Name, Email: user4.Email},
+				Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+			})
+			assert.NoError(t, err)
+			pullIssue := &issues_model.Issue{
+				RepoID:   baseRepo.ID,
+				Title:    "pull request from " + branch,
+				PosterID: user4.ID,
+				Poster:   user4,
+				IsPull:   true,
+			}
+			pullRequest := &issues_model.PullRequest{
+				HeadRepoID: forkedRepo.ID,
+				BaseRepoID: baseRepo.ID,
+				HeadBranch: branch,
+				BaseBranch: baseRepo.DefaultBranch,
+				HeadRepo:   forkedRepo,
+				BaseRepo:   baseRepo,
+				Type:       issues_model.PullRequestGitea,
+			}
+			assert.NoError(t, pull_service.NewPullRequest(git.DefaultContext, baseRepo, pullIssue, nil, nil, pullRequest, nil))
+			return unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: baseRepo.ID, Ref: pullRequest.GetGitRefName()})
+		}
+
+		// the first-time contributor needs approval
+		run := createPullFromFork("branch-1")
+		assert.True(t, run.NeedApproval)
+		assert.True(t, run.IsForkPullRequest)
+
+		session := loginUser(t, user2.Name)
+		runURL := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, baseRepo.Name, run.Index)
+		req := NewRequestWithValues(t, "POST", runURL+"/approve", map[string]string{
+			"_csrf": GetCSRF(t, session, runURL),
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID})
+		assert.False(t, run.NeedApproval)
+		assert.Equal(t, user2.ID, run.ApprovedBy)
+
+		// the contributor has been approved before
+		run = createPullFromFork("branch-2")
+		assert.False(t, run.NeedApproval)
+
+		// require approval for all outside collaborators
+		settingsURL := fmt.Sprintf("/%s/%s/settings/actions/general", user2.Name, baseRepo.Name)
+		req = NewRequestWithValues(t, "POST", settingsURL, map[string]string{
+			"_csrf":           GetCSRF(t, session, settingsURL),
+			"approval_policy": string(repo_model.ActionsApprovalPolicyAllOutside),
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+		baseRepo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: baseRepo.ID})
+		cfg := baseRepo.MustGetUnit(db.DefaultContext, unit_model.TypeActions).ActionsConfig()
+		assert.Equal(t, repo_model.ActionsApprovalPolicyAllOutside, cfg.GetApprovalPolicy())
+
+		run = createPullFromFork("branch-3")
+		assert.True(t, run.NeedApproval)
+	})
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_web "code.gitea.io/gitea/routers/web/repo/actions"
+	actions_service "code.gitea.io/gitea/services/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsRunAttempts(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-attempts", ".gitea/workflows/test.yml",
+			`name: test
+on: push
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make lint
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make build
+`)
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		assert.EqualValues(t, 1, run.Attempt)
+		runner := &actions_model.ActionRunner{UUID: "attempts-runner", TokenHash: "attempts-runner", Name: "attempts-runner", RepoID: repo.ID, AgentLabels: []string{"ubuntu-latest"}}
+		assert.NoError(t, db.Insert(db.DefaultContext, runner))
+		// the runner and the usages of the tasks aren't reset with the fixtures, remove them to not affect the other tests
+		defer func() {
+			_, err := db.DeleteByID[actions_model.ActionRunner](db.DefaultContext, runner.ID)
+			assert.NoError(t, err)
+			_, err = db.DeleteByBean(db.DefaultContext, &actions_model.ActionUsage{RepoID: repo.ID})
+			assert.NoError(t, err)
+		}()
+
+		// runs all the waiting jobs with the results keyed by the job ids
+		runJobs := func(statuses map[string]actions_model.Status) {
+			for range statuses {
+				task, ok, err := actions_model.CreateTaskForRunner(db.DefaultContext, runner)
+				assert.NoError(t, err)
+				if !assert.True(t, ok) {
+					return
+				}
+				job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: task.JobID})
+				assert.NoError(t, actions_model.StopTask(db.DefaultContext, task.ID, statuses[job.JobID]))
+			}
+			assert.NoError(t, actions_service.EmitJobsIfReady(run.ID))
+		}
+		runJobs(map[string]actions_model.Status{"lint": actions_model.StatusSuccess, "build": actions_model.StatusFailure})
+		insertArtifact := func(name string, attempt, size int64) {
+			assert.NoError(t, db.Insert(db.DefaultContext, &actions_model.ActionArtifact{
+				RunID:        run.ID,
+				RunAttempt:   attempt,
+				RepoID:       repo.ID,
+				ArtifactName: name,
+				ArtifactPath: name + ".zip",
+				FileSize:     size,
+				Status:       int64(actions_model.ArtifactStatusUploadConfirmed),
+			}))
+		}
+		insertArtifact("lint-report", 1, 10)
+		insertArtifact("build-output", 1, 100)
+
+		runURL := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.Index)
+		session := loginUser(t, user2.Name)
+		req := NewRequestWithValues(t, "POST", runURL+"/rerun-failed", map[string]string{
+			"_csrf": GetCSRF(t, session, runURL),
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID})
+		assert.EqualValues(t, 2, run.Attempt)
+
+		runJobs(map[string]actions_model.Status{"build": actions_model.StatusSuccess})
+		unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{RepoID: repo.ID, RunAttempt: 2})
+		insertArtifact("build-output", 2, 200)
+
+		view := func(attempt string) *actions_web.ViewResponse {
+			req := NewRequestWithJSON(t, "POST", runURL+"/jobs/1"+attempt, &actions_web.ViewRequest{})
+			req.Header.Add("X-Csrf-Token", GetCSRF(t, session, runURL))
+			resp := session.MakeRequest(t, req, http.StatusOK)
+			view := &actions_web.ViewResponse{}
+			DecodeJSON(t, resp, view)
+			return view
+		}
+		getArtifacts := func(attempt string) map[string]int64 {
+			resp := session.MakeRequest(t, NewRequest(t, "GET", runURL+"/artifacts"+attempt), http.StatusOK)
+			artifacts := &actions_web.ArtifactsViewResponse{}
+			DecodeJSON(t, resp, artifacts)
+			sizes := make(map[string]int64, len(artifacts.Artifacts))
+			for _, artifact := range artifacts.Artifacts {
+				sizes[artifact.Name] = artifact.Size
+			}
+			return sizes
+		}
+
+		t.Run("LatestAttempt", func(t *testing.T) {
+			resp := view("")
+			assert.EqualValues(t, 2, resp.State.Run.Attempt)
+			assert.EqualValues(t, 2, resp.State.Run.LatestAttempt)
+			assert.Equal(t, "success", resp.State.Run.Status)
+			assert.True(t, resp.State.Run.CanRerun)
+			if assert.Len(t, resp.State.Run.Jobs, 2) {
+				assert.Equal(t, "success", resp.State.Run.Jobs[0].Status)
+				assert.Equal(t, "success", resp.State.Run.Jobs[1].Status)
+			}
+			assert.Equal(t, map[string]int64{"lint-report": 10, "build-output": 200}, getArtifacts(""))
+
+			resp = view("?attempt=9")
+			assert.EqualValues(t, 2, resp.State.Run.Attempt)
+		})
+
+		t.Run("PreviousAttempt", func(t *testing.T) {
+			resp := view("?attempt=1")
+			assert.EqualValues(t, 1, resp.State.Run.Attempt)
+			assert.EqualValues(t, 2, resp.State.Run.LatestAttempt)
+			assert.Equal(t, "failure", resp.State.Run.Status)
+			assert.True(t, resp.State.Run.Done)
+			assert.False(t, resp.State.Run.CanRerun)
+			assert.False(t, resp.State.Run.CanDeleteArtifact)
+			if assert.Len(t, resp.State.Run.Jobs, 2) {
+				assert.Equal(t, "success", resp.State.Run.Jobs[0].Status)
+				assert.Equal(t, "failure", resp.State.Run.Jobs[1].Status)
+				assert.False(t, resp.State.Run.Jobs[1].CanRerun)
+			}
+			assert.Equal(t, "build", resp.State.CurrentJob.Title)
+			assert.Equal(t, map[string]int64{"lint-report": 10, "build-output": 100}, getArtifacts("?attempt=1"))
+
+			resp2 := session.MakeRequest(t, NewRequest(t, "GET", runURL+"/jobs/1?attempt=1"), http.StatusOK)
+			assert.Equal(t, "1", NewHTMLParser(t, resp2.Body).doc.Find("#repo-action-view").AttrOr("data-attempt", ""))
+			resp2 = session.MakeRequest(t, NewRequest(t, "GET", runURL+"/jobs/1"), http.StatusOK)
+			assert.Equal(t, "", NewHTMLParser(t, resp2.Body).doc.Find("#repo-action-view").AttrOr("data-attempt", ""))
+		})
+	})
+}
+
+func TestActionsRerunFailedOfUnfinishedRun(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-rerun-unfinished", ".gitea/workflows/test.yml",
+			"on: push\njobs:\n  lint:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make lint\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n")
+		runner := &actions_model.ActionRunner{UUID: "rerun-unfinished-runner", TokenHash: "rerun-unfinished-runner", Name: "rerun-unfinished-runner", RepoID: repo.ID, AgentLabels: []string{"ubuntu-latest"}}
+		assert.NoError(t, db.Insert(db.DefaultContext, runner))
+		defer func() {
+			_, err := db.DeleteByID[actions_model.ActionRunner](db.DefaultContext, runner.ID)
+			assert.NoError(t, err)
+			_, err = db.DeleteByBean(db.DefaultContext, &actions_model.ActionUsage{RepoID: repo.ID})
+			assert.NoError(t, err)
+		}()
+
+		// one of the jobs has failed while the other one is still waiting
+		task, ok, err := actions_model.CreateTaskForRunner(db.DefaultContext, runner)
+		assert.NoError(t, err)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.NoError(t, actions_model.StopTask(db.DefaultContext, task.ID, actions_model.StatusFailure))
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		assert.False(t, run.Status.IsDone())
+
+		runURL := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.Index)
+		session := loginUser(t, user2.Name)
+		req := NewRequestWithValues(t, "POST", runURL+"/rerun-failed", map[string]string{
+			"_csrf": GetCSRF(t, session, runURL),
+		})
+		session.MakeRequest(t, req, http.StatusBadRequest)
+		run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID})
+		assert.EqualValues(t, 1, run.Attempt)
+	})
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowBadge(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-badge", ".gitea/workflows/test.yml",
+			`name: test
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`)
+		badgeURL := fmt.Sprintf("/%s/%s/actions/workflows/test.yml/badge.svg", user2.Name, repo.Name)
+
+		// the run is still in progress
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "test.yml"})
+		resp := MakeRequest(t, NewRequest(t, "GET", badgeURL), http.StatusOK)
+		assert.Equal(t, "image/svg+xml", resp.Header().Get("Content-Type"))
+		assert.Contains(t, resp.Body.String(), "no status")
+
+		run.Status = actions_model.StatusFailure
+		assert.NoError(t, actions_model.UpdateRun(db.DefaultContext, run, "status"))
+		resp = MakeRequest(t, NewRequest(t, "GET", badgeURL), http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "failure")
+
+		// unknown branch
+		resp = MakeRequest(t, NewRequest(t, "GET", badgeURL+"?branch=unknown"), http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "no status")
+	})
+}
@@ -0,0 +1,201 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	actions_web "code.gitea.io/gitea/routers/web/repo/actions"
+	gitea_context "code.gitea.io/gitea/services/context"
+	issue_service "code.gitea.io/gitea/services/issue"
+	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/builder"
+)
+
+func flashMessage(session *TestSession, key string) string {
+	flashCookie := session.GetCookie(gitea_context.CookieNameFlash)
+	if flashCookie == nil {
+		return ""
+	}
+	value, _ := url.QueryUnescape(flashCookie.Value)
+	values, _ := url.ParseQuery(value)
+	return values.Get(key)
+}
+
+func TestActionsCancelRuns(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-cancel-runs", ".gitea/workflows/test.yml",
+			`name: test
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make build
+`)
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		assert.Equal(t, actions_model.StatusWaiting, run.Status)
+
+		cancelURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/cancel", user2.Name, repo.Name)
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+
+		t.Run("Invalid", func(t *testing.T) {
+			MakeRequest(t, NewRequestWithJSON(t, "POST", cancelURL, &api.CancelActionRunsOption{}).
+				AddTokenAuth(token), http.StatusUnprocessableEntity)
+			MakeRequest(t, NewRequestWithJSON(t, "POST", cancelURL, &api.CancelActionRunsOption{Branch: "master", PullRequest: 1}).
+				AddTokenAuth(token), http.StatusUnprocessableEntity)
+			MakeRequest(t, NewRequestWithJSON(t, "POST", cancelURL, &api.CancelActionRunsOption{PullRequest: 999}).
+				AddTokenAuth(token), http.StatusNotFound)
+		})
+
+		t.Run("NoPermission", func(t *testing.T) {
+			user4Token := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteRepository)
+			MakeRequest(t, NewRequestWithJSON(t, "POST", cancelURL, &api.CancelActionRunsOption{Branch: "master"}).
+				AddTokenAuth(user4Token), http.StatusForbidden)
+		})
+
+		t.Run("OtherBranch", func(t *testing.T) {
+			resp := MakeRequest(t, NewRequestWithJSON(t, "POST", cancelURL, &api.CancelActionRunsOption{Branch: "develop"}).
+				AddTokenAuth(token), http.StatusOK)
+			var runs api.ActionWorkflowRunsResponse
+			DecodeJSON(t, resp, &runs)
+			assert.Empty(t, runs.Entries)
+			unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID, Status: actions_model.StatusWaiting})
+		})
+
+		t.Run("Branch", func(t *testing.T) {
+			resp := MakeRequest(t, NewRequestWithJSON(t, "POST", cancelURL, &api.CancelActionRunsOption{Branch: "master", Workflow: "test.yml"}).
+				AddTokenAuth(token), http.StatusOK)
+			var runs api.ActionWorkflowRunsResponse
+			DecodeJSON(t, resp, &runs)
+			if assert.Len(t, runs.Entries, 1) {
+				assert.Equal(t, run.ID, runs.Entries[0].ID)
+				assert.NotEqual(t, actions_model.StatusWaiting.String(), runs.Entries[0].Status)
+			}
+			run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID})
+			assert.True(t, run.Status.IsDone())
+			unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, Status: actions_model.StatusCancelled})
+		})
+
+		t.Run("Web", func(t *testing.T) {
+			session := loginUser(t, user2.Name)
+			listURL := fmt.Sprintf("/%s/%s/actions", user2.Name, repo.Name)
+			req := NewRequestWithValues(t, "POST", listURL+"/cancel", map[string]string{
+				"_csrf":        GetCSRF(t, session, listURL),
+				"workflow":     "test.yml",
+				"branch":       "master",
+				"pull_request": "1",
+			})
+			session.MakeRequest(t, req, http.StatusOK)
+			assert.Contains(t, flashMessage(session, "error"), "can't be specified at the same time")
+
+			req = NewRequestWithValues(t, "POST", listURL+"/cancel", map[string]string{
+				"_csrf":    GetCSRF(t, session, listURL),
+				"workflow": "test.yml",
+			})
+			resp := session.MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, listURL+"?workflow=test.yml", test.RedirectURL(resp))
+			assert.Equal(t, "0 runs have been cancelled.", flashMessage(session, "success"))
+		})
+	})
+}
+
+func TestActionsCancelStaleRuns(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-cancel-stale-runs", ".gitea/workflows/test.yml",
+			`name: test
+on: [push, pull_request]
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make build
+`)
+		masterRun := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Ref: "refs/heads/master"})
+
+		_, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{Operation: "create", TreePath: "feature.txt", ContentReader: strings.NewReader("feature")},
+			},
+			Message:   "add feature",
+			OldBranch: repo.DefaultBranch,
+			NewBranch: "feature",
+			Author:    &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Committer: &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+		})
+		assert.NoError(t, err)
+		branchRun := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Ref: "refs/heads/feature"})
+
+		pullIssue := &issues_model.Issue{
+			RepoID:   repo.ID,
+			Title:    "add feature",
+			PosterID: user2.ID,
+			Poster:   user2,
+			IsPull:   true,
+		}
+		pullRequest := &issues_model.PullRequest{
+			HeadRepoID: repo.ID,
+			BaseRepoID: repo.ID,
+			HeadBranch: "feature",
+			BaseBranch: repo.DefaultBranch,
+			HeadRepo:   repo,
+			BaseRepo:   repo,
+			Type:       issues_model.PullRequestGitea,
+		}
+		assert.NoError(t, pull_service.NewPullRequest(git.DefaultContext, repo, pullIssue, nil, nil, pullRequest, nil))
+		pullRun := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Ref: pullRequest.GetGitRefName()})
+
+		// closing the pull request cancels its runs
+		assert.NoError(t, issue_service.ChangeStatus(db.DefaultContext, pullIssue, user2, "", true))
+		unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: pullRun.ID, Status: actions_model.StatusCancelled, CancelReason: actions_model.RunCancelReasonPullRequestClosed})
+		unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: branchRun.ID, Status: actions_model.StatusWaiting})
+
+		// deleting the branch cancels its runs
+		gitRepo, err := gitrepo.OpenRepository(git.DefaultContext, repo)
+		assert.NoError(t, err)
+		defer gitRepo.Close()
+		assert.NoError(t, repo_service.DeleteBranch(db.DefaultContext, user2, repo, gitRepo, "feature"))
+		unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: branchRun.ID, Status: actions_model.StatusCancelled, CancelReason: actions_model.RunCancelReasonBranchDeleted})
+		unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: masterRun.ID, Status: actions_model.StatusWaiting}, builder.Eq{"cancel_reason": ""})
+
+		// the reason is shown on the run view and returned by the API
+		session := loginUser(t, user2.Name)
+		runURL := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, repo.Name, branchRun.Index)
+		req := NewRequestWithJSON(t, "POST", runURL+"/jobs/0", &actions_web.ViewRequest{})
+		req.Header.Add("X-Csrf-Token", GetCSRF(t, session, runURL))
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		view := &actions_web.ViewResponse{}
+		DecodeJSON(t, resp, view)
+		assert.Equal(t, "This run was cancelled because its branch was deleted.", view.State.Run.CancelReason)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeReadRepository)
+		resp = MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", user2.Name, repo.Name, pullRun.ID)).
+			AddTokenAuth(token), http.StatusOK)
+		apiRun := &api.ActionWorkflowRun{}
+		DecodeJSON(t, resp, apiRun)
+		assert.Equal(t, "pull_request_closed", apiRun.CancelReason)
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsCommentCommands(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-comment-commands", ".gitea/workflows/retest.yml",
+			`name: retest
+on:
+  issue_comment:
+    types: [created]
+    commands: [/retest]
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make test
+`)
+		ownerToken := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteIssue)
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/issues", repo.FullName()), &api.CreateIssueOption{
+			Title: "flaky tests",
+		}).AddTokenAuth(ownerToken)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		issue := &api.Issue{}
+		DecodeJSON(t, resp, issue)
+		comment := func(token, body string) {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/issues/%d/comments", repo.FullName(), issue.Index), &api.CreateIssueCommentOption{
+				Body: body,
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusCreated)
+		}
+
+		// not a command
+		comment(ownerToken, "the tests are flaky, /retest")
+		unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+
+		// the commenter can't write the actions of the repository
+		comment(getUserToken(t, "user4", auth_model.AccessTokenScopeWriteIssue), "/retest")
+		unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+
+		comment(ownerToken, "/retest please")
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		assert.Equal(t, actions_module.GithubEventIssueComment, run.TriggerEvent)
+		assert.Equal(t, user2.ID, run.TriggerUserID)
+	})
+}
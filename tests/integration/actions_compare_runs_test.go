@@ -0,0 +1,89 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/timeutil"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsCompareRuns(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		workflow := "on: push\njobs:\n  build:\n    runs-on: ubuntu-22.04\n    steps:\n      - run: make build\n"
+		repo := createActionsTestRepo(t, user2, "actions-compare-runs", ".gitea/workflows/compare.yml", workflow)
+
+		_, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "update",
+					TreePath:      ".gitea/workflows/compare.yml",
+					ContentReader: strings.NewReader(strings.Replace(workflow, "ubuntu-22.04", "ubuntu-24.04", 1)),
+				},
+			},
+			Message:   "upgrade runner",
+			OldBranch: "master",
+			NewBranch: "master",
+			Author:    &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Committer: &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+		})
+		assert.NoError(t, err)
+
+		baseRun := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Index: 1})
+		headRun := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Index: 2})
+		setJobResult := func(run *actions_model.ActionRun, status actions_model.Status, seconds timeutil.TimeStamp) {
+			_, err := db.GetEngine(db.DefaultContext).Where("run_id = ?", run.ID).Cols("status", "started", "stopped").
+				Update(&actions_model.ActionRunJob{Status: status, Started: 1000, Stopped: 1000 + seconds})
+			assert.NoError(t, err)
+		}
+		setJobResult(baseRun, actions_model.StatusSuccess, 60)
+		setJobResult(headRun, actions_model.StatusFailure, 90)
+
+		session := loginUser(t, user2.Name)
+		compareURL := fmt.Sprintf("/%s/%s/actions/runs/%d/compare", user2.Name, repo.Name, headRun.Index)
+
+		t.Run("CompareWithPrevious", func(t *testing.T) {
+			resp := session.MakeRequest(t, NewRequest(t, "GET", compareURL), http.StatusOK)
+			htmlDoc := NewHTMLParser(t, resp.Body)
+			jobs := htmlDoc.Find(".run-compare-jobs tbody").Text()
+			assert.Contains(t, jobs, "build")
+			assert.Contains(t, jobs, "newly failing")
+			assert.Contains(t, jobs, "+30s")
+			assert.Equal(t, "-     runs-on: ubuntu-22.04", htmlDoc.Find(".run-compare-diff-del").Text())
+			assert.Equal(t, "+     runs-on: ubuntu-24.04", htmlDoc.Find(".run-compare-diff-add").Text())
+		})
+
+		t.Run("CompareWithBase", func(t *testing.T) {
+			resp := session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%d/compare?base=%d", user2.Name, repo.Name, baseRun.Index, headRun.Index)), http.StatusOK)
+			htmlDoc := NewHTMLParser(t, resp.Body)
+			assert.Contains(t, htmlDoc.Find(".run-compare-jobs tbody").Text(), "fixed")
+		})
+
+		t.Run("NoPreviousRun", func(t *testing.T) {
+			resp := session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%d/compare", user2.Name, repo.Name, baseRun.Index)), http.StatusOK)
+			htmlDoc := NewHTMLParser(t, resp.Body)
+			assert.Zero(t, htmlDoc.Find(".run-compare-jobs").Length())
+			assert.Contains(t, htmlDoc.Find(".run-compare").Text(), "There is no previous run")
+		})
+
+		t.Run("NotExist", func(t *testing.T) {
+			session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%d/compare", user2.Name, repo.Name, 100)), http.StatusNotFound)
+		})
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsConcurrency(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-concurrency", ".gitea/workflows/deploy.yml",
+			`name: deploy
+on:
+  workflow_dispatch:
+    inputs:
+      cancel:
+        type: boolean
+concurrency:
+  group: deploy-${{ github.ref_name }}
+  cancel-in-progress: ${{ github.event.inputs.cancel == 'true' }}
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo deploy
+`)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		dispatch := func(cancel string) *actions_model.ActionRun {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/deploy.yml/dispatches", user2.Name, repo.Name), &api.CreateActionWorkflowDispatch{
+				Ref:    "master",
+				Inputs: map[string]string{"cancel": cancel},
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			runs, err := db.Find[actions_model.ActionRun](db.DefaultContext, actions_model.FindRunOptions{RepoID: repo.ID})
+			assert.NoError(t, err)
+			return runs[0]
+		}
+		getRunJob := func(runID int64) *actions_model.ActionRunJob {
+			return unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: runID})
+		}
+
+		run1 := dispatch("false")
+		assert.Equal(t, "deploy-master", run1.ConcurrencyGroup)
+		assert.False(t, run1.ConcurrencyCancel)
+		assert.Equal(t, actions_model.StatusWaiting, getRunJob(run1.ID).Status)
+
+		// the second run waits for the first one
+		run2 := dispatch("false")
+		assert.Equal(t, actions_model.StatusBlocked, run2.Status)
+		assert.Equal(t, actions_model.StatusBlocked, getRunJob(run2.ID).Status)
+
+		// the third run replaces the pending second one
+		run3 := dispatch("false")
+		assert.Equal(t, actions_model.StatusBlocked, run3.Status)
+		assert.Equal(t, actions_model.StatusCancelled, getRunJob(run2.ID).Status)
+		assert.Equal(t, actions_model.StatusWaiting, getRunJob(run1.ID).Status)
+
+		// the fourth run cancels all the others
+		run4 := dispatch("true")
+		assert.True(t, run4.ConcurrencyCancel)
+		assert.Equal(t, actions_model.StatusWaiting, getRunJob(run4.ID).Status)
+		assert.Equal(t, actions_model.StatusCancelled, getRunJob(run1.ID).Status)
+		assert.Equal(t, actions_model.StatusCancelled, getRunJob(run3.ID).Status)
+	})
+}
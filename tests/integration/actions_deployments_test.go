@@ -0,0 +1,97 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_service "code.gitea.io/gitea/services/actions"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsDeployments(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-deployments", ".gitea/workflows/deploy.yml",
+			`name: deploy
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make build
+  staging:
+    needs: build
+    runs-on: ubuntu-latest
+    environment: staging
+    steps:
+      - run: make deploy
+  production:
+    needs: staging
+    runs-on: ubuntu-latest
+    environment:
+      name: production
+      url: https://example.com
+    steps:
+      - run: make deploy
+`)
+		_, err := actions_service.CreateEnvironment(db.DefaultContext, repo.ID, "qa")
+		assert.NoError(t, err)
+
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		staging := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "staging"})
+		assert.Equal(t, "staging", staging.Environment)
+		staging.Status = actions_model.StatusSuccess
+		staging.Stopped = staging.Updated + 10
+		_, err = actions_model.UpdateRunJob(db.DefaultContext, staging, nil, "status", "stopped")
+		assert.NoError(t, err)
+
+		deploymentsURL := fmt.Sprintf("/%s/%s/deployments", user2.Name, repo.Name)
+		session := loginUser(t, user2.Name)
+
+		getItems := func(doc *HTMLDoc, segment int) []string {
+			var items []string
+			doc.doc.Find(".deployments .attached.segment").Eq(segment).Find(".flex-item").Each(func(_ int, s *goquery.Selection) {
+				items = append(items, strings.Join(strings.Fields(s.Find(".flex-item-title, .flex-item-body").Text()), " "))
+			})
+			return items
+		}
+
+		resp := session.MakeRequest(t, NewRequest(t, "GET", deploymentsURL), http.StatusOK)
+		doc := NewHTMLParser(t, resp.Body)
+		environments := getItems(doc, 0)
+		if assert.Len(t, environments, 3) {
+			assert.True(t, strings.HasPrefix(environments[0], "production Not deployed yet"), environments[0])
+			assert.True(t, strings.HasPrefix(environments[1], "qa Not deployed yet"), environments[1])
+			assert.True(t, strings.HasPrefix(environments[2], "staging Commit "+run.CommitSHA[:10]+" was deployed by the run"), environments[2])
+		}
+		assert.Equal(t, fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.Index), doc.doc.Find(".deployments .attached.segment").Eq(0).Find(".flex-item-body a").AttrOr("href", ""))
+		assert.Len(t, getItems(doc, 1), 2)
+
+		resp = session.MakeRequest(t, NewRequest(t, "GET", deploymentsURL+"?environment=staging"), http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		history := getItems(doc, 1)
+		if assert.Len(t, history, 1) {
+			assert.Contains(t, history[0], `job "staging" deploying to "staging"`)
+		}
+
+		resp = session.MakeRequest(t, NewRequest(t, "GET", deploymentsURL+"?environment=qa"), http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		assert.Empty(t, getItems(doc, 1))
+
+		// the deployments are visible to the users who can read the actions
+		MakeRequest(t, NewRequest(t, "GET", deploymentsURL), http.StatusOK)
+		MakeRequest(t, NewRequest(t, "GET", "/user2/repo2/deployments"), http.StatusNotFound)
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsDispatchInputs(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-dispatch-inputs", ".gitea/workflows/deploy.yml",
+			`name: deploy
+on:
+  workflow_dispatch:
+    inputs:
+      target:
+        description: Target environment
+        type: environment
+        required: true
+      retries:
+        type: number
+        default: "3"
+      level:
+        type: choice
+        options: [info, debug]
+        default: info
+      dry-run:
+        type: boolean
+      version:
+        required: true
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo deploy
+`)
+		_, err := actions_model.InsertEnvironment(db.DefaultContext, repo.ID, "staging")
+		assert.NoError(t, err)
+
+		session := loginUser(t, user2.Name)
+		listURL := fmt.Sprintf("/%s/%s/actions?workflow=deploy.yml", user2.Name, repo.Name)
+		resp := session.MakeRequest(t, NewRequest(t, "GET", listURL), http.StatusOK)
+		form := NewHTMLParser(t, resp.Body).doc.Find("#runWorkflowDispatchForm")
+		assert.Equal(t, "staging", form.Find(`select[name="target"] option`).AttrOr("value", ""))
+		assert.Equal(t, "number", form.Find(`input[name="retries"]`).AttrOr("type", ""))
+		assert.Equal(t, 2, form.Find(`select[name="level"] option`).Length())
+		assert.Equal(t, "checkbox", form.Find(`input[name="dry-run"]`).AttrOr("type", ""))
+
+		dispatch := func(values map[string]string) {
+			values["_csrf"] = GetCSRF(t, session, listURL)
+			values["ref"] = "refs/heads/master"
+			req := NewRequestWithValues(t, "POST", fmt.Sprintf("/%s/%s/actions/run?workflow=deploy.yml", user2.Name, repo.Name), values)
+			session.MakeRequest(t, req, http.StatusSeeOther)
+		}
+
+		dispatch(map[string]string{"target": "staging"})
+		assert.Equal(t, `The input "version" is required.`, flashMessage(session, "error"))
+		dispatch(map[string]string{"target": "production", "version": "v1"})
+		assert.Equal(t, `The input "target" must be an environment of the repository.`, flashMessage(session, "error"))
+		dispatch(map[string]string{"target": "staging", "version": "v1", "retries": "many"})
+		assert.Equal(t, `The input "retries" must be a number.`, flashMessage(session, "error"))
+		dispatch(map[string]string{"target": "staging", "version": "v1", "level": "trace"})
+		assert.Equal(t, `The input "level" must be one of: info, debug.`, flashMessage(session, "error"))
+		unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+
+		dispatch(map[string]string{"target": "staging", "version": "v1", "dry-run": "on"})
+		assert.Empty(t, flashMessage(session, "error"))
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		payload := &api.WorkflowDispatchPayload{}
+		assert.NoError(t, json.Unmarshal([]byte(run.EventPayload), payload))
+		assert.Equal(t, map[string]any{
+			"target":  "staging",
+			"retries": "3",
+			"level":   "info",
+			"dry-run": "true",
+			"version": "v1",
+		}, payload.Inputs)
+	})
+}
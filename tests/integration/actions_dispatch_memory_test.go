@@ -0,0 +1,78 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsDispatchMemory(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-dispatch-memory", ".gitea/workflows/deploy.yml",
+			`name: deploy
+on:
+  workflow_dispatch:
+    default-ref: release
+    inputs:
+      version:
+        default: latest
+      level:
+        type: choice
+        options: [info, debug]
+        default: info
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo deploy
+`)
+		session := loginUser(t, user2.Name)
+		testAPICreateBranch(t, session, user2.Name, repo.Name, "master", "release", http.StatusCreated)
+		testAPICreateBranch(t, session, user2.Name, repo.Name, "master", "feature", http.StatusCreated)
+
+		listURL := fmt.Sprintf("/%s/%s/actions?workflow=deploy.yml", user2.Name, repo.Name)
+		getForm := func() *goquery.Selection {
+			resp := session.MakeRequest(t, NewRequest(t, "GET", listURL), http.StatusOK)
+			return NewHTMLParser(t, resp.Body).doc.Find("#runWorkflowDispatchForm")
+		}
+
+		// the default ref of the workflow is selected before the first dispatch
+		form := getForm()
+		assert.Equal(t, "refs/heads/release", form.Find(`input[name="ref"]`).AttrOr("value", ""))
+		assert.Equal(t, "latest", form.Find(`input[name="version"]`).AttrOr("value", ""))
+		assert.Equal(t, "info", form.Find(`select[name="level"] option[selected]`).AttrOr("value", ""))
+
+		req := NewRequestWithValues(t, "POST", fmt.Sprintf("/%s/%s/actions/run?workflow=deploy.yml", user2.Name, repo.Name), map[string]string{
+			"_csrf":   GetCSRF(t, session, listURL),
+			"ref":     "refs/heads/feature",
+			"version": "v1.2",
+			"level":   "debug",
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+		assert.Empty(t, flashMessage(session, "error"))
+
+		// the ref and the inputs of the last dispatch are pre-filled, and the recently used branch is on the top
+		form = getForm()
+		assert.Equal(t, "refs/heads/feature", form.Find(`input[name="ref"]`).AttrOr("value", ""))
+		assert.Equal(t, "feature", form.Find("#branch-list .item").First().AttrOr("title", ""))
+		assert.Equal(t, "v1.2", form.Find(`input[name="version"]`).AttrOr("value", ""))
+		assert.Equal(t, "debug", form.Find(`select[name="level"] option[selected]`).AttrOr("value", ""))
+
+		// the memory is per user
+		session = loginUser(t, "user1")
+		form = getForm()
+		assert.Equal(t, "refs/heads/release", form.Find(`input[name="ref"]`).AttrOr("value", ""))
+		assert.Equal(t, "latest", form.Find(`input[name="version"]`).AttrOr("value", ""))
+	})
+}
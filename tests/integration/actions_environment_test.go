@@ -0,0 +1,163 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	secret_model "code.gitea.io/gitea/models/secret"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	actions_web "code.gitea.io/gitea/routers/web/repo/actions"
+	repo_service "code.gitea.io/gitea/services/repository"
+	secret_service "code.gitea.io/gitea/services/secrets"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsEnvironment(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-environment", ".gitea/workflows/deploy.yml",
+			`name: deploy
+on:
+  workflow_dispatch:
+jobs:
+  staging:
+    runs-on: ubuntu-latest
+    environment: staging
+    steps:
+      - run: echo staging
+  production:
+    runs-on: ubuntu-latest
+    environment:
+      name: production
+      url: https://example.com
+    steps:
+      - run: echo production
+`)
+		session := loginUser(t, user2.Name)
+		settingsURL := fmt.Sprintf("/%s/%s/settings/actions/environments", user2.Name, repo.Name)
+
+		// create a protected environment with a secret
+		req := NewRequestWithValues(t, "POST", settingsURL, map[string]string{
+			"_csrf": GetCSRF(t, session, settingsURL),
+			"name":  "production",
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		env := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionEnvironment{RepoID: repo.ID, Name: "production"})
+		envURL := fmt.Sprintf("%s/%d", settingsURL, env.ID)
+
+		req = NewRequestWithValues(t, "POST", envURL+"/reviewers", map[string]string{
+			"_csrf":     GetCSRF(t, session, envURL),
+			"reviewers": "user2, user4",
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+		env = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionEnvironment{ID: env.ID})
+		assert.Equal(t, []int64{2, 4}, env.ReviewerIDs)
+
+		req = NewRequestWithValues(t, "POST", envURL+"/secrets", map[string]string{
+			"_csrf": GetCSRF(t, session, envURL),
+			"name":  "TOKEN",
+			"data":  "production-token",
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		_, _, err := secret_service.CreateOrUpdateSecret(db.DefaultContext, 0, repo.ID, 0, "TOKEN", "repo-token")
+		assert.NoError(t, err)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/deploy.yml/dispatches", user2.Name, repo.Name), &api.CreateActionWorkflowDispatch{
+			Ref: "master",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		stagingJob := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "staging"})
+		productionJob := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "production"})
+		assert.Equal(t, "staging", stagingJob.Environment)
+		assert.Equal(t, "production", productionJob.Environment)
+
+		// the environment without reviewers doesn't need approval
+		assert.Eventually(t, func() bool {
+			return unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: stagingJob.ID}).Status == actions_model.StatusWaiting
+		}, 5*time.Second, 100*time.Millisecond)
+		assert.Equal(t, actions_model.StatusBlocked, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: productionJob.ID}).Status)
+
+		// the environment secrets take precedence over the repository secrets
+		assert.NoError(t, productionJob.LoadAttributes(db.DefaultContext))
+		secrets, err := secret_model.GetSecretsOfTask(db.DefaultContext, &actions_model.ActionTask{Job: productionJob})
+		assert.NoError(t, err)
+		assert.Equal(t, "production-token", secrets["TOKEN"])
+		assert.NoError(t, stagingJob.LoadAttributes(db.DefaultContext))
+		secrets, err = secret_model.GetSecretsOfTask(db.DefaultContext, &actions_model.ActionTask{Job: stagingJob})
+		assert.NoError(t, err)
+		assert.Equal(t, "repo-token", secrets["TOKEN"])
+
+		runURL := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.Index)
+		req = NewRequestWithJSON(t, "POST", runURL+"/jobs/1", &actions_web.ViewRequest{})
+		req.Header.Add("X-Csrf-Token", GetCSRF(t, session, runURL))
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		view := &actions_web.ViewResponse{}
+		DecodeJSON(t, resp, view)
+		if assert.Len(t, view.State.Run.PendingDeployments, 1) {
+			assert.Equal(t, "production", view.State.Run.PendingDeployments[0].Environment)
+			assert.Equal(t, []string{"production"}, view.State.Run.PendingDeployments[0].Jobs)
+			assert.True(t, view.State.Run.PendingDeployments[0].CanReview)
+		}
+
+		// only the reviewers could approve
+		session5 := loginUser(t, "user5")
+		req = NewRequestWithValues(t, "POST", runURL+"/deployments/approve", map[string]string{
+			"_csrf":       GetCSRF(t, session5, "/user/settings"),
+			"environment": "production",
+		})
+		session5.MakeRequest(t, req, http.StatusForbidden)
+
+		// the anonymous users are asked to sign in
+		req = NewRequestWithValues(t, "POST", runURL+"/deployments/approve", map[string]string{
+			"environment": "production",
+		})
+		MakeRequest(t, req, http.StatusSeeOther)
+
+		req = NewRequestWithValues(t, "POST", runURL+"/deployments/approve", map[string]string{
+			"_csrf":       GetCSRF(t, session, runURL),
+			"environment": "production",
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		productionJob = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: productionJob.ID})
+		assert.Equal(t, user2.ID, productionJob.EnvironmentApprovedBy)
+		assert.Eventually(t, func() bool {
+			return unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: productionJob.ID}).Status == actions_model.StatusWaiting
+		}, 5*time.Second, 100*time.Millisecond)
+
+		// nothing is waiting for the approval anymore
+		req = NewRequestWithValues(t, "POST", runURL+"/deployments/reject", map[string]string{
+			"_csrf":       GetCSRF(t, session, runURL),
+			"environment": "production",
+		})
+		session.MakeRequest(t, req, http.StatusNotFound)
+
+		// deleting the environment deletes its secrets
+		req = NewRequestWithValues(t, "POST", envURL+"/delete", map[string]string{
+			"_csrf": GetCSRF(t, session, envURL),
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		unittest.AssertNotExistsBean(t, &actions_model.ActionEnvironment{ID: env.ID})
+		unittest.AssertNotExistsBean(t, &secret_model.Secret{EnvironmentID: env.ID})
+
+		// the environments are deleted with the repository
+		_, err = actions_model.InsertEnvironment(db.DefaultContext, repo.ID, "qa")
+		assert.NoError(t, err)
+		assert.NoError(t, repo_service.DeleteRepositoryDirectly(db.DefaultContext, user2, repo.ID))
+		unittest.AssertNotExistsBean(t, &actions_model.ActionEnvironment{RepoID: repo.ID})
+	})
+}
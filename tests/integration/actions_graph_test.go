@@ -0,0 +1,66 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_web "code.gitea.io/gitea/routers/web/repo/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsJobGraph(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-graph", ".gitea/workflows/test.yml",
+			`name: test
+on: push
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make lint
+  build:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        os: [linux, windows]
+    steps:
+      - run: make build
+  release:
+    needs: [lint, build]
+    runs-on: ubuntu-latest
+    steps:
+      - run: make release
+`)
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%d/graph", user2.Name, repo.Name, run.Index))
+		resp := MakeRequest(t, req, http.StatusOK)
+		graph := &actions_web.GraphViewResponse{}
+		DecodeJSON(t, resp, graph)
+
+		assert.Equal(t, []*actions_web.GraphViewNode{
+			{Index: 0, JobID: "lint", Name: "lint", Status: "waiting", Level: 0},
+			{Index: 1, JobID: "build", Name: "build (linux)", Status: "waiting", Level: 0},
+			{Index: 2, JobID: "build", Name: "build (windows)", Status: "waiting", Level: 0},
+			{Index: 3, JobID: "release", Name: "release", Status: "blocked", Level: 1},
+		}, graph.Nodes)
+		assert.Equal(t, []*actions_web.GraphViewEdge{
+			{From: 0, To: 3},
+			{From: 1, To: 3},
+			{From: 2, To: 3},
+		}, graph.Edges)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%d/graph", user2.Name, repo.Name, 999))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+	actions_service "code.gitea.io/gitea/services/actions"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsLogColdStorage(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		coldStorage, err := storage.NewLocalStorage(context.Background(), &setting.Storage{Path: t.TempDir()})
+		assert.NoError(t, err)
+		defer test.MockVariableValue(&storage.ActionsLogCold, coldStorage)()
+		defer test.MockVariableValue(&setting.Actions.LogColdStorageDays, 30)()
+
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-log-cold-storage", ".gitea/workflows/build.yml",
+			"on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n")
+		client := newTestRunnerClient(t, u, "log-cold-storage-runner", repo.ID)
+		fetchResp, err := client.FetchTask(context.Background(), connect.NewRequest(&runnerv1.FetchTaskRequest{}))
+		assert.NoError(t, err)
+		if !assert.NotNil(t, fetchResp.Msg.Task) {
+			return
+		}
+		taskID := fetchResp.Msg.Task.Id
+		_, err = client.UpdateLog(context.Background(), connect.NewRequest(&runnerv1.UpdateLogRequest{
+			TaskId: taskID,
+			Rows:   []*runnerv1.LogRow{{Content: "building"}, {Content: "built"}},
+			NoMore: true,
+		}))
+		assert.NoError(t, err)
+		_, err = client.UpdateTask(context.Background(), connect.NewRequest(&runnerv1.UpdateTaskRequest{
+			State: &runnerv1.TaskState{Id: taskID, Result: runnerv1.Result_RESULT_SUCCESS},
+		}))
+		assert.NoError(t, err)
+
+		task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: taskID})
+		assert.True(t, task.LogInStorage)
+		filename := task.LogFilename
+
+		// the logs of the recent tasks are kept in the storage
+		assert.NoError(t, actions_service.MoveLogsToColdStorage(db.DefaultContext))
+		task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: taskID})
+		assert.False(t, task.LogInColdStorage)
+
+		task.Stopped = timeutil.TimeStampNow().AddDuration(-31 * 24 * time.Hour)
+		assert.NoError(t, actions_model.UpdateTask(db.DefaultContext, task, "stopped"))
+		assert.NoError(t, actions_service.MoveLogsToColdStorage(db.DefaultContext))
+		task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: taskID})
+		assert.True(t, task.LogInColdStorage)
+		assert.True(t, strings.HasSuffix(task.LogFilename, ".zst"))
+		_, err = storage.Actions.Stat(filename)
+		assert.Error(t, err)
+		_, err = coldStorage.Stat(task.LogFilename)
+		assert.NoError(t, err)
+
+		// the logs are moved back when they are viewed
+		session := loginUser(t, user2.Name)
+		resp := session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/1/jobs/0/logs", user2.Name, repo.Name)), http.StatusOK)
+		logs := resp.Body.String()
+		assert.Contains(t, logs, "building\n")
+		assert.Contains(t, logs, "built\n")
+
+		task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: taskID})
+		assert.False(t, task.LogInColdStorage)
+		assert.NotZero(t, task.LogRehydrated)
+		_, err = coldStorage.Stat(task.LogFilename)
+		assert.Error(t, err)
+
+		// the rehydrated logs aren't moved again until they are old enough since the rehydration
+		assert.NoError(t, actions_service.MoveLogsToColdStorage(db.DefaultContext))
+		task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: taskID})
+		assert.False(t, task.LogInColdStorage)
+
+		// the logs in the cold storage are removed with the run
+		assert.NoError(t, actions_model.UpdateTask(db.DefaultContext, &actions_model.ActionTask{ID: taskID}, "log_rehydrated"))
+		assert.NoError(t, actions_service.MoveLogsToColdStorage(db.DefaultContext))
+		task = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: taskID})
+		assert.True(t, task.LogInColdStorage)
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Index: 1})
+		assert.NoError(t, actions_service.DeleteRun(db.DefaultContext, run))
+		_, err = coldStorage.Stat(task.LogFilename)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	secret_service "code.gitea.io/gitea/services/secrets"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"code.gitea.io/actions-proto-go/runner/v1/runnerv1connect"
+	"connectrpc.com/connect"
+	gouuid "github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsLogMask(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-log-mask", ".gitea/workflows/deploy.yml",
+			"on: push\njobs:\n  deploy:\n    runs-on: ubuntu-latest\n    steps:\n      - run: ./deploy.sh\n")
+		_, _, err := secret_service.CreateOrUpdateSecret(db.DefaultContext, 0, repo.ID, 0, "DEPLOY_KEY", "s3cr3t-deploy-key")
+		assert.NoError(t, err)
+
+		runner := &actions_model.ActionRunner{
+			UUID:        gouuid.New().String(),
+			Name:        "log-mask-runner",
+			RepoID:      repo.ID,
+			AgentLabels: []string{"ubuntu-latest"},
+		}
+		assert.NoError(t, runner.GenerateToken())
+		assert.NoError(t, actions_model.CreateRunner(db.DefaultContext, runner))
+		// the runners aren't reset with the fixtures, remove it to not affect the other tests
+		defer func() {
+			assert.NoError(t, actions_model.DeleteRunner(db.DefaultContext, runner.ID))
+		}()
+		client := runnerv1connect.NewRunnerServiceClient(http.DefaultClient, u.String()+"api/actions",
+			connect.WithInterceptors(connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+				return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+					req.Header().Set("x-runner-uuid", runner.UUID)
+					req.Header().Set("x-runner-token", runner.Token)
+					return next(ctx, req)
+				}
+			})))
+		fetchResp, err := client.FetchTask(context.Background(), connect.NewRequest(&runnerv1.FetchTaskRequest{}))
+		assert.NoError(t, err)
+		task := fetchResp.Msg.Task
+		if !assert.NotNil(t, task) {
+			return
+		}
+		assert.Equal(t, "s3cr3t-deploy-key", task.Secrets["DEPLOY_KEY"])
+
+		updateLog := func(index int64, lines ...string) {
+			rows := make([]*runnerv1.LogRow, 0, len(lines))
+			for _, line := range lines {
+				rows = append(rows, &runnerv1.LogRow{Content: line})
+			}
+			_, err := client.UpdateLog(context.Background(), connect.NewRequest(&runnerv1.UpdateLogRequest{
+				TaskId: task.Id,
+				Index:  index,
+				Rows:   rows,
+			}))
+			assert.NoError(t, err)
+		}
+		updateLog(0,
+			"deploying with s3cr3t-deploy-key",
+			"::add-mask::generated-password",
+			"user: admin, password: generated-password",
+		)
+		// the values added by the commands are masked in the following chunks too
+		updateLog(3, "login again with generated-password")
+
+		dbTask := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: task.Id})
+		assert.NotContains(t, dbTask.LogMasks, "generated-password")
+
+		session := loginUser(t, user2.Name)
+		resp := session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/1/jobs/0/logs", user2.Name, repo.Name)), http.StatusOK)
+		logs := resp.Body.String()
+		assert.Contains(t, logs, "deploying with ***")
+		assert.Contains(t, logs, "::add-mask::***")
+		assert.Contains(t, logs, "user: admin, password: ***")
+		assert.Contains(t, logs, "login again with ***")
+		assert.NotContains(t, logs, "s3cr3t-deploy-key")
+		assert.NotContains(t, logs, "generated-password")
+	})
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	actions_web "code.gitea.io/gitea/routers/web/repo/actions"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestActionsLogSearch(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-log-search", ".gitea/workflows/test.yml",
+			`name: test
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make build
+      - run: make test
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make lint
+`)
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		jobs, err := actions_model.GetRunJobsByRunID(db.DefaultContext, run.ID)
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 2)
+		searchURL := fmt.Sprintf("/%s/%s/actions/runs/%d/jobs/%%d/logs/search?q=%%s", user2.Name, repo.Name, run.Index)
+
+		// the logs are "Set up job", "make build" with 2 lines, "make test" with 2 lines, and "Complete job"
+		lines := []string{"set up", "building", "error: oops", "ERROR again", "done", "cleaning up the errors"}
+		rows := make([]*runnerv1.LogRow, 0, len(lines))
+		for _, line := range lines {
+			rows = append(rows, &runnerv1.LogRow{Time: timestamppb.New(time.Now()), Content: line})
+		}
+		task := &actions_model.ActionTask{
+			JobID:       jobs[0].ID,
+			Attempt:     1,
+			RunAttempt:  1,
+			Status:      actions_model.StatusFailure,
+			Started:     100,
+			Stopped:     200,
+			RepoID:      repo.ID,
+			OwnerID:     repo.OwnerID,
+			TokenHash:   "log-search",
+			LogFilename: fmt.Sprintf("%s/log-search.log", repo.FullName()),
+			LogLength:   int64(len(rows)),
+		}
+		ns, err := actions_module.WriteLogs(db.DefaultContext, task.LogFilename, 0, rows)
+		assert.NoError(t, err)
+		for _, n := range ns {
+			task.LogIndexes = append(task.LogIndexes, task.LogSize)
+			task.LogSize += int64(n)
+		}
+		assert.NoError(t, db.Insert(db.DefaultContext, task))
+		for i, step := range []*actions_model.ActionTaskStep{
+			{Name: "make build", Status: actions_model.StatusSuccess, Started: 110, Stopped: 150, LogIndex: 1, LogLength: 2},
+			{Name: "make test", Status: actions_model.StatusFailure, Started: 150, Stopped: 190, LogIndex: 3, LogLength: 2},
+		} {
+			step.TaskID, step.Index, step.RepoID = task.ID, int64(i), repo.ID
+			assert.NoError(t, db.Insert(db.DefaultContext, step))
+		}
+		jobs[0].TaskID = task.ID
+		_, err = actions_model.UpdateRunJob(db.DefaultContext, jobs[0], nil, "task_id")
+		assert.NoError(t, err)
+
+		search := func(keyword string) *actions_web.LogSearchResponse {
+			resp := MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf(searchURL, 0, url.QueryEscape(keyword))), http.StatusOK)
+			result := &actions_web.LogSearchResponse{}
+			DecodeJSON(t, resp, result)
+			return result
+		}
+		assert.Equal(t, &actions_web.LogSearchResponse{
+			Matches: []*actions_web.LogSearchMatch{
+				{Step: 1, Line: 2},
+				{Step: 2, Line: 1},
+				{Step: 3, Line: 1},
+			},
+		}, search("Error"))
+		assert.Equal(t, &actions_web.LogSearchResponse{
+			Matches: []*actions_web.LogSearchMatch{{Step: 0, Line: 1}},
+		}, search("set up"))
+		assert.Empty(t, search("warning").Matches)
+		assert.Empty(t, search("").Matches)
+
+		// the job hasn't been started
+		MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf(searchURL, 1, "error")), http.StatusNotFound)
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_web "code.gitea.io/gitea/routers/web/repo/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsMatrixJobs(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-matrix", ".gitea/workflows/test.yml",
+			`name: test
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        os: [linux, windows]
+    steps:
+      - run: echo ${{ matrix.os }}
+  release:
+    needs: build
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo release
+`)
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		session := loginUser(t, user2.Name)
+		runURL := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.Index)
+
+		// each combination of the matrix is a separate job with its matrix values
+		req := NewRequestWithJSON(t, "POST", runURL+"/jobs/0", &actions_web.ViewRequest{})
+		req.Header.Add("X-Csrf-Token", GetCSRF(t, session, runURL))
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		view := &actions_web.ViewResponse{}
+		DecodeJSON(t, resp, view)
+		if assert.Len(t, view.State.Run.Jobs, 3) {
+			assert.Equal(t, "build", view.State.Run.Jobs[0].JobID)
+			assert.Equal(t, "build (linux)", view.State.Run.Jobs[0].Name)
+			assert.Equal(t, []*actions_web.ViewMatrixValue{{Key: "os", Value: "linux"}}, view.State.Run.Jobs[0].Matrix)
+			assert.Equal(t, "build", view.State.Run.Jobs[1].JobID)
+			assert.Equal(t, []*actions_web.ViewMatrixValue{{Key: "os", Value: "windows"}}, view.State.Run.Jobs[1].Matrix)
+			assert.Equal(t, "release", view.State.Run.Jobs[2].JobID)
+			assert.Empty(t, view.State.Run.Jobs[2].Matrix)
+		}
+
+		// re-run a single combination of the matrix
+		jobs, err := db.Find[actions_model.ActionRunJob](db.DefaultContext, actions_model.FindRunJobOptions{RunID: run.ID})
+		assert.NoError(t, err)
+		for _, job := range jobs {
+			job.Status = actions_model.StatusFailure
+			_, err := actions_model.UpdateRunJob(db.DefaultContext, job, nil, "status")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, actions_model.StatusFailure, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: run.ID}).Status)
+
+		req = NewRequestWithValues(t, "POST", runURL+"/jobs/1/rerun", map[string]string{
+			"_csrf": GetCSRF(t, session, runURL),
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+
+		assert.Equal(t, actions_model.StatusFailure, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: jobs[0].ID}).Status)
+		assert.Equal(t, actions_model.StatusWaiting, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: jobs[1].ID}).Status)
+		assert.Equal(t, actions_model.StatusBlocked, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: jobs[2].ID}).Status)
+	})
+}
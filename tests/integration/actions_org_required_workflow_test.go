@@ -0,0 +1,152 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/services/forms"
+	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsOrgRequiredWorkflows(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		org3 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+		session := loginUser(t, user2.Name)
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+
+		changeFile := func(repo *repo_model.Repository, treePath, content, oldBranch, newBranch string) {
+			_, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+				Files:     []*files_service.ChangeRepoFile{{Operation: "create", TreePath: treePath, ContentReader: strings.NewReader(content)}},
+				Message:   "add " + treePath,
+				OldBranch: oldBranch,
+				NewBranch: newBranch,
+				Author:    &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+				Committer: &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+				Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+			})
+			assert.NoError(t, err)
+		}
+		createRepo := func(name string) *repo_model.Repository {
+			repo, err := repo_service.CreateRepository(db.DefaultContext, user2, org3, repo_service.CreateRepoOptions{
+				Name:          name,
+				AutoInit:      true,
+				Readme:        "Default",
+				DefaultBranch: "master",
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+				RepoID: repo.ID,
+				Type:   unit_model.TypeActions,
+			}}, nil))
+			return repo
+		}
+		security := createRepo("org-required-security")
+		changeFile(security, ".gitea/workflows/scan.yml",
+			"on: [push, pull_request]\njobs:\n  scan:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo scan\n", "master", "master")
+		app := createRepo("org-required-app")
+		other := createRepo("org-required-other")
+
+		settingsURL := fmt.Sprintf("/org/%s/settings/actions/required_workflows", org3.Name)
+		addRequiredWorkflow := func(values map[string]string, expectedStatus int) {
+			values["_csrf"] = GetCSRF(t, session, settingsURL)
+			session.MakeRequest(t, NewRequestWithValues(t, "POST", settingsURL+"/new", values), expectedStatus)
+		}
+		addRequiredWorkflow(map[string]string{"repo_name": security.Name, "workflow_id": "missing.yml"}, http.StatusBadRequest)
+		addRequiredWorkflow(map[string]string{"repo_name": security.Name, "workflow_id": "scan.yml", "repo_names": "missing-repo"}, http.StatusBadRequest)
+		addRequiredWorkflow(map[string]string{"repo_name": security.Name, "workflow_id": "scan.yml", "repo_names": app.Name}, http.StatusOK)
+		required := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionOrgRequiredWorkflow{OrgID: org3.ID})
+		assert.Equal(t, security.ID, required.RepoID)
+		assert.Equal(t, []int64{app.ID}, required.RepoIDs)
+		resp := session.MakeRequest(t, NewRequest(t, "GET", settingsURL), http.StatusOK)
+		assert.Contains(t, NewHTMLParser(t, resp.Body).doc.Find(".flex-item-title").Text(), "org-required-security/scan.yml")
+
+		// the workflow runs in the selected repositories only
+		changeFile(app, "app.txt", "app", "master", "master")
+		changeFile(other, "other.txt", "other", "master", "master")
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: app.ID, WorkflowID: "org-required-security/scan.yml"})
+		assert.Equal(t, "refs/heads/master", run.Ref)
+		unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: other.ID})
+		unittest.AssertCount(t, &actions_model.ActionRun{RepoID: security.ID}, 1)
+
+		// the pull request can't be merged until the run for its head commit succeeds
+		changeFile(app, "feature.txt", "feature", "master", "feature")
+		pullIssue := &issues_model.Issue{
+			RepoID:   app.ID,
+			Title:    "add feature",
+			PosterID: user2.ID,
+			Poster:   user2,
+			IsPull:   true,
+		}
+		pullRequest := &issues_model.PullRequest{
+			HeadRepoID: app.ID,
+			BaseRepoID: app.ID,
+			HeadBranch: "feature",
+			BaseBranch: app.DefaultBranch,
+			HeadRepo:   app,
+			BaseRepo:   app,
+			Type:       issues_model.PullRequestGitea,
+		}
+		assert.NoError(t, pull_service.NewPullRequest(git.DefaultContext, app, pullIssue, nil, nil, pullRequest, nil))
+		run = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: app.ID, Ref: pullRequest.GetGitRefName()})
+		assert.Equal(t, "org-required-security/scan.yml", run.WorkflowID)
+
+		mergePull := func(expectedStatus int) {
+			var resp *httptest.ResponseRecorder
+			for i := 0; i < 6; i++ {
+				req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/merge", org3.Name, app.Name, pullIssue.Index), &forms.MergePullRequestForm{
+					Do: string(repo_model.MergeStyleMerge),
+				}).AddTokenAuth(token)
+				resp = MakeRequest(t, req, NoExpectedStatus)
+				if resp.Code != http.StatusMethodNotAllowed || !strings.Contains(resp.Body.String(), "Please try again later") {
+					break
+				}
+				queue.GetManager().FlushAll(context.Background(), 5*time.Second)
+				<-time.After(time.Second)
+			}
+			assert.Equal(t, expectedStatus, resp.Code, resp.Body.String())
+		}
+		mergePull(http.StatusMethodNotAllowed)
+		resp = session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/pulls/%d", org3.Name, app.Name, pullIssue.Index)), http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "org-required-security/scan.yml")
+
+		job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "scan"})
+		job.Status = actions_model.StatusSuccess
+		_, err := actions_model.UpdateRunJob(db.DefaultContext, job, nil, "status")
+		assert.NoError(t, err)
+		mergePull(http.StatusOK)
+
+		// the workflow doesn't run after it's removed
+		req := NewRequestWithValues(t, "POST", fmt.Sprintf("%s/%d/delete", settingsURL, required.ID), map[string]string{
+			"_csrf": GetCSRF(t, session, settingsURL),
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		unittest.AssertNotExistsBean(t, &actions_model.ActionOrgRequiredWorkflow{ID: required.ID})
+		count := unittest.GetCount(t, &actions_model.ActionRun{RepoID: app.ID})
+		changeFile(app, "app2.txt", "app", "master", "master")
+		unittest.AssertCount(t, &actions_model.ActionRun{RepoID: app.ID}, count)
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsOrgRuns(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		org3 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+
+		createRepo := func(name string, isPrivate bool) *repo_model.Repository {
+			repo, err := repo_service.CreateRepository(db.DefaultContext, user2, org3, repo_service.CreateRepoOptions{
+				Name:          name,
+				AutoInit:      true,
+				Readme:        "Default",
+				DefaultBranch: "master",
+				IsPrivate:     isPrivate,
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+				RepoID: repo.ID,
+				Type:   unit_model.TypeActions,
+			}}, nil))
+			_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+				Files: []*files_service.ChangeRepoFile{
+					{
+						Operation:     "create",
+						TreePath:      ".gitea/workflows/org-runs.yml",
+						ContentReader: strings.NewReader("on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n"),
+					},
+				},
+				Message:   "add workflow",
+				OldBranch: "master",
+				NewBranch: "master",
+				Author:    &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+				Committer: &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+				Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+			})
+			assert.NoError(t, err)
+			return repo
+		}
+		publicRepo := createRepo("org-runs-public", false)
+		privateRepo := createRepo("org-runs-private", true)
+		publicRun := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: publicRepo.ID})
+		privateRun := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: privateRepo.ID})
+		publicRun.Repo, privateRun.Repo = publicRepo, privateRepo
+
+		listURL := fmt.Sprintf("/api/v1/orgs/%s/actions/runs", org3.Name)
+		listRuns := func(t *testing.T, token, query string, expectedStatus int) []int64 {
+			req := NewRequest(t, "GET", listURL+"?workflow=org-runs.yml&"+query)
+			if token != "" {
+				req.AddTokenAuth(token)
+			}
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusOK {
+				return nil
+			}
+			var runs api.ActionWorkflowRunsResponse
+			DecodeJSON(t, resp, &runs)
+			ids := make([]int64, 0, len(runs.Entries))
+			for _, run := range runs.Entries {
+				ids = append(ids, run.ID)
+			}
+			return ids
+		}
+		adminToken := getUserToken(t, "user1", auth_model.AccessTokenScopeReadOrganization, auth_model.AccessTokenScopeReadRepository)
+
+		t.Run("API", func(t *testing.T) {
+			assert.Equal(t, []int64{privateRun.ID, publicRun.ID}, listRuns(t, adminToken, "", http.StatusOK))
+			// the runs of the private repositories are hidden from the users who can't read them
+			assert.Equal(t, []int64{publicRun.ID}, listRuns(t, "", "", http.StatusOK))
+			assert.Empty(t, listRuns(t, "", "repo="+privateRepo.Name, http.StatusOK))
+
+			assert.Equal(t, []int64{privateRun.ID}, listRuns(t, adminToken, "repo="+privateRepo.Name, http.StatusOK))
+			assert.Equal(t, []int64{privateRun.ID, publicRun.ID}, listRuns(t, adminToken, "status=waiting&actor="+user2.Name, http.StatusOK))
+			assert.Empty(t, listRuns(t, adminToken, "status=success", http.StatusOK))
+			assert.Empty(t, listRuns(t, adminToken, "actor=user4", http.StatusOK))
+
+			today := time.Now().Format(time.DateOnly)
+			assert.Equal(t, []int64{privateRun.ID, publicRun.ID}, listRuns(t, adminToken, "since="+today+"&until="+today, http.StatusOK))
+			assert.Empty(t, listRuns(t, adminToken, "since="+time.Now().AddDate(0, 0, 2).Format(time.DateOnly), http.StatusOK))
+			assert.Empty(t, listRuns(t, adminToken, "until="+time.Now().AddDate(0, 0, -2).Format(time.DateOnly), http.StatusOK))
+
+			listRuns(t, adminToken, "status=unknown-status", http.StatusBadRequest)
+			listRuns(t, adminToken, "since=yesterday", http.StatusBadRequest)
+		})
+
+		t.Run("Web", func(t *testing.T) {
+			pageURL := fmt.Sprintf("/%s/-/actions?workflow=org-runs.yml", org3.Name)
+			runLinks := func(resp *httptest.ResponseRecorder) []string {
+				return NewHTMLParser(t, resp.Body).Find(".run-list .flex-item-title").Map(func(_ int, s *goquery.Selection) string {
+					return s.AttrOr("href", "")
+				})
+			}
+
+			resp := loginUser(t, "user1").MakeRequest(t, NewRequest(t, "GET", pageURL), http.StatusOK)
+			assert.Equal(t, []string{privateRun.Link(), publicRun.Link()}, runLinks(resp))
+
+			resp = MakeRequest(t, NewRequest(t, "GET", pageURL), http.StatusOK)
+			assert.Equal(t, []string{publicRun.Link()}, runLinks(resp))
+
+			resp = MakeRequest(t, NewRequest(t, "GET", pageURL+"&status=success"), http.StatusOK)
+			assert.Empty(t, runLinks(resp))
+
+			// the page is only for organizations
+			MakeRequest(t, NewRequest(t, "GET", "/user2/-/actions"), http.StatusNotFound)
+		})
+	})
+}
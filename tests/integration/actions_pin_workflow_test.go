@@ -0,0 +1,94 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsPinWorkflow(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		content := "on: pull_request\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo test\n"
+		repo := createActionsTestRepo(t, user2, "actions-pin-workflow", ".gitea/workflows/a.yml", content)
+		_, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{Operation: "create", TreePath: ".gitea/workflows/b.yml", ContentReader: strings.NewReader(content)},
+				{Operation: "create", TreePath: ".gitea/workflows/c.yml", ContentReader: strings.NewReader(content)},
+			},
+			Message:   "add workflows",
+			OldBranch: "master",
+			NewBranch: "master",
+			Author:    &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Committer: &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+		})
+		assert.NoError(t, err)
+
+		listURL := fmt.Sprintf("/%s/%s/actions", user2.Name, repo.Name)
+		// returns the workflows in the sidebar and the pinned ones
+		getWorkflows := func(session *TestSession) (names, pinned []string) {
+			resp := session.MakeRequest(t, NewRequest(t, "GET", listURL), http.StatusOK)
+			NewHTMLParser(t, resp.Body).doc.Find(".vertical.menu a.item[href*='workflow=']").Each(func(_ int, item *goquery.Selection) {
+				name := strings.TrimSpace(item.Contents().First().Text())
+				names = append(names, name)
+				if item.Find(".workflow-pin.pinned").Length() > 0 {
+					pinned = append(pinned, name)
+				}
+			})
+			return names, pinned
+		}
+
+		session := loginUser(t, user2.Name)
+		names, pinned := getWorkflows(session)
+		assert.Equal(t, []string{"a.yml", "b.yml", "c.yml"}, names)
+		assert.Empty(t, pinned)
+
+		setPinned := func(workflow string, pin bool) {
+			action := "pin"
+			if !pin {
+				action = "unpin"
+			}
+			req := NewRequestWithValues(t, "POST", fmt.Sprintf("%s/%s?workflow=%s", listURL, action, workflow), map[string]string{
+				"_csrf": GetCSRF(t, session, listURL),
+			})
+			session.MakeRequest(t, req, http.StatusOK)
+		}
+		setPinned("c.yml", true)
+		setPinned("b.yml", true)
+		names, pinned = getWorkflows(session)
+		assert.Equal(t, []string{"c.yml", "b.yml", "a.yml"}, names)
+		assert.Equal(t, []string{"c.yml", "b.yml"}, pinned)
+
+		// the pins are per-user
+		names, pinned = getWorkflows(loginUser(t, "user1"))
+		assert.Equal(t, []string{"a.yml", "b.yml", "c.yml"}, names)
+		assert.Empty(t, pinned)
+
+		setPinned("c.yml", false)
+		names, pinned = getWorkflows(session)
+		assert.Equal(t, []string{"b.yml", "a.yml", "c.yml"}, names)
+		assert.Equal(t, []string{"b.yml"}, pinned)
+
+		// the anonymous users can't pin workflows
+		names, pinned = getWorkflows(emptyTestSession(t))
+		assert.Equal(t, []string{"a.yml", "b.yml", "c.yml"}, names)
+		assert.Empty(t, pinned)
+		req := NewRequest(t, "POST", listURL+"/pin?workflow=a.yml")
+		MakeRequest(t, req, http.StatusSeeOther)
+	})
+}
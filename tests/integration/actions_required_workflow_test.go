@@ -0,0 +1,120 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/queue"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/forms"
+	pull_service "code.gitea.io/gitea/services/pull"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsRequiredWorkflows(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-required-workflows", ".gitea/workflows/ci.yml",
+			"name: ci\non: pull_request\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo build\n  lint:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo lint\n")
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		apiURL := fmt.Sprintf("/api/v1/repos/%s/%s", user2.Name, repo.Name)
+
+		// invalid required workflows are rejected
+		req := NewRequestWithJSON(t, "POST", apiURL+"/branch_protections", &api.CreateBranchProtectionOption{
+			RuleName:          repo.DefaultBranch,
+			EnableStatusCheck: true,
+			RequiredWorkflows: []string{".gitea/workflows/ci.yml"},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", apiURL+"/branch_protections", &api.CreateBranchProtectionOption{
+			RuleName:          repo.DefaultBranch,
+			EnableStatusCheck: true,
+			RequiredWorkflows: []string{"ci.yml:build"},
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		var bp api.BranchProtection
+		DecodeJSON(t, resp, &bp)
+		assert.Equal(t, []string{"ci.yml:build"}, bp.RequiredWorkflows)
+
+		_, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "create",
+					TreePath:      "feature.txt",
+					ContentReader: strings.NewReader("feature"),
+				},
+			},
+			Message:   "add feature",
+			OldBranch: repo.DefaultBranch,
+			NewBranch: "feature",
+			Author:    &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Committer: &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+		})
+		assert.NoError(t, err)
+		pullIssue := &issues_model.Issue{
+			RepoID:   repo.ID,
+			Title:    "add feature",
+			PosterID: user2.ID,
+			Poster:   user2,
+			IsPull:   true,
+		}
+		pullRequest := &issues_model.PullRequest{
+			HeadRepoID: repo.ID,
+			BaseRepoID: repo.ID,
+			HeadBranch: "feature",
+			BaseBranch: repo.DefaultBranch,
+			HeadRepo:   repo,
+			BaseRepo:   repo,
+			Type:       issues_model.PullRequestGitea,
+		}
+		assert.NoError(t, pull_service.NewPullRequest(git.DefaultContext, repo, pullIssue, nil, nil, pullRequest, nil))
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Ref: pullRequest.GetGitRefName()})
+
+		mergePull := func(expectedStatus int) {
+			var resp *httptest.ResponseRecorder
+			for i := 0; i < 6; i++ {
+				req := NewRequestWithJSON(t, "POST", fmt.Sprintf("%s/pulls/%d/merge", apiURL, pullIssue.Index), &forms.MergePullRequestForm{
+					Do: string(repo_model.MergeStyleMerge),
+				}).AddTokenAuth(token)
+				resp = MakeRequest(t, req, NoExpectedStatus)
+				if resp.Code != http.StatusMethodNotAllowed || !strings.Contains(resp.Body.String(), "Please try again later") {
+					break
+				}
+				queue.GetManager().FlushAll(context.Background(), 5*time.Second)
+				<-time.After(time.Second)
+			}
+			assert.Equal(t, expectedStatus, resp.Code, resp.Body.String())
+		}
+
+		// the required job hasn't succeeded
+		mergePull(http.StatusMethodNotAllowed)
+
+		// only the required job needs to succeed
+		job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "build"})
+		job.Status = actions_model.StatusSuccess
+		_, err = actions_model.UpdateRunJob(db.DefaultContext, job, nil, "status")
+		assert.NoError(t, err)
+		mergePull(http.StatusOK)
+	})
+}
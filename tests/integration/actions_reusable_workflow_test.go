@@ -0,0 +1,122 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	perm_model "code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_web "code.gitea.io/gitea/routers/web/repo/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsReusableWorkflow(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		createActionsTestRepo(t, user2, "actions-shared", ".gitea/workflows/build.yml",
+			`name: build
+on:
+  workflow_call:
+    inputs:
+      target:
+        type: string
+        required: true
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make ${{ inputs.target }}
+  test:
+    needs: build
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+      packages: write
+    steps:
+      - run: make test --token=${{ secrets.TOKEN }}
+`)
+		repo := createActionsTestRepo(t, user2, "actions-caller", ".gitea/workflows/ci.yml",
+			`name: ci
+on: push
+jobs:
+  setup:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo setup
+  call:
+    needs: setup
+    uses: user2/actions-shared/.gitea/workflows/build.yml@master
+    permissions:
+      contents: write
+    with:
+      target: release
+  publish:
+    needs: call
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo publish
+`)
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		jobs, err := db.Find[actions_model.ActionRunJob](db.DefaultContext, actions_model.FindRunJobOptions{RunID: run.ID})
+		assert.NoError(t, err)
+		slices.SortFunc(jobs, func(a, b *actions_model.ActionRunJob) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+		if assert.Len(t, jobs, 4) {
+			assert.Equal(t, "setup", jobs[0].JobID)
+			assert.Equal(t, actions_model.StatusWaiting, jobs[0].Status)
+
+			assert.Equal(t, "call/build", jobs[1].JobID)
+			assert.Equal(t, "call / build", jobs[1].Name)
+			assert.Equal(t, []string{"setup"}, jobs[1].Needs)
+			assert.Equal(t, "call", jobs[1].CallerJobID())
+			assert.Contains(t, string(jobs[1].WorkflowPayload), "make ${{ 'release' }}")
+			// the called job without permissions has the ones of the caller job
+			perms, err := jobs[1].GetTokenPermissions()
+			assert.NoError(t, err)
+			assert.Equal(t, actions_model.TokenPermissions{actions_model.TokenScopeContents: perm_model.AccessModeWrite}, perms)
+
+			// the secrets are not passed to the called workflow
+			assert.Equal(t, "call/test", jobs[2].JobID)
+			assert.Equal(t, []string{"call/build"}, jobs[2].Needs)
+			assert.Contains(t, string(jobs[2].WorkflowPayload), "make test --token=${{ '' }}")
+			// the permissions of the called job can't exceed the ones of the caller job
+			perms, err = jobs[2].GetTokenPermissions()
+			assert.NoError(t, err)
+			assert.Equal(t, actions_model.TokenPermissions{
+				actions_model.TokenScopeContents: perm_model.AccessModeRead,
+				actions_model.TokenScopePackages: perm_model.AccessModeNone,
+			}, perms)
+
+			assert.Equal(t, "publish", jobs[3].JobID)
+			assert.Equal(t, []string{"call/build", "call/test"}, jobs[3].Needs)
+			assert.Empty(t, jobs[3].CallerJobID())
+		}
+
+		// the called jobs are grouped by the caller job in the run view
+		session := loginUser(t, user2.Name)
+		runURL := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.Index)
+		req := NewRequestWithJSON(t, "POST", runURL+"/jobs/0", &actions_web.ViewRequest{})
+		req.Header.Add("X-Csrf-Token", GetCSRF(t, session, runURL))
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		view := &actions_web.ViewResponse{}
+		DecodeJSON(t, resp, view)
+		if assert.Len(t, view.State.Run.Jobs, 4) {
+			assert.Empty(t, view.State.Run.Jobs[0].Caller)
+			assert.Equal(t, "call", view.State.Run.Jobs[1].Caller)
+			assert.Equal(t, "call", view.State.Run.Jobs[2].Caller)
+			assert.Empty(t, view.State.Run.Jobs[3].Caller)
+		}
+	})
+}
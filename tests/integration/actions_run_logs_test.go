@@ -0,0 +1,120 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"code.gitea.io/actions-proto-go/runner/v1/runnerv1connect"
+	"connectrpc.com/connect"
+	gouuid "github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRunnerClient registers a runner of the repository with the "ubuntu-latest" label until the test ends, and returns a client of the runner service
+func newTestRunnerClient(t *testing.T, u *url.URL, name string, repoID int64) runnerv1connect.RunnerServiceClient {
+	runner := &actions_model.ActionRunner{
+		UUID:        gouuid.New().String(),
+		Name:        name,
+		RepoID:      repoID,
+		AgentLabels: []string{"ubuntu-latest"},
+	}
+	assert.NoError(t, runner.GenerateToken())
+	assert.NoError(t, actions_model.CreateRunner(db.DefaultContext, runner))
+	t.Cleanup(func() {
+		assert.NoError(t, actions_model.DeleteRunner(db.DefaultContext, runner.ID))
+	})
+	return runnerv1connect.NewRunnerServiceClient(http.DefaultClient, u.String()+"api/actions",
+		connect.WithInterceptors(connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+			return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+				req.Header().Set("x-runner-uuid", runner.UUID)
+				req.Header().Set("x-runner-token", runner.Token)
+				return next(ctx, req)
+			}
+		})))
+}
+
+func TestActionsRunLogsZip(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-run-logs", ".gitea/workflows/build.release.yml",
+			`on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make build
+  deploy:
+    needs: build
+    runs-on: self-hosted
+    steps:
+      - run: make deploy
+`)
+		client := newTestRunnerClient(t, u, "run-logs-runner", repo.ID)
+		fetchResp, err := client.FetchTask(context.Background(), connect.NewRequest(&runnerv1.FetchTaskRequest{}))
+		assert.NoError(t, err)
+		if !assert.NotNil(t, fetchResp.Msg.Task) {
+			return
+		}
+		taskID := fetchResp.Msg.Task.Id
+		_, err = client.UpdateLog(context.Background(), connect.NewRequest(&runnerv1.UpdateLogRequest{
+			TaskId: taskID,
+			Rows:   []*runnerv1.LogRow{{Content: "building"}, {Content: "built"}},
+			NoMore: true,
+		}))
+		assert.NoError(t, err)
+		_, err = client.UpdateTask(context.Background(), connect.NewRequest(&runnerv1.UpdateTaskRequest{
+			State: &runnerv1.TaskState{Id: taskID, Result: runnerv1.Result_RESULT_SUCCESS},
+		}))
+		assert.NoError(t, err)
+
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "build.release.yml"})
+
+		// only the extension is trimmed from the workflow name, and the job which hasn't started is skipped
+		assertLogsZip := func(t *testing.T, resp *http.Response, body []byte) {
+			assert.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+			assert.Contains(t, resp.Header.Get("Content-Disposition"), fmt.Sprintf(`filename="build.release-%d-logs.zip"`, run.Index))
+
+			archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+			if !assert.NoError(t, err) || !assert.Len(t, archive.File, 1) {
+				return
+			}
+			assert.Equal(t, "0_build.log", archive.File[0].Name)
+			f, err := archive.File[0].Open()
+			assert.NoError(t, err)
+			defer f.Close()
+			content, err := io.ReadAll(f)
+			assert.NoError(t, err)
+			assert.Contains(t, string(content), "building\n")
+			assert.Contains(t, string(content), "built\n")
+		}
+
+		t.Run("Web", func(t *testing.T) {
+			session := loginUser(t, user2.Name)
+			resp := session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%d/logs", user2.Name, repo.Name, run.Index)), http.StatusOK)
+			assertLogsZip(t, resp.Result(), resp.Body.Bytes())
+		})
+
+		t.Run("API", func(t *testing.T) {
+			token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeReadRepository)
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d/logs", user2.Name, repo.Name, run.ID)).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			assertLogsZip(t, resp.Result(), resp.Body.Bytes())
+		})
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsRunnerVersionWarning(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		defer test.MockVariableValue(&setting.Actions.MinRunnerVersion, "v0.2.10")()
+		defer test.MockVariableValue(&setting.Actions.RecommendedRunnerVersion, "v0.2.11")()
+
+		outdated := &actions_model.ActionRunner{UUID: "runner-outdated", TokenHash: "runner-outdated", Name: "outdated", Version: "v0.2.6"}
+		upToDate := &actions_model.ActionRunner{UUID: "runner-up-to-date", TokenHash: "runner-up-to-date", Name: "up-to-date", Version: "v0.2.11"}
+		assert.NoError(t, db.Insert(db.DefaultContext, outdated))
+		assert.NoError(t, db.Insert(db.DefaultContext, upToDate))
+		// the runners aren't reset with the fixtures, remove them to not affect the other tests
+		defer func() {
+			_, err := db.DeleteByID[actions_model.ActionRunner](db.DefaultContext, outdated.ID)
+			assert.NoError(t, err)
+			_, err = db.DeleteByID[actions_model.ActionRunner](db.DefaultContext, upToDate.ID)
+			assert.NoError(t, err)
+		}()
+
+		session := loginUser(t, "user1")
+		resp := session.MakeRequest(t, NewRequest(t, "GET", "/admin/actions/runners"), http.StatusOK)
+		doc := NewHTMLParser(t, resp.Body)
+		assert.Contains(t, doc.doc.Find(".warning.message").Text(), "Some runners are below the minimum version v0.2.10")
+		assert.Equal(t, 1, doc.doc.Find(".runner-container svg.octicon-alert.red").Length())
+
+		resp = session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/admin/actions/runners/%d", outdated.ID)), http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		assert.Equal(t, 1, doc.doc.Find(".runner-container svg.octicon-alert.red").Length())
+
+		resp = session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/admin/actions/runners/%d", upToDate.ID)), http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		assert.Equal(t, 0, doc.doc.Find(".runner-container svg.octicon-alert").Length())
+	})
+}
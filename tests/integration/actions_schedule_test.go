@@ -0,0 +1,64 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	actions_service "code.gitea.io/gitea/services/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsScheduleTrigger(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-schedule", ".gitea/workflows/nightly.yml",
+			`name: nightly
+on:
+  schedule:
+    - cron: '30 2 * * *'
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make nightly
+`)
+
+		// pushing the workflow to the default branch registers its schedule
+		schedule := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionSchedule{RepoID: repo.ID, WorkflowID: "nightly.yml"})
+		assert.Equal(t, "refs/heads/master", schedule.Ref)
+		spec := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionScheduleSpec{RepoID: repo.ID, ScheduleID: schedule.ID})
+		assert.Equal(t, "30 2 * * *", spec.Spec)
+		assert.Greater(t, spec.Next, timeutil.TimeStampNow())
+
+		// nothing is due yet
+		assert.NoError(t, actions_service.StartScheduleTasks(db.DefaultContext))
+		unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+
+		// make the spec due, then the cron task starts a run and schedules the next one
+		due := timeutil.TimeStamp(time.Now().Add(-time.Minute).Unix())
+		_, err := db.GetEngine(db.DefaultContext).ID(spec.ID).Cols("next").Update(&actions_model.ActionScheduleSpec{Next: due})
+		assert.NoError(t, err)
+		assert.NoError(t, actions_service.StartScheduleTasks(db.DefaultContext))
+
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "nightly.yml"})
+		assert.Equal(t, string(webhook_module.HookEventSchedule), run.TriggerEvent)
+		assert.Equal(t, schedule.ID, run.ScheduleID)
+		assert.Equal(t, "refs/heads/master", run.Ref)
+		unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "build"})
+
+		spec = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionScheduleSpec{ID: spec.ID})
+		assert.Equal(t, due, spec.Prev)
+		assert.Greater(t, spec.Next, timeutil.TimeStampNow())
+	})
+}
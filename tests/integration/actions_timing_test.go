@@ -0,0 +1,79 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_web "code.gitea.io/gitea/routers/web/repo/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsStepTiming(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-timing", ".gitea/workflows/test.yml",
+			`name: test
+on: push
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make lint
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make build
+      - run: make test
+`)
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		timingURL := fmt.Sprintf("/%s/%s/actions/runs/%d/timing", user2.Name, repo.Name, run.Index)
+
+		// no step has started
+		resp := MakeRequest(t, NewRequest(t, "GET", timingURL), http.StatusOK)
+		timing := &actions_web.TimingViewResponse{}
+		DecodeJSON(t, resp, timing)
+		assert.Empty(t, timing.Steps)
+
+		jobs, err := actions_model.GetRunJobsByRunID(db.DefaultContext, run.ID)
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 2)
+		addSteps := func(job *actions_model.ActionRunJob, taskID int64, steps ...*actions_model.ActionTaskStep) {
+			job.TaskID = taskID
+			_, err := actions_model.UpdateRunJob(db.DefaultContext, job, nil, "task_id")
+			assert.NoError(t, err)
+			for i, step := range steps {
+				step.TaskID, step.Index, step.RepoID = taskID, int64(i), repo.ID
+				assert.NoError(t, db.Insert(db.DefaultContext, step))
+			}
+		}
+		addSteps(jobs[0], 1000001,
+			&actions_model.ActionTaskStep{Name: "make lint", Status: actions_model.StatusSuccess, Started: 100, Stopped: 130},
+		)
+		addSteps(jobs[1], 1000002,
+			&actions_model.ActionTaskStep{Name: "make build", Status: actions_model.StatusSuccess, Started: 100, Stopped: 220},
+			&actions_model.ActionTaskStep{Name: "make test", Status: actions_model.StatusFailure, Started: 220, Stopped: 225},
+			&actions_model.ActionTaskStep{Name: "post", Status: actions_model.StatusSkipped},
+		)
+
+		resp = MakeRequest(t, NewRequest(t, "GET", timingURL), http.StatusOK)
+		timing = &actions_web.TimingViewResponse{}
+		DecodeJSON(t, resp, timing)
+		assert.Equal(t, []*actions_web.TimingViewStep{
+			{JobIndex: 1, JobName: "build", StepName: "make build", Status: "success", Duration: "2m0s", Seconds: 120},
+			{JobIndex: 0, JobName: "lint", StepName: "make lint", Status: "success", Duration: "30s", Seconds: 30},
+			{JobIndex: 1, JobName: "build", StepName: "make test", Status: "failure", Duration: "5s", Seconds: 5},
+		}, timing.Steps)
+
+		MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/actions/runs/%d/timing", user2.Name, repo.Name, 999)), http.StatusNotFound)
+	})
+}
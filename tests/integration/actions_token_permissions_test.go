@@ -0,0 +1,95 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsTokenPermissions(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+		fetchTaskToken := func(t *testing.T, repoName, workflow string) string {
+			repo := createActionsTestRepo(t, user2, repoName, ".gitea/workflows/build.yml", workflow)
+			client := newTestRunnerClient(t, u, repoName+"-runner", repo.ID)
+			resp, err := client.FetchTask(context.Background(), connect.NewRequest(&runnerv1.FetchTaskRequest{}))
+			assert.NoError(t, err)
+			if !assert.NotNil(t, resp.Msg.Task) {
+				return ""
+			}
+			return resp.Msg.Task.Context.GetFields()["token"].GetStringValue()
+		}
+		createIssue := func(t *testing.T, repoName, token string, expectedStatus int) {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues", user2.Name, repoName), &api.CreateIssueOption{
+				Title: "created by the job",
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, expectedStatus)
+		}
+
+		t.Run("Declared", func(t *testing.T) {
+			token := fetchTaskToken(t, "actions-token-permissions", `on: push
+permissions: write-all
+jobs:
+  triage:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+      issues: write
+    steps:
+      - run: echo triage
+`)
+			MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/actions-token-permissions/contents/README.md", user2.Name)).
+				AddTokenAuth(token), http.StatusOK)
+			createIssue(t, "actions-token-permissions", token, http.StatusCreated)
+
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/actions-token-permissions/contents/new.txt", user2.Name), &api.CreateFileOptions{
+				ContentBase64: "bmV3",
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusForbidden)
+		})
+
+		t.Run("Default", func(t *testing.T) {
+			workflow := "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n"
+			token := fetchTaskToken(t, "actions-token-permissive", workflow)
+			createIssue(t, "actions-token-permissive", token, http.StatusCreated)
+
+			defer test.MockVariableValue(&setting.Actions.DefaultTokenPermissions, setting.ActionsTokenPermissionsRestricted)()
+			token = fetchTaskToken(t, "actions-token-restricted", workflow)
+			MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/actions-token-restricted/contents/README.md", user2.Name)).
+				AddTokenAuth(token), http.StatusOK)
+			// the token has no access to the issues
+			createIssue(t, "actions-token-restricted", token, http.StatusNotFound)
+		})
+
+		t.Run("Packages", func(t *testing.T) {
+			packageURL := fmt.Sprintf("/api/packages/%s/generic/actions-token-package/1.0.0/build.bin", user2.Name)
+			token := fetchTaskToken(t, "actions-token-package-read", "on: push\npermissions:\n  packages: read\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n")
+			MakeRequest(t, NewRequestWithBody(t, "PUT", packageURL, strings.NewReader("build")).AddTokenAuth(token), http.StatusUnauthorized)
+
+			token = fetchTaskToken(t, "actions-token-package-write", "on: push\npermissions:\n  packages: write\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n")
+			MakeRequest(t, NewRequestWithBody(t, "PUT", packageURL, strings.NewReader("build")).AddTokenAuth(token), http.StatusCreated)
+			MakeRequest(t, NewRequest(t, "GET", packageURL).AddTokenAuth(token), http.StatusOK)
+
+			// the token has no access to the packages of the other owners
+			MakeRequest(t, NewRequestWithBody(t, "PUT", "/api/packages/user5/generic/actions-token-package/1.0.0/build.bin", strings.NewReader("build")).
+				AddTokenAuth(token), http.StatusUnauthorized)
+		})
+	})
+}
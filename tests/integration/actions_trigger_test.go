@@ -449,3 +449,58 @@ func TestCreateDeleteRefEvent(t *testing.T) {
 		assert.NotNil(t, run)
 	})
 }
+
+func TestPushFilters(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "push-filters", ".gitea/workflows/push.yml",
+			"on:\n  push:\n    branches: [master]\n    tags: [v*]\n    paths: ['src/**', '!src/**.md']\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo helloworld\n")
+
+		pushFile := func(branch, treePath string) string {
+			resp, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+				Files: []*files_service.ChangeRepoFile{
+					{
+						Operation:     "create",
+						TreePath:      treePath,
+						ContentReader: strings.NewReader(treePath),
+					},
+				},
+				Message:   "add " + treePath,
+				OldBranch: "master",
+				NewBranch: branch,
+				Author: &files_service.IdentityOptions{
+					Name:  user2.Name,
+					Email: user2.Email,
+				},
+				Committer: &files_service.IdentityOptions{
+					Name:  user2.Name,
+					Email: user2.Email,
+				},
+				Dates: &files_service.CommitDateOptions{
+					Author:    time.Now(),
+					Committer: time.Now(),
+				},
+			})
+			assert.NoError(t, err)
+			return resp.Commit.SHA
+		}
+
+		// the changed files are not selected by the path filters
+		pushFile("master", "docs/index.md")
+		pushFile("master", "src/README.md")
+		assert.Equal(t, 0, unittest.GetCount(t, &actions_model.ActionRun{RepoID: repo.ID}))
+
+		// the branch is not selected by the branch filters
+		pushFile("feature", "src/feature.go")
+		assert.Equal(t, 0, unittest.GetCount(t, &actions_model.ActionRun{RepoID: repo.ID}))
+
+		commitID := pushFile("master", "src/main.go")
+		unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Event: "push", Ref: "refs/heads/master", CommitSHA: commitID})
+
+		// path filters are not evaluated for pushes of tags
+		assert.NoError(t, release_service.CreateNewTag(db.DefaultContext, user2, repo, commitID, "v1.0.0", "v1.0.0"))
+		unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, Event: "push", Ref: "refs/tags/v1.0.0"})
+		assert.NoError(t, release_service.CreateNewTag(db.DefaultContext, user2, repo, commitID, "nightly", "nightly"))
+		assert.Equal(t, 2, unittest.GetCount(t, &actions_model.ActionRun{RepoID: repo.ID}))
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsUsage(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	month := actions_model.UsageMonth(time.Now())
+	assert.NoError(t, db.Insert(db.DefaultContext, &actions_model.ActionUsage{
+		OwnerID:  3,
+		RepoID:   3,
+		Month:    month,
+		Jobs:     3,
+		Duration: 150,
+		Minutes:  4,
+	}))
+
+	t.Run("API", func(t *testing.T) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeReadOrganization)
+		req := NewRequest(t, "GET", "/api/v1/orgs/org3/actions/usage").AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		usage := &api.ActionUsage{}
+		DecodeJSON(t, resp, usage)
+		assert.Equal(t, month, usage.Month)
+		assert.EqualValues(t, 4, usage.Minutes)
+		assert.False(t, usage.QuotaExceeded)
+		if assert.Len(t, usage.Repositories, 1) {
+			assert.Equal(t, "org3/repo3", usage.Repositories[0].RepoName)
+			assert.EqualValues(t, 150, usage.Repositories[0].Duration)
+		}
+
+		req = NewRequest(t, "GET", "/api/v1/orgs/org3/actions/usage?month=2024-13").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		// only the site admins can set the quotas
+		req = NewRequestWithJSON(t, "PUT", "/api/v1/admin/users/org3/actions/usage-quota", &api.EditActionUsageQuotaOption{
+			SoftQuota: 3,
+		}).AddTokenAuth(getUserToken(t, "user2", auth_model.AccessTokenScopeWriteAdmin))
+		MakeRequest(t, req, http.StatusForbidden)
+
+		adminToken := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteAdmin)
+		req = NewRequestWithJSON(t, "PUT", "/api/v1/admin/users/org3/actions/usage-quota", &api.EditActionUsageQuotaOption{
+			SoftQuota: 3,
+		}).AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", "/api/v1/orgs/org3/actions/usage").AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, usage)
+		assert.EqualValues(t, 3, usage.SoftQuota)
+		assert.True(t, usage.QuotaExceeded)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/actions/usage").AddTokenAuth(adminToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var owners []*api.ActionOwnerUsage
+		DecodeJSON(t, resp, &owners)
+		assert.Equal(t, []*api.ActionOwnerUsage{
+			{OwnerID: 3, OwnerName: "org3", Jobs: 3, Duration: 150, Minutes: 4, SoftQuota: 3, QuotaExceeded: true},
+		}, owners)
+	})
+
+	t.Run("Web", func(t *testing.T) {
+		session := loginUser(t, "user2")
+		req := NewRequest(t, "GET", "/org/org3/settings/actions/usage")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		assert.Equal(t, 1, htmlDoc.Find(".ui.warning.message").Length())
+		assert.Equal(t, "repo3", htmlDoc.Find("table tbody tr td a").First().Text())
+
+		adminSession := loginUser(t, "user1")
+		req = NewRequest(t, "GET", "/admin/actions/usage")
+		resp = adminSession.MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		assert.Equal(t, "org3", htmlDoc.Find("table tbody tr td a").First().Text())
+
+		req = NewRequestWithValues(t, "POST", "/admin/actions/usage/quota", map[string]string{
+			"_csrf":      htmlDoc.GetCSRF(),
+			"owner":      "org3",
+			"soft_quota": "0",
+		})
+		adminSession.MakeRequest(t, req, http.StatusSeeOther)
+		quota, err := actions_service.GetUsageSoftQuota(db.DefaultContext, 3)
+		assert.NoError(t, err)
+		assert.Zero(t, quota)
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	actions_service "code.gitea.io/gitea/services/actions"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsStatusWebhooks(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-status-webhooks", ".gitea/workflows/pr.yml",
+			"name: PR\non: pull_request\njobs:\n  check:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo check\n")
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/hooks", user2.Name, repo.Name), api.CreateHookOption{
+			Type: "gitea",
+			Config: api.CreateHookOptionConfig{
+				"content_type": "json",
+				"url":          "http://example.com/",
+			},
+			Events: []string{"workflow_run", "workflow_job"},
+			Active: true,
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		apiHook := &api.Hook{}
+		DecodeJSON(t, resp, apiHook)
+		assert.ElementsMatch(t, []string{"workflow_run", "workflow_job"}, apiHook.Events)
+
+		runner := &actions_model.ActionRunner{UUID: "status-webhooks-runner", TokenHash: "status-webhooks-runner", Name: "status-webhooks-runner", RepoID: repo.ID, AgentLabels: []string{"ubuntu-latest"}}
+		assert.NoError(t, db.Insert(db.DefaultContext, runner))
+		// the runner and the usages of the tasks aren't reset with the fixtures, remove them to not affect the other tests
+		defer func() {
+			_, err := db.DeleteByID[actions_model.ActionRunner](db.DefaultContext, runner.ID)
+			assert.NoError(t, err)
+			_, err = db.DeleteByBean(db.DefaultContext, &actions_model.ActionUsage{RepoID: repo.ID})
+			assert.NoError(t, err)
+		}()
+
+		// returns the events and the actions of the deliveries in the order they are created
+		deliveries := func() []string {
+			tasks, err := webhook_model.HookTasks(db.DefaultContext, apiHook.ID, 1)
+			assert.NoError(t, err)
+			events := make([]string, 0, len(tasks))
+			for i := len(tasks) - 1; i >= 0; i-- {
+				payload := struct {
+					Action api.HookWorkflowAction `json:"action"`
+				}{}
+				assert.NoError(t, json.Unmarshal([]byte(tasks[i].PayloadContent), &payload))
+				events = append(events, fmt.Sprintf("%s:%s", tasks[i].EventType, payload.Action))
+			}
+			return events
+		}
+
+		_, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "create",
+					TreePath:      ".gitea/workflows/ci.yml",
+					ContentReader: strings.NewReader("name: CI\non: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo build\n"),
+				},
+			},
+			Message:   "add ci",
+			OldBranch: "master",
+			NewBranch: "master",
+			Author:    &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Committer: &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+		})
+		assert.NoError(t, err)
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "ci.yml"})
+		assert.Equal(t, []string{"workflow_run:requested", "workflow_job:queued"}, deliveries())
+
+		task, ok, err := actions_model.CreateTaskForRunner(db.DefaultContext, runner)
+		assert.NoError(t, err)
+		if !assert.True(t, ok) {
+			return
+		}
+		job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: task.JobID})
+		actions_service.NotifyWorkflowJobsStatusUpdate(db.DefaultContext, job)
+		assert.Equal(t, []string{"workflow_run:requested", "workflow_job:queued", "workflow_job:in_progress", "workflow_run:in_progress"}, deliveries())
+
+		hookTasks, err := webhook_model.HookTasks(db.DefaultContext, apiHook.ID, 1)
+		assert.NoError(t, err)
+		jobPayload := &api.WorkflowJobPayload{}
+		assert.NoError(t, json.Unmarshal([]byte(hookTasks[1].PayloadContent), jobPayload))
+		assert.Equal(t, webhook_module.HookEventWorkflowJob, hookTasks[1].EventType)
+		assert.Equal(t, job.ID, jobPayload.WorkflowJob.ID)
+		assert.Equal(t, run.ID, jobPayload.WorkflowJob.RunID)
+		assert.Equal(t, "CI", jobPayload.WorkflowJob.WorkflowName)
+		assert.Equal(t, "build", jobPayload.WorkflowJob.Name)
+		assert.Equal(t, runner.ID, jobPayload.WorkflowJob.RunnerID)
+		assert.Equal(t, runner.Name, jobPayload.WorkflowJob.RunnerName)
+		assert.Equal(t, fmt.Sprintf("%s/actions/runs/%d/jobs/0", repo.HTMLURL(), run.Index), jobPayload.WorkflowJob.HTMLURL)
+
+		assert.NoError(t, actions_model.StopTask(db.DefaultContext, task.ID, actions_model.StatusSuccess))
+		actions_service.NotifyWorkflowJobsStatusUpdate(db.DefaultContext, job)
+		assert.NoError(t, actions_service.EmitJobsIfReady(run.ID))
+		assert.Equal(t, []string{
+			"workflow_run:requested", "workflow_job:queued", "workflow_job:in_progress", "workflow_run:in_progress",
+			"workflow_job:completed", "workflow_run:completed",
+		}, deliveries())
+
+		// every status is notified only once
+		actions_service.NotifyWorkflowJobsStatusUpdate(db.DefaultContext, job)
+		assert.NoError(t, actions_service.EmitJobsIfReady(run.ID))
+		assert.Len(t, deliveries(), 6)
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/routers/web/repo/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsWorkflowEditor(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-workflow-editor", ".gitea/workflows/ci.yml",
+			"on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ok\n")
+		session := loginUser(t, user2.Name)
+		newURL := fmt.Sprintf("/%s/%s/actions/workflows/new", user2.Name, repo.Name)
+		lintURL := fmt.Sprintf("/%s/%s/actions/workflows/lint", user2.Name, repo.Name)
+
+		session.MakeRequest(t, NewRequest(t, "GET", newURL), http.StatusOK)
+
+		invalidContent := "on:\n  schedule:\n    - cron: \"0 0 * *\"\njobs:\n  test:\n    runs-on: ubuntu-latest\n    step:\n      - run: echo ok\n"
+		req := NewRequestWithValues(t, "POST", lintURL, map[string]string{
+			"_csrf":   GetCSRF(t, session, newURL),
+			"content": invalidContent,
+		})
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var result struct {
+			Problems []*actions.WorkflowLintProblem `json:"problems"`
+		}
+		DecodeJSON(t, resp, &result)
+		if assert.Len(t, result.Problems, 3) {
+			assert.Equal(t, 3, result.Problems[0].Line)
+			assert.Contains(t, result.Problems[0].Message, "0 0 * *")
+			// there is no online runner
+			assert.Equal(t, 6, result.Problems[1].Line)
+			assert.Equal(t, "warning", result.Problems[1].Severity)
+			assert.Equal(t, 7, result.Problems[2].Line)
+			assert.Contains(t, result.Problems[2].Message, "step")
+		}
+
+		// the workflow with errors can't be committed
+		req = NewRequestWithValues(t, "POST", newURL, map[string]string{
+			"_csrf":     GetCSRF(t, session, newURL),
+			"file_name": "invalid.yml",
+			"content":   invalidContent,
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/raw/branch/master/.gitea/workflows/invalid.yml", user2.Name, repo.Name)), http.StatusNotFound)
+
+		// the workflow file name must be valid
+		req = NewRequestWithValues(t, "POST", newURL, map[string]string{
+			"_csrf":     GetCSRF(t, session, newURL),
+			"file_name": "sub/ci.yml",
+			"content":   "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ok\n",
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+
+		// the warnings don't block committing
+		req = NewRequestWithValues(t, "POST", newURL, map[string]string{
+			"_csrf":          GetCSRF(t, session, newURL),
+			"file_name":      "build.yml",
+			"content":        "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ok\n",
+			"commit_summary": "add build workflow",
+		})
+		resp = session.MakeRequest(t, req, http.StatusSeeOther)
+		assert.Equal(t, fmt.Sprintf("/%s/%s/actions?workflow=build.yml", user2.Name, repo.Name), resp.Header().Get("Location"))
+		resp = session.MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/%s/%s/raw/branch/master/.gitea/workflows/build.yml", user2.Name, repo.Name)), http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "echo ok")
+
+		// the existing workflow can't be overwritten
+		req = NewRequestWithValues(t, "POST", newURL, map[string]string{
+			"_csrf":     GetCSRF(t, session, newURL),
+			"file_name": "build.yml",
+			"content":   "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo again\n",
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+
+		// the users who can't write the code can't use the editor
+		session = loginUser(t, "user4")
+		session.MakeRequest(t, NewRequest(t, "GET", newURL), http.StatusNotFound)
+	})
+}
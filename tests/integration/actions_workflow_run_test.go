@@ -0,0 +1,116 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	actions_service "code.gitea.io/gitea/services/actions"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsWorkflowRunEvent(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-workflow-run", ".gitea/workflows/chain.yml",
+			`name: chain
+on:
+  workflow_run:
+    workflows: [CI, chain]
+    types: [completed]
+    branches: [master]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ github.event.workflow_run.conclusion }}
+`)
+		unittest.AssertCount(t, &actions_model.ActionRun{RepoID: repo.ID}, 0)
+
+		_, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "create",
+					TreePath:      ".gitea/workflows/ci.yml",
+					ContentReader: strings.NewReader("name: CI\non: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo build\n"),
+				},
+			},
+			Message:   "add ci",
+			OldBranch: "master",
+			NewBranch: "master",
+			Author:    &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Committer: &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+		})
+		assert.NoError(t, err)
+		ciRun := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "ci.yml"})
+
+		completeRun := func(run *actions_model.ActionRun, status actions_model.Status) {
+			jobs, err := actions_model.GetRunJobsByRunID(db.DefaultContext, run.ID)
+			assert.NoError(t, err)
+			for _, job := range jobs {
+				job.Status = status
+				job.Stopped = job.Updated + 1
+				_, err := actions_model.UpdateRunJob(db.DefaultContext, job, nil, "status", "stopped")
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, actions_service.EmitJobsIfReady(run.ID))
+		}
+		getChainRuns := func() []*actions_model.ActionRun {
+			runs, err := db.Find[actions_model.ActionRun](db.DefaultContext, actions_model.FindRunOptions{RepoID: repo.ID, WorkflowID: "chain.yml"})
+			assert.NoError(t, err)
+			return runs
+		}
+
+		// the CI run isn't completed
+		assert.NoError(t, actions_service.EmitJobsIfReady(ciRun.ID))
+		assert.Empty(t, getChainRuns())
+
+		completeRun(ciRun, actions_model.StatusFailure)
+		runs := getChainRuns()
+		if assert.Len(t, runs, 1) {
+			run := runs[0]
+			assert.Equal(t, webhook_module.HookEventWorkflowRun, run.Event)
+			assert.Equal(t, "workflow_run", run.TriggerEvent)
+			assert.Equal(t, "refs/heads/master", run.Ref)
+			assert.Equal(t, user2.ID, run.TriggerUserID)
+
+			payload := &api.WorkflowRunPayload{}
+			assert.NoError(t, json.Unmarshal([]byte(run.EventPayload), payload))
+			assert.Equal(t, api.HookWorkflowCompleted, payload.Action)
+			assert.Equal(t, "CI", payload.Workflow.Name)
+			assert.Equal(t, ciRun.ID, payload.WorkflowRun.ID)
+			assert.Equal(t, "failure", payload.WorkflowRun.Conclusion)
+			assert.Equal(t, ciRun.CommitSHA, payload.WorkflowRun.HeadSHA)
+			assert.Equal(t, "master", payload.WorkflowRun.HeadBranch)
+		}
+
+		// the completion of a run is notified only once
+		assert.NoError(t, actions_service.EmitJobsIfReady(ciRun.ID))
+		assert.Len(t, getChainRuns(), 1)
+
+		// the workflows can be chained by no more than three levels
+		for i := 0; i < 3; i++ {
+			completeRun(getChainRuns()[0], actions_model.StatusSuccess)
+		}
+		runs = getChainRuns()
+		assert.Len(t, runs, 3)
+		for _, run := range runs {
+			assert.True(t, run.Status.IsDone())
+		}
+	})
+}
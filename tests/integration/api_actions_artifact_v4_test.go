@@ -9,10 +9,13 @@ import (
 	"encoding/hex"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/routers/api/actions"
 	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/tests"
@@ -168,6 +171,17 @@ func TestActionsArtifactV4UploadSingleFileWithRetentionDays(t *testing.T) {
 	var finalizeResp actions.FinalizeArtifactResponse
 	protojson.Unmarshal(resp.Body.Bytes(), &finalizeResp)
 	assert.True(t, finalizeResp.Ok)
+
+	// the meta of the artifact contains the size and the expiry which are shown in the artifacts view of the run
+	metas, err := actions_model.ListUploadedArtifactsMeta(db.DefaultContext, 792, 1)
+	assert.NoError(t, err)
+	idx = slices.IndexFunc(metas, func(meta *actions_model.ActionArtifactMeta) bool {
+		return meta.ArtifactName == "artifactWithRetentionDays"
+	})
+	if assert.NotEqual(t, -1, idx) {
+		assert.EqualValues(t, 1024, metas[idx].FileSize)
+		assert.Greater(t, int64(metas[idx].ExpiredUnix), time.Now().Add(3*24*time.Hour).Unix())
+	}
 }
 
 func TestActionsArtifactV4DownloadSingle(t *testing.T) {
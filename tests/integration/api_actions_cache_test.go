@@ -0,0 +1,133 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/storage"
+	repo_service "code.gitea.io/gitea/services/repository"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	reserveCacheResponse struct {
+		CacheID int64 `json:"cacheId"`
+	}
+	getCacheResponse struct {
+		Result          string `json:"result"`
+		ArchiveLocation string `json:"archiveLocation"`
+		CacheKey        string `json:"cacheKey"`
+	}
+)
+
+func TestActionsCache(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	const (
+		token   = "8061e833a55f6fc0157c98b883e91fcfeeb1a71a"
+		baseURL = "/api/actions_cache/_apis/artifactcache"
+		version = "c0ffee"
+	)
+	content := strings.Repeat("A", 1024) + strings.Repeat("B", 512)
+
+	// nothing is cached yet
+	req := NewRequest(t, "GET", baseURL+"/cache?keys=npm-linux-&version="+version).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// reserve, upload and commit a cache
+	req = NewRequestWithJSON(t, "POST", baseURL+"/caches", map[string]any{
+		"key":       "npm-linux-abc",
+		"version":   version,
+		"cacheSize": len(content),
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var reserveResp reserveCacheResponse
+	DecodeJSON(t, resp, &reserveResp)
+	assert.NotZero(t, reserveResp.CacheID)
+	cacheURL := fmt.Sprintf("%s/caches/%d", baseURL, reserveResp.CacheID)
+
+	req = NewRequestWithBody(t, "PATCH", cacheURL, strings.NewReader(content[1024:])).
+		SetHeader("Content-Range", "bytes 1024-1535/*").
+		AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// the first chunk is missing
+	req = NewRequestWithJSON(t, "POST", cacheURL, map[string]any{"size": len(content)}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusBadRequest)
+
+	req = NewRequestWithBody(t, "PATCH", cacheURL, strings.NewReader(content[:1024])).
+		SetHeader("Content-Range", "bytes 0-1023/*").
+		AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestWithJSON(t, "POST", cacheURL, map[string]any{"size": len(content)}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	cache := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionCache{ID: reserveResp.CacheID})
+	assert.True(t, cache.Complete)
+	assert.EqualValues(t, len(content), cache.Size)
+	assert.Equal(t, int64(4), cache.RepoID)
+	assert.Equal(t, "refs/heads/master", cache.Ref)
+
+	// the cache with the same key and version can't be reserved again
+	req = NewRequestWithJSON(t, "POST", baseURL+"/caches", map[string]any{
+		"key":     "npm-linux-abc",
+		"version": version,
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusConflict)
+
+	// restore the cache with a prefix key
+	req = NewRequest(t, "GET", baseURL+"/cache?keys=npm-linux-def,npm-linux-&version="+version).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var getResp getCacheResponse
+	DecodeJSON(t, resp, &getResp)
+	assert.Equal(t, "hit", getResp.Result)
+	assert.Equal(t, "npm-linux-abc", getResp.CacheKey)
+
+	idx := strings.Index(getResp.ArchiveLocation, "/api/actions_cache")
+	assert.Greater(t, idx, 0)
+	req = NewRequest(t, "GET", getResp.ArchiveLocation[idx:])
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.True(t, bytes.Equal([]byte(content), resp.Body.Bytes()))
+
+	// the signature is required to download
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/artifacts/%d", baseURL, cache.ID))
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// the signed link can't be used to download the cache of another repository
+	other := &actions_model.ActionCache{RepoID: 1, Ref: "refs/heads/master", CacheKey: "npm-linux-abc", Version: version, Complete: true}
+	assert.NoError(t, db.Insert(db.DefaultContext, other))
+	otherLocation := strings.Replace(getResp.ArchiveLocation[idx:], fmt.Sprintf("/artifacts/%d?", cache.ID), fmt.Sprintf("/artifacts/%d?", other.ID), 1)
+	MakeRequest(t, NewRequest(t, "GET", otherLocation), http.StatusUnauthorized)
+	// the repository is taken from the cache instead of the query
+	otherLocation = strings.Replace(getResp.ArchiveLocation[idx:], "?", "?repoID=1&", 1)
+	resp = MakeRequest(t, NewRequest(t, "GET", otherLocation), http.StatusOK)
+	assert.True(t, bytes.Equal([]byte(content), resp.Body.Bytes()))
+
+	// a cache could only be restored with the same version
+	req = NewRequest(t, "GET", baseURL+"/cache?keys=npm-linux-&version=other").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// the token is required
+	req = NewRequest(t, "GET", baseURL+"/cache?keys=npm-linux-&version="+version)
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// the caches are deleted from the database and the storage with the repository
+	user5 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+	assert.NoError(t, repo_service.DeleteRepositoryDirectly(db.DefaultContext, user5, cache.RepoID))
+	unittest.AssertNotExistsBean(t, &actions_model.ActionCache{RepoID: cache.RepoID})
+	_, err := storage.ActionsArtifacts.Stat(cache.StoragePath)
+	assert.Error(t, err)
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionCache{ID: other.ID})
+}
@@ -0,0 +1,247 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createActionsTestRepo(t *testing.T, owner *user_model.User, name, workflowPath, workflowContent string) *repo_model.Repository {
+	repo, err := repo_service.CreateRepository(db.DefaultContext, owner, owner, repo_service.CreateRepoOptions{
+		Name:          name,
+		AutoInit:      true,
+		Readme:        "Default",
+		DefaultBranch: "master",
+	})
+	assert.NoError(t, err)
+
+	// enable actions
+	err = repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+		RepoID: repo.ID,
+		Type:   unit_model.TypeActions,
+	}}, nil)
+	assert.NoError(t, err)
+
+	// add workflow file to the repo
+	_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, owner, &files_service.ChangeRepoFilesOptions{
+		Files: []*files_service.ChangeRepoFile{
+			{
+				Operation:     "create",
+				TreePath:      workflowPath,
+				ContentReader: strings.NewReader(workflowContent),
+			},
+		},
+		Message:   "add workflow",
+		OldBranch: "master",
+		NewBranch: "master",
+		Author: &files_service.IdentityOptions{
+			Name:  owner.Name,
+			Email: owner.Email,
+		},
+		Committer: &files_service.IdentityOptions{
+			Name:  owner.Name,
+			Email: owner.Email,
+		},
+		Dates: &files_service.CommitDateOptions{
+			Author:    time.Now(),
+			Committer: time.Now(),
+		},
+	})
+	assert.NoError(t, err)
+	return repo
+}
+
+func TestAPIActionsWorkflowRuns(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-runs-api", ".gitea/workflows/build.yml",
+			"on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n")
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "build.yml"})
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeReadRepository)
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs?workflow=build.yml&branch=master&actor=%s", user2.Name, repo.Name, user2.Name)).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		runs := new(api.ActionWorkflowRunsResponse)
+		DecodeJSON(t, resp, runs)
+		assert.EqualValues(t, 1, runs.TotalCount)
+		if assert.Len(t, runs.Entries, 1) {
+			assert.Equal(t, run.ID, runs.Entries[0].ID)
+			assert.Equal(t, "push", runs.Entries[0].Event)
+			assert.Equal(t, user2.Name, runs.Entries[0].Actor.UserName)
+		}
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs?workflow=unknown.yml", user2.Name, repo.Name)).
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		runs = new(api.ActionWorkflowRunsResponse)
+		DecodeJSON(t, resp, runs)
+		assert.EqualValues(t, 0, runs.TotalCount)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs?status=success", user2.Name, repo.Name)).
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		runs = new(api.ActionWorkflowRunsResponse)
+		DecodeJSON(t, resp, runs)
+		assert.EqualValues(t, 0, runs.TotalCount)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs?status=invalid", user2.Name, repo.Name)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.ID)).
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		apiRun := new(api.ActionWorkflowRun)
+		DecodeJSON(t, resp, apiRun)
+		assert.Equal(t, run.Index, apiRun.RunNumber)
+		assert.Equal(t, "waiting", apiRun.Status)
+		assert.Equal(t, "master", apiRun.HeadBranch)
+
+		// the run doesn't belong to the repo
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", user2.Name, "repo1", run.ID)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
+
+func TestAPIActionsWorkflowRunTimes(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-run-times", ".gitea/workflows/push.yml",
+			"on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n")
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "push.yml"})
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeReadRepository)
+		runURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.ID)
+
+		// the times of a run which hasn't started are omitted instead of being zero
+		resp := MakeRequest(t, NewRequest(t, "GET", runURL).AddTokenAuth(token), http.StatusOK)
+		var raw map[string]any
+		DecodeJSON(t, resp, &raw)
+		assert.Equal(t, "waiting", raw["status"])
+		assert.NotContains(t, raw, "started_at")
+		assert.NotContains(t, raw, "completed_at")
+
+		resp = MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs", user2.Name, repo.Name)).AddTokenAuth(token), http.StatusOK)
+		runs := new(api.ActionWorkflowRunsResponse)
+		DecodeJSON(t, resp, runs)
+		if assert.Len(t, runs.Entries, 1) {
+			assert.Nil(t, runs.Entries[0].StartedAt)
+			assert.Nil(t, runs.Entries[0].CompletedAt)
+		}
+
+		started := time.Now().Add(-time.Hour).Truncate(time.Second)
+		stopped := started.Add(5 * time.Minute)
+		run.Status = actions_model.StatusSuccess
+		run.Started = timeutil.TimeStamp(started.Unix())
+		run.Stopped = timeutil.TimeStamp(stopped.Unix())
+		assert.NoError(t, actions_model.UpdateRun(db.DefaultContext, run, "status", "started", "stopped"))
+
+		resp = MakeRequest(t, NewRequest(t, "GET", runURL).AddTokenAuth(token), http.StatusOK)
+		apiRun := new(api.ActionWorkflowRun)
+		DecodeJSON(t, resp, apiRun)
+		assert.Equal(t, "success", apiRun.Status)
+		if assert.NotNil(t, apiRun.StartedAt) && assert.NotNil(t, apiRun.CompletedAt) {
+			assert.True(t, started.Equal(*apiRun.StartedAt))
+			assert.True(t, stopped.Equal(*apiRun.CompletedAt))
+		}
+	})
+}
+
+func TestAPIActionsDispatchWorkflow(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-dispatch-api", ".gitea/workflows/dispatch.yml",
+			`name: dispatch
+on:
+  workflow_dispatch:
+    inputs:
+      env:
+        type: choice
+        options: [staging, production]
+        required: true
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ inputs.env }}
+`)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		dispatchURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/dispatch.yml/dispatches", user2.Name, repo.Name)
+
+		// invalid inputs
+		req := NewRequestWithJSON(t, "POST", dispatchURL, &api.CreateActionWorkflowDispatch{
+			Ref:    "master",
+			Inputs: map[string]string{"env": "dev"},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		// unknown workflow
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/unknown.yml/dispatches", user2.Name, repo.Name), &api.CreateActionWorkflowDispatch{
+			Ref: "master",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequestWithJSON(t, "POST", dispatchURL, &api.CreateActionWorkflowDispatch{
+			Ref:    "master",
+			Inputs: map[string]string{"env": "production"},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "dispatch.yml"})
+		assert.Equal(t, "refs/heads/master", run.Ref)
+		assert.Contains(t, run.EventPayload, `"env": "production"`)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs?workflow=dispatch.yml&branch=master&actor=%s", user2.Name, repo.Name, user2.Name)).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		runs := new(api.ActionWorkflowRunsResponse)
+		DecodeJSON(t, resp, runs)
+		assert.EqualValues(t, 1, runs.TotalCount)
+		if assert.Len(t, runs.Entries, 1) {
+			assert.Equal(t, run.ID, runs.Entries[0].ID)
+			assert.Equal(t, "workflow_dispatch", runs.Entries[0].Event)
+			assert.Equal(t, user2.Name, runs.Entries[0].Actor.UserName)
+		}
+	})
+}
+
+func TestAPIActionsDispatchWorkflowWithoutTrigger(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-dispatch-no-trigger", ".gitea/workflows/push.yml",
+			"on:\n  push:\n  schedule:\n    - cron: '0 0 * * *'\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n")
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/push.yml/dispatches", user2.Name, repo.Name), &api.CreateActionWorkflowDispatch{
+			Ref: "master",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: repo.ID, TriggerEvent: "workflow_dispatch"})
+	})
+}
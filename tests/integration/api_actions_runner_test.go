@@ -0,0 +1,209 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/tests"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"code.gitea.io/actions-proto-go/runner/v1/runnerv1connect"
+	"connectrpc.com/connect"
+	gouuid "github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestActionRunner(t *testing.T, name string, ownerID, repoID int64) *actions_model.ActionRunner {
+	runner := &actions_model.ActionRunner{
+		UUID:        gouuid.New().String(),
+		Name:        name,
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		TokenHash:   name,
+		AgentLabels: []string{"ubuntu-latest"},
+	}
+	assert.NoError(t, actions_model.CreateRunner(db.DefaultContext, runner))
+	return runner
+}
+
+func TestAPIActionsRunner(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repoRunner := createTestActionRunner(t, "repo-runner", 0, 1)
+	orgRunner := createTestActionRunner(t, "org-runner", 3, 0)
+	userRunner := createTestActionRunner(t, "user-runner", 2, 0)
+	globalRunner := createTestActionRunner(t, "global-runner", 0, 0)
+
+	cases := []struct {
+		name   string
+		url    string
+		user   string
+		scope  auth_model.AccessTokenScope
+		runner *actions_model.ActionRunner
+		// the runners of the previous cases have been deleted when listing all runners by the admin
+		total int64
+	}{
+		{"Repo", "/api/v1/repos/user2/repo1/actions/runners", "user2", auth_model.AccessTokenScopeWriteRepository, repoRunner, 1},
+		{"Org", "/api/v1/orgs/org3/actions/runners", "user2", auth_model.AccessTokenScopeWriteOrganization, orgRunner, 1},
+		{"User", "/api/v1/user/actions/runners", "user2", auth_model.AccessTokenScopeWriteUser, userRunner, 1},
+		{"Admin", "/api/v1/admin/runners", "user1", auth_model.AccessTokenScopeWriteAdmin, globalRunner, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			token := getUserToken(t, c.user, c.scope)
+
+			req := NewRequest(t, "GET", c.url+"/registration-token").AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			oldToken := &shared.RegistrationToken{}
+			DecodeJSON(t, resp, oldToken)
+			assert.NotEmpty(t, oldToken.Token)
+
+			req = NewRequest(t, "POST", c.url+"/registration-token").AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusCreated)
+			newToken := &shared.RegistrationToken{}
+			DecodeJSON(t, resp, newToken)
+			assert.NotEqual(t, oldToken.Token, newToken.Token)
+
+			req = NewRequest(t, "GET", c.url).AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			list := &api.ActionRunnersResponse{}
+			DecodeJSON(t, resp, list)
+			assert.EqualValues(t, c.total, list.TotalCount)
+			assert.Len(t, list.Entries, int(c.total))
+
+			runnerURL := fmt.Sprintf("%s/%d", c.url, c.runner.ID)
+			req = NewRequest(t, "GET", runnerURL).AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			runner := &api.ActionRunner{}
+			DecodeJSON(t, resp, runner)
+			assert.Equal(t, c.runner.Name, runner.Name)
+			assert.Equal(t, "offline", runner.Status)
+			assert.Equal(t, []string{"ubuntu-latest"}, runner.Labels)
+
+			description := "edited"
+			req = NewRequestWithJSON(t, "PATCH", runnerURL, &api.EditActionRunnerOption{
+				Description: &description,
+				Labels:      &[]string{"linux", " linux ", "arm64"},
+			}).AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, runner)
+			assert.Equal(t, "edited", runner.Description)
+			assert.Equal(t, []string{"linux", "arm64"}, runner.Labels)
+
+			req = NewRequestWithJSON(t, "PATCH", runnerURL, &api.EditActionRunnerOption{
+				Labels: &[]string{""},
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+			req = NewRequest(t, "DELETE", runnerURL).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+			req = NewRequest(t, "GET", runnerURL).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+	}
+
+	t.Run("OtherScope", func(t *testing.T) {
+		runner := createTestActionRunner(t, "other-runner", 0, 2)
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/user2/repo1/actions/runners/%d", runner.ID)).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
+
+func TestAPIActionsRunnerJITConfig(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-jit", ".gitea/workflows/test.yml",
+			`name: test
+on: push
+jobs:
+  job1:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo job1
+  job2:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo job2
+`)
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		jitURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/runners/generate-jitconfig", user2.Name, repo.Name)
+
+		req := NewRequestWithJSON(t, "POST", jitURL, &api.GenerateActionRunnerJITConfigOption{
+			Name:   "jit-runner",
+			Labels: []string{""},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", jitURL, &api.GenerateActionRunnerJITConfigOption{
+			Name:   "jit-runner",
+			Labels: []string{"ubuntu-latest"},
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		jit := &api.ActionRunnerJITConfig{}
+		DecodeJSON(t, resp, jit)
+		assert.Equal(t, "jit-runner", jit.Runner.Name)
+		assert.True(t, jit.Runner.Ephemeral)
+
+		content, err := base64.StdEncoding.DecodeString(jit.EncodedJITConfig)
+		assert.NoError(t, err)
+		config := struct {
+			ID        int64    `json:"id"`
+			UUID      string   `json:"uuid"`
+			Token     string   `json:"token"`
+			Address   string   `json:"address"`
+			Labels    []string `json:"labels"`
+			Ephemeral bool     `json:"ephemeral"`
+		}{}
+		assert.NoError(t, json.Unmarshal(content, &config))
+		assert.Equal(t, jit.Runner.ID, config.ID)
+		assert.Equal(t, setting.AppURL, config.Address)
+		assert.Equal(t, []string{"ubuntu-latest"}, config.Labels)
+		assert.True(t, config.Ephemeral)
+
+		// the runner can work with the config without registering
+		client := runnerv1connect.NewRunnerServiceClient(http.DefaultClient, u.String()+"api/actions",
+			connect.WithInterceptors(connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+				return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+					req.Header().Set("x-runner-uuid", config.UUID)
+					req.Header().Set("x-runner-token", config.Token)
+					return next(ctx, req)
+				}
+			})))
+
+		// the ephemeral runner only picks up one task though there are two waiting jobs
+		fetchResp, err := client.FetchTask(context.Background(), connect.NewRequest(&runnerv1.FetchTaskRequest{}))
+		assert.NoError(t, err)
+		task := fetchResp.Msg.Task
+		assert.NotNil(t, task)
+		fetchResp, err = client.FetchTask(context.Background(), connect.NewRequest(&runnerv1.FetchTaskRequest{}))
+		assert.NoError(t, err)
+		assert.Nil(t, fetchResp.Msg.Task)
+
+		// the ephemeral runner is deleted when the task is done
+		_, err = client.UpdateTask(context.Background(), connect.NewRequest(&runnerv1.UpdateTaskRequest{
+			State: &runnerv1.TaskState{
+				Id:     task.Id,
+				Result: runnerv1.Result_RESULT_SUCCESS,
+			},
+		}))
+		assert.NoError(t, err)
+		unittest.AssertNotExistsBean(t, &actions_model.ActionRunner{ID: config.ID})
+	})
+}
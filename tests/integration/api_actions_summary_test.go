@@ -0,0 +1,86 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_web "code.gitea.io/gitea/routers/web/repo/actions"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/builder"
+)
+
+func TestActionsStepSummary(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo := createActionsTestRepo(t, user2, "actions-step-summary", ".gitea/workflows/test.yml",
+			"on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make build\n      - run: make test\n")
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID, JobID: "test"})
+
+		task := &actions_model.ActionTask{
+			JobID:      job.ID,
+			Attempt:    1,
+			RunAttempt: 1,
+			Status:     actions_model.StatusRunning,
+			RepoID:     repo.ID,
+			OwnerID:    repo.OwnerID,
+			CommitSHA:  job.CommitSHA,
+		}
+		assert.NoError(t, task.GenerateToken())
+		assert.NoError(t, db.Insert(db.DefaultContext, task))
+		job.TaskID = task.ID
+		_, err := actions_model.UpdateRunJob(db.DefaultContext, job, nil, "task_id")
+		assert.NoError(t, err)
+
+		upload := func(step, content string, expectedStatus int) {
+			req := NewRequestWithBody(t, "PUT", "/api/actions_summary/_apis/summaries/"+step, strings.NewReader(content)).AddTokenAuth(task.Token)
+			MakeRequest(t, req, expectedStatus)
+		}
+		upload("1", "### Tests\n\n- 10 passed", http.StatusNoContent)
+		upload("0", "## Build\n\nfailed", http.StatusNoContent)
+		// the summary uploaded again replaces the old one
+		upload("0", "## Build\n\n**succeeded**", http.StatusNoContent)
+		upload("-1", "step summary", http.StatusBadRequest)
+		upload("2", strings.Repeat("a", actions_model.MaxStepSummarySize+1), http.StatusRequestEntityTooLarge)
+
+		summary := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionStepSummary{TaskID: task.ID}, builder.Eq{"step_index": 0})
+		assert.Equal(t, "## Build\n\n**succeeded**", summary.Content)
+		assert.Equal(t, repo.ID, summary.RepoID)
+		assert.Equal(t, run.ID, summary.RunID)
+		assert.Equal(t, job.ID, summary.JobID)
+		unittest.AssertCount(t, &actions_model.ActionStepSummary{TaskID: task.ID}, 2)
+
+		// the summaries of the steps of a job are rendered in order on the run view
+		session := loginUser(t, user2.Name)
+		runURL := fmt.Sprintf("/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.Index)
+		req := NewRequestWithJSON(t, "POST", runURL+"/jobs/0", &actions_web.ViewRequest{})
+		req.Header.Add("X-Csrf-Token", GetCSRF(t, session, runURL))
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		view := &actions_web.ViewResponse{}
+		DecodeJSON(t, resp, view)
+		if assert.Len(t, view.State.Run.Summaries, 1) {
+			assert.Equal(t, "test", view.State.Run.Summaries[0].JobName)
+			assert.Equal(t, runURL+"/jobs/0", view.State.Run.Summaries[0].JobLink)
+			content := view.State.Run.Summaries[0].Content
+			assert.Contains(t, content, "<strong>succeeded</strong>")
+			assert.Less(t, strings.Index(content, "Build"), strings.Index(content, "Tests"))
+			assert.NotContains(t, content, "failed")
+		}
+
+		// the summaries can't be uploaded once the task is done
+		task.Status = actions_model.StatusSuccess
+		assert.NoError(t, actions_model.UpdateTask(db.DefaultContext, task, "status"))
+		upload("0", "step summary", http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminVariables(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	token := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteAdmin)
+
+	t.Run("CreateVariable", func(t *testing.T) {
+		cases := []struct {
+			Name           string
+			ExpectedStatus int
+		}{
+			{
+				Name:           "-",
+				ExpectedStatus: http.StatusBadRequest,
+			},
+			{
+				Name:           "GLOBAL_VAR",
+				ExpectedStatus: http.StatusNoContent,
+			},
+			{
+				Name:           "global_var",
+				ExpectedStatus: http.StatusConflict,
+			},
+			{
+				Name:           "ci",
+				ExpectedStatus: http.StatusBadRequest,
+			},
+			{
+				Name:           "gitea_var",
+				ExpectedStatus: http.StatusBadRequest,
+			},
+		}
+
+		for _, c := range cases {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/admin/actions/variables/%s", c.Name), api.CreateVariableOption{
+				Value: "value",
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, c.ExpectedStatus)
+		}
+
+		req := NewRequest(t, "GET", "/api/v1/admin/actions/variables/global_var").AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		variable := &api.ActionVariable{}
+		DecodeJSON(t, resp, variable)
+		assert.Equal(t, api.ActionVariable{Name: "GLOBAL_VAR", Data: "value"}, *variable)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/actions/variables").AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var variables []*api.ActionVariable
+		DecodeJSON(t, resp, &variables)
+		if assert.Len(t, variables, 1) {
+			assert.Equal(t, "GLOBAL_VAR", variables[0].Name)
+		}
+	})
+
+	t.Run("UpdateVariable", func(t *testing.T) {
+		req := NewRequestWithJSON(t, "PUT", "/api/v1/admin/actions/variables/not_found_var", api.UpdateVariableOption{
+			Value: "updated_val",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequestWithJSON(t, "PUT", "/api/v1/admin/actions/variables/global_var", api.UpdateVariableOption{
+			Name:  "updated_var",
+			Value: "updated_val",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/actions/variables/updated_var").AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		variable := &api.ActionVariable{}
+		DecodeJSON(t, resp, variable)
+		assert.Equal(t, "updated_val", variable.Data)
+	})
+
+	t.Run("DeleteVariable", func(t *testing.T) {
+		req := NewRequest(t, "DELETE", "/api/v1/admin/actions/variables/updated_var").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "DELETE", "/api/v1/admin/actions/variables/updated_var").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteAdmin)
+		req := NewRequest(t, "GET", "/api/v1/admin/actions/variables").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+}
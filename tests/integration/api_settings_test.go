@@ -9,6 +9,7 @@ import (
 
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
 	"code.gitea.io/gitea/tests"
 
 	"github.com/stretchr/testify/assert"
@@ -61,4 +62,18 @@ func TestAPIExposedSettings(t *testing.T) {
 		MaxFiles:     setting.Attachment.MaxFiles,
 		MaxSize:      setting.Attachment.MaxSize,
 	}, attachment)
+
+	defer test.MockVariableValue(&setting.Actions.MinRunnerVersion, "v0.2.10")()
+	defer test.MockVariableValue(&setting.Actions.RecommendedRunnerVersion, "v0.2.11")()
+	actions := new(api.GeneralActionsSettings)
+	req = NewRequest(t, "GET", "/api/v1/settings/actions")
+	resp = MakeRequest(t, req, http.StatusOK)
+
+	DecodeJSON(t, resp, &actions)
+	assert.EqualValues(t, &api.GeneralActionsSettings{
+		Enabled:                  setting.Actions.Enabled,
+		MinRunnerVersion:         "v0.2.10",
+		RecommendedRunnerVersion: "v0.2.11",
+		RejectOutdatedRunners:    setting.Actions.RejectOutdatedRunners,
+	}, actions)
 }
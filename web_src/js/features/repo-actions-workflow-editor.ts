@@ -0,0 +1,69 @@
+import {createMonaco} from './codeeditor.ts';
+import {onInputDebounce, toggleElem} from '../utils/dom.ts';
+import {POST} from '../modules/fetch.ts';
+
+type WorkflowLintProblem = {
+  line: number,
+  column: number,
+  severity: 'error' | 'warning',
+  message: string,
+};
+
+export async function initRepoActionsWorkflowEditor() {
+  const form = document.querySelector<HTMLFormElement>('#workflow-editor-form');
+  if (!form) return;
+
+  const textarea = form.querySelector<HTMLTextAreaElement>('#workflow-content');
+  const problemList = form.querySelector('#workflow-lint-problems');
+  const noProblem = form.querySelector('#workflow-lint-no-problem');
+  const {monaco, editor} = await createMonaco(textarea, 'workflow.yml', {language: 'yaml'});
+  const model = editor.getModel();
+
+  const renderProblems = (problems: WorkflowLintProblem[]) => {
+    problemList.replaceChildren(...problems.map((problem) => {
+      const li = document.createElement('li');
+      li.className = `tw-cursor-pointer text ${problem.severity === 'error' ? 'red' : 'yellow'}`;
+      li.setAttribute('data-line', String(problem.line));
+      li.setAttribute('data-column', String(problem.column));
+      li.textContent = problem.line ? `${problem.line}:${problem.column} ${problem.message}` : problem.message;
+      return li;
+    }));
+    toggleElem(noProblem, !problems.length);
+
+    // highlight the problems in the editor, the whole word at the position or the whole line if the column is unknown
+    monaco.editor.setModelMarkers(model, 'workflow-lint', problems.map((problem) => {
+      const lineNumber = Math.min(Math.max(problem.line, 1), model.getLineCount());
+      const word = problem.column ? model.getWordAtPosition({lineNumber, column: problem.column}) : null;
+      return {
+        severity: problem.severity === 'error' ? monaco.MarkerSeverity.Error : monaco.MarkerSeverity.Warning,
+        message: problem.message,
+        startLineNumber: lineNumber,
+        startColumn: word ? word.startColumn : model.getLineMinColumn(lineNumber),
+        endLineNumber: lineNumber,
+        endColumn: word ? word.endColumn : model.getLineMaxColumn(lineNumber),
+      };
+    }));
+  };
+
+  const lint = onInputDebounce(async () => {
+    try {
+      const resp = await POST(form.getAttribute('data-lint-url'), {data: new URLSearchParams({content: textarea.value})});
+      const data = await resp.json();
+      renderProblems(data.problems);
+    } catch (error) {
+      console.error('Failed to lint the workflow', error);
+    }
+  });
+  model.onDidChangeContent(lint);
+
+  problemList.addEventListener('click', (e) => {
+    const li = (e.target as HTMLElement).closest('li[data-line]');
+    const lineNumber = Number(li?.getAttribute('data-line'));
+    if (!lineNumber) return;
+    editor.revealLineInCenter(lineNumber);
+    editor.setPosition({lineNumber, column: Number(li.getAttribute('data-column')) || 1});
+    editor.focus();
+  });
+
+  lint();
+}